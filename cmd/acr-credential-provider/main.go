@@ -33,6 +33,8 @@ import (
 
 func main() {
 	rand.Seed(time.Now().UnixNano())
+	var repositoryScopedTokens bool
+	var tokenCacheDir string
 	command := &cobra.Command{
 		Use:   "acr-credential-provider configFile",
 		Short: "Acr credential provider for Kubelet",
@@ -44,7 +46,14 @@ func main() {
 				os.Exit(1)
 			}
 
-			acrProvider, err := credentialprovider.NewAcrProvider(args[0])
+			var opts []credentialprovider.Option
+			if repositoryScopedTokens {
+				opts = append(opts, credentialprovider.WithRepositoryScopedTokens())
+			}
+			if tokenCacheDir != "" {
+				opts = append(opts, credentialprovider.WithTokenCache(tokenCacheDir))
+			}
+			acrProvider, err := credentialprovider.NewAcrProvider(args[0], opts...)
 			if err != nil {
 				klog.Errorf("Failed to initialize ACR provider: %v", err)
 				os.Exit(1)
@@ -57,6 +66,13 @@ func main() {
 		},
 	}
 
+	command.Flags().BoolVar(&repositoryScopedTokens, "repository-scoped-tokens", false,
+		"Return an access token which can only pull the repository of the image, cached per image until it expires, instead of the registry-wide refresh token. "+
+			"The refresh token is returned if the registry doesn't issue the repository scoped tokens.")
+	command.Flags().StringVar(&tokenCacheDir, "token-cache-dir", "",
+		"The directory caching the AAD access tokens, the auth challenges and the ACR refresh tokens across the provider executions until they expire. "+
+			"It's created only accessible by the owner if it doesn't exist. The tokens are not cached if empty.")
+
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/kubelet/pkg/apis/credentialprovider/install"
 	v1 "k8s.io/kubelet/pkg/apis/credentialprovider/v1"
 
@@ -42,6 +43,16 @@ func init() {
 	install.Install(scheme)
 }
 
+// serviceAccountRequest holds the service account fields of the CredentialProviderRequest, which kubelet
+// sets for the plugins configured with the service account token audience. The fields are decoded
+// separately since the vendored kubelet API predates them.
+type serviceAccountRequest struct {
+	// ServiceAccountToken is the service account token of the pod pulling the image.
+	ServiceAccountToken string `json:"serviceAccountToken,omitempty"`
+	// ServiceAccountAnnotations are the annotations of the service account in the configured keys.
+	ServiceAccountAnnotations map[string]string `json:"serviceAccountAnnotations,omitempty"`
+}
+
 // ExecPlugin implements the exec-based plugin for fetching credentials that is invoked by the kubelet.
 type ExecPlugin struct {
 	plugin credentialprovider.CredentialProvider
@@ -85,7 +96,21 @@ func (e *ExecPlugin) runPlugin(ctx context.Context, r io.Reader, w io.Writer, ar
 		return errors.New("image in plugin request was empty")
 	}
 
-	response, err := e.plugin.GetCredentials(ctx, request.Image, args)
+	var saRequest serviceAccountRequest
+	if err := utiljson.Unmarshal(data, &saRequest); err != nil {
+		return err
+	}
+
+	var response *v1.CredentialProviderResponse
+	if saRequest.ServiceAccountToken != "" {
+		saPlugin, ok := e.plugin.(credentialprovider.ServiceAccountCredentialProvider)
+		if !ok {
+			return errors.New("the plugin doesn't support pulling images with service account tokens")
+		}
+		response, err = saPlugin.GetCredentialsWithServiceAccount(ctx, request.Image, saRequest.ServiceAccountToken, saRequest.ServiceAccountAnnotations)
+	} else {
+		response, err = e.plugin.GetCredentials(ctx, request.Image, args)
+	}
 	if err != nil {
 		return err
 	}
@@ -43,6 +43,23 @@ func (f *fakePlugin) GetCredentials(_ context.Context, _ string, _ []string) (*v
 	}, nil
 }
 
+type fakeServiceAccountPlugin struct {
+	fakePlugin
+}
+
+func (f *fakeServiceAccountPlugin) GetCredentialsWithServiceAccount(_ context.Context, image, serviceAccountToken string, serviceAccountAnnotations map[string]string) (*v1.CredentialProviderResponse, error) {
+	return &v1.CredentialProviderResponse{
+		CacheKeyType:  v1.ImagePluginCacheKeyType,
+		CacheDuration: &metav1.Duration{Duration: 10 * time.Minute},
+		Auth: map[string]v1.AuthConfig{
+			image: {
+				Username: serviceAccountAnnotations["azure.workload.identity/client-id"],
+				Password: serviceAccountToken,
+			},
+		},
+	}, nil
+}
+
 func Test_runPlugin(t *testing.T) {
 	testcases := []struct {
 		name        string
@@ -99,3 +116,24 @@ func Test_runPlugin(t *testing.T) {
 		})
 	}
 }
+
+func Test_runPluginWithServiceAccountToken(t *testing.T) {
+	request := `{"kind":"CredentialProviderRequest","apiVersion":"credentialprovider.kubelet.k8s.io/v1","image":"test.registry.io/foobar",` +
+		`"serviceAccountToken":"sa-token","serviceAccountAnnotations":{"azure.workload.identity/client-id":"client"}}`
+
+	out := &bytes.Buffer{}
+	err := NewCredentialProvider(&fakeServiceAccountPlugin{}).runPlugin(context.TODO(), bytes.NewBufferString(request), out, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOut := `{"kind":"CredentialProviderResponse","apiVersion":"credentialprovider.kubelet.k8s.io/v1","cacheKeyType":"Image","cacheDuration":"10m0s","auth":{"test.registry.io/foobar":{"username":"client","password":"sa-token"}}}
+`
+	if out.String() != expectedOut {
+		t.Errorf("unexpected output %s, expected %s", out.String(), expectedOut)
+	}
+
+	err = NewCredentialProvider(&fakePlugin{}).runPlugin(context.TODO(), bytes.NewBufferString(request), &bytes.Buffer{}, nil)
+	if err == nil {
+		t.Error("expected error for the plugin not supporting service account tokens but got none")
+	}
+}
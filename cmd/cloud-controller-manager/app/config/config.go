@@ -24,6 +24,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	cloudprovider "k8s.io/cloud-provider"
 	ccmconfig "k8s.io/cloud-provider/config"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
 
 	nodeipamconfig "sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/config"
 )
@@ -60,6 +61,9 @@ type Config struct {
 	SharedInformers informers.SharedInformerFactory
 
 	DynamicReloadingConfig DynamicReloadingConfig
+
+	// TracingConfig is the config of the OTLP span exporter, nil if tracing is disabled
+	TracingConfig *tracingapi.TracingConfiguration
 }
 
 type DynamicReloadingConfig struct {
@@ -67,6 +71,7 @@ type DynamicReloadingConfig struct {
 	CloudConfigSecretName      string
 	CloudConfigSecretNamespace string
 	CloudConfigKey             string
+	EnableConfigFileReloading  bool
 }
 
 type completedConfig struct {
@@ -24,6 +24,8 @@ import (
 	"math/big"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -57,6 +59,7 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/dynamic"
 	"sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/options"
 	"sigs.k8s.io/cloud-provider-azure/pkg/provider"
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace"
 	"sigs.k8s.io/cloud-provider-azure/pkg/version"
 	"sigs.k8s.io/cloud-provider-azure/pkg/version/verflag"
 )
@@ -66,8 +69,45 @@ const (
 	ControllerStartJitter = 1.0
 	// ConfigzName is the name used for register cloud-controller manager /configz, same with GroupName.
 	ConfigzName = "cloudcontrollermanager.config.k8s.io"
+	// controllerDebugPath is the path prefix of the debugging handlers of the controllers, which
+	// are served at /debug/controllers/<controller name>/.
+	controllerDebugPath = "/debug/controllers/"
 )
 
+// debugHandlers serves the debugging handlers of the running controllers, which are replaced
+// whenever the controllers are restarted.
+var debugHandlers = newControllerDebugHandlers()
+
+// controllerDebugHandlers dispatches the requests under controllerDebugPath to the debugging
+// handlers of the controllers by name.
+type controllerDebugHandlers struct {
+	lock     sync.RWMutex
+	handlers map[string]http.Handler
+}
+
+func newControllerDebugHandlers() *controllerDebugHandlers {
+	return &controllerDebugHandlers{handlers: make(map[string]http.Handler)}
+}
+
+// set replaces the debugging handlers of the controllers.
+func (h *controllerDebugHandlers) set(handlers map[string]http.Handler) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.handlers = handlers
+}
+
+func (h *controllerDebugHandlers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, controllerDebugPath), "/")
+	h.lock.RLock()
+	handler, ok := h.handlers[name]
+	h.lock.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.StripPrefix(controllerDebugPath+name, handler).ServeHTTP(w, r)
+}
+
 // NewCloudControllerManagerCommand creates a *cobra.Command object with default parameters
 func NewCloudControllerManagerCommand() *cobra.Command {
 	s, err := options.NewCloudControllerManagerOptions()
@@ -149,6 +189,8 @@ func NewCloudControllerManagerCommand() *cobra.Command {
 		},
 	}
 
+	cmd.AddCommand(NewValidateConfigCommand())
+
 	fs := cmd.Flags()
 	namedFlagSets := s.Flags(KnownControllers(), ControllersDisabledByDefault.List())
 	verflag.AddFlags(namedFlagSets.FlagSet("global"))
@@ -293,6 +335,7 @@ func StartHTTPServer(c *cloudcontrollerconfig.CompletedConfig, stopCh <-chan str
 		}
 
 		healthz.InstallReadyzHandler(unsecuredMux, checks...)
+		unsecuredMux.UnlistedHandlePrefix(controllerDebugPath, debugHandlers)
 	}
 
 	return healthzHandler, nil
@@ -308,11 +351,29 @@ func Run(ctx context.Context, c *cloudcontrollerconfig.CompletedConfig, h *contr
 		err   error
 	)
 
+	if c.TracingConfig != nil {
+		shutdownTracerProvider, err := trace.InitTracerProvider(ctx, c.TracingConfig, "cloud-controller-manager")
+		if err != nil {
+			klog.Fatalf("Run: failed to initialize the tracer provider: %v", err)
+		}
+		defer func() {
+			if err := shutdownTracerProvider(context.Background()); err != nil {
+				klog.Errorf("Run: failed to shut down the tracer provider: %v", err)
+			}
+		}()
+	}
+
 	if c.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile != "" {
+		validateCloudConfigFileOrDie(c.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile)
 		cloud, err = provider.NewCloudFromConfigFile(ctx, c.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile, true)
 		if err != nil {
 			klog.Fatalf("Cloud provider azure could not be initialized: %v", err)
 		}
+		if c.DynamicReloadingConfig.EnableConfigFileReloading {
+			if err := dynamic.RunConfigFileReloader(ctx, cloud, c.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile); err != nil {
+				klog.Fatalf("Run: failed to start the cloud config file reloader: %v", err)
+			}
+		}
 	} else if c.DynamicReloadingConfig.EnableDynamicReloading && c.DynamicReloadingConfig.CloudConfigSecretName != "" {
 		cloud, err = provider.NewCloudFromSecret(ctx, c.ClientBuilder, c.DynamicReloadingConfig.CloudConfigSecretName, c.DynamicReloadingConfig.CloudConfigSecretNamespace, c.DynamicReloadingConfig.CloudConfigKey)
 		if err != nil {
@@ -364,6 +425,7 @@ func startControllers(ctx context.Context, controllerContext genericcontrollerma
 	}
 
 	var controllerChecks []healthz.HealthChecker
+	controllerDebugHandlers := make(map[string]http.Handler)
 	for controllerName, initFn := range controllers {
 		if !genericcontrollermanager.IsControllerEnabled(controllerName, ControllersDisabledByDefault, completedConfig.ComponentConfig.Generic.Controllers) {
 			klog.Warningf("%q is disabled", controllerName)
@@ -382,6 +444,7 @@ func startControllers(ctx context.Context, controllerContext genericcontrollerma
 		}
 		check := controllerhealthz.NamedPingChecker(controllerName)
 		if ctrl != nil {
+			controllerDebugHandlers[controllerName] = ctrl
 			if healthCheckable, ok := ctrl.(controller.HealthCheckable); ok {
 				if realCheck := healthCheckable.HealthChecker(); realCheck != nil {
 					check = controllerhealthz.NamedHealthChecker(controllerName, realCheck)
@@ -396,6 +459,7 @@ func startControllers(ctx context.Context, controllerContext genericcontrollerma
 	if healthzHandler != nil {
 		healthzHandler.AddHealthChecker(controllerChecks...)
 	}
+	debugHandlers.set(controllerDebugHandlers)
 
 	// If apiserver is not running we should wait for some time and fail only then. This is particularly
 	// important when we start apiserver and controller manager at the same time.
@@ -17,6 +17,8 @@ limitations under the License.
 package app
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -43,3 +45,27 @@ func TestShouldDisableCloudProvider(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, res)
 }
+
+func TestControllerDebugHandlers(t *testing.T) {
+	handlers := newControllerDebugHandlers()
+	handlers.set(map[string]http.Handler{
+		"node-ipam": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.URL.Path))
+		}),
+	})
+
+	recorder := httptest.NewRecorder()
+	handlers.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/controllers/node-ipam/cidr-capacity", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "/cidr-capacity", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	handlers.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/controllers/route/", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	// the handlers of the restarted controllers replace the previous ones
+	handlers.set(map[string]http.Handler{})
+	recorder = httptest.NewRecorder()
+	handlers.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/debug/controllers/node-ipam/cidr-capacity", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
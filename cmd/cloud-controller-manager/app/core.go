@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	cloudprovider "k8s.io/cloud-provider"
 	nodecontroller "k8s.io/cloud-provider/controllers/node"
@@ -205,21 +206,40 @@ func startNodeIpamController(_ context.Context, _ genericcontrollermanager.Contr
 	// get list of node cidr mask sizes
 	nodeCIDRMaskSizes := getNodeCIDRMaskSizes(clusterCIDRs, nodeCIDRMaskSizeIPv4, nodeCIDRMaskSizeIPv6)
 
+	kubeClient := completedConfig.ClientBuilder.ClientOrDie("node-controller")
+
+	// get the cluster cidrs dedicated to node pools
+	var (
+		nodePoolCIDRs          []ipam.NodePoolCIDR
+		nodePoolCIDRsConfigMap *v1.ObjectReference
+	)
+	if configMap := completedConfig.NodeIPAMControllerConfig.NodePoolCIDRsConfigMap; configMap != "" {
+		namespace, name, _ := strings.Cut(configMap, "/")
+		nodePoolCIDRs, nodePoolCIDRsConfigMap, err = ipam.LoadNodePoolCIDRs(kubeClient, namespace, name)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
 	nodeIpamController, err := nodeipamcontroller.NewNodeIpamController(
 		completedConfig.SharedInformers.Core().V1().Nodes(),
 		cloud,
-		completedConfig.ClientBuilder.ClientOrDie("node-controller"),
+		kubeClient,
 		clusterCIDRs,
 		serviceCIDR,
 		secondaryServiceCIDR,
 		nodeCIDRMaskSizes,
+		nodePoolCIDRs,
+		nodePoolCIDRsConfigMap,
+		float64(completedConfig.NodeIPAMControllerConfig.NodeCIDRCapacityWarningThreshold)/100,
 		ipam.CIDRAllocatorType(completedConfig.ComponentConfig.KubeCloudShared.CIDRAllocatorType),
 	)
 	if err != nil {
 		return nil, true, err
 	}
 	go nodeIpamController.Run(stopCh)
-	return nil, true, nil
+	// the controller serves the CIDR capacity checks of the planned nodes
+	return nodeIpamController, true, nil
 }
 
 // setNodeCIDRMaskSizesDualStack returns the IPv4 and IPv6 node cidr mask sizes to the value provided
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider"
+)
+
+// RunConfigFileReloader starts reloading the cloud config file into the cloud in place until the context is done.
+func RunConfigFileReloader(ctx context.Context, cloud cloudprovider.Interface, path string) error {
+	az, ok := cloud.(*provider.Cloud)
+	if !ok {
+		return fmt.Errorf("RunConfigFileReloader: unexpected cloud provider type %T", cloud)
+	}
+	reloader, err := provider.NewConfigFileReloader(az, path, true, podReference())
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := reloader.Run(ctx); err != nil {
+			klog.Errorf("RunConfigFileReloader: stopped reloading cloud config file %s: %v", path, err)
+		}
+	}()
+	return nil
+}
+
+// podReference returns the reference of the controller manager pod, set by the downward API in the
+// POD_NAME and POD_NAMESPACE environment variables, for the reload events. It returns nil if they are not set.
+func podReference() runtime.Object {
+	name, namespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return &v1.ObjectReference{Kind: "Pod", APIVersion: "v1", Name: name, Namespace: namespace}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package options
 
 import (
+	"fmt"
+
 	"github.com/spf13/pflag"
 
 	app "sigs.k8s.io/cloud-provider-azure/cmd/cloud-controller-manager/app/config"
@@ -28,6 +30,7 @@ type DynamicReloadingOptions struct {
 	CloudConfigSecretName      string
 	CloudConfigSecretNamespace string
 	CloudConfigKey             string
+	EnableConfigFileReloading  bool
 }
 
 // AddFlags adds flags related to dynamic reloading for controller manager to the specified FlagSet
@@ -40,6 +43,7 @@ func (o *DynamicReloadingOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.CloudConfigSecretName, "cloud-config-secret-name", "", "The name of the cloud config secret.")
 	fs.StringVar(&o.CloudConfigSecretNamespace, "cloud-config-secret-namespace", "kube-system", "The k8s namespace of the cloud config secret, default to 'kube-system'.")
 	fs.StringVar(&o.CloudConfigKey, "cloud-config-key", "cloud-config", "The key of the config data in the cloud config secret, default to 'cloud-config'.")
+	fs.BoolVar(&o.EnableConfigFileReloading, "enable-cloud-config-file-reloading", false, "Enable reloading the --cloud-config file in place without restarting the controllers. An invalid file is rejected and the working config is kept.")
 }
 
 // ApplyTo fills up dynamic reloading config with options
@@ -52,13 +56,22 @@ func (o *DynamicReloadingOptions) ApplyTo(cfg *app.DynamicReloadingConfig) error
 	cfg.CloudConfigSecretName = o.CloudConfigSecretName
 	cfg.CloudConfigSecretNamespace = o.CloudConfigSecretNamespace
 	cfg.CloudConfigKey = o.CloudConfigKey
+	cfg.EnableConfigFileReloading = o.EnableConfigFileReloading
 
 	return nil
 }
 
 // Validate checks validation of DynamicReloadingOptions
 func (o *DynamicReloadingOptions) Validate() []error {
-	return nil
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	if o.EnableDynamicReloading && o.EnableConfigFileReloading {
+		errs = append(errs, fmt.Errorf("--enable-dynamic-reloading and --enable-cloud-config-file-reloading are mutually exclusive"))
+	}
+	return errs
 }
 
 func defaultDynamicReloadingOptions() *DynamicReloadingOptions {
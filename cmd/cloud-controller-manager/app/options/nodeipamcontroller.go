@@ -40,6 +40,8 @@ func (o *NodeIPAMControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.Int32Var(&o.NodeCIDRMaskSize, "node-cidr-mask-size", consts.DefaultNodeCIDRMaskSize, "Mask size for node cidr in cluster. Default is 24 for IPv4 and 64 for IPv6.")
 	fs.Int32Var(&o.NodeCIDRMaskSizeIPv4, "node-cidr-mask-size-ipv4", 0, "Mask size for IPv4 node cidr in dual-stack cluster. Default is 24.")
 	fs.Int32Var(&o.NodeCIDRMaskSizeIPv6, "node-cidr-mask-size-ipv6", 0, "Mask size for IPv6 node cidr in dual-stack cluster. Default is 64.")
+	fs.Int32Var(&o.NodeCIDRCapacityWarningThreshold, "node-cidr-capacity-warning-threshold", consts.DefaultNodeCIDRCapacityWarningThreshold, "The percentage of free node cidrs in a cluster cidr below which warning events are emitted. 0 disables the warnings.")
+	fs.StringVar(&o.NodePoolCIDRsConfigMap, "node-pool-cidrs-configmap", "", "The <namespace>/<name> of the ConfigMap holding the cluster CIDRs dedicated to the node pools selected by node labels. Nodes matching none of them get the pod CIDRs from --cluster-cidr.")
 }
 
 // ApplyTo fills up NodeIpamController config with options.
@@ -60,6 +62,8 @@ func (o *NodeIPAMControllerOptions) ApplyTo(cfg *nodeipamconfig.NodeIPAMControll
 	cfg.NodeCIDRMaskSize = o.NodeCIDRMaskSize
 	cfg.NodeCIDRMaskSizeIPv4 = o.NodeCIDRMaskSizeIPv4
 	cfg.NodeCIDRMaskSizeIPv6 = o.NodeCIDRMaskSizeIPv6
+	cfg.NodePoolCIDRsConfigMap = o.NodePoolCIDRsConfigMap
+	cfg.NodeCIDRCapacityWarningThreshold = o.NodeCIDRCapacityWarningThreshold
 
 	return nil
 }
@@ -76,6 +80,16 @@ func (o *NodeIPAMControllerOptions) Validate() []error {
 		errs = append(errs, fmt.Errorf("--service-cluster-ip-range can not contain more than two entries"))
 	}
 
+	if o.NodeCIDRCapacityWarningThreshold < 0 || o.NodeCIDRCapacityWarningThreshold > 100 {
+		errs = append(errs, fmt.Errorf("--node-cidr-capacity-warning-threshold must be between 0 and 100"))
+	}
+
+	if o.NodePoolCIDRsConfigMap != "" {
+		if parts := strings.Split(o.NodePoolCIDRsConfigMap, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			errs = append(errs, fmt.Errorf("--node-pool-cidrs-configmap must be in the format of <namespace>/<name>"))
+		}
+	}
+
 	return errs
 }
 
@@ -86,6 +100,8 @@ func defaultNodeIPAMControllerOptions() *NodeIPAMControllerOptions {
 			NodeCIDRMaskSize:     consts.DefaultNodeCIDRMaskSize,
 			NodeCIDRMaskSizeIPv4: 0,
 			NodeCIDRMaskSizeIPv6: 0,
+
+			NodeCIDRCapacityWarningThreshold: consts.DefaultNodeCIDRCapacityWarningThreshold,
 		},
 	}
 }
@@ -98,7 +98,8 @@ func TestDefaultFlags(t *testing.T) {
 		},
 		NodeIPAMController: &NodeIPAMControllerOptions{
 			NodeIPAMControllerConfiguration: &config.NodeIPAMControllerConfiguration{
-				NodeCIDRMaskSize: consts.DefaultNodeCIDRMaskSize,
+				NodeCIDRMaskSize:                 consts.DefaultNodeCIDRMaskSize,
+				NodeCIDRCapacityWarningThreshold: consts.DefaultNodeCIDRCapacityWarningThreshold,
 			},
 		},
 		SecureServing: (&apiserveroptions.SecureServingOptions{
@@ -140,6 +141,10 @@ func TestDefaultFlags(t *testing.T) {
 			CloudConfigSecretNamespace: "kube-system",
 			CloudConfigKey:             "",
 		},
+		Tracing: &TracingOptions{
+			Endpoint:               "",
+			SamplingRatePerMillion: 0,
+		},
 	}
 	if !reflect.DeepEqual(expected, s) {
 		t.Errorf("Got different run options than expected.\nDifference detected on:\n%s", diff.ObjectReflectDiff(expected, s))
@@ -184,6 +189,8 @@ func TestAddFlags(t *testing.T) {
 		"--use-service-account-credentials=false",
 		"--enable-dynamic-reloading=true",
 		"--cloud-config-secret-name=test-secret",
+		"--tracing-endpoint=localhost:4317",
+		"--tracing-sampling-rate-per-million=1000",
 	}
 	err := fs.Parse(args)
 	if err != nil {
@@ -243,7 +250,8 @@ func TestAddFlags(t *testing.T) {
 		},
 		NodeIPAMController: &NodeIPAMControllerOptions{
 			NodeIPAMControllerConfiguration: &config.NodeIPAMControllerConfiguration{
-				NodeCIDRMaskSize: consts.DefaultNodeCIDRMaskSize,
+				NodeCIDRMaskSize:                 consts.DefaultNodeCIDRMaskSize,
+				NodeCIDRCapacityWarningThreshold: consts.DefaultNodeCIDRCapacityWarningThreshold,
 			},
 		},
 		SecureServing: (&apiserveroptions.SecureServingOptions{
@@ -285,6 +293,10 @@ func TestAddFlags(t *testing.T) {
 			CloudConfigSecretNamespace: "kube-system",
 			CloudConfigKey:             "cloud-config",
 		},
+		Tracing: &TracingOptions{
+			Endpoint:               "localhost:4317",
+			SamplingRatePerMillion: 1000,
+		},
 	}
 	if !reflect.DeepEqual(expected, s) {
 		t.Errorf("Got different run options than expected.\nDifference detected on:\n%s", diff.ObjectReflectDiff(expected, s))
@@ -326,6 +338,39 @@ func TestValidate(t *testing.T) {
 				return s
 			},
 		},
+		{
+			desc:     "should return an error if both the dynamic reloading and the cloud config file reloading are enabled",
+			expected: "--enable-dynamic-reloading and --enable-cloud-config-file-reloading are mutually exclusive",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.DynamicReloading.EnableDynamicReloading = true
+				s.DynamicReloading.EnableConfigFileReloading = true
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should not return an error when validating options with a tracing endpoint",
+			expected: "",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.Tracing.Endpoint = "localhost:4317"
+				s.Tracing.SamplingRatePerMillion = 100
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
+		{
+			desc:     "should return an error if the tracing sampling rate is out of range",
+			expected: "tracing.samplingRatePerMillion: Invalid value: 1000001: sampling rate per million must be less than or equal to one million",
+			generateTestCloudControllerManagerOptions: func() *CloudControllerManagerOptions {
+				s, _ := NewCloudControllerManagerOptions()
+				s.Tracing.Endpoint = "localhost:4317"
+				s.Tracing.SamplingRatePerMillion = 1000001
+				s.KubeCloudShared.CloudProvider.CloudConfigFile = "azure.json"
+				return s
+			},
+		},
 		{
 			desc:     "should return an error if the cloud config file is empty and the dynamic reloading is not enabled",
 			expected: "--cloud-config cannot be empty when --enable-dynamic-reloading is not set to true",
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+)
+
+// TracingOptions holds the configurations of the OpenTelemetry tracing
+type TracingOptions struct {
+	Endpoint               string
+	SamplingRatePerMillion int32
+}
+
+// AddFlags adds flags related to tracing for controller manager to the specified FlagSet
+func (o *TracingOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.Endpoint, "tracing-endpoint", o.Endpoint, "The OTLP gRPC collector endpoint, e.g. localhost:4317, which the spans of the Service, node and route operations and of their ARM requests are exported to. Tracing is disabled if empty.")
+	fs.Int32Var(&o.SamplingRatePerMillion, "tracing-sampling-rate-per-million", o.SamplingRatePerMillion, "The number of the traces sampled per million operations. The spans of the operations with a sampled parent are always sampled.")
+}
+
+// ApplyTo fills up tracing config with options
+func (o *TracingOptions) ApplyTo(cfg **tracingapi.TracingConfiguration) error {
+	if o == nil {
+		return nil
+	}
+
+	*cfg = o.tracingConfiguration()
+	return nil
+}
+
+// Validate checks validation of TracingOptions
+func (o *TracingOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+
+	cfg := o.tracingConfiguration()
+	if cfg == nil {
+		return nil
+	}
+	var errs []error
+	for _, err := range tracingapi.ValidateTracingConfiguration(cfg, nil, field.NewPath("tracing")) {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// tracingConfiguration returns the tracing config of the options, or nil if tracing is disabled.
+func (o *TracingOptions) tracingConfiguration() *tracingapi.TracingConfiguration {
+	if o.Endpoint == "" {
+		return nil
+	}
+	endpoint := o.Endpoint
+	samplingRatePerMillion := o.SamplingRatePerMillion
+	return &tracingapi.TracingConfiguration{
+		Endpoint:               &endpoint,
+		SamplingRatePerMillion: &samplingRatePerMillion,
+	}
+}
+
+func defaultTracingOptions() *TracingOptions {
+	return &TracingOptions{
+		Endpoint:               "",
+		SamplingRatePerMillion: 0,
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/provider"
+)
+
+// NewValidateConfigCommand creates the command validating a cloud config file, or printing its JSON Schema.
+func NewValidateConfigCommand() *cobra.Command {
+	var (
+		cloudConfigFile string
+		strict          bool
+		printSchema     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "validate-config",
+		Short: "Validate a cloud config file",
+		Long: `Validate a cloud config file against the JSON Schema of the cloud config, and check its semantics:
+the enum values, the mutually exclusive options and the multiple standard load balancer configurations.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if printSchema {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(provider.ConfigSchema())
+			}
+			if cloudConfigFile == "" {
+				return fmt.Errorf("--cloud-config is required")
+			}
+			content, err := os.ReadFile(cloudConfigFile)
+			if err != nil {
+				return err
+			}
+			return validateConfig(cmd.OutOrStdout(), cloudConfigFile, content, strict)
+		},
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&cloudConfigFile, "cloud-config", "", "The path to the cloud config file to validate.")
+	cmd.Flags().BoolVar(&strict, "strict", true, "Report the unknown fields as errors instead of warnings.")
+	cmd.Flags().BoolVar(&printSchema, "print-schema", false, "Print the JSON Schema of the cloud config instead of validating a file.")
+	return cmd
+}
+
+func validateConfig(out io.Writer, path string, content []byte, strict bool) error {
+	unknownFields, errs := provider.ValidateConfigContent(content)
+	if strict {
+		errs = append(unknownFields, errs...)
+	} else {
+		for _, unknownField := range unknownFields {
+			fmt.Fprintf(out, "warning: %s\n", unknownField.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid cloud config %s: %w", path, errs.ToAggregate())
+	}
+	fmt.Fprintf(out, "cloud config %s is valid\n", path)
+	return nil
+}
+
+// validateCloudConfigFileOrDie validates the cloud config file at startup. The unknown fields are only
+// logged, as the file may be shared with other components having their own fields.
+func validateCloudConfigFileOrDie(path string) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		klog.Fatalf("Couldn't read cloud provider configuration %s: %v", path, err)
+	}
+	unknownFields, errs := provider.ValidateConfigContent(content)
+	for _, unknownField := range unknownFields {
+		klog.Warningf("cloud config %s: %s", path, unknownField.Error())
+	}
+	if len(errs) > 0 {
+		klog.Fatalf("Invalid cloud config %s: %v", path, errs.ToAggregate())
+	}
+}
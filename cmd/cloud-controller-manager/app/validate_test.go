@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfig(t *testing.T) {
+	content := []byte(`{"vmType": "vmss", "unknownField": true}`)
+
+	out := &bytes.Buffer{}
+	err := validateConfig(out, "azure.json", content, true)
+	assert.ErrorContains(t, err, `unknownField: Invalid value: "unknownField": unknown field`)
+
+	out.Reset()
+	err = validateConfig(out, "azure.json", content, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "warning: unknownField: Invalid value: \"unknownField\": unknown field\ncloud config azure.json is valid\n", out.String())
+
+	err = validateConfig(out, "azure.json", []byte(`{"vmType": "vm"}`), false)
+	assert.ErrorContains(t, err, `vmType: Unsupported value: "vm"`)
+}
+
+func TestValidateConfigCommandPrintSchema(t *testing.T) {
+	cmd := NewValidateConfigCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--print-schema"})
+	assert.NoError(t, cmd.Execute())
+
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &schema))
+	assert.Equal(t, false, schema["additionalProperties"])
+	assert.Contains(t, schema["properties"], "vmType")
+}
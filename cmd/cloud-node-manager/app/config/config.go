@@ -78,6 +78,13 @@ type Config struct {
 	// Specifies if node information is retrieved via IMDS or ARM.
 	UseInstanceMetadata bool
 
+	// EnableARMFallback indicates whether node information should be retrieved from ARM
+	// when IMDS is slow or unhealthy. It only takes effect when UseInstanceMetadata is true.
+	EnableARMFallback bool
+
+	// IMDSRequestTimeout is the timeout of a single IMDS request before falling back to ARM.
+	IMDSRequestTimeout metav1.Duration
+
 	// WindowsService should be set to true if cloud-node-manager is running as a service on Windows.
 	// Its corresponding flag only gets registered in Windows builds
 	WindowsService bool
@@ -135,7 +135,7 @@ func startControllers(ctx context.Context, c *cloudnodeconfig.Config, stopCh <-c
 		c.SharedInformers.Core().V1().Nodes(),
 		// cloud node controller uses existing cluster role from node-controller
 		c.ClientBuilder.ClientOrDie("node-controller"),
-		nodeprovider.NewNodeProvider(ctx, c.UseInstanceMetadata, c.CloudConfigFilePath),
+		nodeprovider.NewNodeProvider(ctx, c.UseInstanceMetadata, c.EnableARMFallback, c.IMDSRequestTimeout.Duration, c.CloudConfigFilePath),
 		c.NodeStatusUpdateFrequency.Duration,
 		c.WaitForRoutes,
 		c.EnableDeprecatedBetaTopologyLabels)
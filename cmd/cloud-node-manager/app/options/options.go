@@ -56,6 +56,8 @@ const (
 	CloudControllerManagerPort = 10263
 	// defaultNodeStatusUpdateFrequencyInMinute is the default frequency at which the manager updates nodes' status.
 	defaultNodeStatusUpdateFrequencyInMinute = 5
+	// defaultIMDSRequestTimeoutInSecond is the default timeout of a single IMDS request before falling back to ARM.
+	defaultIMDSRequestTimeoutInSecond = 5
 )
 
 // CloudNodeManagerOptions is the main context object for the controller manager.
@@ -83,6 +85,12 @@ type CloudNodeManagerOptions struct {
 
 	UseInstanceMetadata bool
 
+	// EnableARMFallback indicates whether node information should be retrieved from ARM
+	// when IMDS is slow or unhealthy. It only takes effect when UseInstanceMetadata is true.
+	EnableARMFallback bool
+	// IMDSRequestTimeout is the timeout of a single IMDS request before falling back to ARM.
+	IMDSRequestTimeout metav1.Duration
+
 	// WindowsService should be set to true if cloud-node-manager is running as a service on Windows.
 	// Its corresponding flag only gets registered in Windows builds
 	WindowsService bool
@@ -102,6 +110,9 @@ func NewCloudNodeManagerOptions() (*CloudNodeManagerOptions, error) {
 		NodeStatusUpdateFrequency: metav1.Duration{
 			Duration: defaultNodeStatusUpdateFrequencyInMinute * time.Minute,
 		},
+		IMDSRequestTimeout: metav1.Duration{
+			Duration: defaultIMDSRequestTimeoutInSecond * time.Second,
+		},
 	}
 
 	s.Authentication.RemoteKubeConfigFileOptional = true
@@ -135,6 +146,8 @@ func (o *CloudNodeManagerOptions) Flags() cliflag.NamedFlagSets {
 	fs.Int32Var(&o.ClientConnection.Burst, "kube-api-burst", 30, "Burst to use while talking with kubernetes apiserver.")
 	fs.BoolVar(&o.WaitForRoutes, "wait-routes", false, "Whether the nodes should wait for routes created on Azure route table. It should be set to true when using kubenet plugin.")
 	fs.BoolVar(&o.UseInstanceMetadata, "use-instance-metadata", true, "Should use Instance Metadata Service for fetching node information; if false will use ARM instead.")
+	fs.BoolVar(&o.EnableARMFallback, "enable-arm-fallback", o.EnableARMFallback, "Whether to fall back to ARM for fetching node information when IMDS is slow or unhealthy. Only takes effect when --use-instance-metadata is true, and requires --cloud-config.")
+	fs.DurationVar(&o.IMDSRequestTimeout.Duration, "imds-request-timeout", o.IMDSRequestTimeout.Duration, "Timeout of a single IMDS request before falling back to ARM when --enable-arm-fallback is set.")
 	fs.StringVar(&o.CloudConfigFilePath, "cloud-config", o.CloudConfigFilePath, "The path to the cloud config file to be used when using ARM to fetch node information.")
 	fs.BoolVar(&o.EnableDeprecatedBetaTopologyLabels, "enable-deprecated-beta-topology-labels", o.EnableDeprecatedBetaTopologyLabels, "DEPRECATED: This flag will be removed in a future release. If true, the node will apply beta topology labels.")
 	return fss
@@ -195,7 +208,12 @@ func (o *CloudNodeManagerOptions) ApplyTo(c *cloudnodeconfig.Config, userAgent s
 	}))
 	c.NodeStatusUpdateFrequency = o.NodeStatusUpdateFrequency
 	c.UseInstanceMetadata = o.UseInstanceMetadata
+	c.EnableARMFallback = o.EnableARMFallback
+	c.IMDSRequestTimeout = o.IMDSRequestTimeout
 	c.CloudConfigFilePath = o.CloudConfigFilePath
+	if c.UseInstanceMetadata && c.EnableARMFallback && c.CloudConfigFilePath == "" {
+		return fmt.Errorf("--cloud-config must be set when --enable-arm-fallback is true")
+	}
 
 	c.WindowsService = o.WindowsService
 
@@ -1,5 +1,5 @@
 /*
-Copyright 2023 The Kubernetes Authors.
+Copyright 2024 The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,5 +14,16 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// flowcontrol is imported from https://github.com/kubernetes/client-go/tree/master/util/flowcontrol
-package flowcontrol
+package armfake_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestArmfake(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Armfake Suite")
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armfake
+
+import (
+	"fmt"
+	"strings"
+)
+
+const resourceGroupType = "Microsoft.Resources/resourceGroups"
+
+// resourcePath is a parsed ARM request path. It identifies either a resource, or a collection
+// of resources when the last type has no name.
+type resourcePath struct {
+	subscriptionID string
+	// resourceGroup is empty for the requests scoped to the subscription
+	resourceGroup string
+	// namespace is the resource provider namespace, e.g. Microsoft.Network. It is empty for
+	// resource groups.
+	namespace string
+	// types and names of the resource and its parents, names has one less element than
+	// types for collections
+	types []string
+	names []string
+}
+
+// parseResourcePath parses the path of an ARM request.
+func parseResourcePath(path string) (*resourcePath, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 2 || !strings.EqualFold(segments[0], "subscriptions") || segments[1] == "" {
+		return nil, fmt.Errorf("invalid request path %s", path)
+	}
+	p := &resourcePath{subscriptionID: segments[1]}
+	rest := segments[2:]
+	if len(rest) == 0 {
+		return nil, fmt.Errorf("invalid request path %s", path)
+	}
+
+	if strings.EqualFold(rest[0], "resourceGroups") {
+		switch len(rest) {
+		case 1:
+			// resource group collection
+			p.types = []string{"resourceGroups"}
+			return p, nil
+		case 2:
+			p.types = []string{"resourceGroups"}
+			p.names = []string{rest[1]}
+			return p, nil
+		}
+		p.resourceGroup = rest[1]
+		rest = rest[2:]
+	}
+	if len(rest) < 3 || !strings.EqualFold(rest[0], "providers") {
+		return nil, fmt.Errorf("invalid request path %s", path)
+	}
+	p.namespace = rest[1]
+	for i := 2; i < len(rest); i += 2 {
+		p.types = append(p.types, rest[i])
+		if i+1 < len(rest) {
+			p.names = append(p.names, rest[i+1])
+		}
+	}
+	return p, nil
+}
+
+// isResourceGroup returns true if the path targets resource groups.
+func (p *resourcePath) isResourceGroup() bool {
+	return p.namespace == ""
+}
+
+// isCollection returns true if the path targets a collection of resources.
+func (p *resourcePath) isCollection() bool {
+	return len(p.names) < len(p.types)
+}
+
+// resourceType returns the full type of the resource, e.g. Microsoft.Network/virtualNetworks/subnets.
+func (p *resourcePath) resourceType() string {
+	if p.isResourceGroup() {
+		return resourceGroupType
+	}
+	return p.namespace + "/" + strings.Join(p.types, "/")
+}
+
+// subscriptionScope returns the ID of the subscription.
+func (p *resourcePath) subscriptionScope() string {
+	return "/subscriptions/" + p.subscriptionID
+}
+
+// resourceGroupID returns the ID of the resource group of the path, or an empty string for
+// the requests scoped to the subscription.
+func (p *resourcePath) resourceGroupID() string {
+	if p.isResourceGroup() {
+		if len(p.names) == 0 {
+			return ""
+		}
+		return p.subscriptionScope() + "/resourceGroups/" + p.names[0]
+	}
+	if p.resourceGroup == "" {
+		return ""
+	}
+	return p.subscriptionScope() + "/resourceGroups/" + p.resourceGroup
+}
+
+// idWithNames returns the ID made of the first n names of the path.
+func (p *resourcePath) idWithNames(n int) string {
+	if p.isResourceGroup() {
+		return p.resourceGroupID()
+	}
+	var sb strings.Builder
+	if rg := p.resourceGroupID(); rg != "" {
+		sb.WriteString(rg)
+	} else {
+		sb.WriteString(p.subscriptionScope())
+	}
+	sb.WriteString("/providers/")
+	sb.WriteString(p.namespace)
+	for i := 0; i < n; i++ {
+		sb.WriteString("/")
+		sb.WriteString(p.types[i])
+		sb.WriteString("/")
+		sb.WriteString(p.names[i])
+	}
+	return sb.String()
+}
+
+// id returns the ID of the resource.
+func (p *resourcePath) id() string {
+	return p.idWithNames(len(p.names))
+}
+
+// parentID returns the ID of the parent of the resource, or of the resources in the collection.
+// It is the resource group for top level resources and the subscription for resource groups.
+func (p *resourcePath) parentID() string {
+	if p.isResourceGroup() {
+		return p.subscriptionScope()
+	}
+	if len(p.types) == 1 {
+		if rg := p.resourceGroupID(); rg != "" {
+			return rg
+		}
+		return p.subscriptionScope()
+	}
+	return p.idWithNames(len(p.types) - 1)
+}
+
+// name returns the name of the resource.
+func (p *resourcePath) name() string {
+	if len(p.names) == 0 {
+		return ""
+	}
+	return p.names[len(p.names)-1]
+}
+
+// resource is a resource stored in the server.
+type resource struct {
+	id           string
+	resourceType string
+	// parentKey is the key of the parent resource, the resource group or the subscription
+	parentKey string
+	// subscriptionKey is the key of the subscription of the resource
+	subscriptionKey string
+	etag            string
+	body            map[string]interface{}
+	// operation is the key of the long running operation in progress on the resource
+	operation string
+}
+
+// key returns the case-insensitive key of an ID.
+func key(id string) string {
+	return strings.ToLower(id)
+}
+
+// setProvisioningState sets the provisioning state in the properties of the resource.
+func (r *resource) setProvisioningState(state string) {
+	properties, ok := r.body["properties"].(map[string]interface{})
+	if !ok {
+		properties = map[string]interface{}{}
+		r.body["properties"] = properties
+	}
+	properties["provisioningState"] = state
+}
+
+// mergePatch applies the JSON merge patch to the target. Tags are replaced as a whole like
+// ARM does.
+func mergePatch(target, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		patchValue, isMap := v.(map[string]interface{})
+		targetValue, targetIsMap := target[k].(map[string]interface{})
+		if !isMap || !targetIsMap || strings.EqualFold(k, "tags") {
+			target[k] = v
+			continue
+		}
+		mergePatch(targetValue, patchValue)
+	}
+}
@@ -0,0 +1,653 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package armfake provides an in-memory emulator of Azure Resource Manager, so that flows using
+// the azclient clients can be tested end-to-end without network.
+package armfake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// operationsNamespace is the resource provider namespace of the long running operation status URLs
+	operationsNamespace = "Microsoft.ARMFake"
+
+	headerRetryAfter   = "Retry-After"
+	headerRetryAfterMS = "Retry-After-Ms"
+	headerAsyncOp      = "Azure-AsyncOperation"
+	headerLocation     = "Location"
+	headerETag         = "ETag"
+	headerIfMatch      = "If-Match"
+	headerIfNoneMatch  = "If-None-Match"
+
+	provisioningStateSucceeded = "Succeeded"
+	provisioningStateUpdating  = "Updating"
+	provisioningStateDeleting  = "Deleting"
+
+	operationStatusInProgress = "InProgress"
+	operationStatusSucceeded  = "Succeeded"
+)
+
+// synchronousOperations lists the HTTP methods ARM completes synchronously per resource type
+// or resource provider namespace, which never return a long running operation because the
+// SDK clients do not poll them.
+var synchronousOperations = map[string][]string{
+	"microsoft.resources/resourcegroups":                        {http.MethodPut, http.MethodPatch},
+	"microsoft.network":                                         {http.MethodPatch},
+	"microsoft.storage/storageaccounts":                         {http.MethodDelete, http.MethodPatch},
+	"microsoft.storage/storageaccounts/blobservices":            {http.MethodPut, http.MethodPatch},
+	"microsoft.storage/storageaccounts/blobservices/containers": {http.MethodPut, http.MethodPatch, http.MethodDelete},
+	"microsoft.storage/storageaccounts/fileservices/shares":     {http.MethodPut, http.MethodPatch, http.MethodDelete},
+	"microsoft.storage/storageaccounts/managementpolicies":      {http.MethodPut, http.MethodDelete},
+	"microsoft.keyvault/vaults":                                 {http.MethodDelete, http.MethodPatch},
+	"microsoft.keyvault/vaults/secrets":                         {http.MethodPut, http.MethodPatch},
+}
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+}
+
+// operation is a long running operation.
+type operation struct {
+	resourceKey string
+	method      string
+	polls       int
+	done        bool
+}
+
+// Server is an in-memory emulator of Azure Resource Manager. It implements resource group scoped
+// PUT, PATCH, GET, DELETE and LIST for any resource type with ETags, long running operations and
+// the 404, 409, 412 and 429 errors of ARM. Child resources are stored independently of their
+// parents, e.g. the subnets of a virtual network are not embedded in the virtual network.
+type Server struct {
+	lock       sync.Mutex
+	resources  map[string]*resource
+	operations map[string]*operation
+	requests   []Request
+
+	// lroPolls is the number of polls after which a long running operation completes
+	lroPolls int
+	// lroPollInterval is the interval the clients are told to poll the operations at
+	lroPollInterval time.Duration
+	// throttled is the number of next requests to throttle
+	throttled  int
+	retryAfter time.Duration
+}
+
+// NewServer creates an empty server which completes all the operations synchronously.
+func NewServer() *Server {
+	return &Server{
+		resources:       make(map[string]*resource),
+		operations:      make(map[string]*operation),
+		lroPollInterval: time.Millisecond,
+	}
+}
+
+// SetLongRunningOperationPolls makes the asynchronous operations complete after being polled the
+// given number of times. 0 completes them synchronously.
+func (s *Server) SetLongRunningOperationPolls(polls int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lroPolls = polls
+}
+
+// Throttle makes the server reply to the next count requests with 429 Too Many Requests and the
+// given Retry-After.
+func (s *Server) Throttle(count int, retryAfter time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.throttled = count
+	s.retryAfter = retryAfter
+}
+
+// Requests returns the requests received by the server.
+func (s *Server) Requests() []Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// ResourceIDs returns the IDs of the stored resources, including the resource groups, sorted.
+func (s *Server) ResourceIDs() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ids := make([]string, 0, len(s.resources))
+	for _, r := range s.resources {
+		ids = append(ids, r.id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// EnsureResourceGroup creates the resource group if it does not exist.
+func (s *Server) EnsureResourceGroup(subscriptionID, resourceGroupName, location string) {
+	path := &resourcePath{subscriptionID: subscriptionID, types: []string{"resourceGroups"}, names: []string{resourceGroupName}}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.resources[key(path.id())]; ok {
+		return
+	}
+	s.store(path, map[string]interface{}{"location": location}, provisioningStateSucceeded)
+}
+
+// SetResource stores the resource with the given ID, which must be a valid ARM resource ID,
+// bypassing the checks of the requests. resource is marshalled to JSON, it is typically a
+// resource of the SDK.
+func (s *Server) SetResource(id string, resource interface{}) error {
+	path, err := parseResourcePath(id)
+	if err != nil {
+		return err
+	}
+	if path.isCollection() {
+		return fmt.Errorf("%s is not a resource ID", id)
+	}
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.store(path, body, provisioningStateSucceeded)
+	return nil
+}
+
+// GetResource unmarshals the stored resource with the given ID into out. It returns false if
+// the resource does not exist.
+func (s *Server) GetResource(id string, out interface{}) (bool, error) {
+	s.lock.Lock()
+	r, ok := s.resources[key(id)]
+	var data []byte
+	var err error
+	if ok {
+		data, err = json.Marshal(r.body)
+	}
+	s.lock.Unlock()
+	if !ok || err != nil {
+		return ok, err
+	}
+	return true, json.Unmarshal(data, out)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path})
+	if s.throttled > 0 {
+		s.throttled--
+		w.Header().Set(headerRetryAfter, strconv.Itoa(int(math.Ceil(s.retryAfter.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, "SubscriptionRequestsThrottled", "Number of requests for subscription exceeded the limit.")
+		return
+	}
+
+	if op, ok := s.operations[key(r.URL.Path)]; ok {
+		s.serveOperation(w, r, op)
+		return
+	}
+	if s.serveProvider(w, r) {
+		return
+	}
+
+	path, err := parseResourcePath(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequestUri", err.Error())
+		return
+	}
+	if path.isCollection() {
+		// the actions on resources look like collections, e.g. .../storageAccounts/{name}/listKeys
+		if r.Method == http.MethodPost && len(path.types) > 1 {
+			s.post(w, path)
+			return
+		}
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("method %s is not allowed on collection %s", r.Method, r.URL.Path))
+			return
+		}
+		s.list(w, path)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s.get(w, r, path)
+	case http.MethodPut:
+		s.put(w, r, path)
+	case http.MethodPatch:
+		s.patch(w, r, path)
+	case http.MethodDelete:
+		s.delete(w, r, path)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("method %s is not allowed", r.Method))
+	}
+}
+
+// serveProvider serves the resource provider registrations, which are always registered. It
+// returns false if the request does not target them.
+func (s *Server) serveProvider(w http.ResponseWriter, r *http.Request) bool {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if r.Method != http.MethodGet || len(segments) < 3 || len(segments) > 4 || !strings.EqualFold(segments[0], "subscriptions") || !strings.EqualFold(segments[2], "providers") {
+		return false
+	}
+	provider := func(namespace string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":                "/subscriptions/" + segments[1] + "/providers/" + namespace,
+			"namespace":         namespace,
+			"registrationState": "Registered",
+		}
+	}
+	if len(segments) == 4 {
+		writeJSON(w, http.StatusOK, provider(segments[3]))
+		return true
+	}
+	namespaces := map[string]string{}
+	for _, r := range s.resources {
+		if ns, _, found := strings.Cut(r.resourceType, "/"); found {
+			namespaces[key(ns)] = ns
+		}
+	}
+	value := []interface{}{}
+	for _, k := range sortedKeys(namespaces) {
+		value = append(value, provider(namespaces[k]))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"value": value})
+	return true
+}
+
+func (s *Server) get(w http.ResponseWriter, req *http.Request, path *resourcePath) {
+	r, ok := s.resources[key(path.id())]
+	if !ok {
+		s.writeNotFound(w, path)
+		return
+	}
+	if ifNoneMatch := req.Header.Get(headerIfNoneMatch); ifNoneMatch != "" && (ifNoneMatch == "*" || ifNoneMatch == r.etag) {
+		w.Header().Set(headerETag, r.etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.writeResource(w, http.StatusOK, r)
+}
+
+func (s *Server) list(w http.ResponseWriter, path *resourcePath) {
+	parentKey := key(path.parentID())
+	if rg := path.resourceGroupID(); rg != "" {
+		if _, ok := s.resources[key(rg)]; !ok {
+			s.writeNotFound(w, path)
+			return
+		}
+	}
+	if len(path.types) > 1 {
+		if _, ok := s.resources[parentKey]; !ok {
+			s.writeNotFound(w, path)
+			return
+		}
+	}
+	// the top level resources of the subscription are listed across the resource groups
+	subscriptionWide := !path.isResourceGroup() && path.resourceGroup == "" && len(path.types) == 1
+	resourceType := key(path.resourceType())
+	keys := []string{}
+	for k, r := range s.resources {
+		if key(r.resourceType) != resourceType {
+			continue
+		}
+		if subscriptionWide && r.subscriptionKey != key(path.subscriptionScope()) {
+			continue
+		}
+		if !subscriptionWide && r.parentKey != parentKey {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	value := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		value = append(value, s.resources[k].body)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"value": value})
+}
+
+func (s *Server) put(w http.ResponseWriter, req *http.Request, path *resourcePath) {
+	body, ok := readBody(w, req)
+	if !ok {
+		return
+	}
+	existing := s.resources[key(path.id())]
+	if !s.checkParent(w, path) || !s.checkPreconditions(w, req, existing) || !s.checkOperation(w, existing) {
+		return
+	}
+	// 200 is accepted by the SDK clients of all the resource types, unlike 201 and 202
+	if s.isLongRunning(http.MethodPut, path) {
+		r := s.store(path, body, provisioningStateUpdating)
+		s.writeOperation(w, http.StatusOK, r, http.MethodPut)
+		return
+	}
+	s.writeResource(w, http.StatusOK, s.store(path, body, provisioningStateSucceeded))
+}
+
+func (s *Server) patch(w http.ResponseWriter, req *http.Request, path *resourcePath) {
+	patch, ok := readBody(w, req)
+	if !ok {
+		return
+	}
+	existing, exists := s.resources[key(path.id())]
+	if !exists {
+		s.writeNotFound(w, path)
+		return
+	}
+	if !s.checkPreconditions(w, req, existing) || !s.checkOperation(w, existing) {
+		return
+	}
+	body := existing.body
+	mergePatch(body, patch)
+	if s.isLongRunning(http.MethodPatch, path) {
+		r := s.store(path, body, provisioningStateUpdating)
+		s.writeOperation(w, http.StatusOK, r, http.MethodPatch)
+		return
+	}
+	s.writeResource(w, http.StatusOK, s.store(path, body, provisioningStateSucceeded))
+}
+
+func (s *Server) delete(w http.ResponseWriter, req *http.Request, path *resourcePath) {
+	existing, exists := s.resources[key(path.id())]
+	if !s.checkPreconditions(w, req, existing) {
+		return
+	}
+	if !exists {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if !s.checkOperation(w, existing) {
+		return
+	}
+	if s.isLongRunning(http.MethodDelete, path) {
+		existing.setProvisioningState(provisioningStateDeleting)
+		s.writeOperation(w, http.StatusAccepted, existing, http.MethodDelete)
+		return
+	}
+	s.remove(existing)
+	w.WriteHeader(http.StatusOK)
+}
+
+// post serves the resource actions. The storage account keys are listed and the other actions
+// are accepted without changing anything.
+func (s *Server) post(w http.ResponseWriter, path *resourcePath) {
+	actionPath := *path
+	actionPath.types = path.types[:len(path.types)-1]
+	r, ok := s.resources[key(actionPath.id())]
+	if !ok {
+		s.writeNotFound(w, &actionPath)
+		return
+	}
+	action := path.types[len(path.types)-1]
+	if strings.EqualFold(action, "listKeys") && strings.EqualFold(r.resourceType, "Microsoft.Storage/storageAccounts") {
+		keys := []interface{}{}
+		for _, name := range []string{"key1", "key2"} {
+			keys = append(keys, map[string]interface{}{
+				"keyName":     name,
+				"value":       base64.StdEncoding.EncodeToString([]byte(r.id + "/" + name)),
+				"permissions": "FULL",
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"keys": keys})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// checkParent writes 404 and returns false if the resource group or the parent resource does not exist.
+func (s *Server) checkParent(w http.ResponseWriter, path *resourcePath) bool {
+	if path.isResourceGroup() {
+		return true
+	}
+	if rg := path.resourceGroupID(); rg != "" {
+		if _, ok := s.resources[key(rg)]; !ok {
+			writeError(w, http.StatusNotFound, "ResourceGroupNotFound", fmt.Sprintf("Resource group '%s' could not be found.", path.resourceGroup))
+			return false
+		}
+	}
+	if len(path.types) > 1 {
+		if _, ok := s.resources[key(path.parentID())]; !ok {
+			writeError(w, http.StatusNotFound, "ParentResourceNotFound", fmt.Sprintf("Can not perform requested operation on nested resource. Parent resource '%s' not found.", path.parentID()))
+			return false
+		}
+	}
+	return true
+}
+
+// checkPreconditions writes 412 and returns false if the If-Match or If-None-Match headers do not match.
+func (s *Server) checkPreconditions(w http.ResponseWriter, req *http.Request, existing *resource) bool {
+	if ifMatch := req.Header.Get(headerIfMatch); ifMatch != "" {
+		if existing == nil || (ifMatch != "*" && ifMatch != existing.etag) {
+			writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", fmt.Sprintf("The condition specified using HTTP conditional header(s) is not met, If-Match: %s.", ifMatch))
+			return false
+		}
+	}
+	if ifNoneMatch := req.Header.Get(headerIfNoneMatch); ifNoneMatch != "" && existing != nil {
+		if ifNoneMatch == "*" || ifNoneMatch == existing.etag {
+			writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", fmt.Sprintf("The condition specified using HTTP conditional header(s) is not met, If-None-Match: %s.", ifNoneMatch))
+			return false
+		}
+	}
+	return true
+}
+
+// checkOperation writes 409 and returns false if a long running operation is in progress on the resource.
+func (s *Server) checkOperation(w http.ResponseWriter, existing *resource) bool {
+	if existing == nil || existing.operation == "" {
+		return true
+	}
+	writeError(w, http.StatusConflict, "AnotherOperationInProgress", fmt.Sprintf("Another operation on resource %s is in progress.", existing.id))
+	return false
+}
+
+// isLongRunning returns true if the operation on the resource must complete asynchronously.
+func (s *Server) isLongRunning(method string, path *resourcePath) bool {
+	if s.lroPolls <= 0 {
+		return false
+	}
+	resourceType := key(path.resourceType())
+	for _, k := range []string{resourceType, key(path.namespace)} {
+		for _, m := range synchronousOperations[k] {
+			if m == method {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// store creates or replaces the resource with the body.
+func (s *Server) store(path *resourcePath, body map[string]interface{}, provisioningState string) *resource {
+	id := path.id()
+	if existing, ok := s.resources[key(id)]; ok {
+		// keep the casing of the ID the resource was created with
+		id = existing.id
+	}
+	r := &resource{
+		id:              id,
+		resourceType:    path.resourceType(),
+		parentKey:       key(path.parentID()),
+		subscriptionKey: key(path.subscriptionScope()),
+		etag:            fmt.Sprintf("W/\"%s\"", uuid.NewString()),
+		body:            body,
+	}
+	r.body["id"] = r.id
+	r.body["name"] = path.name()
+	r.body["type"] = r.resourceType
+	r.body["etag"] = r.etag
+	r.setProvisioningState(provisioningState)
+	s.resources[key(id)] = r
+	return r
+}
+
+// remove deletes the resource and its children.
+func (s *Server) remove(r *resource) {
+	prefix := key(r.id) + "/"
+	for k := range s.resources {
+		if k == key(r.id) || strings.HasPrefix(k, prefix) {
+			delete(s.resources, k)
+		}
+	}
+}
+
+// writeOperation starts a long running operation on the resource and writes the response
+// pointing at its status.
+func (s *Server) writeOperation(w http.ResponseWriter, status int, r *resource, method string) {
+	opPath := fmt.Sprintf("/subscriptions/%s/providers/%s/operations/%s", strings.TrimPrefix(r.subscriptionKey, "/subscriptions/"), operationsNamespace, uuid.NewString())
+	s.operations[key(opPath)] = &operation{resourceKey: key(r.id), method: method}
+	r.operation = key(opPath)
+
+	opURL := "https://management.azure.com" + opPath
+	w.Header().Set(headerAsyncOp, opURL)
+	w.Header().Set(headerRetryAfterMS, strconv.FormatInt(s.lroPollInterval.Milliseconds(), 10))
+	if method == http.MethodDelete {
+		w.Header().Set(headerLocation, opURL)
+		w.WriteHeader(status)
+		return
+	}
+	s.writeResource(w, status, r)
+}
+
+// serveOperation serves the status of a long running operation, which completes after lroPolls polls.
+func (s *Server) serveOperation(w http.ResponseWriter, req *http.Request, op *operation) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("method %s is not allowed on operations", req.Method))
+		return
+	}
+	if !op.done {
+		op.polls++
+		if op.polls >= s.lroPolls {
+			s.completeOperation(op)
+		}
+	}
+	status := operationStatusInProgress
+	if op.done {
+		status = operationStatusSucceeded
+	} else {
+		w.Header().Set(headerRetryAfterMS, strconv.FormatInt(s.lroPollInterval.Milliseconds(), 10))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":   req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:],
+		"status": status,
+	})
+}
+
+func (s *Server) completeOperation(op *operation) {
+	op.done = true
+	r, ok := s.resources[op.resourceKey]
+	if !ok {
+		return
+	}
+	r.operation = ""
+	if op.method == http.MethodDelete {
+		s.remove(r)
+		return
+	}
+	r.setProvisioningState(provisioningStateSucceeded)
+}
+
+func (s *Server) writeNotFound(w http.ResponseWriter, path *resourcePath) {
+	if rg := path.resourceGroupID(); rg != "" && !path.isResourceGroup() {
+		if _, ok := s.resources[key(rg)]; !ok {
+			writeError(w, http.StatusNotFound, "ResourceGroupNotFound", fmt.Sprintf("Resource group '%s' could not be found.", path.resourceGroup))
+			return
+		}
+	}
+	if path.isResourceGroup() {
+		writeError(w, http.StatusNotFound, "ResourceGroupNotFound", fmt.Sprintf("Resource group '%s' could not be found.", path.name()))
+		return
+	}
+	writeError(w, http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("The Resource '%s/%s' under resource group '%s' was not found.", path.resourceType(), path.name(), path.resourceGroup))
+}
+
+func (s *Server) writeResource(w http.ResponseWriter, status int, r *resource) {
+	w.Header().Set(headerETag, r.etag)
+	writeJSON(w, status, r.body)
+}
+
+// readBody reads the JSON object in the request body. It writes 400 and returns false if the
+// body is not a JSON object.
+func readBody(w http.ResponseWriter, req *http.Request) (map[string]interface{}, bool) {
+	body := map[string]interface{}{}
+	if req.Body == nil {
+		return body, true
+	}
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+		return nil, false
+	}
+	if len(data) == 0 {
+		return body, true
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequestContent", fmt.Sprintf("The request content was invalid and could not be deserialized: %v", err))
+		return nil, false
+	}
+	return body, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalServerError", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+	})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Ms-Failure-Cause", "gateway")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armfake_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	resources "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/armfake"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/etag"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
+)
+
+const (
+	subscriptionID    = "00000000-0000-0000-0000-000000000000"
+	resourceGroupName = "rg"
+	location          = "eastus"
+)
+
+func statusCode(err error) int {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode
+	}
+	return 0
+}
+
+var _ = Describe("Server", func() {
+	var (
+		server  *armfake.Server
+		factory azclient.ClientFactory
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		server = armfake.NewServer()
+		factory, err = armfake.NewClientFactory(server, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("resource groups", func() {
+		It("should create, get, list and delete resource groups", func() {
+			client := factory.GetResourceGroupClient()
+			rg, err := client.CreateOrUpdate(ctx, resourceGroupName, resources.ResourceGroup{Location: to.Ptr(location)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*rg.ID).To(Equal("/subscriptions/" + subscriptionID + "/resourceGroups/" + resourceGroupName))
+			Expect(*rg.Properties.ProvisioningState).To(Equal("Succeeded"))
+
+			rg, err = client.Get(ctx, resourceGroupName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*rg.Location).To(Equal(location))
+
+			rgs, err := client.List(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rgs).To(HaveLen(1))
+
+			Expect(client.Delete(ctx, resourceGroupName)).To(Succeed())
+			_, err = client.Get(ctx, resourceGroupName)
+			Expect(statusCode(err)).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("resources", func() {
+		BeforeEach(func() {
+			server.EnsureResourceGroup(subscriptionID, resourceGroupName, location)
+		})
+
+		It("should create, update, get, list and delete resources", func() {
+			client := factory.GetRouteTableClient()
+			rt, err := client.CreateOrUpdate(ctx, resourceGroupName, "rt", armnetwork.RouteTable{Location: to.Ptr(location)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*rt.Name).To(Equal("rt"))
+			Expect(*rt.Type).To(Equal("Microsoft.Network/routeTables"))
+			Expect(rt.Etag).NotTo(BeNil())
+
+			rt, err = client.UpdateTags(ctx, resourceGroupName, "rt", map[string]*string{"updated": to.Ptr("true")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*rt.Tags["updated"]).To(Equal("true"))
+			Expect(*rt.Location).To(Equal(location))
+
+			rt, err = client.Get(ctx, resourceGroupName, "rt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*rt.Tags["updated"]).To(Equal("true"))
+
+			rts, err := client.List(ctx, resourceGroupName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rts).To(HaveLen(1))
+
+			Expect(client.Delete(ctx, resourceGroupName, "rt")).To(Succeed())
+			_, err = client.Get(ctx, resourceGroupName, "rt")
+			Expect(statusCode(err)).To(Equal(http.StatusNotFound))
+		})
+
+		It("should store child resources and delete them with their parent", func() {
+			vnet := "/subscriptions/" + subscriptionID + "/resourceGroups/" + resourceGroupName + "/providers/Microsoft.Network/virtualNetworks/vnet"
+			_, err := factory.GetSubnetClient().CreateOrUpdate(ctx, resourceGroupName, "vnet", "subnet", armnetwork.Subnet{})
+			Expect(statusCode(err)).To(Equal(http.StatusNotFound))
+
+			_, err = factory.GetVirtualNetworkClient().CreateOrUpdate(ctx, resourceGroupName, "vnet", armnetwork.VirtualNetwork{Location: to.Ptr(location)})
+			Expect(err).NotTo(HaveOccurred())
+			subnet, err := factory.GetSubnetClient().CreateOrUpdate(ctx, resourceGroupName, "vnet", "subnet", armnetwork.Subnet{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*subnet.ID).To(Equal(vnet + "/subnets/subnet"))
+
+			Expect(factory.GetVirtualNetworkClient().Delete(ctx, resourceGroupName, "vnet")).To(Succeed())
+			Expect(server.ResourceIDs()).To(Equal([]string{"/subscriptions/" + subscriptionID + "/resourceGroups/" + resourceGroupName}))
+		})
+
+		It("should return 404 for missing resource groups and resources", func() {
+			_, err := factory.GetRouteTableClient().Get(ctx, resourceGroupName, "missing")
+			Expect(statusCode(err)).To(Equal(http.StatusNotFound))
+			Expect(err.Error()).To(ContainSubstring("ResourceNotFound"))
+
+			_, err = factory.GetRouteTableClient().CreateOrUpdate(ctx, "missing", "rt", armnetwork.RouteTable{Location: to.Ptr(location)})
+			Expect(statusCode(err)).To(Equal(http.StatusNotFound))
+			Expect(err.Error()).To(ContainSubstring("ResourceGroupNotFound"))
+		})
+
+		It("should reject updates with a stale etag", func() {
+			factory, err := armfake.NewClientFactory(server, nil, func(option *arm.ClientOptions) {
+				option.PerCallPolicies = append(option.PerCallPolicies, utils.FuncPolicyWrapper(etag.AppendEtag))
+			})
+			Expect(err).NotTo(HaveOccurred())
+			client := factory.GetRouteTableClient()
+			rt, err := client.CreateOrUpdate(ctx, resourceGroupName, "rt", armnetwork.RouteTable{Location: to.Ptr(location)})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.CreateOrUpdate(ctx, resourceGroupName, "rt", *rt)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.CreateOrUpdate(ctx, resourceGroupName, "rt", *rt)
+			Expect(statusCode(err)).To(Equal(http.StatusPreconditionFailed))
+		})
+
+		It("should list storage account keys", func() {
+			client := factory.GetAccountClient()
+			_, err := client.Create(ctx, resourceGroupName, "account", &armstorage.AccountCreateParameters{Location: to.Ptr(location)})
+			Expect(err).NotTo(HaveOccurred())
+			keys, err := client.ListKeys(ctx, resourceGroupName, "account")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(HaveLen(2))
+		})
+
+		It("should set, get and delete the management policy of storage accounts", func() {
+			_, err := factory.GetAccountClient().Create(ctx, resourceGroupName, "account", &armstorage.AccountCreateParameters{Location: to.Ptr(location)})
+			Expect(err).NotTo(HaveOccurred())
+			client := factory.GetManagementPolicyClient()
+			_, err = client.Get(ctx, resourceGroupName, "account")
+			Expect(statusCode(err)).To(Equal(http.StatusNotFound))
+
+			policy, err := client.CreateOrUpdate(ctx, resourceGroupName, "account", armstorage.ManagementPolicy{
+				Properties: &armstorage.ManagementPolicyProperties{
+					Policy: &armstorage.ManagementPolicySchema{
+						Rules: []*armstorage.ManagementPolicyRule{{
+							Name:    to.Ptr("rule"),
+							Enabled: to.Ptr(true),
+							Type:    to.Ptr(armstorage.RuleTypeLifecycle),
+						}},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*policy.Name).To(Equal("default"))
+
+			policy, err = client.Get(ctx, resourceGroupName, "account")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*policy.Properties.Policy.Rules[0].Name).To(Equal("rule"))
+
+			Expect(client.Delete(ctx, resourceGroupName, "account")).To(Succeed())
+			_, err = client.Get(ctx, resourceGroupName, "account")
+			Expect(statusCode(err)).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("long running operations", func() {
+		BeforeEach(func() {
+			server.EnsureResourceGroup(subscriptionID, resourceGroupName, location)
+			server.SetLongRunningOperationPolls(3)
+		})
+
+		It("should complete the operations after polling", func() {
+			client := factory.GetRouteTableClient()
+			rt, err := client.CreateOrUpdate(ctx, resourceGroupName, "rt", armnetwork.RouteTable{Location: to.Ptr(location)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*rt.Properties.ProvisioningState).To(Equal(armnetwork.ProvisioningStateSucceeded))
+
+			Expect(client.Delete(ctx, resourceGroupName, "rt")).To(Succeed())
+			_, err = client.Get(ctx, resourceGroupName, "rt")
+			Expect(statusCode(err)).To(Equal(http.StatusNotFound))
+
+			polls := 0
+			for _, req := range server.Requests() {
+				if strings.Contains(req.Path, "/operations/") {
+					polls++
+				}
+			}
+			Expect(polls).To(Equal(6))
+		})
+
+		It("should reject concurrent operations on a resource", func() {
+			client := factory.GetRouteTableClient()
+			poller, err := client.BeginCreateOrUpdate(ctx, resourceGroupName, "rt", armnetwork.RouteTable{Location: to.Ptr(location)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(poller.Done()).To(BeFalse())
+
+			_, err = client.UpdateTags(ctx, resourceGroupName, "rt", map[string]*string{"updated": to.Ptr("true")})
+			Expect(statusCode(err)).To(Equal(http.StatusConflict))
+
+			_, err = poller.WaitforPollerResp(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.UpdateTags(ctx, resourceGroupName, "rt", map[string]*string{"updated": to.Ptr("true")})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("throttling", func() {
+		It("should reply 429 and make the clients back off", func() {
+			server.Throttle(1, time.Minute)
+			_, err := factory.GetResourceGroupClient().Get(ctx, resourceGroupName)
+			Expect(err).To(MatchError(ContainSubstring("Too many requests")))
+
+			// the client does not send requests until Retry-After elapses
+			_, err = factory.GetResourceGroupClient().Get(ctx, resourceGroupName)
+			Expect(err).To(MatchError(ContainSubstring("Too many requests")))
+			Expect(server.Requests()).To(HaveLen(1))
+		})
+
+		It("should set Retry-After in seconds", func() {
+			server.Throttle(1, 1500*time.Millisecond)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/subscriptions/"+subscriptionID+"/resourceGroups/"+resourceGroupName, nil))
+			Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(recorder.Header().Get("Retry-After")).To(Equal("2"))
+		})
+	})
+
+	Describe("providers", func() {
+		It("should report the providers as registered", func() {
+			provider, err := factory.GetProviderClient().GetProvider(ctx, "Microsoft.Network")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*provider.RegistrationState).To(Equal("Registered"))
+		})
+	})
+})
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armfake
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+)
+
+// transport serves the requests with a handler in memory.
+type transport struct {
+	handler http.Handler
+}
+
+// NewTransport returns a transporter which serves the requests with the handler without network.
+func NewTransport(handler http.Handler) policy.Transporter {
+	return &transport{handler: handler}
+}
+
+// Do implements policy.Transporter.
+func (t *transport) Do(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// TokenCredential is a credential returning a static token, which the server does not check.
+type TokenCredential struct{}
+
+// GetToken implements azcore.TokenCredential.
+func (TokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "armfake", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// NewClientFactory creates a client factory whose clients send their requests to the handler,
+// typically a Server. config may be nil.
+func NewClientFactory(handler http.Handler, config *azclient.ClientFactoryConfig, clientOptionsMutFn ...func(option *arm.ClientOptions)) (azclient.ClientFactory, error) {
+	if config == nil {
+		config = &azclient.ClientFactoryConfig{SubscriptionID: "00000000-0000-0000-0000-000000000000"}
+	}
+	fakeTransport := NewTransport(handler)
+	mutFns := append([]func(option *arm.ClientOptions){
+		func(option *arm.ClientOptions) {
+			option.Transport = fakeTransport
+			option.Retry.RetryDelay = time.Millisecond
+			option.Retry.MaxRetryDelay = 10 * time.Millisecond
+		},
+	}, clientOptionsMutFn...)
+	return azclient.NewClientFactory(config, nil, TokenCredential{}, mutFns...)
+}
@@ -39,6 +39,10 @@ type TypeScaffoldOptions struct {
 	Expand          bool
 	RateLimitKey    string
 	CrossSubFactory bool
+	// PatchType is the type of the patch parameters, <Resource>Update by default
+	PatchType string
+	// PatchLongRunning is true if the patch operation is a long running operation
+	PatchLongRunning bool
 }
 
 var (
@@ -71,11 +75,14 @@ import (
 )
 `
 	TypeResourceTemplate = `
-// +azure:client:verbs={{join .Verbs ";"}},resource={{.Resource}},packageName={{.Package}},packageAlias={{tolower .PackageAlias}},clientName={{.ClientName}},expand={{.Expand}}{{with .RateLimitKey}},rateLimitKey={{.}}{{end}}
+// +azure:client:verbs={{join .Verbs ";"}},resource={{.Resource}},packageName={{.Package}},packageAlias={{tolower .PackageAlias}},clientName={{.ClientName}},expand={{.Expand}}{{with .RateLimitKey}},rateLimitKey={{.}}{{end}}{{with .PatchType}},patchType={{.}}{{end}}{{if .PatchLongRunning}},patchLongRunning=true{{end}}
 type Interface interface {
 {{ $expandable := .Expand}}
 {{ $packageAlias := .PackageAlias}}
 {{ $resource := .Resource}}
+{{ $clientName := .ClientName}}
+{{ $patchType := printf "%sUpdate" $resource}}
+{{ with .PatchType}}{{ $patchType = .}}{{ end }}
 {{range $index,$element := .Verbs}}
 {{if strequal $element "Get"}}
 {{ if $expandable }}utils.GetWithExpandFunc[{{tolower $packageAlias}}.{{$resource}}]{{ else }}utils.GetFunc[{{tolower $packageAlias}}.{{$resource}}]{{- end -}}
@@ -83,15 +90,23 @@ type Interface interface {
 {{if or (strequal $element "ListByRG") (strequal $element "List") }}utils.ListFunc[{{tolower $packageAlias}}.{{$resource}}]{{- end -}}
 {{if strequal $element "CreateOrUpdate"}}utils.CreateOrUpdateFunc[{{tolower $packageAlias}}.{{$resource}}]{{- end -}}
 {{if strequal $element "Delete"}}utils.DeleteFunc[{{tolower $packageAlias}}.{{$resource}}]{{- end -}}
+{{if strequal $element "Patch"}}utils.PatchFunc[{{tolower $packageAlias}}.{{$resource}},{{tolower $packageAlias}}.{{$patchType}}]{{- end -}}
+{{if strequal $element "UpdateTags"}}utils.UpdateTagsFunc[{{tolower $packageAlias}}.{{$resource}}]{{- end -}}
+{{if strequal $element "BeginCreateOrUpdate"}}utils.BeginCreateOrUpdateFunc[{{tolower $packageAlias}}.{{$resource}},{{tolower $packageAlias}}.{{$clientName}}CreateOrUpdateResponse]{{- end -}}
+{{if strequal $element "BeginDelete"}}utils.BeginDeleteFunc[{{tolower $packageAlias}}.{{$clientName}}DeleteResponse]{{- end -}}
+{{if strequal $element "ListByFilter"}}utils.ListByFilterFunc[{{tolower $packageAlias}}.{{$resource}},{{tolower $packageAlias}}.{{$clientName}}ListOptions]{{- end -}}
 {{- end -}}
 }
 `
 	TypeSubResourceTemplate = `
-// +azure:client:verbs={{join .Verbs ";"}},resource={{.Resource}},subResource={{.SubResource}},packageName={{.Package}},packageAlias={{tolower .PackageAlias}},clientName={{.ClientName}},expand={{.Expand}}{{with .RateLimitKey}},rateLimitKey={{.}}{{end}}
+// +azure:client:verbs={{join .Verbs ";"}},resource={{.Resource}},subResource={{.SubResource}},packageName={{.Package}},packageAlias={{tolower .PackageAlias}},clientName={{.ClientName}},expand={{.Expand}}{{with .RateLimitKey}},rateLimitKey={{.}}{{end}}{{with .PatchType}},patchType={{.}}{{end}}{{if .PatchLongRunning}},patchLongRunning=true{{end}}
 type Interface interface {
 {{ $expandable := .Expand}}
 {{ $packageAlias := .PackageAlias}}
 {{ $resource := .SubResource}}
+{{ $clientName := .ClientName}}
+{{ $patchType := printf "%sUpdate" $resource}}
+{{ with .PatchType}}{{ $patchType = .}}{{ end }}
 {{range $index,$element := .Verbs}}
 {{if strequal $element "Get"}}
 {{ if $expandable }}utils.SubResourceGetWithExpandFunc[{{tolower $packageAlias}}.{{$resource}}]{{ else }}utils.SubResourceGetFunc[{{tolower $packageAlias}}.{{$resource}}]{{- end -}}
@@ -101,6 +116,10 @@ type Interface interface {
 {{if strequal $element "CreateOrUpdate"}}utils.SubResourceCreateOrUpdateFunc[{{tolower $packageAlias}}.{{$resource}}]
 {{- end -}}
 {{if strequal $element "Delete"}}utils.SubResourceDeleteFunc[{{tolower $packageAlias}}.{{$resource}}]{{- end -}}
+{{if strequal $element "Patch"}}utils.SubResourcePatchFunc[{{tolower $packageAlias}}.{{$resource}},{{tolower $packageAlias}}.{{$patchType}}]{{- end -}}
+{{if strequal $element "BeginCreateOrUpdate"}}utils.SubResourceBeginCreateOrUpdateFunc[{{tolower $packageAlias}}.{{$resource}},{{tolower $packageAlias}}.{{$clientName}}CreateOrUpdateResponse]{{- end -}}
+{{if strequal $element "BeginDelete"}}utils.SubResourceBeginDeleteFunc[{{tolower $packageAlias}}.{{$clientName}}DeleteResponse]{{- end -}}
+{{if strequal $element "ListByFilter"}}utils.SubResourceListByFilterFunc[{{tolower $packageAlias}}.{{$resource}},{{tolower $packageAlias}}.{{$clientName}}ListOptions]{{- end -}}
 {{- end -}}
 }
 `
@@ -192,6 +211,8 @@ func main() {
 	rootCmd.Flags().BoolVar(&scaffoldOptions.Expand, "expand", false, "get support expand params")
 	rootCmd.Flags().StringVar(&scaffoldOptions.SubResource, "subresource", "", "subresource name")
 	rootCmd.Flags().StringVar(&scaffoldOptions.RateLimitKey, "ratelimitkey", "", "ratelimit config key")
+	rootCmd.Flags().StringVar(&scaffoldOptions.PatchType, "patch-type", "", "patch parameters type, <resource>Update by default")
+	rootCmd.Flags().BoolVar(&scaffoldOptions.PatchLongRunning, "patch-long-running", false, "patch is a long running operation")
 	rootCmd.Flags().BoolVar(&scaffoldOptions.CrossSubFactory, "cross-sub-factory-support", false, "cross sub factory support")
 	err := rootCmd.Execute()
 	if err != nil {
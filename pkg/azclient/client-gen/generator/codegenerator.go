@@ -34,6 +34,12 @@ const (
 	FuncDelete         = "Delete"
 	FuncListByRG       = "ListByRG"
 	FuncList           = "List"
+
+	FuncPatch               = "Patch"
+	FuncUpdateTags          = "UpdateTags"
+	FuncBeginCreateOrUpdate = "BeginCreateOrUpdate"
+	FuncBeginDelete         = "BeginDelete"
+	FuncListByFilter        = "ListByFilter"
 )
 
 // clientGenMarker s a marker for generating client code for azure services.
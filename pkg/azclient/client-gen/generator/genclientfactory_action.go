@@ -104,6 +104,8 @@ func (generator *ClientFactoryGenerator) Generate(_ *genall.GenerationContext) e
 
 		importList["github.com/Azure/azure-sdk-for-go/sdk/azcore"] = make(map[string]struct{})
 		importList["github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"] = make(map[string]struct{})
+		importList["sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/etagcache"] = make(map[string]struct{})
+		importList["sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/faultinjection"] = make(map[string]struct{})
 		importList["sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"] = make(map[string]struct{})
 		importList["github.com/Azure/azure-sdk-for-go/sdk/azidentity"] = make(map[string]struct{})
 
@@ -168,6 +170,9 @@ type ClientFactoryImpl struct {
 	facotryConfig *ClientFactoryConfig
 	cred               azcore.TokenCredential
 	clientOptionsMutFn []func(option *arm.ClientOptions)
+	rateLimitBuckets   *ratelimit.Buckets
+	responseCache      *etagcache.Cache
+	faultInjectionPolicy *faultinjection.Policy
 	{{range $key, $client := . -}}
 	{{ if $client.CrossSubFactory -}}
 	{{ $key }} sync.Map
@@ -192,6 +197,17 @@ func NewClientFactory(config *ClientFactoryConfig, armConfig *ARMClientConfig, c
 		facotryConfig: config,
 		cred:          cred,
 		clientOptionsMutFn: clientOptionsMutFn,
+		rateLimitBuckets:   ratelimit.NewBuckets(),
+	}
+	if config.EnableResponseCache {
+		factory.responseCache = etagcache.NewCache(config.ResponseCacheMaxEntries, config.ResponseCacheMaxBytes)
+	}
+	if config.FaultInjectionConfigFile != "" {
+		// the policy is shared by the clients of the factory so that they inject the same faults
+		factory.faultInjectionPolicy, err = faultinjection.NewPolicyFromFile(config.FaultInjectionConfigFile)
+		if err != nil {
+			return nil, err
+		}
 	}
 	{{range $key, $client := . }}
 	{{- $resource := .Resource}}
@@ -222,10 +238,17 @@ func (factory *ClientFactoryImpl) create{{$resource}}Client(subscription string)
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 	{{with $client.RateLimitKey}}
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("{{.}}")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
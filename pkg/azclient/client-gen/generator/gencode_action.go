@@ -74,6 +74,36 @@ func generateClient(ctx *genall.GenerationContext, root *loader.Package, _ strin
 				root.AddError(err)
 				return err
 			}
+		case strings.EqualFold(FuncPatch, verb):
+			if err := PatchFuncTemplate.Execute(&outContent, markerConf); err != nil {
+				root.AddError(err)
+				return err
+			}
+		case strings.EqualFold(FuncUpdateTags, verb):
+			if markerConf.SubResource != "" {
+				err := fmt.Errorf("verb %s is not supported on sub resource %s", verb, markerConf.SubResource)
+				root.AddError(err)
+				return err
+			}
+			if err := UpdateTagsFuncTemplate.Execute(&outContent, markerConf); err != nil {
+				root.AddError(err)
+				return err
+			}
+		case strings.EqualFold(FuncBeginCreateOrUpdate, verb):
+			if err := BeginCreateOrUpdateFuncTemplate.Execute(&outContent, markerConf); err != nil {
+				root.AddError(err)
+				return err
+			}
+		case strings.EqualFold(FuncBeginDelete, verb):
+			if err := BeginDeleteFuncTemplate.Execute(&outContent, markerConf); err != nil {
+				root.AddError(err)
+				return err
+			}
+		case strings.EqualFold(FuncListByFilter, verb):
+			if err := ListByFilterFuncTemplate.Execute(&outContent, markerConf); err != nil {
+				root.AddError(err)
+				return err
+			}
 		}
 	}
 
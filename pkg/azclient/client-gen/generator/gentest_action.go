@@ -90,6 +90,14 @@ func generateTestCase(ctx *genall.GenerationContext, root *loader.Package, _ str
 			importList[markerConf.PackageName] = aliasMap
 			importList["strings"] = make(map[string]struct{})
 		}
+		if strings.EqualFold(FuncBeginCreateOrUpdate, verb) || strings.EqualFold(FuncPatch, verb) {
+			aliasMap := make(map[string]struct{})
+			aliasMap[markerConf.PackageAlias] = struct{}{}
+			importList[markerConf.PackageName] = aliasMap
+		}
+		if strings.EqualFold(FuncUpdateTags, verb) {
+			importList["github.com/Azure/azure-sdk-for-go/sdk/azcore/to"] = make(map[string]struct{})
+		}
 	}
 	if len(markerConf.Verbs) > 0 {
 		importList["github.com/onsi/gomega"] = map[string]struct{}{".": {}}
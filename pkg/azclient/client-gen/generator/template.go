@@ -28,6 +28,10 @@ type ClientGenConfig struct {
 	Expand          bool   `marker:"expand,optional"`
 	RateLimitKey    string `marker:"rateLimitKey,optional"`
 	CrossSubFactory bool   `marker:"crossSubFactory,optional"`
+	// PatchType is the type of the parameters of the patch verb, <Resource>Update by default.
+	PatchType string `marker:"patchType,optional"`
+	// PatchLongRunning is true if the SDK client updates the resource with BeginUpdate instead of Update.
+	PatchLongRunning bool `marker:"patchLongRunning,optional"`
 }
 
 var ClientTemplate = template.Must(template.New("object-scaffolding-client-struct").Parse(`
@@ -148,7 +152,7 @@ func (client *Client) Delete(ctx context.Context, resourceGroupName string, {{wi
 	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
 	ctx, endSpan := runtime.StartSpan(ctx, DeleteOperationName, client.tracer, nil)
 	defer endSpan(err)
-	_, err = utils.NewPollerWrapper(client.BeginDelete(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} resourceName, nil)).WaitforPollerResp(ctx)
+	_, err = utils.NewPollerWrapper(client.{{.ClientName}}.BeginDelete(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} resourceName, nil)).WaitforPollerResp(ctx)
 	return err
 }
 `))
@@ -180,6 +184,129 @@ func (client *Client) Get(ctx context.Context, resourceGroupName string, {{with
 }
 `))
 
+var PatchFuncTemplate = template.Must(template.New("object-scaffolding-patch-func").Parse(`
+{{- $resource := .Resource}}
+{{- if (gt (len .SubResource) 0) }}
+{{- $resource = .SubResource}}
+{{- end }}
+{{- $patchType := printf "%sUpdate" $resource}}
+{{- with .PatchType}}{{$patchType = .}}{{end}}
+const PatchOperationName = "{{.ClientName}}.Patch"
+// Patch updates the given properties of a {{$resource}}.
+func (client *Client) Patch(ctx context.Context, resourceGroupName string, {{with .SubResource}}parentResourceName string, {{end}}resourceName string, parameters {{.PackageAlias}}.{{$patchType}}) (result *{{.PackageAlias}}.{{$resource}}, err error) {
+	ctx = utils.ContextWithClientName(ctx, "{{.ClientName}}")
+	ctx = utils.ContextWithRequestMethod(ctx, "Patch")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, PatchOperationName, client.tracer, nil)
+	defer endSpan(err)
+{{- if .PatchLongRunning}}
+	resp, err := utils.NewPollerWrapper(client.{{.ClientName}}.BeginUpdate(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} resourceName, parameters, nil)).WaitforPollerResp(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		return &resp.{{$resource}}, nil
+	}
+	return nil, nil
+{{- else}}
+	resp, err := client.{{.ClientName}}.Update(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} resourceName, parameters, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.{{$resource}}, nil
+{{- end}}
+}
+`))
+
+var UpdateTagsFuncTemplate = template.Must(template.New("object-scaffolding-updatetags-func").Parse(`
+const UpdateTagsOperationName = "{{.ClientName}}.UpdateTags"
+// UpdateTags replaces the tags of a {{.Resource}} without sending the whole resource.
+func (client *Client) UpdateTags(ctx context.Context, resourceGroupName string, resourceName string, tags map[string]*string) (result *{{.PackageAlias}}.{{.Resource}}, err error) {
+	ctx = utils.ContextWithClientName(ctx, "{{.ClientName}}")
+	ctx = utils.ContextWithRequestMethod(ctx, "UpdateTags")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, UpdateTagsOperationName, client.tracer, nil)
+	defer endSpan(err)
+	resp, err := client.{{.ClientName}}.UpdateTags(ctx, resourceGroupName, resourceName, {{.PackageAlias}}.TagsObject{Tags: tags}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.{{.Resource}}, nil
+}
+`))
+
+var BeginCreateOrUpdateFuncTemplate = template.Must(template.New("object-scaffolding-begincreate-func").Parse(`
+{{- $resource := .Resource}}
+{{- if (gt (len .SubResource) 0) }}
+{{- $resource = .SubResource}}
+{{- end }}
+const BeginCreateOrUpdateOperationName = "{{.ClientName}}.BeginCreateOrUpdate"
+// BeginCreateOrUpdate starts creating or updating a {{$resource}} and returns without waiting for the operation to complete.
+func (client *Client) BeginCreateOrUpdate(ctx context.Context, resourceGroupName string, {{with .SubResource}}parentResourceName string, {{end}}resourceName string, resource {{.PackageAlias}}.{{$resource}}) (result *utils.PollerWrapper[{{.PackageAlias}}.{{.ClientName}}CreateOrUpdateResponse], err error) {
+	ctx = utils.ContextWithClientName(ctx, "{{.ClientName}}")
+	ctx = utils.ContextWithRequestMethod(ctx, "BeginCreateOrUpdate")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, BeginCreateOrUpdateOperationName, client.tracer, nil)
+	defer endSpan(err)
+	poller, err := client.{{.ClientName}}.BeginCreateOrUpdate(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} resourceName, resource, nil)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewPollerWrapper(poller, nil), nil
+}
+`))
+
+var BeginDeleteFuncTemplate = template.Must(template.New("object-scaffolding-begindelete-func").Parse(`
+{{- $resource := .Resource}}
+{{- if (gt (len .SubResource) 0) }}
+{{- $resource = .SubResource}}
+{{- end }}
+const BeginDeleteOperationName = "{{.ClientName}}.BeginDelete"
+// BeginDelete starts deleting a {{$resource}} by name and returns without waiting for the operation to complete.
+func (client *Client) BeginDelete(ctx context.Context, resourceGroupName string, {{with .SubResource}}parentResourceName string, {{end}}resourceName string) (result *utils.PollerWrapper[{{.PackageAlias}}.{{.ClientName}}DeleteResponse], err error) {
+	ctx = utils.ContextWithClientName(ctx, "{{.ClientName}}")
+	ctx = utils.ContextWithRequestMethod(ctx, "BeginDelete")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, BeginDeleteOperationName, client.tracer, nil)
+	defer endSpan(err)
+	poller, err := client.{{.ClientName}}.BeginDelete(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} resourceName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewPollerWrapper(poller, nil), nil
+}
+`))
+
+var ListByFilterFuncTemplate = template.Must(template.New("object-scaffolding-listbyfilter-func").Parse(`
+{{- $resource := .Resource}}
+{{- if (gt (len .SubResource) 0) }}
+{{- $resource = .SubResource}}
+{{- end }}
+const ListByFilterOperationName = "{{.ClientName}}.ListByFilter"
+// ListByFilter gets a list of {{$resource}} in the resource group with the list options, e.g. $filter and $expand.
+func (client *Client) ListByFilter(ctx context.Context, resourceGroupName string{{with .SubResource}}, parentResourceName string{{end}}, options *{{.PackageAlias}}.{{.ClientName}}ListOptions) (result []*{{.PackageAlias}}.{{$resource}}, rerr error) {
+	ctx = utils.ContextWithClientName(ctx, "{{.ClientName}}")
+	ctx = utils.ContextWithRequestMethod(ctx, "ListByFilter")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, ListByFilterOperationName, client.tracer, nil)
+	defer endSpan(rerr)
+	pager := client.{{.ClientName}}.NewListPager(resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} options)
+	for pager.More() {
+		nextResult, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nextResult.Value...)
+	}
+	return result, nil
+}
+`))
+
 var ImportTemplate = template.Must(template.New("import").Parse(
 	`
 import (
@@ -265,17 +392,30 @@ var TestCaseTemplate = template.Must(template.New("object-scaffolding-test-case"
 {{-  $HasDelete := false }}
 {{- $HasListByRG := false }}
 {{- $HasList := false }}
+{{- $HasPatch := false }}
+{{- $HasUpdateTags := false }}
+{{- $HasBeginCreateOrUpdate := false }}
+{{- $HasBeginDelete := false }}
+{{- $HasListByFilter := false }}
 {{- range .Verbs}}
 {{- if eq . "createorupdate"}}{{$HasCreateOrUpdate = true}}{{end}}
 {{- if eq . "get"}}{{$HasGet = true}}{{end}}
 {{- if eq . "delete"}}{{$HasDelete = true}}{{end}}
 {{- if eq . "listbyrg"}}{{$HasListByRG = true}}{{end}}
 {{- if eq . "list"}}{{$HasList = true}}{{end}}
+{{- if eq . "patch"}}{{$HasPatch = true}}{{end}}
+{{- if eq . "updatetags"}}{{$HasUpdateTags = true}}{{end}}
+{{- if eq . "begincreateorupdate"}}{{$HasBeginCreateOrUpdate = true}}{{end}}
+{{- if eq . "begindelete"}}{{$HasBeginDelete = true}}{{end}}
+{{- if eq . "listbyfilter"}}{{$HasListByFilter = true}}{{end}}
 {{- end -}}
+{{- $patchType := printf "%sUpdate" $resource}}
+{{- with .PatchType}}{{$patchType = .}}{{end -}}
 var beforeAllFunc func(context.Context)
 var afterAllFunc func(context.Context)
 var additionalTestCases func()
-{{if or $HasCreateOrUpdate}}var newResource *{{.PackageAlias}}.{{$resource}} = &{{.PackageAlias}}.{{$resource}}{} {{- end }}
+{{if or $HasCreateOrUpdate $HasBeginCreateOrUpdate}}var newResource *{{.PackageAlias}}.{{$resource}} = &{{.PackageAlias}}.{{$resource}}{} {{- end }}
+{{if $HasPatch}}var patchResource *{{.PackageAlias}}.{{$patchType}} = &{{.PackageAlias}}.{{$patchType}}{} {{- end }}
 
 var _ = Describe("{{.ClientName}}", Ordered, func() {
 
@@ -322,6 +462,38 @@ var _ = Describe("{{.ClientName}}", Ordered, func() {
 		})
 	})
 {{end -}}
+{{if $HasBeginCreateOrUpdate}}
+	When("non-blocking update requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			poller, err := realClient.BeginCreateOrUpdate(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} resourceName, *newResource)
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := poller.WaitforPollerResp(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).NotTo(BeNil())
+			Expect(poller.Done()).To(BeTrue())
+		})
+	})
+{{end -}}
+{{if $HasPatch}}
+	When("patch requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			newResource, err := realClient.Patch(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} resourceName, *patchResource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newResource).NotTo(BeNil())
+		})
+	})
+{{end -}}
+{{if $HasUpdateTags}}
+	When("tag update requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			newResource, err := realClient.UpdateTags(ctx, resourceGroupName, resourceName, map[string]*string{"updated": to.Ptr("true")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newResource).NotTo(BeNil())
+			Expect(newResource.Tags).To(HaveKey("updated"))
+			Expect(*newResource.Tags["updated"]).To(Equal("true"))
+		})
+	})
+{{end -}}
 {{if or $HasListByRG $HasList}}
 	When("list requests are raised", func() {
 		It("should not return error", func(ctx context.Context) {
@@ -340,6 +512,16 @@ var _ = Describe("{{.ClientName}}", Ordered, func() {
 		})
 	})
 {{end -}}
+{{if $HasListByFilter}}
+	When("filtered list requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			resourceList, err := realClient.ListByFilter(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resourceList).NotTo(BeNil())
+			Expect(len(resourceList)).To(Equal(1))
+		})
+	})
+{{end -}}
 {{if $HasDelete}}
 	When("deletion requests are raised", func() {
 		It("should not return error", func(ctx context.Context) {
@@ -348,6 +530,16 @@ var _ = Describe("{{.ClientName}}", Ordered, func() {
 		})
 	})
 {{end -}}
+{{if $HasBeginDelete}}
+	When("non-blocking deletion requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			poller, err := realClient.BeginDelete(ctx, resourceGroupName,{{with .SubResource}}parentResourceName,{{end}} resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = poller.WaitforPollerResp(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+{{end -}}
 
 	if afterAllFunc != nil {
 		AfterAll(afterAllFunc)
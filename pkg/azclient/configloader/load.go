@@ -50,7 +50,35 @@ type ConfigMergeConfig struct {
 	CloudConfigType CloudConfigType `json:"cloudConfigType,omitempty" yaml:"cloudConfigType,omitempty"`
 }
 
+// Load loads the config from the cloud-config file and the Kubernetes secret according to the CloudConfigType of the file.
 func Load[Type any](ctx context.Context, secretLoaderConfig *K8sSecretLoaderConfig, fileLoaderConfig *FileLoaderConfig) (*Type, error) {
+	return LoadWithOverrides[Type](ctx, secretLoaderConfig, fileLoaderConfig, nil, nil)
+}
+
+// LoadWithOverrides loads the config like Load, then overrides it with Key Vault secrets and environment variables.
+// The sources are applied in the following order, each source overriding the fields it sets in the previous ones:
+//  1. the cloud-config file
+//  2. the Kubernetes secret, according to the CloudConfigType of the file
+//  3. the Key Vault secrets, the config document first, then the field secrets
+//  4. the environment variables
+//
+// The Key Vault and environment variable sources are skipped if their config is nil.
+func LoadWithOverrides[Type any](ctx context.Context, secretLoaderConfig *K8sSecretLoaderConfig, fileLoaderConfig *FileLoaderConfig, keyVaultLoaderConfig *KeyVaultLoaderConfig, envLoaderConfig *EnvLoaderConfig) (*Type, error) {
+	configloader, err := newLoader[Type](ctx, secretLoaderConfig, fileLoaderConfig)
+	if err != nil {
+		return nil, err
+	}
+	if keyVaultLoaderConfig != nil {
+		configloader = newKeyVaultLoader(keyVaultLoaderConfig, configloader, newYamlByteLoader[Type])
+	}
+	if envLoaderConfig != nil {
+		configloader = newEnvLoader(envLoaderConfig, configloader)
+	}
+	return configloader.Load(ctx)
+}
+
+// newLoader returns the loader of the cloud-config file and the Kubernetes secret.
+func newLoader[Type any](ctx context.Context, secretLoaderConfig *K8sSecretLoaderConfig, fileLoaderConfig *FileLoaderConfig) (configLoader[Type], error) {
 	configloader := newEmptyLoader[Type](nil)
 	var loadConfig *ConfigMergeConfig
 	var err error
@@ -64,7 +92,7 @@ func Load[Type any](ctx context.Context, secretLoaderConfig *K8sSecretLoaderConf
 		}
 		configloader = newFileLoader(fileLoaderConfig.FilePath, nil, newYamlByteLoader[Type])
 		if strings.EqualFold(string(loadConfig.CloudConfigType), string(CloudConfigTypeFile)) {
-			return configloader.Load(ctx)
+			return configloader, nil
 		}
 	}
 	if secretLoaderConfig != nil {
@@ -74,5 +102,5 @@ func Load[Type any](ctx context.Context, secretLoaderConfig *K8sSecretLoaderConf
 			configloader = newK8sSecretLoader(&secretLoaderConfig.K8sSecretConfig, secretLoaderConfig.KubeClient, configloader, newYamlByteLoader[Type])
 		}
 	}
-	return configloader.Load(ctx)
+	return configloader, nil
 }
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables overriding the config fields.
+const DefaultEnvPrefix = "AZURE_CLOUD_"
+
+type EnvLoaderConfig struct {
+	// Prefix of the environment variables, DefaultEnvPrefix if empty.
+	Prefix string
+}
+
+// envLoader overrides the fields of the config with environment variables.
+// AZURE_CLOUD_AAD_CLIENT_SECRET and AZURE_CLOUD_AADCLIENTSECRET both override the aadClientSecret field:
+// the prefix is stripped, and the rest is matched against the JSON field names ignoring case and underscores.
+// The values of string fields are used as is, the values of other fields are decoded as JSON, e.g. true or 5.
+// Only the top level fields, including the fields of embedded structs, can be overridden.
+type envLoader[Type any] struct {
+	prefix  string
+	environ func() []string
+	configLoader[Type]
+}
+
+// newEnvLoader creates a loader overriding the config of the inner loader with environment variables.
+func newEnvLoader[Type any](config *EnvLoaderConfig, loader configLoader[Type]) configLoader[Type] {
+	prefix := DefaultEnvPrefix
+	if config != nil && config.Prefix != "" {
+		prefix = config.Prefix
+	}
+	return &envLoader[Type]{
+		prefix:       prefix,
+		environ:      os.Environ,
+		configLoader: loader,
+	}
+}
+
+func (e *envLoader[Type]) Load(ctx context.Context) (*Type, error) {
+	if e.configLoader == nil {
+		e.configLoader = newEmptyLoader[Type](nil)
+	}
+	config, err := e.configLoader.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fields := jsonFieldsOf(reflect.TypeOf(config).Elem())
+	values := map[string]string{}
+	for _, env := range e.environ() {
+		name, value, found := strings.Cut(env, "=")
+		if !found || !strings.HasPrefix(name, e.prefix) {
+			continue
+		}
+		field, ok := fields[normalizeFieldName(strings.TrimPrefix(name, e.prefix))]
+		if !ok {
+			continue
+		}
+		values[field.name] = value
+	}
+	if err := overrideFields(config, fields, values); err != nil {
+		return nil, fmt.Errorf("failed to override the config with environment variables: %w", err)
+	}
+	return config, nil
+}
+
+// jsonField is a top level JSON field of a config type.
+type jsonField struct {
+	name     string
+	isString bool
+}
+
+// jsonFieldsOf returns the top level JSON fields of the struct type, keyed by their normalized names.
+func jsonFieldsOf(t reflect.Type) map[string]jsonField {
+	fields := map[string]jsonField{}
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			// the fields of embedded structs are promoted, the outer fields win on conflicts
+			for key, promoted := range jsonFieldsOf(fieldType) {
+				if _, ok := fields[key]; !ok {
+					fields[key] = promoted
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[normalizeFieldName(name)] = jsonField{name: name, isString: fieldType.Kind() == reflect.String}
+	}
+	return fields
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// overrideFields sets the fields of the config to the values keyed by JSON field names.
+func overrideFields[Type any](config *Type, fields map[string]jsonField, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	overrides := map[string]json.RawMessage{}
+	for name, value := range values {
+		field, ok := fields[normalizeFieldName(name)]
+		if !ok {
+			return fmt.Errorf("unknown field %q", name)
+		}
+		if field.isString {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			overrides[field.name] = encoded
+			continue
+		}
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("invalid JSON value for field %q", field.name)
+		}
+		overrides[field.name] = json.RawMessage(value)
+	}
+	content, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(content, config)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configloader
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoaderEnv", func() {
+	var environ []string
+	newLoader := func(inner configLoader[TestConfig]) configLoader[TestConfig] {
+		loader := newEnvLoader[TestConfig](nil, inner).(*envLoader[TestConfig])
+		loader.environ = func() []string { return environ }
+		return loader
+	}
+
+	When("environment variables are set", func() {
+		It("should override the matching fields of the config", func() {
+			environ = []string{
+				"AZURE_CLOUD_CLOUD=AzureChinaCloud",
+				"AZURE_CLOUD_FROM_SECRET=true",
+				"AZURE_CLOUD_CLOUDCONFIGTYPE=secret",
+				"AZURE_CLOUD_UNKNOWN=ignored",
+				"CLOUD=ignored",
+			}
+			config, err := newLoader(newFileLoader[TestConfig]("testdata/azure.json", nil, newYamlByteLoader[TestConfig])).Load(context.Background())
+			Expect(err).To(BeNil())
+			Expect(*config.Cloud).To(Equal("AzureChinaCloud"))
+			Expect(config.FromSecret).To(BeTrue())
+			Expect(config.CloudConfigType).To(Equal(CloudConfigTypeSecret))
+			// the fields without environment variables keep the values of the inner loader
+			Expect(config.UseInstanceMetadata).To(BeTrue())
+		})
+	})
+	When("the value of a non-string field is invalid", func() {
+		It("should return error", func() {
+			environ = []string{"AZURE_CLOUD_USE_INSTANCE_METADATA=yes"}
+			config, err := newLoader(nil).Load(context.Background())
+			Expect(err).NotTo(BeNil())
+			Expect(config).To(BeNil())
+		})
+	})
+	When("a custom prefix is configured", func() {
+		It("should only use the variables with the prefix", func() {
+			GinkgoT().Setenv("TEST_CLOUD_VALUE", "custom")
+			GinkgoT().Setenv("AZURE_CLOUD_CLOUD", "AzureChinaCloud")
+			config, err := newEnvLoader[TestConfig](&EnvLoaderConfig{Prefix: "TEST_CLOUD_"}, nil).Load(context.Background())
+			Expect(err).To(BeNil())
+			Expect(*config.Value).To(Equal("custom"))
+			Expect(config.Cloud).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ConfigChangeHandler is called with the config loaded after the cloud-config file changed, or with the error
+// loading it. The handler decides whether to apply the config, so that an invalid file never replaces a working config.
+type ConfigChangeHandler[Type any] func(ctx context.Context, config *Type, err error)
+
+// Watch watches the cloud-config file and calls the handler with the config loaded like Load each time the content
+// of the file changes, until the context is done. The directory of the file is watched rather than the file itself,
+// so that the atomic symlink swaps of projected volumes, which replace the ..data symlink of the directory, are detected.
+// The current content of the file is not passed to the handler, it is expected to have been loaded with Load.
+func Watch[Type any](ctx context.Context, secretLoaderConfig *K8sSecretLoaderConfig, fileLoaderConfig *FileLoaderConfig, handler ConfigChangeHandler[Type]) error {
+	if fileLoaderConfig == nil {
+		return fmt.Errorf("no cloud-config file to watch")
+	}
+	content, err := os.ReadFile(fileLoaderConfig.FilePath)
+	if err != nil {
+		return err
+	}
+	lastHash := sha256.Sum256(content)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to initialize file watcher: %w", err)
+	}
+	defer watcher.Close()
+	for _, dir := range watchedDirs(fileLoaderConfig.FilePath) {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return fmt.Errorf("events channel closed unexpectedly")
+			}
+			// the events of the other files in the directories are not filtered out, the swaps of
+			// projected volumes only touch the ..data symlink. Unchanged content is skipped.
+			content, err := os.ReadFile(fileLoaderConfig.FilePath)
+			if err != nil {
+				// the file may be missing in the middle of a swap, it is read again on the next event
+				continue
+			}
+			hash := sha256.Sum256(content)
+			if hash == lastHash {
+				continue
+			}
+			lastHash = hash
+			config, err := Load[Type](ctx, secretLoaderConfig, fileLoaderConfig)
+			handler(ctx, config, err)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return fmt.Errorf("errors channel closed unexpectedly")
+			}
+			handler(ctx, nil, fmt.Errorf("failed to watch %s: %w", fileLoaderConfig.FilePath, err))
+		}
+	}
+}
+
+// watchedDirs returns the directory of the file, and the directory of its target if the file is a symlink.
+func watchedDirs(filePath string) []string {
+	dirs := []string{filepath.Dir(filePath)}
+	if target, err := filepath.EvalSymlinks(filePath); err == nil && filepath.Dir(target) != dirs[0] {
+		dirs = append(dirs, filepath.Dir(target))
+	}
+	return dirs
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configloader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watch", func() {
+	type change struct {
+		config *TestConfig
+		err    error
+	}
+	var (
+		dir     string
+		changes chan change
+		cancel  context.CancelFunc
+		done    chan error
+	)
+	watch := func(filePath string) {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		done = make(chan error, 1)
+		go func() {
+			done <- Watch[TestConfig](ctx, nil, &FileLoaderConfig{FilePath: filePath}, func(_ context.Context, config *TestConfig, err error) {
+				changes <- change{config: config, err: err}
+			})
+		}()
+	}
+	// writeFile replaces the file atomically, so that the watcher never reads a partially written file
+	writeFile := func(filePath, content string) {
+		Expect(os.WriteFile(filePath+".tmp", []byte(content), 0600)).To(Succeed())
+		Expect(os.Rename(filePath+".tmp", filePath)).To(Succeed())
+	}
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		changes = make(chan change, 10)
+	})
+	AfterEach(func() {
+		if cancel != nil {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		}
+	})
+
+	When("the file does not exist", func() {
+		It("should return error", func() {
+			err := Watch[TestConfig](context.Background(), nil, &FileLoaderConfig{FilePath: filepath.Join(dir, "azure.json")}, nil)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	When("the file is written", func() {
+		It("should call the handler with the new config", func() {
+			filePath := filepath.Join(dir, "azure.json")
+			writeFile(filePath, `{"cloud": "AzurePublicCloud"}`)
+			watch(filePath)
+
+			// the watch may not be set up yet, a new content is written until a change is seen
+			writes := 0
+			Eventually(func() int {
+				writes++
+				writeFile(filePath, fmt.Sprintf(`{"cloud": "AzureChinaCloud", "value": "%d"}`, writes))
+				return len(changes)
+			}).ShouldNot(BeZero())
+			// the changes of the earlier writes may be seen first, the last one is eventually seen
+			Eventually(func(g Gomega) *string {
+				var c change
+				g.Expect(changes).To(Receive(&c))
+				g.Expect(c.err).To(BeNil())
+				g.Expect(*c.config.Cloud).To(Equal("AzureChinaCloud"))
+				return c.config.Value
+			}).Should(HaveValue(Equal(fmt.Sprint(writes))))
+		})
+		It("should not call the handler when the content is unchanged", func() {
+			filePath := filepath.Join(dir, "azure.json")
+			writeFile(filePath, `{"cloud": "AzurePublicCloud"}`)
+			watch(filePath)
+
+			Consistently(func() int {
+				writeFile(filePath, `{"cloud": "AzurePublicCloud"}`)
+				return len(changes)
+			}, "200ms").Should(BeZero())
+		})
+	})
+	When("the file is invalid", func() {
+		It("should call the handler with the error", func() {
+			filePath := filepath.Join(dir, "azure.json")
+			writeFile(filePath, `{"cloud": "AzurePublicCloud"}`)
+			watch(filePath)
+
+			writes := 0
+			Eventually(func() int {
+				writes++
+				writeFile(filePath, fmt.Sprintf(`{"cloud": %d`, writes))
+				return len(changes)
+			}).ShouldNot(BeZero())
+			var c change
+			Expect(changes).To(Receive(&c))
+			Expect(c.err).NotTo(BeNil())
+			Expect(c.config).To(BeNil())
+		})
+	})
+	When("the symlink of a projected volume is swapped", func() {
+		It("should call the handler with the new config", func() {
+			// the layout of projected volumes: azure.json -> ..data/azure.json, ..data -> ..<timestamp>
+			writeVersion := func(version, cloud string) {
+				// the content differs between the versions, a swap to the same content is skipped
+				versionDir := filepath.Join(dir, version)
+				Expect(os.Mkdir(versionDir, 0700)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(versionDir, "azure.json"), []byte(fmt.Sprintf(`{"cloud": %q, "value": %q}`, cloud, version)), 0600)).To(Succeed())
+				Expect(os.Symlink(version, filepath.Join(dir, "..data_tmp"))).To(Succeed())
+				Expect(os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data"))).To(Succeed())
+			}
+			writeVersion("..1", "AzurePublicCloud")
+			filePath := filepath.Join(dir, "azure.json")
+			Expect(os.Symlink(filepath.Join("..data", "azure.json"), filePath)).To(Succeed())
+			watch(filePath)
+
+			versions := 1
+			Eventually(func() int {
+				versions++
+				writeVersion(fmt.Sprintf("..%d", versions), "AzureChinaCloud")
+				return len(changes)
+			}).ShouldNot(BeZero())
+			var c change
+			Expect(changes).To(Receive(&c))
+			Expect(c.err).To(BeNil())
+			Expect(*c.config.Cloud).To(Equal("AzureChinaCloud"))
+		})
+	})
+})
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+)
+
+var ErrNoKeyVaultClient = errors.New("no key vault client or credential provided")
+
+// KeyVaultSecretClient gets the secrets of a Key Vault. It is implemented by *azsecrets.Client.
+// The data plane client is used like armauth.KeyVaultCredential does: the ARM secretclient of azclient
+// never returns the secret values, and this module does not depend on azclient.
+type KeyVaultSecretClient interface {
+	GetSecret(ctx context.Context, name string, version string, options *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error)
+}
+
+type KeyVaultLoaderConfig struct {
+	// VaultURL is the URL of the Key Vault, e.g. https://myvault.vault.azure.net/. Ignored if Client is set.
+	VaultURL string
+	// Credential authenticates the requests to the Key Vault, e.g. a managed identity credential. Ignored if Client is set.
+	Credential azcore.TokenCredential
+	// Client gets the secrets. If nil, a secret client is created from VaultURL and Credential.
+	Client KeyVaultSecretClient
+
+	// ConfigSecretName is the name of an optional secret holding a config document, which overrides
+	// the fields it sets like the merge mode of the Kubernetes secret.
+	ConfigSecretName string
+	// FieldSecrets maps the JSON fields of the config to the names of the secrets holding their values,
+	// e.g. {"aadClientSecret": "cloud-provider-client-secret"}. They override the config document.
+	FieldSecrets map[string]string
+}
+
+// keyVaultLoader overrides the config of the inner loader with Key Vault secrets.
+type keyVaultLoader[Type any] struct {
+	*KeyVaultLoaderConfig
+	configLoader[Type]
+	decoderFactory[Type]
+}
+
+// newKeyVaultLoader returns a config loader which loads config from Key Vault secrets.
+// decoderFactory is a function that creates a new loader from the content of the config secret. it should never be nil.
+func newKeyVaultLoader[Type any](config *KeyVaultLoaderConfig, loader configLoader[Type], decoder decoderFactory[Type]) configLoader[Type] {
+	if config == nil {
+		return nil
+	}
+	return &keyVaultLoader[Type]{
+		KeyVaultLoaderConfig: config,
+		configLoader:         loader,
+		decoderFactory:       decoder,
+	}
+}
+
+func (k *keyVaultLoader[Type]) Load(ctx context.Context) (*Type, error) {
+	if k.configLoader == nil {
+		k.configLoader = newEmptyLoader[Type](nil)
+	}
+	if k.Client == nil {
+		if k.Credential == nil || k.VaultURL == "" {
+			return nil, ErrNoKeyVaultClient
+		}
+		client, err := azsecrets.NewClient(k.VaultURL, k.Credential, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create secret client: %w", err)
+		}
+		k.Client = client
+	}
+
+	loader := k.configLoader
+	if k.ConfigSecretName != "" {
+		content, err := k.getSecret(ctx, k.ConfigSecretName)
+		if err != nil {
+			return nil, err
+		}
+		loader = k.decoderFactory([]byte(content), loader)
+	}
+	config, err := loader.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(k.FieldSecrets))
+	for field, secretName := range k.FieldSecrets {
+		value, err := k.getSecret(ctx, secretName)
+		if err != nil {
+			return nil, err
+		}
+		values[field] = value
+	}
+	if err := overrideFields(config, jsonFieldsOf(reflect.TypeOf(config).Elem()), values); err != nil {
+		return nil, fmt.Errorf("failed to override the config with key vault secrets: %w", err)
+	}
+	return config, nil
+}
+
+func (k *keyVaultLoader[Type]) getSecret(ctx context.Context, name string) (string, error) {
+	const latestVersion = ""
+	resp, err := k.Client.GetSecret(ctx, name, latestVersion, nil)
+	if err != nil {
+		return "", fmt.Errorf("get secret %s: %w", name, err)
+	}
+	if resp.Value == nil {
+		return "", fmt.Errorf("secret %s value is nil", name)
+	}
+	return *resp.Value, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configloader
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeSecretClient map[string]string
+
+func (f fakeSecretClient) GetSecret(_ context.Context, name string, _ string, _ *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error) {
+	value, ok := f[name]
+	if !ok {
+		return azsecrets.GetSecretResponse{}, fmt.Errorf("secret %s not found", name)
+	}
+	return azsecrets.GetSecretResponse{SecretBundle: azsecrets.SecretBundle{Value: to.Ptr(value)}}, nil
+}
+
+var _ = Describe("LoaderKeyVault", func() {
+	var client fakeSecretClient
+	BeforeEach(func() {
+		client = fakeSecretClient{
+			"cloud-config": `{"cloud": "AzureCloud", "fromSecret": true}`,
+			"cloud":        "AzureChinaCloud",
+			"value":        "from-key-vault",
+		}
+	})
+
+	When("the config secret and field secrets are configured", func() {
+		It("should override the config of the inner loader", func() {
+			loader := newKeyVaultLoader(&KeyVaultLoaderConfig{
+				Client:           client,
+				ConfigSecretName: "cloud-config",
+				FieldSecrets:     map[string]string{"value": "value"},
+			}, newFileLoader[TestConfig]("testdata/azure.json", nil, newYamlByteLoader[TestConfig]), newYamlByteLoader[TestConfig])
+			config, err := loader.Load(context.Background())
+			Expect(err).To(BeNil())
+			Expect(*config.Cloud).To(Equal("AzureCloud"))
+			Expect(*config.Value).To(Equal("from-key-vault"))
+			Expect(config.FromSecret).To(BeTrue())
+			Expect(config.UseInstanceMetadata).To(BeTrue())
+		})
+	})
+	When("a secret does not exist", func() {
+		It("should return error", func() {
+			loader := newKeyVaultLoader(&KeyVaultLoaderConfig{
+				Client:       client,
+				FieldSecrets: map[string]string{"value": "missing"},
+			}, nil, newYamlByteLoader[TestConfig])
+			config, err := loader.Load(context.Background())
+			Expect(err).NotTo(BeNil())
+			Expect(config).To(BeNil())
+		})
+	})
+	When("a field secret targets an unknown field", func() {
+		It("should return error", func() {
+			loader := newKeyVaultLoader(&KeyVaultLoaderConfig{
+				Client:       client,
+				FieldSecrets: map[string]string{"unknown": "value"},
+			}, nil, newYamlByteLoader[TestConfig])
+			_, err := loader.Load(context.Background())
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	When("no client or credential is provided", func() {
+		It("should return error", func() {
+			_, err := newKeyVaultLoader(&KeyVaultLoaderConfig{}, nil, newYamlByteLoader[TestConfig]).Load(context.Background())
+			Expect(err).To(Equal(ErrNoKeyVaultClient))
+		})
+	})
+	When("all the sources are configured", func() {
+		It("should apply them in the precedence order", func() {
+			GinkgoT().Setenv("AZURE_CLOUD_VALUE", "from-env")
+			config, err := LoadWithOverrides[TestConfig](context.Background(), nil, &FileLoaderConfig{FilePath: "testdata/azure.json"},
+				&KeyVaultLoaderConfig{Client: client, FieldSecrets: map[string]string{"cloud": "cloud", "value": "value"}},
+				&EnvLoaderConfig{})
+			Expect(err).To(BeNil())
+			Expect(*config.Cloud).To(Equal("AzureChinaCloud"))
+			Expect(*config.Value).To(Equal("from-env"))
+			Expect(config.UseInstanceMetadata).To(BeTrue())
+		})
+	})
+})
@@ -101,7 +101,7 @@ func (client *Client) Delete(ctx context.Context, resourceGroupName string, reso
 	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
 	ctx, endSpan := runtime.StartSpan(ctx, DeleteOperationName, client.tracer, nil)
 	defer endSpan(err)
-	_, err = utils.NewPollerWrapper(client.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
+	_, err = utils.NewPollerWrapper(client.DisksClient.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
 	return err
 }
 
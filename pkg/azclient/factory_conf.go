@@ -24,6 +24,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
 
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/etagcache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils/armbalancer"
@@ -51,6 +52,20 @@ type ClientFactoryConfig struct {
 
 	// The ID of the Azure Subscription that the cluster is deployed in
 	SubscriptionID string `json:"subscriptionId,omitempty" yaml:"subscriptionId,omitempty"`
+
+	// Path of the fault injection config file, e.g. a mounted ConfigMap. Faults are injected into the responses
+	// of the clients of the factory when set. For chaos testing only, never set it in production.
+	FaultInjectionConfigFile string `json:"faultInjectionConfigFile,omitempty" yaml:"faultInjectionConfigFile,omitempty"`
+
+	// Cache the GET responses carrying an ETag and revalidate them with If-None-Match. The cache is owned by the
+	// client factory and shared by its clients, whose writes invalidate the cached responses.
+	EnableResponseCache bool `json:"enableResponseCache,omitempty" yaml:"enableResponseCache,omitempty"`
+	// Maximum number of cached responses, defaults to 1000
+	ResponseCacheMaxEntries int `json:"responseCacheMaxEntries,omitempty" yaml:"responseCacheMaxEntries,omitempty"`
+	// Maximum total size of the cached response bodies in bytes, defaults to 64MiB
+	ResponseCacheMaxBytes int64 `json:"responseCacheMaxBytes,omitempty" yaml:"responseCacheMaxBytes,omitempty"`
+	// Recorder of the hits and misses of the response cache, e.g. the metrics of the trace module
+	ResponseCacheRecorder etagcache.Recorder `json:"-" yaml:"-"`
 }
 
 func GetDefaultResourceClientOption(armConfig *ARMClientConfig, factoryConfig *ClientFactoryConfig) (*policy.ClientOptions, error) {
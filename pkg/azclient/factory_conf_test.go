@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azclient_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+)
+
+type fakeTokenCredential struct{}
+
+func (fakeTokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+var _ = Describe("ClientFactoryConfig", func() {
+	Context("FaultInjectionConfigFile", func() {
+		When("the config file does not exist", func() {
+			It("should fail to create the factory", func() {
+				_, err := azclient.NewClientFactory(&azclient.ClientFactoryConfig{
+					FaultInjectionConfigFile: filepath.Join(GinkgoT().TempDir(), "faults.json"),
+				}, nil, fakeTokenCredential{})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+		When("the config file is set", func() {
+			It("should inject the faults per factory", func() {
+				path := filepath.Join(GinkgoT().TempDir(), "faults.json")
+				Expect(os.WriteFile(path, []byte(`{"faults": [{"methods": ["GET"], "statusCode": 400, "errorCode": "ReferencedResourceNotProvisioned", "count": 1}]}`), 0600)).To(Succeed())
+
+				// the fault is injected once by each factory, the factories do not share the count
+				for i := 0; i < 2; i++ {
+					factory, err := azclient.NewClientFactory(&azclient.ClientFactoryConfig{
+						SubscriptionID:           "subscription",
+						FaultInjectionConfigFile: path,
+					}, nil, fakeTokenCredential{})
+					Expect(err).NotTo(HaveOccurred())
+					_, err = factory.GetResourceGroupClient().Get(context.Background(), "rg")
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("ReferencedResourceNotProvisioned"))
+				}
+			})
+		})
+	})
+})
@@ -36,6 +36,9 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/ipgroupclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/managedclusterclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/managementpolicyclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/etagcache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/faultinjection"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/privateendpointclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/privatelinkserviceclient"
@@ -64,6 +67,9 @@ type ClientFactoryImpl struct {
 	facotryConfig                           *ClientFactoryConfig
 	cred                                    azcore.TokenCredential
 	clientOptionsMutFn                      []func(option *arm.ClientOptions)
+	rateLimitBuckets                        *ratelimit.Buckets
+	responseCache                           *etagcache.Cache
+	faultInjectionPolicy                    *faultinjection.Policy
 	accountclientInterface                  sync.Map
 	availabilitysetclientInterface          availabilitysetclient.Interface
 	blobcontainerclientInterface            sync.Map
@@ -75,6 +81,7 @@ type ClientFactoryImpl struct {
 	ipgroupclientInterface                  ipgroupclient.Interface
 	loadbalancerclientInterface             loadbalancerclient.Interface
 	managedclusterclientInterface           managedclusterclient.Interface
+	managementpolicyclientInterface         managementpolicyclient.Interface
 	privateendpointclientInterface          privateendpointclient.Interface
 	privatelinkserviceclientInterface       privatelinkserviceclient.Interface
 	privatezoneclientInterface              privatezoneclient.Interface
@@ -112,6 +119,17 @@ func NewClientFactory(config *ClientFactoryConfig, armConfig *ARMClientConfig, c
 		facotryConfig:      config,
 		cred:               cred,
 		clientOptionsMutFn: clientOptionsMutFn,
+		rateLimitBuckets:   ratelimit.NewBuckets(),
+	}
+	if config.EnableResponseCache {
+		factory.responseCache = etagcache.NewCache(config.ResponseCacheMaxEntries, config.ResponseCacheMaxBytes)
+	}
+	if config.FaultInjectionConfigFile != "" {
+		// the policy is shared by the clients of the factory so that they inject the same faults
+		factory.faultInjectionPolicy, err = faultinjection.NewPolicyFromFile(config.FaultInjectionConfigFile)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	//initialize accountclient
@@ -180,6 +198,12 @@ func NewClientFactory(config *ClientFactoryConfig, armConfig *ARMClientConfig, c
 		return nil, err
 	}
 
+	//initialize managementpolicyclient
+	factory.managementpolicyclientInterface, err = factory.createManagementPolicyClient(config.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
 	//initialize privateendpointclient
 	factory.privateendpointclientInterface, err = factory.createPrivateEndpointClient(config.SubscriptionID)
 	if err != nil {
@@ -308,6 +332,13 @@ func (factory *ClientFactoryImpl) createAccountClient(subscription string) (acco
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -344,10 +375,17 @@ func (factory *ClientFactoryImpl) createAvailabilitySetClient(subscription strin
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("availabilitySetRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -369,6 +407,13 @@ func (factory *ClientFactoryImpl) createBlobContainerClient(subscription string)
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -405,6 +450,13 @@ func (factory *ClientFactoryImpl) createBlobServicePropertiesClient(subscription
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -424,10 +476,17 @@ func (factory *ClientFactoryImpl) createDeploymentClient(subscription string) (d
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("deploymentRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -449,10 +508,17 @@ func (factory *ClientFactoryImpl) createDiskClient(subscription string) (diskcli
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("diskRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -491,6 +557,13 @@ func (factory *ClientFactoryImpl) createFileShareClient(subscription string) (fi
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -527,10 +600,17 @@ func (factory *ClientFactoryImpl) createInterfaceClient(subscription string) (in
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("interfaceRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -552,10 +632,17 @@ func (factory *ClientFactoryImpl) createIPGroupClient(subscription string) (ipgr
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("ipGroupRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -577,10 +664,17 @@ func (factory *ClientFactoryImpl) createLoadBalancerClient(subscription string)
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("loadBalancerRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -602,10 +696,17 @@ func (factory *ClientFactoryImpl) createManagedClusterClient(subscription string
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("containerServiceRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -621,16 +722,49 @@ func (factory *ClientFactoryImpl) GetManagedClusterClient() managedclusterclient
 	return factory.managedclusterclientInterface
 }
 
+func (factory *ClientFactoryImpl) createManagementPolicyClient(subscription string) (managementpolicyclient.Interface, error) {
+	//initialize managementpolicyclient
+	options, err := GetDefaultResourceClientOption(factory.armConfig, factory.facotryConfig)
+	if err != nil {
+		return nil, err
+	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
+
+	for _, optionMutFn := range factory.clientOptionsMutFn {
+		if optionMutFn != nil {
+			optionMutFn(options)
+		}
+	}
+	return managementpolicyclient.New(subscription, factory.cred, options)
+}
+
+func (factory *ClientFactoryImpl) GetManagementPolicyClient() managementpolicyclient.Interface {
+	return factory.managementpolicyclientInterface
+}
+
 func (factory *ClientFactoryImpl) createPrivateEndpointClient(subscription string) (privateendpointclient.Interface, error) {
 	//initialize privateendpointclient
 	options, err := GetDefaultResourceClientOption(factory.armConfig, factory.facotryConfig)
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("privateEndpointRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -652,10 +786,17 @@ func (factory *ClientFactoryImpl) createPrivateLinkServiceClient(subscription st
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("privateLinkServiceRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -677,10 +818,17 @@ func (factory *ClientFactoryImpl) createPrivateZoneClient(subscription string) (
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("privateDNSRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -702,6 +850,13 @@ func (factory *ClientFactoryImpl) createProviderClient(subscription string) (pro
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -721,10 +876,17 @@ func (factory *ClientFactoryImpl) createPublicIPAddressClient(subscription strin
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("publicIPAddressRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -746,6 +908,13 @@ func (factory *ClientFactoryImpl) createPublicIPPrefixClient(subscription string
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -765,6 +934,13 @@ func (factory *ClientFactoryImpl) createRegistryClient(subscription string) (reg
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -784,6 +960,13 @@ func (factory *ClientFactoryImpl) createResourceGroupClient(subscription string)
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -803,10 +986,17 @@ func (factory *ClientFactoryImpl) createRouteTableClient(subscription string) (r
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("routeTableRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -828,6 +1018,13 @@ func (factory *ClientFactoryImpl) createSecretClient(subscription string) (secre
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -847,10 +1044,17 @@ func (factory *ClientFactoryImpl) createSecurityGroupClient(subscription string)
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("securityGroupRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -872,10 +1076,17 @@ func (factory *ClientFactoryImpl) createSnapshotClient(subscription string) (sna
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("snapshotRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -914,6 +1125,13 @@ func (factory *ClientFactoryImpl) createSSHPublicKeyResourceClient(subscription
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -933,10 +1151,17 @@ func (factory *ClientFactoryImpl) createSubnetClient(subscription string) (subne
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("subnetsRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -958,6 +1183,13 @@ func (factory *ClientFactoryImpl) createVaultClient(subscription string) (vaultc
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -977,10 +1209,17 @@ func (factory *ClientFactoryImpl) createVirtualMachineClient(subscription string
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("virtualMachineRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -1002,10 +1241,17 @@ func (factory *ClientFactoryImpl) createVirtualMachineScaleSetClient(subscriptio
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("virtualMachineSizesRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -1027,6 +1273,13 @@ func (factory *ClientFactoryImpl) createVirtualMachineScaleSetVMClient(subscript
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -1046,6 +1299,13 @@ func (factory *ClientFactoryImpl) createVirtualNetworkClient(subscription string
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	for _, optionMutFn := range factory.clientOptionsMutFn {
 		if optionMutFn != nil {
@@ -1065,10 +1325,17 @@ func (factory *ClientFactoryImpl) createVirtualNetworkLinkClient(subscription st
 	if err != nil {
 		return nil, err
 	}
+	if factory.responseCache != nil {
+		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, etagcache.NewPolicy(factory.responseCache, factory.facotryConfig.ResponseCacheRecorder))
+	}
+	if factory.faultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retry policies handle them
+		options.ClientOptions.PerRetryPolicies = append(options.ClientOptions.PerRetryPolicies, factory.faultInjectionPolicy)
+	}
 
 	//add ratelimit policy
 	ratelimitOption := factory.facotryConfig.GetRateLimitConfig("virtualNetworkRateLimit")
-	rateLimitPolicy := ratelimit.NewRateLimitPolicy(ratelimitOption)
+	rateLimitPolicy := ratelimit.NewSharedRateLimitPolicy(ratelimitOption, factory.rateLimitBuckets)
 	if rateLimitPolicy != nil {
 		options.ClientOptions.PerCallPolicies = append(options.ClientOptions.PerCallPolicies, rateLimitPolicy)
 	}
@@ -101,6 +101,13 @@ var _ = Describe("Factory", func() {
 			client := factory.GetManagedClusterClient()
 			Expect(client).NotTo(BeNil())
 		})
+		It("should create factory instance without painc - ManagementPolicy", func() {
+			factory, err := NewClientFactory(nil, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(factory).NotTo(BeNil())
+			client := factory.GetManagementPolicyClient()
+			Expect(client).NotTo(BeNil())
+		})
 		It("should create factory instance without painc - PrivateEndpoint", func() {
 			factory, err := NewClientFactory(nil, nil, nil)
 			Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managementpolicyclient
+
+import (
+	"context"
+
+	armstorage "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+)
+
+// Get gets the ManagementPolicy of the storage account. A storage account has at most one
+// management policy, which is always named "default".
+func (client *Client) Get(ctx context.Context, resourceGroupName string, accountName string) (*armstorage.ManagementPolicy, error) {
+	resp, err := client.ManagementPoliciesClient.Get(ctx, resourceGroupName, accountName, armstorage.ManagementPolicyNameDefault, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.ManagementPolicy, nil
+}
+
+// CreateOrUpdate sets the ManagementPolicy of the storage account, replacing all the existing rules.
+func (client *Client) CreateOrUpdate(ctx context.Context, resourceGroupName string, accountName string, policy armstorage.ManagementPolicy) (*armstorage.ManagementPolicy, error) {
+	resp, err := client.ManagementPoliciesClient.CreateOrUpdate(ctx, resourceGroupName, accountName, armstorage.ManagementPolicyNameDefault, policy, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.ManagementPolicy, nil
+}
+
+// Delete deletes the ManagementPolicy of the storage account.
+func (client *Client) Delete(ctx context.Context, resourceGroupName string, accountName string) error {
+	_, err := client.ManagementPoliciesClient.Delete(ctx, resourceGroupName, accountName, armstorage.ManagementPolicyNameDefault, nil)
+	return err
+}
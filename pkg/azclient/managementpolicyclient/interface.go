@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +azure:enableclientgen:=true
+package managementpolicyclient
+
+import (
+	"context"
+
+	armstorage "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+)
+
+// +azure:client:resource=ManagementPolicy,packageName=github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage,packageAlias=armstorage,clientName=ManagementPoliciesClient,expand=false
+type Interface interface {
+	Get(ctx context.Context, resourceGroupName string, accountName string) (*armstorage.ManagementPolicy, error)
+	CreateOrUpdate(ctx context.Context, resourceGroupName string, accountName string, policy armstorage.ManagementPolicy) (*armstorage.ManagementPolicy, error)
+	Delete(ctx context.Context, resourceGroupName string, accountName string) error
+}
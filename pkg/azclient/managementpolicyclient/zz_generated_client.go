@@ -0,0 +1,50 @@
+// /*
+// Copyright The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// */
+
+// Code generated by client-gen. DO NOT EDIT.
+package managementpolicyclient
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
+	armstorage "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
+)
+
+type Client struct {
+	*armstorage.ManagementPoliciesClient
+	subscriptionID string
+	tracer         tracing.Tracer
+}
+
+func New(subscriptionID string, credential azcore.TokenCredential, options *arm.ClientOptions) (Interface, error) {
+	if options == nil {
+		options = utils.GetDefaultOption()
+	}
+	tr := options.TracingProvider.NewTracer(utils.ModuleName, utils.ModuleVersion)
+
+	client, err := armstorage.NewManagementPoliciesClient(subscriptionID, credential, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		ManagementPoliciesClient: client,
+		subscriptionID:           subscriptionID,
+		tracer:                   tr,
+	}, nil
+}
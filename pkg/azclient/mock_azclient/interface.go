@@ -41,6 +41,7 @@ import (
 	ipgroupclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/ipgroupclient"
 	loadbalancerclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/loadbalancerclient"
 	managedclusterclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/managedclusterclient"
+	managementpolicyclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/managementpolicyclient"
 	privateendpointclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/privateendpointclient"
 	privatelinkserviceclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/privatelinkserviceclient"
 	privatezoneclient "sigs.k8s.io/cloud-provider-azure/pkg/azclient/privatezoneclient"
@@ -300,6 +301,20 @@ func (mr *MockClientFactoryMockRecorder) GetManagedClusterClient() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetManagedClusterClient", reflect.TypeOf((*MockClientFactory)(nil).GetManagedClusterClient))
 }
 
+// GetManagementPolicyClient mocks base method.
+func (m *MockClientFactory) GetManagementPolicyClient() managementpolicyclient.Interface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetManagementPolicyClient")
+	ret0, _ := ret[0].(managementpolicyclient.Interface)
+	return ret0
+}
+
+// GetManagementPolicyClient indicates an expected call of GetManagementPolicyClient.
+func (mr *MockClientFactoryMockRecorder) GetManagementPolicyClient() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetManagementPolicyClient", reflect.TypeOf((*MockClientFactory)(nil).GetManagementPolicyClient))
+}
+
 // GetPrivateEndpointClient mocks base method.
 func (m *MockClientFactory) GetPrivateEndpointClient() privateendpointclient.Interface {
 	m.ctrl.T.Helper()
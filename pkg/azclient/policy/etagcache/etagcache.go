@@ -0,0 +1,247 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package etagcache provides a policy caching the GET responses carrying an ETag and revalidating
+// them with If-None-Match, so that unchanged resources are not transferred again.
+package etagcache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	DefaultMaxEntries = 1000
+	DefaultMaxBytes   = 64 * 1024 * 1024
+
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// Recorder records the hits and misses of a cache, e.g. as metrics.
+type Recorder interface {
+	CacheHit(ctx context.Context, resourceType string)
+	CacheMiss(ctx context.Context, resourceType string)
+}
+
+// entry is a cached response.
+type entry struct {
+	key    string
+	path   string
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// Cache is a LRU cache of responses bounded by the number of entries and their total body size.
+// It is safe for concurrent use.
+type Cache struct {
+	lock       sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	entries    map[string]*list.Element
+	lru        *list.List
+}
+
+// NewCache creates a cache. Non-positive limits are replaced by the defaults.
+func NewCache(maxEntries int, maxBytes int64) *Cache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &Cache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Len returns the number of cached responses.
+func (c *Cache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.lru.Len()
+}
+
+func (c *Cache) get(key string) *entry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*entry)
+}
+
+func (c *Cache) set(e *entry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if elem, ok := c.entries[e.key]; ok {
+		c.removeElement(elem)
+	}
+	if int64(len(e.body)) > c.maxBytes {
+		return
+	}
+	c.entries[e.key] = c.lru.PushFront(e)
+	c.bytes += int64(len(e.body))
+	for c.lru.Len() > c.maxEntries || c.bytes > c.maxBytes {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// invalidate removes the responses of the resource at the path, of its parents, whose responses may
+// embed it, and of its children.
+func (c *Cache) invalidate(path string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		cached := elem.Value.(*entry).path
+		if cached == path || strings.HasPrefix(cached, path+"/") || strings.HasPrefix(path, cached+"/") {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+}
+
+func (c *Cache) removeElement(elem *list.Element) {
+	e := c.lru.Remove(elem).(*entry)
+	delete(c.entries, e.key)
+	c.bytes -= int64(len(e.body))
+}
+
+// Policy revalidates the cached GET responses with If-None-Match and returns the cached body when ARM
+// replies 304 Not Modified. The writes through the policy invalidate the cached responses of the resource.
+type Policy struct {
+	cache    *Cache
+	recorder Recorder
+}
+
+// NewPolicy creates a policy using the cache, which may be shared by several clients so that the writes
+// of a client invalidate the responses cached by the others. The recorder may be nil.
+func NewPolicy(cache *Cache, recorder Recorder) *Policy {
+	return &Policy{cache: cache, recorder: recorder}
+}
+
+// Do implements policy.Policy.
+func (p *Policy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	path := strings.ToLower(strings.TrimSuffix(raw.URL.Path, "/"))
+	if raw.Method != http.MethodGet {
+		resp, err := req.Next()
+		if raw.Method != http.MethodHead {
+			// writes invalidate the cache even when they fail, the resource may have been partially updated
+			p.cache.invalidate(path)
+		}
+		return resp, err
+	}
+
+	key := raw.URL.String()
+	cached := p.cache.get(key)
+	if cached != nil && raw.Header.Get(headerIfNoneMatch) == "" {
+		raw.Header.Set(headerIfNoneMatch, cached.etag)
+	}
+	resp, err := req.Next()
+	if err != nil {
+		return resp, err
+	}
+	resourceType := resourceTypeOf(raw.URL.Path)
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		if p.recorder != nil {
+			p.recorder.CacheHit(raw.Context(), resourceType)
+		}
+		_ = resp.Body.Close()
+		return cached.response(raw), nil
+	}
+	if p.recorder != nil {
+		p.recorder.CacheMiss(raw.Context(), resourceType)
+	}
+	if resp.StatusCode != http.StatusOK {
+		p.cache.remove(key)
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if etag := etagOf(resp.Header, body); etag != "" {
+		p.cache.set(&entry{key: key, path: path, etag: etag, header: resp.Header.Clone(), body: body})
+	} else {
+		p.cache.remove(key)
+	}
+	return resp, nil
+}
+
+// response returns a 200 response with the cached body.
+func (e *entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// etagOf returns the ETag header of the response, or the etag property of the resource in the body.
+func etagOf(header http.Header, body []byte) string {
+	if etag := header.Get(headerETag); etag != "" {
+		return etag
+	}
+	resource := struct {
+		ETag string `json:"etag"`
+	}{}
+	if err := json.Unmarshal(body, &resource); err != nil {
+		return ""
+	}
+	return resource.ETag
+}
+
+func resourceTypeOf(path string) string {
+	id, err := arm.ParseResourceID(path)
+	if err != nil {
+		return ""
+	}
+	return id.ResourceType.String()
+}
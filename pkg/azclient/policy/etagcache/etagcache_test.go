@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etagcache_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/armfake"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/etagcache"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
+)
+
+const (
+	subscriptionID = "00000000-0000-0000-0000-000000000000"
+	lbPath         = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/"
+)
+
+type fakeRecorder struct {
+	lock   sync.Mutex
+	hits   map[string]int
+	misses map[string]int
+}
+
+func (r *fakeRecorder) CacheHit(_ context.Context, resourceType string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.hits[resourceType]++
+}
+
+func (r *fakeRecorder) CacheMiss(_ context.Context, resourceType string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.misses[resourceType]++
+}
+
+func (r *fakeRecorder) counts(resourceType string) (int, int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.hits[resourceType], r.misses[resourceType]
+}
+
+// getRequest sends a GET through the cache to a backend always replying the etag of the path, and
+// returns the response body and the If-None-Match header the backend received.
+func getRequest(cache *etagcache.Cache, recorder etagcache.Recorder, path string) (string, string) {
+	var ifNoneMatch string
+	pipeline := runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerCallPolicies: []policy.Policy{
+			etagcache.NewPolicy(cache, recorder),
+			utils.FuncPolicyWrapper(func(req *policy.Request) (*http.Response, error) {
+				ifNoneMatch = req.Raw().Header.Get("If-None-Match")
+				if ifNoneMatch == path {
+					return &http.Response{StatusCode: http.StatusNotModified, Header: http.Header{}, Body: http.NoBody}, nil
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Etag": []string{path}},
+					Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"id":%q}`, path))),
+				}, nil
+			}),
+		},
+	})
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, "https://management.azure.com"+path)
+	Expect(err).NotTo(HaveOccurred())
+	resp, err := pipeline.Do(req)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	body, err := io.ReadAll(resp.Body)
+	Expect(err).NotTo(HaveOccurred())
+	return string(body), ifNoneMatch
+}
+
+var _ = Describe("ETagCache", func() {
+	var recorder *fakeRecorder
+
+	BeforeEach(func() {
+		recorder = &fakeRecorder{hits: map[string]int{}, misses: map[string]int{}}
+	})
+
+	It("should revalidate the cached responses and serve them on 304", func() {
+		cache := etagcache.NewCache(0, 0)
+		body, ifNoneMatch := getRequest(cache, recorder, lbPath+"lb")
+		Expect(ifNoneMatch).To(BeEmpty())
+		Expect(body).To(Equal(`{"id":"` + lbPath + `lb"}`))
+
+		body, ifNoneMatch = getRequest(cache, recorder, lbPath+"lb")
+		Expect(ifNoneMatch).To(Equal(lbPath + "lb"))
+		Expect(body).To(Equal(`{"id":"` + lbPath + `lb"}`))
+
+		hits, misses := recorder.counts("Microsoft.Network/loadBalancers")
+		Expect(hits).To(Equal(1))
+		Expect(misses).To(Equal(1))
+	})
+
+	It("should evict the least recently used responses", func() {
+		cache := etagcache.NewCache(2, 0)
+		getRequest(cache, recorder, lbPath+"lb1")
+		getRequest(cache, recorder, lbPath+"lb2")
+		getRequest(cache, recorder, lbPath+"lb1")
+		getRequest(cache, recorder, lbPath+"lb3")
+		Expect(cache.Len()).To(Equal(2))
+
+		_, ifNoneMatch := getRequest(cache, recorder, lbPath+"lb1")
+		Expect(ifNoneMatch).NotTo(BeEmpty())
+		_, ifNoneMatch = getRequest(cache, recorder, lbPath+"lb2")
+		Expect(ifNoneMatch).To(BeEmpty())
+	})
+
+	It("should bound the size of the cached bodies", func() {
+		cache := etagcache.NewCache(0, int64(len(`{"id":"`+lbPath+`lb1"}`)))
+		getRequest(cache, recorder, lbPath+"lb1")
+		Expect(cache.Len()).To(Equal(1))
+		getRequest(cache, recorder, lbPath+"lb2")
+		Expect(cache.Len()).To(Equal(1))
+		// the responses larger than the bound are not cached
+		getRequest(cache, recorder, lbPath+"longer-name")
+		_, ifNoneMatch := getRequest(cache, recorder, lbPath+"longer-name")
+		Expect(ifNoneMatch).To(BeEmpty())
+		Expect(cache.Len()).To(Equal(1))
+	})
+
+	Describe("client factory", func() {
+		var (
+			server  *armfake.Server
+			factory azclient.ClientFactory
+			ctx     context.Context
+		)
+
+		getCount := func() int {
+			count := 0
+			for _, req := range server.Requests() {
+				if req.Method == http.MethodGet {
+					count++
+				}
+			}
+			return count
+		}
+
+		BeforeEach(func() {
+			var err error
+			ctx = context.Background()
+			server = armfake.NewServer()
+			server.EnsureResourceGroup(subscriptionID, "rg", "eastus")
+			factory, err = armfake.NewClientFactory(server, &azclient.ClientFactoryConfig{SubscriptionID: subscriptionID, EnableResponseCache: true, ResponseCacheRecorder: recorder})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = factory.GetVirtualNetworkClient().CreateOrUpdate(ctx, "rg", "vnet", armnetwork.VirtualNetwork{Location: to.Ptr("eastus")})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should serve unchanged resources from the cache", func() {
+			vnet, err := factory.GetVirtualNetworkClient().Get(ctx, "rg", "vnet", nil)
+			Expect(err).NotTo(HaveOccurred())
+			cached, err := factory.GetVirtualNetworkClient().Get(ctx, "rg", "vnet", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cached).To(Equal(vnet))
+			Expect(getCount()).To(Equal(2))
+			hits, _ := recorder.counts("Microsoft.Network/virtualNetworks")
+			Expect(hits).To(Equal(1))
+		})
+
+		It("should not share the cache between factories", func() {
+			_, err := factory.GetVirtualNetworkClient().Get(ctx, "rg", "vnet", nil)
+			Expect(err).NotTo(HaveOccurred())
+			other, err := armfake.NewClientFactory(server, &azclient.ClientFactoryConfig{SubscriptionID: subscriptionID, EnableResponseCache: true, ResponseCacheRecorder: recorder})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = other.GetVirtualNetworkClient().Get(ctx, "rg", "vnet", nil)
+			Expect(err).NotTo(HaveOccurred())
+			hits, misses := recorder.counts("Microsoft.Network/virtualNetworks")
+			Expect(hits).To(Equal(0))
+			Expect(misses).To(Equal(2))
+		})
+
+		It("should invalidate the cached resources on writes through the factory", func() {
+			_, err := factory.GetVirtualNetworkClient().Get(ctx, "rg", "vnet", nil)
+			Expect(err).NotTo(HaveOccurred())
+			// a write of a child resource invalidates its parent
+			_, err = factory.GetSubnetClient().CreateOrUpdate(ctx, "rg", "vnet", "subnet", armnetwork.Subnet{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = factory.GetVirtualNetworkClient().Get(ctx, "rg", "vnet", nil)
+			Expect(err).NotTo(HaveOccurred())
+			hits, _ := recorder.counts("Microsoft.Network/virtualNetworks")
+			Expect(hits).To(Equal(0))
+
+			Expect(factory.GetVirtualNetworkClient().Delete(ctx, "rg", "vnet")).To(Succeed())
+			_, err = factory.GetVirtualNetworkClient().Get(ctx, "rg", "vnet", nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
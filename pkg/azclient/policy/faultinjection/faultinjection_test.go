@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/armfake"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/faultinjection"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/retryrepectthrottled"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
+)
+
+const lbPath = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"
+
+func newPipeline(policies ...policy.Policy) runtime.Pipeline {
+	return runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerCallPolicies: append(policies, utils.FuncPolicyWrapper(
+			func(_ *policy.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+			},
+		)),
+	})
+}
+
+func do(ctx context.Context, pipeline runtime.Pipeline, method, path string) (*http.Response, error) {
+	req, err := runtime.NewRequest(ctx, method, "https://management.azure.com"+path)
+	Expect(err).NotTo(HaveOccurred())
+	return pipeline.Do(req)
+}
+
+var _ = Describe("FaultInjection", func() {
+	It("should inject faults into the matching requests", func() {
+		p, err := faultinjection.NewPolicy(&faultinjection.Config{
+			Faults: []faultinjection.Fault{{
+				Methods:           []string{http.MethodPut},
+				ResourceTypes:     []string{"microsoft.network/loadbalancers"},
+				StatusCode:        http.StatusTooManyRequests,
+				ErrorCode:         "SubscriptionRequestsThrottled",
+				RetryAfterSeconds: 10,
+			}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		pipeline := newPipeline(p)
+
+		resp, err := do(context.Background(), pipeline, http.MethodPut, lbPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+		Expect(resp.Header.Get("Retry-After")).To(Equal("10"))
+		respErr := &azcore.ResponseError{}
+		Expect(errors.As(runtime.NewResponseError(resp), &respErr)).To(BeTrue())
+		Expect(respErr.ErrorCode).To(Equal("SubscriptionRequestsThrottled"))
+
+		resp, err = do(context.Background(), pipeline, http.MethodGet, lbPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp, err = do(context.Background(), pipeline, http.MethodPut, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("should inject the faults the configured number of times", func() {
+		p, err := faultinjection.NewPolicy(&faultinjection.Config{
+			Faults: []faultinjection.Fault{{StatusCode: http.StatusPreconditionFailed, ErrorCode: "PreconditionFailed", Count: 2}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		pipeline := newPipeline(p)
+		statusCodes := []int{}
+		for i := 0; i < 3; i++ {
+			resp, err := do(context.Background(), pipeline, http.MethodPut, lbPath)
+			Expect(err).NotTo(HaveOccurred())
+			statusCodes = append(statusCodes, resp.StatusCode)
+		}
+		Expect(statusCodes).To(Equal([]int{http.StatusPreconditionFailed, http.StatusPreconditionFailed, http.StatusOK}))
+	})
+
+	It("should inject the faults deterministically for a seed", func() {
+		inject := func() []int {
+			p, err := faultinjection.NewPolicy(&faultinjection.Config{
+				Seed:   42,
+				Faults: []faultinjection.Fault{{StatusCode: http.StatusInternalServerError, Probability: to.Ptr(0.5)}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			pipeline := newPipeline(p)
+			statusCodes := []int{}
+			for i := 0; i < 50; i++ {
+				resp, err := do(context.Background(), pipeline, http.MethodGet, lbPath)
+				Expect(err).NotTo(HaveOccurred())
+				statusCodes = append(statusCodes, resp.StatusCode)
+			}
+			return statusCodes
+		}
+		statusCodes := inject()
+		Expect(statusCodes).To(ContainElement(http.StatusOK))
+		Expect(statusCodes).To(ContainElement(http.StatusInternalServerError))
+		Expect(inject()).To(Equal(statusCodes))
+	})
+
+	It("should delay the requests until the context is done", func() {
+		p, err := faultinjection.NewPolicy(&faultinjection.Config{
+			Faults: []faultinjection.Fault{{Delay: "1h"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = do(ctx, newPipeline(p), http.MethodGet, lbPath)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should let the throttling policy handle the injected 429", func() {
+		p, err := faultinjection.NewPolicy(&faultinjection.Config{
+			Faults: []faultinjection.Fault{{StatusCode: http.StatusTooManyRequests, RetryAfterSeconds: 60, Count: 1}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		pipeline := newPipeline(retryrepectthrottled.NewThrottlingPolicy(), p)
+		_, err = do(context.Background(), pipeline, http.MethodGet, lbPath)
+		Expect(err).To(MatchError(ContainSubstring("Too many requests")))
+		_, err = do(context.Background(), pipeline, http.MethodGet, lbPath)
+		Expect(err).To(MatchError(ContainSubstring("Too many requests")))
+	})
+
+	It("should reject invalid configs", func() {
+		_, err := faultinjection.NewPolicy(&faultinjection.Config{Faults: []faultinjection.Fault{{StatusCode: 500, Probability: to.Ptr(2.0)}}})
+		Expect(err).To(HaveOccurred())
+		_, err = faultinjection.NewPolicy(&faultinjection.Config{Faults: []faultinjection.Fault{{Delay: "soon"}}})
+		Expect(err).To(HaveOccurred())
+		_, err = faultinjection.NewPolicy(&faultinjection.Config{Faults: []faultinjection.Fault{{Methods: []string{http.MethodGet}}}})
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("ResourceType", func() {
+		It("should return the type of the resource", func() {
+			Expect(faultinjection.ResourceType(lbPath)).To(Equal("Microsoft.Network/loadBalancers"))
+			Expect(faultinjection.ResourceType(lbPath + "/backendAddressPools/pool")).To(Equal("Microsoft.Network/loadBalancers/backendAddressPools"))
+			Expect(faultinjection.ResourceType("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers")).To(Equal("Microsoft.Network/loadBalancers"))
+			Expect(faultinjection.ResourceType("/subscriptions/sub/providers/Microsoft.Network/locations/eastus/operations/op")).To(Equal("Microsoft.Network/locations/operations"))
+			Expect(faultinjection.ResourceType("/subscriptions/sub/resourceGroups/rg")).To(Equal("Microsoft.Resources/resourceGroups"))
+		})
+	})
+
+	Describe("client factory", func() {
+		It("should inject the faults of the config file into the clients", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "faults.json")
+			Expect(os.WriteFile(path, []byte(`{"faults":[{"methods":["PUT"],"resourceTypes":["Microsoft.Network/routeTables"],"statusCode":400,"errorCode":"ReferencedResourceNotProvisioned","count":1}]}`), 0600)).To(Succeed())
+			server := armfake.NewServer()
+			server.EnsureResourceGroup("sub", "rg", "eastus")
+
+			factory, err := armfake.NewClientFactory(server, &azclient.ClientFactoryConfig{SubscriptionID: "sub", FaultInjectionConfigFile: path})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = factory.GetRouteTableClient().CreateOrUpdate(context.Background(), "rg", "rt", armnetwork.RouteTable{Location: to.Ptr("eastus")})
+			Expect(err).To(MatchError(ContainSubstring("ReferencedResourceNotProvisioned")))
+
+			// the fault is injected once and shared by the clients of the config file
+			_, err = factory.GetRouteTableClient().CreateOrUpdate(context.Background(), "rg", "rt", armnetwork.RouteTable{Location: to.Ptr("eastus")})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail to create the factory with an invalid config file", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "faults.json")
+			Expect(os.WriteFile(path, []byte(`{"faults":[{"statusCode":1000}]}`), 0600)).To(Succeed())
+			_, err := armfake.NewClientFactory(armfake.NewServer(), &azclient.ClientFactoryConfig{SubscriptionID: "sub", FaultInjectionConfigFile: path})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
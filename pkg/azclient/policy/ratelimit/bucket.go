@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	operationRead  = "read"
+	operationWrite = "write"
+
+	// minAdaptiveRatio is the lowest fraction of the configured QPS the adaptive rate limit goes down to,
+	// so that the bucket recovers once ARM refills the remaining requests.
+	minAdaptiveRatio = 0.1
+)
+
+// bucketKey identifies a bucket. The configured QPS and burst are part of the key so that clients
+// with different configs do not share their buckets.
+type bucketKey struct {
+	scope            string
+	subscriptionID   string
+	resourceProvider string
+	operation        string
+	qps              float32
+	burst            int
+}
+
+// bucket is a token bucket whose QPS is lowered by the adaptive rate limit.
+type bucket struct {
+	key     bucketKey
+	limiter *rate.Limiter
+}
+
+// adapt scales the QPS down proportionally to the remaining requests when they are below the
+// threshold, and restores the configured QPS otherwise.
+func (b *bucket) adapt(remaining, threshold int64) {
+	qps := float64(b.key.qps)
+	if remaining < threshold {
+		qps = qps * float64(remaining) / float64(threshold)
+		if minQPS := float64(b.key.qps) * minAdaptiveRatio; qps < minQPS {
+			qps = minQPS
+		}
+	}
+	if rate.Limit(qps) != b.limiter.Limit() {
+		b.limiter.SetLimit(rate.Limit(qps))
+		defaultMetrics.adapted(b, qps)
+	}
+}
+
+// Buckets holds the rate limit buckets shared by the policies created with it, e.g. the policies of
+// the clients of a client factory. The buckets are released with it.
+type Buckets struct {
+	lock    sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+// NewBuckets returns an empty set of buckets.
+func NewBuckets() *Buckets {
+	return &Buckets{buckets: make(map[bucketKey]*bucket)}
+}
+
+// get returns the bucket of the key, creating it if it does not exist.
+func (bs *Buckets) get(k bucketKey) *bucket {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+	b, ok := bs.buckets[k]
+	if !ok {
+		b = &bucket{key: k, limiter: rate.NewLimiter(rate.Limit(k.qps), k.burst)}
+		bs.buckets[k] = b
+	}
+	return b
+}
+
+// parseScope returns the subscription ID and the resource provider namespace of an ARM request path,
+// e.g. /subscriptions/{subscriptionID}/resourceGroups/{rg}/providers/Microsoft.Network/loadBalancers/{name}.
+// The namespace of the last providers segment is returned for extension resources.
+func parseScope(path string) (subscriptionID, resourceProvider string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		switch {
+		case strings.EqualFold(segments[i], "subscriptions") && subscriptionID == "":
+			subscriptionID = strings.ToLower(segments[i+1])
+			i++
+		case strings.EqualFold(segments[i], "providers"):
+			resourceProvider = strings.ToLower(segments[i+1])
+			i++
+		}
+	}
+	return subscriptionID, resourceProvider
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var defaultMetrics = newMetrics()
+
+// metrics exports the state of the rate limit buckets. The instruments are no-op until a meter provider is set.
+type metrics struct {
+	waitDuration  metric.Float64Histogram
+	rejectedCount metric.Int64Counter
+	adaptedQPS    metric.Float64Histogram
+}
+
+func newMetrics() *metrics {
+	meter := otel.Meter("cloud-provider-azclient")
+	m := &metrics{}
+	var err error
+	if m.waitDuration, err = meter.Float64Histogram(
+		"ratelimit_wait_duration_seconds",
+		metric.WithDescription("Time Azure API calls waited for a rate limit token"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(.001, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.rejectedCount, err = meter.Int64Counter(
+		"ratelimit_rejected_count",
+		metric.WithDescription("Number of Azure API calls rejected by the rate limit"),
+		metric.WithUnit("{call}"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.adaptedQPS, err = meter.Float64Histogram(
+		"ratelimit_adapted_qps",
+		metric.WithDescription("QPS the adaptive rate limit set on the rate limit buckets"),
+		metric.WithUnit("{call}/s"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	return m
+}
+
+func (m *metrics) waited(ctx context.Context, b *bucket, d time.Duration) {
+	if m.waitDuration != nil {
+		m.waitDuration.Record(ctx, d.Seconds(), metric.WithAttributes(bucketAttributes(b)...))
+	}
+}
+
+func (m *metrics) rejected(ctx context.Context, b *bucket) {
+	if m.rejectedCount != nil {
+		m.rejectedCount.Add(ctx, 1, metric.WithAttributes(bucketAttributes(b)...))
+	}
+}
+
+func (m *metrics) adapted(b *bucket, qps float64) {
+	if m.adaptedQPS != nil {
+		m.adaptedQPS.Record(context.Background(), qps, metric.WithAttributes(bucketAttributes(b)...))
+	}
+}
+
+func bucketAttributes(b *bucket) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("scope", b.key.scope),
+		attribute.String("subscription_id", b.key.subscriptionID),
+		attribute.String("resource_provider", b.key.resourceProvider),
+		attribute.String("operation", b.key.operation),
+	}
+}
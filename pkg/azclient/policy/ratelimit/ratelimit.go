@@ -18,11 +18,29 @@ package ratelimit
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
 
-	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit/flowcontrol"
+const (
+	// ScopeClient uses a single bucket for all the requests of a client. It is the default scope.
+	ScopeClient = "client"
+	// ScopeSubscription uses a bucket per subscription, shared by the clients of a client factory with the same
+	// rate limit config.
+	ScopeSubscription = "subscription"
+	// ScopeResourceProvider uses a bucket per subscription and resource provider namespace, e.g. Microsoft.Network,
+	// shared by the clients of a client factory with the same rate limit config.
+	ScopeResourceProvider = "resourceProvider"
+
+	// HeaderRemainingReads and HeaderRemainingWrites are the remaining requests ARM allows in the subscription.
+	HeaderRemainingReads  = "X-Ms-Ratelimit-Remaining-Subscription-Reads"
+	HeaderRemainingWrites = "X-Ms-Ratelimit-Remaining-Subscription-Writes"
+
+	defaultAdaptiveThreshold = 100
 )
 
 // Config indicates the rate limit config options.
@@ -37,41 +55,119 @@ type Config struct {
 	CloudProviderRateLimitQPSWrite float32 `json:"cloudProviderRateLimitQPSWrite,omitempty" yaml:"cloudProviderRateLimitQPSWrite,omitempty"`
 	// Rate limit Bucket Size
 	CloudProviderRateLimitBucketWrite int `json:"cloudProviderRateLimitBucketWrite,omitempty" yaml:"cloudProviderRateLimitBucketWrite,omitempty"`
+	// Wait for a token until the request context is done instead of failing the request when the rate limit is reached
+	CloudProviderRateLimitWait bool `json:"cloudProviderRateLimitWait,omitempty" yaml:"cloudProviderRateLimitWait,omitempty"`
+	// Scope of the rate limit buckets: client (default), subscription or resourceProvider. Unknown scopes fall back to client.
+	CloudProviderRateLimitScope string `json:"cloudProviderRateLimitScope,omitempty" yaml:"cloudProviderRateLimitScope,omitempty"`
+	// Adjust the QPS to the x-ms-ratelimit-remaining-subscription-reads/writes headers returned by ARM
+	CloudProviderRateLimitAdaptive bool `json:"cloudProviderRateLimitAdaptive,omitempty" yaml:"cloudProviderRateLimitAdaptive,omitempty"`
+	// Remaining requests below which the adaptive rate limit lowers the QPS proportionally. Defaults to 100.
+	CloudProviderRateLimitAdaptiveThreshold int `json:"cloudProviderRateLimitAdaptiveThreshold,omitempty" yaml:"cloudProviderRateLimitAdaptiveThreshold,omitempty"`
 }
 
 func NewRateLimitPolicy(config *Config) policy.Policy {
+	return NewSharedRateLimitPolicy(config, nil)
+}
+
+// NewSharedRateLimitPolicy returns a rate limit policy sharing the buckets of the subscription and resource provider
+// scopes with the other policies created with the same buckets. A nil shared set of buckets shares nothing.
+func NewSharedRateLimitPolicy(config *Config, shared *Buckets) policy.Policy {
 	if config != nil && config.CloudProviderRateLimit {
-		readLimiter := flowcontrol.NewTokenBucketRateLimiter(
-			config.CloudProviderRateLimitQPS,
-			config.CloudProviderRateLimitBucket)
-
-		writeLimiter := flowcontrol.NewTokenBucketRateLimiter(
-			config.CloudProviderRateLimitQPSWrite,
-			config.CloudProviderRateLimitBucketWrite)
-		return &Policy{
-			rateLimiterReader: readLimiter,
-			rateLimiterWriter: writeLimiter,
+		scope := config.CloudProviderRateLimitScope
+		if scope != ScopeSubscription && scope != ScopeResourceProvider {
+			scope = ScopeClient
+		}
+		threshold := config.CloudProviderRateLimitAdaptiveThreshold
+		if threshold == 0 {
+			threshold = defaultAdaptiveThreshold
+		}
+		p := &Policy{
+			scope:             scope,
+			wait:              config.CloudProviderRateLimitWait,
+			adaptive:          config.CloudProviderRateLimitAdaptive,
+			adaptiveThreshold: int64(threshold),
+			readQPS:           config.CloudProviderRateLimitQPS,
+			readBurst:         config.CloudProviderRateLimitBucket,
+			writeQPS:          config.CloudProviderRateLimitQPSWrite,
+			writeBurst:        config.CloudProviderRateLimitBucketWrite,
 		}
+		p.buckets = shared
+		if scope == ScopeClient || shared == nil {
+			// the buckets of a client are not shared with the other clients
+			p.buckets = NewBuckets()
+		}
+		return p
 	}
 	return nil
 }
 
 type Policy struct {
-	rateLimiterWriter flowcontrol.RateLimiter
-	rateLimiterReader flowcontrol.RateLimiter
+	scope string
+	// buckets holds the buckets of the policy, shared with other policies in the subscription and resource provider scopes
+	buckets           *Buckets
+	wait              bool
+	adaptive          bool
+	adaptiveThreshold int64
+
+	readQPS    float32
+	readBurst  int
+	writeQPS   float32
+	writeBurst int
+}
+
+func (f *Policy) Do(req *policy.Request) (*http.Response, error) {
+	isRead := req.Raw().Method == http.MethodGet || req.Raw().Method == http.MethodHead
+	b := f.bucketFor(req.Raw().URL.Path, isRead)
+	if err := f.accept(req, b); err != nil {
+		return nil, err
+	}
+	resp, err := req.Next()
+	if err != nil || !f.adaptive {
+		return resp, err
+	}
+	header := HeaderRemainingWrites
+	if isRead {
+		header = HeaderRemainingReads
+	}
+	if remaining, parseErr := strconv.ParseInt(resp.Header.Get(header), 10, 64); parseErr == nil {
+		b.adapt(remaining, f.adaptiveThreshold)
+	}
+	return resp, nil
 }
 
-func (f Policy) Do(req *policy.Request) (*http.Response, error) {
-	if req.Raw().Method == http.MethodGet || req.Raw().Method == http.MethodHead {
-		if !f.rateLimiterReader.TryAccept() {
-			return nil, errors.New("rate limit reached")
+// accept takes a token from the bucket, waiting for it until the request context is done in the waiting mode.
+func (f *Policy) accept(req *policy.Request, b *bucket) error {
+	ctx := req.Raw().Context()
+	if !f.wait {
+		if !b.limiter.Allow() {
+			defaultMetrics.rejected(ctx, b)
+			return errors.New("rate limit reached")
 		}
-	} else {
-		if !f.rateLimiterWriter.TryAccept() {
-			return nil, errors.New("rate limit reached")
+		return nil
+	}
+	start := time.Now()
+	if err := b.limiter.Wait(ctx); err != nil {
+		defaultMetrics.rejected(ctx, b)
+		return fmt.Errorf("rate limit reached: %w", err)
+	}
+	defaultMetrics.waited(ctx, b, time.Since(start))
+	return nil
+}
+
+// bucketFor returns the bucket of the request in the scope of the policy.
+func (f *Policy) bucketFor(path string, isRead bool) *bucket {
+	operation, qps, burst := operationWrite, f.writeQPS, f.writeBurst
+	if isRead {
+		operation, qps, burst = operationRead, f.readQPS, f.readBurst
+	}
+	k := bucketKey{scope: f.scope, operation: operation, qps: qps, burst: burst}
+	if f.scope != ScopeClient {
+		k.subscriptionID, k.resourceProvider = parseScope(path)
+		if f.scope == ScopeSubscription {
+			k.resourceProvider = ""
 		}
 	}
-	return req.Next()
+	return f.buckets.get(k)
 }
 
 // CloudProviderRateLimitConfig indicates the rate limit config for each clients.
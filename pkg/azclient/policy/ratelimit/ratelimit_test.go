@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/ratelimit"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
+)
+
+func newPipeline(config *ratelimit.Config, header http.Header) runtime.Pipeline {
+	return newSharedPipeline(config, nil, header)
+}
+
+func newSharedPipeline(config *ratelimit.Config, shared *ratelimit.Buckets, header http.Header) runtime.Pipeline {
+	return runtime.NewPipeline("testmodule", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerCallPolicies: []policy.Policy{
+			ratelimit.NewSharedRateLimitPolicy(config, shared),
+			utils.FuncPolicyWrapper(
+				func(_ *policy.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       http.NoBody,
+						Header:     header,
+					}, nil
+				},
+			),
+		},
+	})
+}
+
+func do(ctx context.Context, pipeline runtime.Pipeline, method, path string) error {
+	req, err := runtime.NewRequest(ctx, method, "https://management.azure.com"+path)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = pipeline.Do(req)
+	return err
+}
+
+var _ = Describe("RateLimit", func() {
+	var subscriptionID string
+
+	BeforeEach(func() {
+		subscriptionID = uuid.NewString()
+	})
+
+	It("should return nil when rate limiting is disabled", func() {
+		Expect(ratelimit.NewRateLimitPolicy(nil)).To(BeNil())
+		Expect(ratelimit.NewRateLimitPolicy(&ratelimit.Config{})).To(BeNil())
+	})
+
+	It("should fail the requests when the rate limit is reached", func() {
+		pipeline := newPipeline(&ratelimit.Config{
+			CloudProviderRateLimit:            true,
+			CloudProviderRateLimitQPS:         0.001,
+			CloudProviderRateLimitBucket:      1,
+			CloudProviderRateLimitQPSWrite:    0.001,
+			CloudProviderRateLimitBucketWrite: 1,
+		}, nil)
+		Expect(do(context.Background(), pipeline, http.MethodGet, "/subscriptions/"+subscriptionID)).To(Succeed())
+		Expect(do(context.Background(), pipeline, http.MethodGet, "/subscriptions/"+subscriptionID)).To(MatchError("rate limit reached"))
+		// reads and writes use different buckets
+		Expect(do(context.Background(), pipeline, http.MethodPut, "/subscriptions/"+subscriptionID)).To(Succeed())
+	})
+
+	Describe("waiting mode", func() {
+		It("should wait for a token", func() {
+			pipeline := newPipeline(&ratelimit.Config{
+				CloudProviderRateLimit:       true,
+				CloudProviderRateLimitQPS:    20,
+				CloudProviderRateLimitBucket: 1,
+				CloudProviderRateLimitWait:   true,
+			}, nil)
+			start := time.Now()
+			for i := 0; i < 3; i++ {
+				Expect(do(context.Background(), pipeline, http.MethodGet, "/subscriptions/"+subscriptionID)).To(Succeed())
+			}
+			Expect(time.Since(start)).To(BeNumerically(">=", 90*time.Millisecond))
+		})
+
+		It("should fail when the token is not available before the context deadline", func() {
+			pipeline := newPipeline(&ratelimit.Config{
+				CloudProviderRateLimit:       true,
+				CloudProviderRateLimitQPS:    0.001,
+				CloudProviderRateLimitBucket: 1,
+				CloudProviderRateLimitWait:   true,
+			}, nil)
+			Expect(do(context.Background(), pipeline, http.MethodGet, "/subscriptions/"+subscriptionID)).To(Succeed())
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			start := time.Now()
+			err := do(ctx, pipeline, http.MethodGet, "/subscriptions/"+subscriptionID)
+			Expect(err).To(MatchError(ContainSubstring("rate limit reached")))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+	})
+
+	Describe("scopes", func() {
+		config := func(scope string) *ratelimit.Config {
+			return &ratelimit.Config{
+				CloudProviderRateLimit:       true,
+				CloudProviderRateLimitQPS:    0.001,
+				CloudProviderRateLimitBucket: 1,
+				CloudProviderRateLimitScope:  scope,
+			}
+		}
+
+		It("should not share the buckets of the clients by default", func() {
+			path := "/subscriptions/" + subscriptionID + "/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"
+			shared := ratelimit.NewBuckets()
+			Expect(do(context.Background(), newSharedPipeline(config(""), shared, nil), http.MethodGet, path)).To(Succeed())
+			Expect(do(context.Background(), newSharedPipeline(config(""), shared, nil), http.MethodGet, path)).To(Succeed())
+		})
+
+		It("should not share the buckets of different bucket sets", func() {
+			path := "/subscriptions/" + subscriptionID + "/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"
+			Expect(do(context.Background(), newSharedPipeline(config(ratelimit.ScopeSubscription), ratelimit.NewBuckets(), nil), http.MethodGet, path)).To(Succeed())
+			Expect(do(context.Background(), newSharedPipeline(config(ratelimit.ScopeSubscription), ratelimit.NewBuckets(), nil), http.MethodGet, path)).To(Succeed())
+			Expect(do(context.Background(), newPipeline(config(ratelimit.ScopeSubscription), nil), http.MethodGet, path)).To(Succeed())
+		})
+
+		It("should share a bucket per subscription", func() {
+			shared := ratelimit.NewBuckets()
+			first, second := newSharedPipeline(config(ratelimit.ScopeSubscription), shared, nil), newSharedPipeline(config(ratelimit.ScopeSubscription), shared, nil)
+			Expect(do(context.Background(), first, http.MethodGet, "/subscriptions/"+subscriptionID+"/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb")).To(Succeed())
+			Expect(do(context.Background(), second, http.MethodGet, "/subscriptions/"+subscriptionID+"/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm")).To(MatchError("rate limit reached"))
+			Expect(do(context.Background(), second, http.MethodGet, "/subscriptions/"+uuid.NewString()+"/resourceGroups/rg")).To(Succeed())
+		})
+
+		It("should share a bucket per subscription and resource provider", func() {
+			shared := ratelimit.NewBuckets()
+			first, second := newSharedPipeline(config(ratelimit.ScopeResourceProvider), shared, nil), newSharedPipeline(config(ratelimit.ScopeResourceProvider), shared, nil)
+			Expect(do(context.Background(), first, http.MethodGet, "/subscriptions/"+subscriptionID+"/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb")).To(Succeed())
+			Expect(do(context.Background(), second, http.MethodGet, "/subscriptions/"+subscriptionID+"/resourceGroups/rg/providers/microsoft.network/publicIPAddresses/pip")).To(MatchError("rate limit reached"))
+			Expect(do(context.Background(), second, http.MethodGet, "/subscriptions/"+subscriptionID+"/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm")).To(Succeed())
+		})
+	})
+
+	Describe("adaptive mode", func() {
+		It("should lower the QPS when ARM reports few remaining requests", func() {
+			header := http.Header{}
+			pipeline := newPipeline(&ratelimit.Config{
+				CloudProviderRateLimit:                  true,
+				CloudProviderRateLimitQPS:               10,
+				CloudProviderRateLimitBucket:            1,
+				CloudProviderRateLimitWait:              true,
+				CloudProviderRateLimitAdaptive:          true,
+				CloudProviderRateLimitAdaptiveThreshold: 100,
+			}, header)
+			path := "/subscriptions/" + subscriptionID
+
+			header.Set(ratelimit.HeaderRemainingReads, "1000")
+			Expect(do(context.Background(), pipeline, http.MethodGet, path)).To(Succeed())
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			// 10 QPS, the next token is available in 100ms
+			Expect(do(ctx, pipeline, http.MethodGet, path)).To(Succeed())
+
+			header.Set(ratelimit.HeaderRemainingReads, "1")
+			Expect(do(context.Background(), pipeline, http.MethodGet, path)).To(Succeed())
+			// lowered to the minimum of 1 QPS, the next token is not available before the deadline
+			ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			Expect(do(ctx, pipeline, http.MethodGet, path)).To(MatchError(ContainSubstring("rate limit reached")))
+		})
+	})
+})
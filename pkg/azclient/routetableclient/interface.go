@@ -23,10 +23,13 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
 )
 
-// +azure:client:verbs=get;createorupdate;delete;list,resource=RouteTable,packageName=github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4,packageAlias=armnetwork,clientName=RouteTablesClient,expand=false,rateLimitKey=routeTableRateLimit
+// +azure:client:verbs=get;createorupdate;delete;list;updatetags;begincreateorupdate;begindelete,resource=RouteTable,packageName=github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4,packageAlias=armnetwork,clientName=RouteTablesClient,expand=false,rateLimitKey=routeTableRateLimit
 type Interface interface {
 	utils.CreateOrUpdateFunc[armnetwork.RouteTable]
 	utils.DeleteFunc[armnetwork.RouteTable]
 	utils.ListFunc[armnetwork.RouteTable]
 	utils.GetFunc[armnetwork.RouteTable]
+	utils.UpdateTagsFunc[armnetwork.RouteTable]
+	utils.BeginCreateOrUpdateFunc[armnetwork.RouteTable, armnetwork.RouteTablesClientCreateOrUpdateResponse]
+	utils.BeginDeleteFunc[armnetwork.RouteTablesClientDeleteResponse]
 }
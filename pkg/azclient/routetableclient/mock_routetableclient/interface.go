@@ -31,6 +31,8 @@ import (
 
 	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	gomock "go.uber.org/mock/gomock"
+
+	utils "sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
 )
 
 // MockInterface is a mock of Interface interface.
@@ -56,6 +58,36 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// BeginCreateOrUpdate mocks base method.
+func (m *MockInterface) BeginCreateOrUpdate(ctx context.Context, resourceGroupName, resourceName string, resourceParam armnetwork.RouteTable) (*utils.PollerWrapper[armnetwork.RouteTablesClientCreateOrUpdateResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginCreateOrUpdate", ctx, resourceGroupName, resourceName, resourceParam)
+	ret0, _ := ret[0].(*utils.PollerWrapper[armnetwork.RouteTablesClientCreateOrUpdateResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginCreateOrUpdate indicates an expected call of BeginCreateOrUpdate.
+func (mr *MockInterfaceMockRecorder) BeginCreateOrUpdate(ctx, resourceGroupName, resourceName, resourceParam any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginCreateOrUpdate", reflect.TypeOf((*MockInterface)(nil).BeginCreateOrUpdate), ctx, resourceGroupName, resourceName, resourceParam)
+}
+
+// BeginDelete mocks base method.
+func (m *MockInterface) BeginDelete(ctx context.Context, resourceGroupName, resourceName string) (*utils.PollerWrapper[armnetwork.RouteTablesClientDeleteResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginDelete", ctx, resourceGroupName, resourceName)
+	ret0, _ := ret[0].(*utils.PollerWrapper[armnetwork.RouteTablesClientDeleteResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginDelete indicates an expected call of BeginDelete.
+func (mr *MockInterfaceMockRecorder) BeginDelete(ctx, resourceGroupName, resourceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginDelete", reflect.TypeOf((*MockInterface)(nil).BeginDelete), ctx, resourceGroupName, resourceName)
+}
+
 // CreateOrUpdate mocks base method.
 func (m *MockInterface) CreateOrUpdate(ctx context.Context, resourceGroupName, resourceName string, resourceParam armnetwork.RouteTable) (*armnetwork.RouteTable, error) {
 	m.ctrl.T.Helper()
@@ -114,3 +146,18 @@ func (mr *MockInterfaceMockRecorder) List(ctx, resourceGroupName any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInterface)(nil).List), ctx, resourceGroupName)
 }
+
+// UpdateTags mocks base method.
+func (m *MockInterface) UpdateTags(ctx context.Context, resourceGroupName, resourceName string, tags map[string]*string) (*armnetwork.RouteTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTags", ctx, resourceGroupName, resourceName, tags)
+	ret0, _ := ret[0].(*armnetwork.RouteTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTags indicates an expected call of UpdateTags.
+func (mr *MockInterfaceMockRecorder) UpdateTags(ctx, resourceGroupName, resourceName, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTags", reflect.TypeOf((*MockInterface)(nil).UpdateTags), ctx, resourceGroupName, resourceName, tags)
+}
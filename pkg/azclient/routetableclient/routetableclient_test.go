@@ -21,6 +21,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -73,6 +74,27 @@ var _ = Describe("RouteTablesClient", Ordered, func() {
 		})
 	})
 
+	When("non-blocking update requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			poller, err := realClient.BeginCreateOrUpdate(ctx, resourceGroupName, resourceName, *newResource)
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := poller.WaitforPollerResp(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).NotTo(BeNil())
+			Expect(poller.Done()).To(BeTrue())
+		})
+	})
+
+	When("tag update requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			newResource, err := realClient.UpdateTags(ctx, resourceGroupName, resourceName, map[string]*string{"updated": to.Ptr("true")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newResource).NotTo(BeNil())
+			Expect(newResource.Tags).To(HaveKey("updated"))
+			Expect(*newResource.Tags["updated"]).To(Equal("true"))
+		})
+	})
+
 	When("list requests are raised", func() {
 		It("should not return error", func(ctx context.Context) {
 			resourceList, err := realClient.List(ctx, resourceGroupName)
@@ -96,6 +118,15 @@ var _ = Describe("RouteTablesClient", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	When("non-blocking deletion requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			poller, err := realClient.BeginDelete(ctx, resourceGroupName, resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = poller.WaitforPollerResp(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 	if afterAllFunc != nil {
 		AfterAll(afterAllFunc)
 	}
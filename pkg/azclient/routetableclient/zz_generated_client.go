@@ -101,7 +101,7 @@ func (client *Client) Delete(ctx context.Context, resourceGroupName string, reso
 	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
 	ctx, endSpan := runtime.StartSpan(ctx, DeleteOperationName, client.tracer, nil)
 	defer endSpan(err)
-	_, err = utils.NewPollerWrapper(client.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
+	_, err = utils.NewPollerWrapper(client.RouteTablesClient.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
 	return err
 }
 
@@ -125,3 +125,54 @@ func (client *Client) List(ctx context.Context, resourceGroupName string) (resul
 	}
 	return result, nil
 }
+
+const UpdateTagsOperationName = "RouteTablesClient.UpdateTags"
+
+// UpdateTags replaces the tags of a RouteTable without sending the whole resource.
+func (client *Client) UpdateTags(ctx context.Context, resourceGroupName string, resourceName string, tags map[string]*string) (result *armnetwork.RouteTable, err error) {
+	ctx = utils.ContextWithClientName(ctx, "RouteTablesClient")
+	ctx = utils.ContextWithRequestMethod(ctx, "UpdateTags")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, UpdateTagsOperationName, client.tracer, nil)
+	defer endSpan(err)
+	resp, err := client.RouteTablesClient.UpdateTags(ctx, resourceGroupName, resourceName, armnetwork.TagsObject{Tags: tags}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.RouteTable, nil
+}
+
+const BeginCreateOrUpdateOperationName = "RouteTablesClient.BeginCreateOrUpdate"
+
+// BeginCreateOrUpdate starts creating or updating a RouteTable and returns without waiting for the operation to complete.
+func (client *Client) BeginCreateOrUpdate(ctx context.Context, resourceGroupName string, resourceName string, resource armnetwork.RouteTable) (result *utils.PollerWrapper[armnetwork.RouteTablesClientCreateOrUpdateResponse], err error) {
+	ctx = utils.ContextWithClientName(ctx, "RouteTablesClient")
+	ctx = utils.ContextWithRequestMethod(ctx, "BeginCreateOrUpdate")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, BeginCreateOrUpdateOperationName, client.tracer, nil)
+	defer endSpan(err)
+	poller, err := client.RouteTablesClient.BeginCreateOrUpdate(ctx, resourceGroupName, resourceName, resource, nil)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewPollerWrapper(poller, nil), nil
+}
+
+const BeginDeleteOperationName = "RouteTablesClient.BeginDelete"
+
+// BeginDelete starts deleting a RouteTable by name and returns without waiting for the operation to complete.
+func (client *Client) BeginDelete(ctx context.Context, resourceGroupName string, resourceName string) (result *utils.PollerWrapper[armnetwork.RouteTablesClientDeleteResponse], err error) {
+	ctx = utils.ContextWithClientName(ctx, "RouteTablesClient")
+	ctx = utils.ContextWithRequestMethod(ctx, "BeginDelete")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, BeginDeleteOperationName, client.tracer, nil)
+	defer endSpan(err)
+	poller, err := client.RouteTablesClient.BeginDelete(ctx, resourceGroupName, resourceName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewPollerWrapper(poller, nil), nil
+}
@@ -23,10 +23,11 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
 )
 
-// +azure:client:verbs=get;createorupdate;delete;list,resource=SecurityGroup,packageName=github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4,packageAlias=armnetwork,clientName=SecurityGroupsClient,expand=false,rateLimitKey=securityGroupRateLimit
+// +azure:client:verbs=get;createorupdate;delete;list;updatetags,resource=SecurityGroup,packageName=github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4,packageAlias=armnetwork,clientName=SecurityGroupsClient,expand=false,rateLimitKey=securityGroupRateLimit
 type Interface interface {
 	utils.GetFunc[armnetwork.SecurityGroup]
 	utils.CreateOrUpdateFunc[armnetwork.SecurityGroup]
 	utils.DeleteFunc[armnetwork.SecurityGroup]
 	utils.ListFunc[armnetwork.SecurityGroup]
+	utils.UpdateTagsFunc[armnetwork.SecurityGroup]
 }
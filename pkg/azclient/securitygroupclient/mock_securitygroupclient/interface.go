@@ -114,3 +114,18 @@ func (mr *MockInterfaceMockRecorder) List(ctx, resourceGroupName any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInterface)(nil).List), ctx, resourceGroupName)
 }
+
+// UpdateTags mocks base method.
+func (m *MockInterface) UpdateTags(ctx context.Context, resourceGroupName, resourceName string, tags map[string]*string) (*armnetwork.SecurityGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTags", ctx, resourceGroupName, resourceName, tags)
+	ret0, _ := ret[0].(*armnetwork.SecurityGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTags indicates an expected call of UpdateTags.
+func (mr *MockInterfaceMockRecorder) UpdateTags(ctx, resourceGroupName, resourceName, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTags", reflect.TypeOf((*MockInterface)(nil).UpdateTags), ctx, resourceGroupName, resourceName, tags)
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -73,6 +74,16 @@ var _ = Describe("SecurityGroupsClient", Ordered, func() {
 		})
 	})
 
+	When("tag update requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			newResource, err := realClient.UpdateTags(ctx, resourceGroupName, resourceName, map[string]*string{"updated": to.Ptr("true")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newResource).NotTo(BeNil())
+			Expect(newResource.Tags).To(HaveKey("updated"))
+			Expect(*newResource.Tags["updated"]).To(Equal("true"))
+		})
+	})
+
 	When("list requests are raised", func() {
 		It("should not return error", func(ctx context.Context) {
 			resourceList, err := realClient.List(ctx, resourceGroupName)
@@ -101,7 +101,7 @@ func (client *Client) Delete(ctx context.Context, resourceGroupName string, reso
 	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
 	ctx, endSpan := runtime.StartSpan(ctx, DeleteOperationName, client.tracer, nil)
 	defer endSpan(err)
-	_, err = utils.NewPollerWrapper(client.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
+	_, err = utils.NewPollerWrapper(client.SecurityGroupsClient.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
 	return err
 }
 
@@ -125,3 +125,20 @@ func (client *Client) List(ctx context.Context, resourceGroupName string) (resul
 	}
 	return result, nil
 }
+
+const UpdateTagsOperationName = "SecurityGroupsClient.UpdateTags"
+
+// UpdateTags replaces the tags of a SecurityGroup without sending the whole resource.
+func (client *Client) UpdateTags(ctx context.Context, resourceGroupName string, resourceName string, tags map[string]*string) (result *armnetwork.SecurityGroup, err error) {
+	ctx = utils.ContextWithClientName(ctx, "SecurityGroupsClient")
+	ctx = utils.ContextWithRequestMethod(ctx, "UpdateTags")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, UpdateTagsOperationName, client.tracer, nil)
+	defer endSpan(err)
+	resp, err := client.SecurityGroupsClient.UpdateTags(ctx, resourceGroupName, resourceName, armnetwork.TagsObject{Tags: tags}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.SecurityGroup, nil
+}
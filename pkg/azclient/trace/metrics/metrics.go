@@ -51,6 +51,8 @@ type Metrics struct {
 	apiErrorCount       metric.Int64Counter
 	apiRateLimitedCount metric.Int64Counter
 	apiThrottledCount   metric.Int64Counter
+	cacheHitCount       metric.Int64Counter
+	cacheMissCount      metric.Int64Counter
 }
 
 func New() (*Metrics, error) {
@@ -89,15 +91,48 @@ func New() (*Metrics, error) {
 		metric.WithDescription("Number of throttled Azure API calls"),
 		metric.WithUnit("{call}"),
 	)
+	if err != nil {
+		return nil, fmt.Errorf("create api_request_throttled_count counter: %w", err)
+	}
+
+	cacheHitCount, err := meter.Int64Counter(
+		"api_response_cache_hit_count",
+		metric.WithDescription("Number of Azure API GET calls served from the response cache after a 304 Not Modified"),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create api_response_cache_hit_count counter: %w", err)
+	}
+
+	cacheMissCount, err := meter.Int64Counter(
+		"api_response_cache_miss_count",
+		metric.WithDescription("Number of Azure API GET calls not served from the response cache"),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create api_response_cache_miss_count counter: %w", err)
+	}
 	return &Metrics{
 		meter:               meter,
 		apiLatency:          apiLatency,
 		apiErrorCount:       apiErrorCount,
 		apiRateLimitedCount: apiRateLimitedCount,
 		apiThrottledCount:   apiThrottledCount,
+		cacheHitCount:       cacheHitCount,
+		cacheMissCount:      cacheMissCount,
 	}, nil
 }
 
+// CacheHit implements etagcache.Recorder.
+func (m *Metrics) CacheHit(ctx context.Context, resourceType string) {
+	m.cacheHitCount.Add(ctx, 1, metric.WithAttributes(attribute.String("resource_type", resourceType)))
+}
+
+// CacheMiss implements etagcache.Recorder.
+func (m *Metrics) CacheMiss(ctx context.Context, resourceType string) {
+	m.cacheMissCount.Add(ctx, 1, metric.WithAttributes(attribute.String("resource_type", resourceType)))
+}
+
 type Span struct {
 	metrics    *Metrics
 	start      time.Time
@@ -67,3 +67,48 @@ type DeleteFunc[Type interface{}] interface {
 type SubResourceDeleteFunc[Type interface{}] interface {
 	Delete(ctx context.Context, resourceGroupName string, parentResourceName string, resourceName string) error
 }
+
+// Patch updates the given properties of a service resource.
+type PatchFunc[Type interface{}, PatchType interface{}] interface {
+	Patch(ctx context.Context, resourceGroupName string, resourceName string, parameters PatchType) (*Type, error)
+}
+
+// Patch updates the given properties of a service resource.
+type SubResourcePatchFunc[Type interface{}, PatchType interface{}] interface {
+	Patch(ctx context.Context, resourceGroupName string, parentResourceName string, resourceName string, parameters PatchType) (*Type, error)
+}
+
+// UpdateTags replaces the tags of a service resource without sending the whole resource.
+type UpdateTagsFunc[Type interface{}] interface {
+	UpdateTags(ctx context.Context, resourceGroupName string, resourceName string, tags map[string]*string) (*Type, error)
+}
+
+// BeginCreateOrUpdate starts creating or updating a service resource and returns without waiting for the operation to complete.
+type BeginCreateOrUpdateFunc[Type interface{}, ResponseType interface{}] interface {
+	BeginCreateOrUpdate(ctx context.Context, resourceGroupName string, resourceName string, resourceParam Type) (*PollerWrapper[ResponseType], error)
+}
+
+// BeginCreateOrUpdate starts creating or updating a service resource and returns without waiting for the operation to complete.
+type SubResourceBeginCreateOrUpdateFunc[Type interface{}, ResponseType interface{}] interface {
+	BeginCreateOrUpdate(ctx context.Context, resourceGroupName string, parentResourceName string, resourceName string, resourceParam Type) (*PollerWrapper[ResponseType], error)
+}
+
+// BeginDelete starts deleting a service resource by name and returns without waiting for the operation to complete.
+type BeginDeleteFunc[ResponseType interface{}] interface {
+	BeginDelete(ctx context.Context, resourceGroupName string, resourceName string) (*PollerWrapper[ResponseType], error)
+}
+
+// BeginDelete starts deleting a service resource by name and returns without waiting for the operation to complete.
+type SubResourceBeginDeleteFunc[ResponseType interface{}] interface {
+	BeginDelete(ctx context.Context, resourceGroupName string, parentResourceName string, resourceName string) (*PollerWrapper[ResponseType], error)
+}
+
+// ListByFilter gets a list of service resource in the resource group with the list options, e.g. $filter and $expand.
+type ListByFilterFunc[Type interface{}, OptionsType interface{}] interface {
+	ListByFilter(ctx context.Context, resourceGroupName string, options *OptionsType) (result []*Type, rerr error)
+}
+
+// ListByFilter gets a list of service resource in the resource group with the list options, e.g. $filter and $expand.
+type SubResourceListByFilterFunc[Type interface{}, OptionsType interface{}] interface {
+	ListByFilter(ctx context.Context, resourceGroupName string, parentResourceName string, options *OptionsType) (result []*Type, rerr error)
+}
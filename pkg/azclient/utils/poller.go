@@ -36,6 +36,23 @@ type PollerWrapper[ResponseType interface{}] struct {
 	err    error
 }
 
+// Done returns true if the long running operation has reached a terminal state.
+func (handler *PollerWrapper[ResponseType]) Done() bool {
+	return handler.err == nil && handler.poller != nil && handler.poller.Done()
+}
+
+// ResumeToken returns a token the long running operation can be resumed with,
+// e.g. after a restart, by passing it to the Begin* options of the SDK client.
+func (handler *PollerWrapper[ResponseType]) ResumeToken() (string, error) {
+	if handler.err != nil {
+		return "", handler.err
+	}
+	if handler.poller == nil {
+		return "", errors.New("poller is nil")
+	}
+	return handler.poller.ResumeToken()
+}
+
 // Poller is the poller to be used for polling.
 // assume that the poller will ends
 func (handler *PollerWrapper[ResponseType]) WaitforPollerResp(ctx context.Context) (result *ResponseType, err error) {
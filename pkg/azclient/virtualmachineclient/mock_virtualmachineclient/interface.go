@@ -161,6 +161,21 @@ func (mr *MockInterfaceMockRecorder) List(ctx, resourceGroupName any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInterface)(nil).List), ctx, resourceGroupName)
 }
 
+// ListByFilter mocks base method.
+func (m *MockInterface) ListByFilter(ctx context.Context, resourceGroupName string, options *armcompute.VirtualMachinesClientListOptions) ([]*armcompute.VirtualMachine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByFilter", ctx, resourceGroupName, options)
+	ret0, _ := ret[0].([]*armcompute.VirtualMachine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByFilter indicates an expected call of ListByFilter.
+func (mr *MockInterfaceMockRecorder) ListByFilter(ctx, resourceGroupName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFilter", reflect.TypeOf((*MockInterface)(nil).ListByFilter), ctx, resourceGroupName, options)
+}
+
 // ListVMInstanceView mocks base method.
 func (m *MockInterface) ListVMInstanceView(ctx context.Context, resourceGroupName string) ([]*armcompute.VirtualMachine, error) {
 	m.ctrl.T.Helper()
@@ -75,6 +75,15 @@ var _ = Describe("VirtualMachinesClient", Ordered, func() {
 		})
 	})
 
+	When("filtered list requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			resourceList, err := realClient.ListByFilter(ctx, resourceGroupName, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resourceList).NotTo(BeNil())
+			Expect(len(resourceList)).To(Equal(1))
+		})
+	})
+
 	When("deletion requests are raised", func() {
 		It("should not return error", func(ctx context.Context) {
 			err = realClient.Delete(ctx, resourceGroupName, resourceName)
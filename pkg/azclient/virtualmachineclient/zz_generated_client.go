@@ -82,7 +82,7 @@ func (client *Client) Delete(ctx context.Context, resourceGroupName string, reso
 	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
 	ctx, endSpan := runtime.StartSpan(ctx, DeleteOperationName, client.tracer, nil)
 	defer endSpan(err)
-	_, err = utils.NewPollerWrapper(client.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
+	_, err = utils.NewPollerWrapper(client.VirtualMachinesClient.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
 	return err
 }
 
@@ -106,3 +106,24 @@ func (client *Client) List(ctx context.Context, resourceGroupName string) (resul
 	}
 	return result, nil
 }
+
+const ListByFilterOperationName = "VirtualMachinesClient.ListByFilter"
+
+// ListByFilter gets a list of VirtualMachine in the resource group with the list options, e.g. $filter and $expand.
+func (client *Client) ListByFilter(ctx context.Context, resourceGroupName string, options *armcompute.VirtualMachinesClientListOptions) (result []*armcompute.VirtualMachine, rerr error) {
+	ctx = utils.ContextWithClientName(ctx, "VirtualMachinesClient")
+	ctx = utils.ContextWithRequestMethod(ctx, "ListByFilter")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, ListByFilterOperationName, client.tracer, nil)
+	defer endSpan(rerr)
+	pager := client.VirtualMachinesClient.NewListPager(resourceGroupName, options)
+	for pager.More() {
+		nextResult, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nextResult.Value...)
+	}
+	return result, nil
+}
@@ -25,10 +25,13 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
 )
 
-// +azure:client:verbs=createorupdate;delete;list,resource=VirtualMachineScaleSet,packageName=github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5,packageAlias=armcompute,clientName=VirtualMachineScaleSetsClient,expand=true,rateLimitKey=virtualMachineSizesRateLimit
+// +azure:client:verbs=createorupdate;delete;list;patch;begincreateorupdate;begindelete,resource=VirtualMachineScaleSet,packageName=github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5,packageAlias=armcompute,clientName=VirtualMachineScaleSetsClient,expand=true,rateLimitKey=virtualMachineSizesRateLimit,patchLongRunning=true
 type Interface interface {
 	Get(ctx context.Context, resourceGroupName string, resourceName string, expand *armcompute.ExpandTypesForGetVMScaleSets) (result *armcompute.VirtualMachineScaleSet, rerr error)
 	utils.CreateOrUpdateFunc[armcompute.VirtualMachineScaleSet]
 	utils.DeleteFunc[armcompute.VirtualMachineScaleSet]
 	utils.ListFunc[armcompute.VirtualMachineScaleSet]
+	utils.PatchFunc[armcompute.VirtualMachineScaleSet, armcompute.VirtualMachineScaleSetUpdate]
+	utils.BeginCreateOrUpdateFunc[armcompute.VirtualMachineScaleSet, armcompute.VirtualMachineScaleSetsClientCreateOrUpdateResponse]
+	utils.BeginDeleteFunc[armcompute.VirtualMachineScaleSetsClientDeleteResponse]
 }
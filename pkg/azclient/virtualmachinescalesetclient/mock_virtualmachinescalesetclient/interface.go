@@ -31,6 +31,8 @@ import (
 
 	armcompute "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	gomock "go.uber.org/mock/gomock"
+
+	utils "sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
 )
 
 // MockInterface is a mock of Interface interface.
@@ -56,6 +58,36 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// BeginCreateOrUpdate mocks base method.
+func (m *MockInterface) BeginCreateOrUpdate(ctx context.Context, resourceGroupName, resourceName string, resourceParam armcompute.VirtualMachineScaleSet) (*utils.PollerWrapper[armcompute.VirtualMachineScaleSetsClientCreateOrUpdateResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginCreateOrUpdate", ctx, resourceGroupName, resourceName, resourceParam)
+	ret0, _ := ret[0].(*utils.PollerWrapper[armcompute.VirtualMachineScaleSetsClientCreateOrUpdateResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginCreateOrUpdate indicates an expected call of BeginCreateOrUpdate.
+func (mr *MockInterfaceMockRecorder) BeginCreateOrUpdate(ctx, resourceGroupName, resourceName, resourceParam any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginCreateOrUpdate", reflect.TypeOf((*MockInterface)(nil).BeginCreateOrUpdate), ctx, resourceGroupName, resourceName, resourceParam)
+}
+
+// BeginDelete mocks base method.
+func (m *MockInterface) BeginDelete(ctx context.Context, resourceGroupName, resourceName string) (*utils.PollerWrapper[armcompute.VirtualMachineScaleSetsClientDeleteResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginDelete", ctx, resourceGroupName, resourceName)
+	ret0, _ := ret[0].(*utils.PollerWrapper[armcompute.VirtualMachineScaleSetsClientDeleteResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginDelete indicates an expected call of BeginDelete.
+func (mr *MockInterfaceMockRecorder) BeginDelete(ctx, resourceGroupName, resourceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginDelete", reflect.TypeOf((*MockInterface)(nil).BeginDelete), ctx, resourceGroupName, resourceName)
+}
+
 // CreateOrUpdate mocks base method.
 func (m *MockInterface) CreateOrUpdate(ctx context.Context, resourceGroupName, resourceName string, resourceParam armcompute.VirtualMachineScaleSet) (*armcompute.VirtualMachineScaleSet, error) {
 	m.ctrl.T.Helper()
@@ -114,3 +146,18 @@ func (mr *MockInterfaceMockRecorder) List(ctx, resourceGroupName any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInterface)(nil).List), ctx, resourceGroupName)
 }
+
+// Patch mocks base method.
+func (m *MockInterface) Patch(ctx context.Context, resourceGroupName, resourceName string, parameters armcompute.VirtualMachineScaleSetUpdate) (*armcompute.VirtualMachineScaleSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, resourceGroupName, resourceName, parameters)
+	ret0, _ := ret[0].(*armcompute.VirtualMachineScaleSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockInterfaceMockRecorder) Patch(ctx, resourceGroupName, resourceName, parameters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockInterface)(nil).Patch), ctx, resourceGroupName, resourceName, parameters)
+}
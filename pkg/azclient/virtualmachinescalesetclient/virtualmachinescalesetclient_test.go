@@ -30,6 +30,7 @@ var beforeAllFunc func(context.Context)
 var afterAllFunc func(context.Context)
 var additionalTestCases func()
 var newResource *armcompute.VirtualMachineScaleSet = &armcompute.VirtualMachineScaleSet{}
+var patchResource *armcompute.VirtualMachineScaleSetUpdate = &armcompute.VirtualMachineScaleSetUpdate{}
 
 var _ = Describe("VirtualMachineScaleSetsClient", Ordered, func() {
 
@@ -58,6 +59,25 @@ var _ = Describe("VirtualMachineScaleSetsClient", Ordered, func() {
 		})
 	})
 
+	When("non-blocking update requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			poller, err := realClient.BeginCreateOrUpdate(ctx, resourceGroupName, resourceName, *newResource)
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := poller.WaitforPollerResp(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).NotTo(BeNil())
+			Expect(poller.Done()).To(BeTrue())
+		})
+	})
+
+	When("patch requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			newResource, err := realClient.Patch(ctx, resourceGroupName, resourceName, *patchResource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newResource).NotTo(BeNil())
+		})
+	})
+
 	When("list requests are raised", func() {
 		It("should not return error", func(ctx context.Context) {
 			resourceList, err := realClient.List(ctx, resourceGroupName)
@@ -81,6 +101,15 @@ var _ = Describe("VirtualMachineScaleSetsClient", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	When("non-blocking deletion requests are raised", func() {
+		It("should not return error", func(ctx context.Context) {
+			poller, err := realClient.BeginDelete(ctx, resourceGroupName, resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = poller.WaitforPollerResp(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 	if afterAllFunc != nil {
 		AfterAll(afterAllFunc)
 	}
@@ -82,7 +82,7 @@ func (client *Client) Delete(ctx context.Context, resourceGroupName string, reso
 	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
 	ctx, endSpan := runtime.StartSpan(ctx, DeleteOperationName, client.tracer, nil)
 	defer endSpan(err)
-	_, err = utils.NewPollerWrapper(client.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
+	_, err = utils.NewPollerWrapper(client.VirtualMachineScaleSetsClient.BeginDelete(ctx, resourceGroupName, resourceName, nil)).WaitforPollerResp(ctx)
 	return err
 }
 
@@ -106,3 +106,57 @@ func (client *Client) List(ctx context.Context, resourceGroupName string) (resul
 	}
 	return result, nil
 }
+
+const PatchOperationName = "VirtualMachineScaleSetsClient.Patch"
+
+// Patch updates the given properties of a VirtualMachineScaleSet.
+func (client *Client) Patch(ctx context.Context, resourceGroupName string, resourceName string, parameters armcompute.VirtualMachineScaleSetUpdate) (result *armcompute.VirtualMachineScaleSet, err error) {
+	ctx = utils.ContextWithClientName(ctx, "VirtualMachineScaleSetsClient")
+	ctx = utils.ContextWithRequestMethod(ctx, "Patch")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, PatchOperationName, client.tracer, nil)
+	defer endSpan(err)
+	resp, err := utils.NewPollerWrapper(client.VirtualMachineScaleSetsClient.BeginUpdate(ctx, resourceGroupName, resourceName, parameters, nil)).WaitforPollerResp(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		return &resp.VirtualMachineScaleSet, nil
+	}
+	return nil, nil
+}
+
+const BeginCreateOrUpdateOperationName = "VirtualMachineScaleSetsClient.BeginCreateOrUpdate"
+
+// BeginCreateOrUpdate starts creating or updating a VirtualMachineScaleSet and returns without waiting for the operation to complete.
+func (client *Client) BeginCreateOrUpdate(ctx context.Context, resourceGroupName string, resourceName string, resource armcompute.VirtualMachineScaleSet) (result *utils.PollerWrapper[armcompute.VirtualMachineScaleSetsClientCreateOrUpdateResponse], err error) {
+	ctx = utils.ContextWithClientName(ctx, "VirtualMachineScaleSetsClient")
+	ctx = utils.ContextWithRequestMethod(ctx, "BeginCreateOrUpdate")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, BeginCreateOrUpdateOperationName, client.tracer, nil)
+	defer endSpan(err)
+	poller, err := client.VirtualMachineScaleSetsClient.BeginCreateOrUpdate(ctx, resourceGroupName, resourceName, resource, nil)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewPollerWrapper(poller, nil), nil
+}
+
+const BeginDeleteOperationName = "VirtualMachineScaleSetsClient.BeginDelete"
+
+// BeginDelete starts deleting a VirtualMachineScaleSet by name and returns without waiting for the operation to complete.
+func (client *Client) BeginDelete(ctx context.Context, resourceGroupName string, resourceName string) (result *utils.PollerWrapper[armcompute.VirtualMachineScaleSetsClientDeleteResponse], err error) {
+	ctx = utils.ContextWithClientName(ctx, "VirtualMachineScaleSetsClient")
+	ctx = utils.ContextWithRequestMethod(ctx, "BeginDelete")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, BeginDeleteOperationName, client.tracer, nil)
+	defer endSpan(err)
+	poller, err := client.VirtualMachineScaleSetsClient.BeginDelete(ctx, resourceGroupName, resourceName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewPollerWrapper(poller, nil), nil
+}
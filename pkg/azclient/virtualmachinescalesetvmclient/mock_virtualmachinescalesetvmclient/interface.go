@@ -131,6 +131,21 @@ func (mr *MockInterfaceMockRecorder) List(ctx, resourceGroupName, parentResource
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInterface)(nil).List), ctx, resourceGroupName, parentResourceName)
 }
 
+// ListByFilter mocks base method.
+func (m *MockInterface) ListByFilter(ctx context.Context, resourceGroupName, parentResourceName string, options *armcompute.VirtualMachineScaleSetVMsClientListOptions) ([]*armcompute.VirtualMachineScaleSetVM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByFilter", ctx, resourceGroupName, parentResourceName, options)
+	ret0, _ := ret[0].([]*armcompute.VirtualMachineScaleSetVM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByFilter indicates an expected call of ListByFilter.
+func (mr *MockInterfaceMockRecorder) ListByFilter(ctx, resourceGroupName, parentResourceName, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFilter", reflect.TypeOf((*MockInterface)(nil).ListByFilter), ctx, resourceGroupName, parentResourceName, options)
+}
+
 // ListVMInstanceView mocks base method.
 func (m *MockInterface) ListVMInstanceView(ctx context.Context, resourceGroupName, parentResourceName string) ([]*armcompute.VirtualMachineScaleSetVM, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +81,27 @@ func (client *Client) Delete(ctx context.Context, resourceGroupName string, pare
 	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
 	ctx, endSpan := runtime.StartSpan(ctx, DeleteOperationName, client.tracer, nil)
 	defer endSpan(err)
-	_, err = utils.NewPollerWrapper(client.BeginDelete(ctx, resourceGroupName, parentResourceName, resourceName, nil)).WaitforPollerResp(ctx)
+	_, err = utils.NewPollerWrapper(client.VirtualMachineScaleSetVMsClient.BeginDelete(ctx, resourceGroupName, parentResourceName, resourceName, nil)).WaitforPollerResp(ctx)
 	return err
 }
+
+const ListByFilterOperationName = "VirtualMachineScaleSetVMsClient.ListByFilter"
+
+// ListByFilter gets a list of VirtualMachineScaleSetVM in the resource group with the list options, e.g. $filter and $expand.
+func (client *Client) ListByFilter(ctx context.Context, resourceGroupName string, parentResourceName string, options *armcompute.VirtualMachineScaleSetVMsClientListOptions) (result []*armcompute.VirtualMachineScaleSetVM, rerr error) {
+	ctx = utils.ContextWithClientName(ctx, "VirtualMachineScaleSetVMsClient")
+	ctx = utils.ContextWithRequestMethod(ctx, "ListByFilter")
+	ctx = utils.ContextWithResourceGroupName(ctx, resourceGroupName)
+	ctx = utils.ContextWithSubscriptionID(ctx, client.subscriptionID)
+	ctx, endSpan := runtime.StartSpan(ctx, ListByFilterOperationName, client.tracer, nil)
+	defer endSpan(rerr)
+	pager := client.VirtualMachineScaleSetVMsClient.NewListPager(resourceGroupName, parentResourceName, options)
+	for pager.More() {
+		nextResult, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nextResult.Value...)
+	}
+	return result, nil
+}
@@ -33,10 +33,12 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients"
 	"sigs.k8s.io/cloud-provider-azure/pkg/retry"
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace"
 	"sigs.k8s.io/cloud-provider-azure/pkg/version"
 )
 
@@ -116,6 +118,10 @@ func New(authorizer autorest.Authorizer, clientConfig azureclients.ClientConfig,
 	restClient := autorest.NewClientWithUserAgent(clientConfig.UserAgent)
 	restClient.Authorizer = authorizer
 	restClient.Sender = sender()
+	if clientConfig.FaultInjectionPolicy != nil {
+		// the faults are injected closest to the transport so that the retries handle them
+		restClient.Sender = clientConfig.FaultInjectionPolicy.SendDecorator()(restClient.Sender)
+	}
 
 	if clientConfig.UserAgent == "" {
 		restClient.UserAgent = GetUserAgent(restClient)
@@ -188,10 +194,22 @@ func NormalizeAzureRegion(name string) string {
 }
 
 // Send sends a http request to ARM service with possible retry to regional ARM endpoint.
-func (c *Client) Send(_ context.Context, request *http.Request, decorators ...autorest.SendDecorator) (*http.Response, *retry.Error) {
+// The request is traced as a child of the span of its context, e.g. the span of the Service reconcile.
+func (c *Client) Send(_ context.Context, request *http.Request, decorators ...autorest.SendDecorator) (response *http.Response, rerr *retry.Error) {
+	ctx, span := trace.StartSpan(request.Context(), "armclient.Send",
+		semconv.HTTPMethod(request.Method),
+		trace.ResourceIDKey.String(request.URL.Path),
+	)
+	defer func() {
+		if response != nil {
+			span.SetAttributes(semconv.HTTPStatusCode(response.StatusCode))
+		}
+		trace.EndSpan(span, rerr.Error())
+	}()
+
 	response, err := autorest.SendWithSender(
 		c.client,
-		request,
+		request.WithContext(ctx),
 		decorators...,
 	)
 
@@ -22,6 +22,7 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	"k8s.io/client-go/util/flowcontrol"
 
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/faultinjection"
 	"sigs.k8s.io/cloud-provider-azure/pkg/retry"
 )
 
@@ -38,6 +39,8 @@ type ClientConfig struct {
 	Backoff                 *retry.Backoff
 	UserAgent               string
 	DisableAzureStackCloud  bool
+	// FaultInjectionPolicy injects faults into the responses of the client, for chaos testing only.
+	FaultInjectionPolicy *faultinjection.Policy
 }
 
 // WithRateLimiter returns a new ClientConfig with rateLimitConfig set.
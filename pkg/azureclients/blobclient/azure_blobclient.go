@@ -38,12 +38,13 @@ var _ Interface = &Client{}
 
 // Client implements the blobclient interface
 type Client struct {
-	blobServicesClient storage.BlobServicesClient
-	armClient          armclient.Interface
-	subscriptionID     string
-	cloudName          string
-	baseURI            string
-	authorizer         autorest.Authorizer
+	blobServicesClient       storage.BlobServicesClient
+	managementPoliciesClient storage.ManagementPoliciesClient
+	armClient                armclient.Interface
+	subscriptionID           string
+	cloudName                string
+	baseURI                  string
+	authorizer               autorest.Authorizer
 
 	// Rate limiting configures.
 	rateLimiterReader flowcontrol.RateLimiter
@@ -66,6 +67,9 @@ func New(config *azclients.ClientConfig) *Client {
 	blobServicesClient := storage.NewBlobServicesClientWithBaseURI(baseURI, config.SubscriptionID)
 	blobServicesClient.Authorizer = authorizer
 
+	managementPoliciesClient := storage.NewManagementPoliciesClientWithBaseURI(baseURI, config.SubscriptionID)
+	managementPoliciesClient.Authorizer = authorizer
+
 	if strings.EqualFold(config.CloudName, AzureStackCloudName) && !config.DisableAzureStackCloud {
 		apiVersion = AzureStackCloudAPIVersion
 	}
@@ -84,15 +88,16 @@ func New(config *azclients.ClientConfig) *Client {
 	}
 
 	client := &Client{
-		blobServicesClient: blobServicesClient,
-		armClient:          armClient,
-		rateLimiterReader:  rateLimiterReader,
-		rateLimiterWriter:  rateLimiterWriter,
-		subscriptionID:     config.SubscriptionID,
-		cloudName:          config.CloudName,
-		now:                time.Now,
-		baseURI:            baseURI,
-		authorizer:         authorizer,
+		blobServicesClient:       blobServicesClient,
+		managementPoliciesClient: managementPoliciesClient,
+		armClient:                armClient,
+		rateLimiterReader:        rateLimiterReader,
+		rateLimiterWriter:        rateLimiterWriter,
+		subscriptionID:           config.SubscriptionID,
+		cloudName:                config.CloudName,
+		now:                      time.Now,
+		baseURI:                  baseURI,
+		authorizer:               authorizer,
 	}
 
 	return client
@@ -295,3 +300,36 @@ func (c *Client) SetServiceProperties(ctx context.Context, subsID, resourceGroup
 	}
 	return blobServicesClient.SetServiceProperties(ctx, resourceGroupName, accountName, parameters)
 }
+
+func (c *Client) getManagementPoliciesClient(subsID string) storage.ManagementPoliciesClient {
+	if subsID == "" || subsID == c.subscriptionID {
+		return c.managementPoliciesClient
+	}
+	managementPoliciesClient := storage.NewManagementPoliciesClientWithBaseURI(c.baseURI, subsID)
+	managementPoliciesClient.Authorizer = c.authorizer
+	return managementPoliciesClient
+}
+
+// GetManagementPolicy gets the blob lifecycle management policy of the storage account
+func (c *Client) GetManagementPolicy(ctx context.Context, subsID, resourceGroupName, accountName string) (storage.ManagementPolicy, error) {
+	mc := metrics.NewMetricContext("management_policies", "get", resourceGroupName, subsID, "")
+	policy, err := c.getManagementPoliciesClient(subsID).Get(ctx, resourceGroupName, accountName)
+	mc.Observe(retry.GetError(policy.Response.Response, err))
+	return policy, err
+}
+
+// CreateOrUpdateManagementPolicy sets the blob lifecycle management policy of the storage account
+func (c *Client) CreateOrUpdateManagementPolicy(ctx context.Context, subsID, resourceGroupName, accountName string, policy storage.ManagementPolicy) (storage.ManagementPolicy, error) {
+	mc := metrics.NewMetricContext("management_policies", "create_or_update", resourceGroupName, subsID, "")
+	result, err := c.getManagementPoliciesClient(subsID).CreateOrUpdate(ctx, resourceGroupName, accountName, policy)
+	mc.Observe(retry.GetError(result.Response.Response, err))
+	return result, err
+}
+
+// DeleteManagementPolicy deletes the blob lifecycle management policy of the storage account
+func (c *Client) DeleteManagementPolicy(ctx context.Context, subsID, resourceGroupName, accountName string) error {
+	mc := metrics.NewMetricContext("management_policies", "delete", resourceGroupName, subsID, "")
+	resp, err := c.getManagementPoliciesClient(subsID).Delete(ctx, resourceGroupName, accountName)
+	mc.Observe(retry.GetError(resp.Response, err))
+	return err
+}
@@ -41,4 +41,7 @@ type Interface interface {
 	GetContainer(ctx context.Context, subsID, resourceGroupName, accountName, containerName string) (storage.BlobContainer, *retry.Error)
 	GetServiceProperties(ctx context.Context, subsID, resourceGroupName, accountName string) (storage.BlobServiceProperties, error)
 	SetServiceProperties(ctx context.Context, subsID, resourceGroupName, accountName string, parameters storage.BlobServiceProperties) (storage.BlobServiceProperties, error)
+	GetManagementPolicy(ctx context.Context, subsID, resourceGroupName, accountName string) (storage.ManagementPolicy, error)
+	CreateOrUpdateManagementPolicy(ctx context.Context, subsID, resourceGroupName, accountName string, policy storage.ManagementPolicy) (storage.ManagementPolicy, error)
+	DeleteManagementPolicy(ctx context.Context, subsID, resourceGroupName, accountName string) error
 }
@@ -72,6 +72,21 @@ func (mr *MockInterfaceMockRecorder) CreateContainer(ctx, subsID, resourceGroupN
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContainer", reflect.TypeOf((*MockInterface)(nil).CreateContainer), ctx, subsID, resourceGroupName, accountName, containerName, parameters)
 }
 
+// CreateOrUpdateManagementPolicy mocks base method.
+func (m *MockInterface) CreateOrUpdateManagementPolicy(ctx context.Context, subsID, resourceGroupName, accountName string, policy storage.ManagementPolicy) (storage.ManagementPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateManagementPolicy", ctx, subsID, resourceGroupName, accountName, policy)
+	ret0, _ := ret[0].(storage.ManagementPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdateManagementPolicy indicates an expected call of CreateOrUpdateManagementPolicy.
+func (mr *MockInterfaceMockRecorder) CreateOrUpdateManagementPolicy(ctx, subsID, resourceGroupName, accountName, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateManagementPolicy", reflect.TypeOf((*MockInterface)(nil).CreateOrUpdateManagementPolicy), ctx, subsID, resourceGroupName, accountName, policy)
+}
+
 // DeleteContainer mocks base method.
 func (m *MockInterface) DeleteContainer(ctx context.Context, subsID, resourceGroupName, accountName, containerName string) *retry.Error {
 	m.ctrl.T.Helper()
@@ -86,6 +101,20 @@ func (mr *MockInterfaceMockRecorder) DeleteContainer(ctx, subsID, resourceGroupN
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContainer", reflect.TypeOf((*MockInterface)(nil).DeleteContainer), ctx, subsID, resourceGroupName, accountName, containerName)
 }
 
+// DeleteManagementPolicy mocks base method.
+func (m *MockInterface) DeleteManagementPolicy(ctx context.Context, subsID, resourceGroupName, accountName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteManagementPolicy", ctx, subsID, resourceGroupName, accountName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteManagementPolicy indicates an expected call of DeleteManagementPolicy.
+func (mr *MockInterfaceMockRecorder) DeleteManagementPolicy(ctx, subsID, resourceGroupName, accountName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteManagementPolicy", reflect.TypeOf((*MockInterface)(nil).DeleteManagementPolicy), ctx, subsID, resourceGroupName, accountName)
+}
+
 // GetContainer mocks base method.
 func (m *MockInterface) GetContainer(ctx context.Context, subsID, resourceGroupName, accountName, containerName string) (storage.BlobContainer, *retry.Error) {
 	m.ctrl.T.Helper()
@@ -101,6 +130,21 @@ func (mr *MockInterfaceMockRecorder) GetContainer(ctx, subsID, resourceGroupName
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainer", reflect.TypeOf((*MockInterface)(nil).GetContainer), ctx, subsID, resourceGroupName, accountName, containerName)
 }
 
+// GetManagementPolicy mocks base method.
+func (m *MockInterface) GetManagementPolicy(ctx context.Context, subsID, resourceGroupName, accountName string) (storage.ManagementPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetManagementPolicy", ctx, subsID, resourceGroupName, accountName)
+	ret0, _ := ret[0].(storage.ManagementPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetManagementPolicy indicates an expected call of GetManagementPolicy.
+func (mr *MockInterfaceMockRecorder) GetManagementPolicy(ctx, subsID, resourceGroupName, accountName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetManagementPolicy", reflect.TypeOf((*MockInterface)(nil).GetManagementPolicy), ctx, subsID, resourceGroupName, accountName)
+}
+
 // GetServiceProperties mocks base method.
 func (m *MockInterface) GetServiceProperties(ctx context.Context, subsID, resourceGroupName, accountName string) (storage.BlobServiceProperties, error) {
 	m.ctrl.T.Helper()
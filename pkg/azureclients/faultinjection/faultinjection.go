@@ -0,0 +1,341 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package faultinjection injects ARM faults into the responses of the Azure clients of the cloud provider,
+// to rehearse ARM outages in tests and staging. It must never be enabled in production.
+//
+// The faults are injected into the autorest clients of pkg/azureclients with SendDecorator, and into the
+// clients of the azclient factories with the Policy, which implements policy.Policy.
+// TODO: use the policy of sigs.k8s.io/cloud-provider-azure/pkg/azclient/policy/faultinjection, which reads the
+// same config, once the vendored azclient release includes it.
+//
+// The faults are configured in a JSON file, which may be a mounted ConfigMap, e.g.
+//
+//	{
+//	  "seed": 1,
+//	  "faults": [
+//	    {"methods": ["PUT"], "resourceTypes": ["Microsoft.Network/loadBalancers"], "probability": 0.5,
+//	     "statusCode": 429, "errorCode": "SubscriptionRequestsThrottled", "retryAfterSeconds": 10},
+//	    {"methods": ["PUT"], "statusCode": 412, "errorCode": "PreconditionFailed", "count": 1},
+//	    {"statusCode": 400, "errorCode": "ReferencedResourceNotProvisioned", "resourceTypes": ["Microsoft.Network/virtualNetworks/subnets"]},
+//	    {"methods": ["GET"], "resourceTypes": ["Microsoft.Network/locations/operations"], "statusCode": 200,
+//	     "body": "{\"status\":\"InProgress\"}", "delay": "5s"}
+//	  ]
+//	}
+package faultinjection // import "sigs.k8s.io/cloud-provider-azure/pkg/azureclients/faultinjection"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/go-autorest/autorest"
+	"k8s.io/klog/v2"
+)
+
+// defaultReloadInterval is the interval at which the config file is checked for changes.
+const defaultReloadInterval = 10 * time.Second
+
+// Config is the fault injection config.
+type Config struct {
+	// Seed of the random source deciding whether a fault with a probability is injected. The
+	// faults are injected in a deterministic order for a given seed and sequence of requests.
+	Seed int64 `json:"seed,omitempty"`
+	// Faults are evaluated in order, the first matching fault is injected.
+	Faults []Fault `json:"faults,omitempty"`
+}
+
+// Fault is a fault injected into the responses of the matching requests.
+type Fault struct {
+	// Methods are the HTTP methods of the requests, all methods if empty.
+	Methods []string `json:"methods,omitempty"`
+	// ResourceTypes are the types of the resources the requests target, e.g. Microsoft.Network/loadBalancers
+	// or Microsoft.Resources/resourceGroups. All types if empty.
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+	// Probability of injecting the fault into a matching request, between 0 and 1. Defaults to 1.
+	Probability *float64 `json:"probability,omitempty"`
+	// Count is the number of times the fault is injected, unlimited if 0.
+	Count int `json:"count,omitempty"`
+
+	// Delay delays the request, e.g. "2s". If StatusCode is 0 the request is sent to ARM after the delay.
+	Delay string `json:"delay,omitempty"`
+	// StatusCode of the injected response. If 0, no response is injected.
+	StatusCode int `json:"statusCode,omitempty"`
+	// ErrorCode and ErrorMessage are returned in the ARM error body of the injected response.
+	ErrorCode    string `json:"errorCode,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// Body overrides the ARM error body of the injected response.
+	Body string `json:"body,omitempty"`
+	// RetryAfterSeconds sets the Retry-After header of the injected response.
+	RetryAfterSeconds int `json:"retryAfterSeconds,omitempty"`
+	// Headers are added to the injected response.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	delay    time.Duration
+	injected int
+}
+
+// Validate checks the config and parses the delays of the faults.
+func (config *Config) Validate() error {
+	for i := range config.Faults {
+		fault := &config.Faults[i]
+		if fault.Probability != nil && (*fault.Probability < 0 || *fault.Probability > 1) {
+			return fmt.Errorf("fault %d: probability must be between 0 and 1, got %v", i, *fault.Probability)
+		}
+		if fault.StatusCode != 0 && (fault.StatusCode < 100 || fault.StatusCode > 599) {
+			return fmt.Errorf("fault %d: invalid status code %d", i, fault.StatusCode)
+		}
+		if fault.Delay != "" {
+			delay, err := time.ParseDuration(fault.Delay)
+			if err != nil {
+				return fmt.Errorf("fault %d: invalid delay %q: %w", i, fault.Delay, err)
+			}
+			fault.delay = delay
+		}
+		if fault.StatusCode == 0 && fault.delay == 0 {
+			return fmt.Errorf("fault %d: either statusCode or delay must be set", i)
+		}
+	}
+	return nil
+}
+
+// matches returns true if the fault applies to the request.
+func (fault *Fault) matches(method, resourceType string) bool {
+	if fault.Count > 0 && fault.injected >= fault.Count {
+		return false
+	}
+	if len(fault.Methods) > 0 && !containsFold(fault.Methods, method) {
+		return false
+	}
+	if len(fault.ResourceTypes) > 0 && !containsFold(fault.ResourceTypes, resourceType) {
+		return false
+	}
+	return true
+}
+
+// response returns the injected response.
+func (fault *Fault) response(req *http.Request) *http.Response {
+	body := fault.Body
+	if body == "" {
+		message := fault.ErrorMessage
+		if message == "" {
+			message = "Fault injected by the fault injection policy."
+		}
+		data, _ := json.Marshal(map[string]interface{}{
+			"error": map[string]string{
+				"code":    fault.ErrorCode,
+				"message": message,
+			},
+		})
+		body = string(data)
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	if fault.RetryAfterSeconds > 0 {
+		header.Set("Retry-After", strconv.Itoa(fault.RetryAfterSeconds))
+	}
+	for k, v := range fault.Headers {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fault.StatusCode, http.StatusText(fault.StatusCode)),
+		StatusCode:    fault.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Policy injects the configured faults. It is safe for concurrent use.
+type Policy struct {
+	lock   sync.Mutex
+	config *Config
+	random *rand.Rand
+
+	// path of the config file, empty if the config is static
+	path           string
+	modTime        time.Time
+	lastCheck      time.Time
+	reloadInterval time.Duration
+}
+
+// NewPolicy creates a policy injecting the faults of the config.
+func NewPolicy(config *Config) (*Policy, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &Policy{
+		config: config,
+		random: rand.New(rand.NewSource(config.Seed)), //nolint:gosec // faults do not need a secure random source
+	}, nil
+}
+
+// NewPolicyFromFile creates a policy injecting the faults configured in the file. The file is reloaded
+// when it changes, e.g. when the ConfigMap it is mounted from is updated.
+func NewPolicyFromFile(path string) (*Policy, error) {
+	config, modTime, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := NewPolicy(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fault injection config %s: %w", path, err)
+	}
+	p.path = path
+	p.modTime = modTime
+	p.lastCheck = time.Now()
+	p.reloadInterval = defaultReloadInterval
+	return p, nil
+}
+
+func readConfig(path string) (*Config, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("read fault injection config: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("read fault injection config: %w", err)
+	}
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, time.Time{}, fmt.Errorf("parse fault injection config %s: %w", path, err)
+	}
+	return config, info.ModTime(), nil
+}
+
+// reload reloads the config file if it changed. An invalid config is ignored and the previous config is kept.
+func (p *Policy) reload(now time.Time) {
+	if p.path == "" || now.Sub(p.lastCheck) < p.reloadInterval {
+		return
+	}
+	p.lastCheck = now
+	info, err := os.Stat(p.path)
+	if err != nil || info.ModTime().Equal(p.modTime) {
+		return
+	}
+	config, modTime, err := readConfig(p.path)
+	if err != nil {
+		klog.Errorf("faultinjection: keep the previous config, failed to reload %s: %v", p.path, err)
+		return
+	}
+	if err := config.Validate(); err != nil {
+		klog.Errorf("faultinjection: keep the previous config, invalid fault injection config %s: %v", p.path, err)
+		return
+	}
+	p.config = config
+	p.modTime = modTime
+	p.random = rand.New(rand.NewSource(config.Seed)) //nolint:gosec // faults do not need a secure random source
+}
+
+// pick returns the fault to inject into the request, or nil.
+func (p *Policy) pick(req *http.Request) *Fault {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.reload(time.Now())
+	resourceType := ResourceType(req.URL.Path)
+	for i := range p.config.Faults {
+		fault := &p.config.Faults[i]
+		if !fault.matches(req.Method, resourceType) {
+			continue
+		}
+		if fault.Probability != nil && p.random.Float64() >= *fault.Probability {
+			continue
+		}
+		fault.injected++
+		// return a copy, the config may be reloaded while the fault is injected
+		injected := *fault
+		return &injected
+	}
+	return nil
+}
+
+// Do implements policy.Policy.
+func (p *Policy) Do(req *policy.Request) (*http.Response, error) {
+	return p.inject(req.Raw(), func(*http.Request) (*http.Response, error) {
+		return req.Next()
+	})
+}
+
+// SendDecorator returns the autorest.SendDecorator injecting the faults into the responses of the sender.
+func (p *Policy) SendDecorator() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			return p.inject(req, s.Do)
+		})
+	}
+}
+
+// inject injects the fault picked for the request, or sends the request with next.
+func (p *Policy) inject(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	fault := p.pick(req)
+	if fault == nil {
+		return next(req)
+	}
+	if fault.delay > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(fault.delay):
+		}
+	}
+	if fault.StatusCode == 0 {
+		return next(req)
+	}
+	return fault.response(req), nil
+}
+
+// ResourceType returns the type of the resource an ARM request path targets, e.g.
+// Microsoft.Network/virtualNetworks/subnets for
+// /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Network/virtualNetworks/{vnet}/subnets/{subnet}.
+// The type of extension resources is the type after the last providers segment.
+func ResourceType(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if strings.EqualFold(segments[i], "providers") {
+			types := []string{segments[i+1]}
+			for j := i + 2; j < len(segments); j += 2 {
+				types = append(types, segments[j])
+			}
+			return strings.Join(types, "/")
+		}
+	}
+	if len(segments) >= 3 && strings.EqualFold(segments[2], "resourceGroups") {
+		return "Microsoft.Resources/resourceGroups"
+	}
+	return ""
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+)
+
+const lbURL = "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb"
+
+func newSender(p *Policy) (autorest.Sender, *int) {
+	sent := 0
+	return p.SendDecorator()(autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})), &sent
+}
+
+func send(t *testing.T, sender autorest.Sender, method, url string) *http.Response {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := sender.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestSendDecorator(t *testing.T) {
+	p, err := NewPolicy(&Config{
+		Faults: []Fault{{
+			Methods:           []string{http.MethodPut},
+			ResourceTypes:     []string{"microsoft.network/loadbalancers"},
+			StatusCode:        http.StatusTooManyRequests,
+			ErrorCode:         "SubscriptionRequestsThrottled",
+			RetryAfterSeconds: 10,
+			Count:             1,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, sent := newSender(p)
+
+	resp := send(t, sender, http.MethodPut, lbURL)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "10", resp.Header.Get("Retry-After"))
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "SubscriptionRequestsThrottled")
+	assert.Equal(t, 0, *sent)
+
+	// the other methods are not faulted
+	resp = send(t, sender, http.MethodGet, lbURL)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	// the fault is injected once
+	resp = send(t, sender, http.MethodPut, lbURL)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, *sent)
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		desc        string
+		fault       Fault
+		expectedErr string
+	}{
+		{
+			desc:  "valid fault",
+			fault: Fault{StatusCode: http.StatusInternalServerError, Probability: pointer.Float64(0.5)},
+		},
+		{
+			desc:        "invalid probability",
+			fault:       Fault{StatusCode: http.StatusInternalServerError, Probability: pointer.Float64(2)},
+			expectedErr: "fault 0: probability must be between 0 and 1, got 2",
+		},
+		{
+			desc:        "invalid delay",
+			fault:       Fault{Delay: "1"},
+			expectedErr: `fault 0: invalid delay "1": time: missing unit in duration "1"`,
+		},
+		{
+			desc:        "neither status code nor delay",
+			fault:       Fault{},
+			expectedErr: "fault 0: either statusCode or delay must be set",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := (&Config{Faults: []Fault{tc.fault}}).Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestNewPolicyFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "faults.json")
+	_, err := NewPolicyFromFile(path)
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"faults": [{"resourceTypes": ["Microsoft.Resources/resourceGroups"], "statusCode": 500}]}`), 0600))
+	p, err := NewPolicyFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, _ := newSender(p)
+	resp := send(t, sender, http.MethodGet, "https://management.azure.com/subscriptions/sub/resourceGroups/rg")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	resp = send(t, sender, http.MethodGet, lbURL)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestResourceType(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/subnet": "Microsoft.Network/virtualNetworks/subnets",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/lb":                    "Microsoft.Network/loadBalancers",
+		"/subscriptions/sub/resourceGroups/rg":                                                                 "Microsoft.Resources/resourceGroups",
+		"/subscriptions/sub":                                                                                   "",
+	} {
+		assert.Equal(t, expected, ResourceType(path), path)
+	}
+}
@@ -217,3 +217,71 @@ func (c *Client) createOrUpdateResponder(resp *http.Response) (*network.RouteTab
 	result.Response = autorest.Response{Response: resp}
 	return result, retry.GetError(resp, err)
 }
+
+// UpdateTags updates the tags of a RouteTable.
+func (c *Client) UpdateTags(ctx context.Context, resourceGroupName string, routeTableName string, parameters network.TagsObject) *retry.Error {
+	mc := metrics.NewMetricContext("route_tables", "update_tags", resourceGroupName, c.subscriptionID, "")
+
+	// Report errors if the client is rate limited.
+	if !c.rateLimiterWriter.TryAccept() {
+		mc.RateLimitedCount()
+		return retry.GetRateLimitError(true, "RouteTableUpdateTags")
+	}
+
+	// Report errors if the client is throttled.
+	if c.RetryAfterWriter.After(time.Now()) {
+		mc.ThrottledCount()
+		rerr := retry.GetThrottlingError("RouteTableUpdateTags", "client throttled", c.RetryAfterWriter)
+		return rerr
+	}
+
+	rerr := c.updateRouteTableTags(ctx, resourceGroupName, routeTableName, parameters)
+	mc.Observe(rerr)
+	if rerr != nil {
+		if rerr.IsThrottled() {
+			// Update RetryAfterReader so that no more requests would be sent until RetryAfter expires.
+			c.RetryAfterWriter = rerr.RetryAfter
+		}
+
+		return rerr
+	}
+
+	return nil
+}
+
+// updateRouteTableTags updates the tags of a RouteTable.
+func (c *Client) updateRouteTableTags(ctx context.Context, resourceGroupName string, routeTableName string, parameters network.TagsObject) *retry.Error {
+	resourceID := armclient.GetResourceID(
+		c.subscriptionID,
+		resourceGroupName,
+		routeTablesResourceType,
+		routeTableName,
+	)
+
+	response, rerr := c.armClient.PatchResource(ctx, resourceID, parameters)
+	defer c.armClient.CloseResponse(ctx, response)
+	if rerr != nil {
+		klog.V(5).Infof("Received error in %s: resourceID: %s, error: %s", "routetable.patch.request", resourceID, rerr.Error())
+		return rerr
+	}
+
+	if response != nil && response.StatusCode != http.StatusNoContent {
+		_, rerr = c.updateTagsResponder(response)
+		if rerr != nil {
+			klog.V(5).Infof("Received error in %s: resourceID: %s, error: %s", "routetable.patch.respond", resourceID, rerr.Error())
+			return rerr
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) updateTagsResponder(resp *http.Response) (*network.RouteTable, *retry.Error) {
+	result := &network.RouteTable{}
+	err := autorest.Respond(
+		resp,
+		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&result))
+	result.Response = autorest.Response{Response: resp}
+	return result, retry.GetError(resp, err)
+}
@@ -303,6 +303,63 @@ func TestCreateOrUpdateWithCreateOrUpdateResponderError(t *testing.T) {
 	assert.NotNil(t, rerr)
 }
 
+func TestUpdateTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rt1 := getTestRouteTable("rt1")
+	tags := network.TagsObject{Tags: map[string]*string{"foo": pointer.String("bar")}}
+	armClient := mockarmclient.NewMockInterface(ctrl)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+	}
+	armClient.EXPECT().PatchResource(gomock.Any(), pointer.StringDeref(rt1.ID, ""), tags).Return(response, nil).Times(1)
+	armClient.EXPECT().CloseResponse(gomock.Any(), gomock.Any()).Times(1)
+
+	rtClient := getTestRouteTableClient(armClient)
+	rerr := rtClient.UpdateTags(context.TODO(), "rg", "rt1", tags)
+	assert.Nil(t, rerr)
+}
+
+func TestUpdateTagsWithNeverRateLimiter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rcUpdateTagsErr := retry.GetRateLimitError(true, "RouteTableUpdateTags")
+	armClient := mockarmclient.NewMockInterface(ctrl)
+
+	routetableClient := getTestRouteTableClientWithNeverRateLimiter(armClient)
+	rerr := routetableClient.UpdateTags(context.TODO(), "rg", "rt1", network.TagsObject{})
+	assert.Equal(t, rcUpdateTagsErr, rerr)
+}
+
+func TestUpdateTagsThrottle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	response := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Body:       io.NopCloser(bytes.NewReader([]byte("{}"))),
+	}
+	throttleErr := &retry.Error{
+		HTTPStatusCode: http.StatusTooManyRequests,
+		RawError:       fmt.Errorf("error"),
+		Retriable:      true,
+		RetryAfter:     time.Unix(100, 0),
+	}
+
+	rt1 := getTestRouteTable("rt1")
+	armClient := mockarmclient.NewMockInterface(ctrl)
+	armClient.EXPECT().PatchResource(gomock.Any(), pointer.StringDeref(rt1.ID, ""), network.TagsObject{}).Return(response, throttleErr).Times(1)
+	armClient.EXPECT().CloseResponse(gomock.Any(), gomock.Any()).Times(1)
+
+	routetableClient := getTestRouteTableClient(armClient)
+	rerr := routetableClient.UpdateTags(context.TODO(), "rg", "rt1", network.TagsObject{})
+	assert.Equal(t, throttleErr, rerr)
+	assert.Equal(t, throttleErr.RetryAfter, routetableClient.RetryAfterWriter)
+}
+
 func getTestRouteTable(name string) network.RouteTable {
 	return network.RouteTable{
 		ID:       pointer.String(fmt.Sprintf("/subscriptions/subscriptionID/resourceGroups/rg/providers/Microsoft.Network/routeTables/%s", name)),
@@ -41,4 +41,7 @@ type Interface interface {
 
 	// CreateOrUpdate creates or updates a RouteTable.
 	CreateOrUpdate(ctx context.Context, resourceGroupName string, routeTableName string, parameters network.RouteTable, etag string) *retry.Error
+
+	// UpdateTags updates the tags of a RouteTable without sending the routes.
+	UpdateTags(ctx context.Context, resourceGroupName string, routeTableName string, parameters network.TagsObject) *retry.Error
 }
@@ -86,3 +86,17 @@ func (mr *MockInterfaceMockRecorder) Get(ctx, resourceGroupName, routeTableName,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), ctx, resourceGroupName, routeTableName, expand)
 }
+
+// UpdateTags mocks base method.
+func (m *MockInterface) UpdateTags(ctx context.Context, resourceGroupName, routeTableName string, parameters network.TagsObject) *retry.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTags", ctx, resourceGroupName, routeTableName, parameters)
+	ret0, _ := ret[0].(*retry.Error)
+	return ret0
+}
+
+// UpdateTags indicates an expected call of UpdateTags.
+func (mr *MockInterfaceMockRecorder) UpdateTags(ctx, resourceGroupName, routeTableName, parameters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTags", reflect.TypeOf((*MockInterface)(nil).UpdateTags), ctx, resourceGroupName, routeTableName, parameters)
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/util/deepcopy"
 )
@@ -46,6 +47,10 @@ const (
 // GetFunc defines a getter function for timedCache.
 type GetFunc func(key string) (interface{}, error)
 
+// GetWithPreviousFunc defines a getter function for timedCache which is passed the data
+// cached for the key, or nil if the key is not cached yet.
+type GetWithPreviousFunc func(key string, previous interface{}) (interface{}, error)
+
 // AzureCacheEntry is the internal structure stores inside TTLStore.
 type AzureCacheEntry struct {
 	Key  string
@@ -55,6 +60,9 @@ type AzureCacheEntry struct {
 	Lock sync.Mutex
 	// time when entry was fetched and created
 	CreatedOn time.Time
+
+	// refreshing is true while the expired entry is refreshed in the background
+	refreshing bool
 }
 
 // cacheKeyFunc defines the key function required in TTLStore.
@@ -80,24 +88,51 @@ type TimedCache struct {
 	Store     cache.Store
 	MutexLock sync.RWMutex
 	TTL       time.Duration
+	// MaxStaleness enables the stale-while-revalidate mode if positive: an entry expired
+	// for less than MaxStaleness is returned by the default read and refreshed in the background.
+	MaxStaleness time.Duration
 
-	resourceProvider Resource
+	resourceProvider *ResourceProvider
+}
+
+// CacheOption configures the TimedCache.
+type CacheOption func(*TimedCache)
+
+// WithStaleWhileRevalidate returns the expired entries immediately on CacheReadTypeDefault reads
+// and refreshes them in the background, so that the callers don't wait for the getter. Only one
+// refresh per entry is in flight at a time. The entries expired for longer than maxStaleness are
+// refreshed synchronously as usual. The option is ignored if maxStaleness is not positive.
+func WithStaleWhileRevalidate(maxStaleness time.Duration) CacheOption {
+	return func(t *TimedCache) {
+		t.MaxStaleness = maxStaleness
+	}
 }
 
 type ResourceProvider struct {
 	Getter GetFunc
+	// GetterWithPrevious is used instead of Getter if set.
+	GetterWithPrevious GetWithPreviousFunc
 }
 
 // NewTimedCache creates a new azcache.Resource.
-func NewTimedCache(ttl time.Duration, getter GetFunc, disabled bool) (Resource, error) {
+func NewTimedCache(ttl time.Duration, getter GetFunc, disabled bool, opts ...CacheOption) (Resource, error) {
 	if getter == nil {
 		return nil, fmt.Errorf("getter is not provided")
 	}
+	return newTimedCache(ttl, &ResourceProvider{Getter: getter}, disabled, opts...)
+}
 
-	provider := &ResourceProvider{
-		Getter: getter,
+// NewTimedCacheWithPrevious creates a new azcache.Resource whose getter is passed the data cached
+// for the key, so that it doesn't have to read the entry, which is not locked while it is refreshed
+// in the background. The previous data is always nil if the cache is disabled.
+func NewTimedCacheWithPrevious(ttl time.Duration, getter GetWithPreviousFunc, disabled bool, opts ...CacheOption) (Resource, error) {
+	if getter == nil {
+		return nil, fmt.Errorf("getter is not provided")
 	}
+	return newTimedCache(ttl, &ResourceProvider{GetterWithPrevious: getter}, disabled, opts...)
+}
 
+func newTimedCache(ttl time.Duration, provider *ResourceProvider, disabled bool, opts ...CacheOption) (Resource, error) {
 	if disabled {
 		return provider, nil
 	}
@@ -111,6 +146,9 @@ func NewTimedCache(ttl time.Duration, getter GetFunc, disabled bool) (Resource,
 		TTL:              ttl,
 		resourceProvider: provider,
 	}
+	for _, opt := range opts {
+		opt(timedCache)
+	}
 	return timedCache, nil
 }
 
@@ -156,6 +194,13 @@ func (t *TimedCache) Get(key string, crt AzureCacheReadType) (interface{}, error
 }
 
 func (c *ResourceProvider) Get(key string, _ AzureCacheReadType) (interface{}, error) {
+	return c.get(key, nil)
+}
+
+func (c *ResourceProvider) get(key string, previous interface{}) (interface{}, error) {
+	if c.GetterWithPrevious != nil {
+		return c.GetterWithPrevious(key, previous)
+	}
 	return c.Getter(key)
 }
 
@@ -167,7 +212,7 @@ func (t *TimedCache) GetWithDeepCopy(key string, crt AzureCacheReadType) (interf
 }
 
 func (c *ResourceProvider) GetWithDeepCopy(key string, _ AzureCacheReadType) (interface{}, error) {
-	return c.Getter(key)
+	return c.get(key, nil)
 }
 
 func (t *TimedCache) get(key string, crt AzureCacheReadType) (interface{}, error) {
@@ -189,11 +234,20 @@ func (t *TimedCache) get(key string, crt AzureCacheReadType) (interface{}, error
 		if crt == CacheReadTypeDefault && time.Since(entry.CreatedOn) < t.TTL {
 			return entry.Data, nil
 		}
+		// if cached data is expired but not too stale, return cached data
+		// and refresh it in the background
+		if crt == CacheReadTypeDefault && t.MaxStaleness > 0 && time.Since(entry.CreatedOn) < t.TTL+t.MaxStaleness {
+			if !entry.refreshing {
+				entry.refreshing = true
+				go t.refresh(entry, entry.CreatedOn, entry.Data)
+			}
+			return entry.Data, nil
+		}
 	}
 	// Data is not cached yet, cache data is expired or requested force refresh
 	// cache it by getter. entry is locked before getting to ensure concurrent
 	// gets don't result in multiple ARM calls.
-	data, err := t.resourceProvider.Get(key, CacheReadTypeDefault /* not matter */)
+	data, err := t.resourceProvider.get(key, entry.Data)
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +260,27 @@ func (t *TimedCache) get(key string, crt AzureCacheReadType) (interface{}, error
 	return entry.Data, nil
 }
 
+// refresh fetches the data of the expired entry by getter. The data is dropped if the
+// entry has been updated since createdOn, as it may be older than the updated data.
+// previous is the data of the entry read under its lock when the refresh was started.
+func (t *TimedCache) refresh(entry *AzureCacheEntry, createdOn time.Time, previous interface{}) {
+	data, err := t.resourceProvider.get(entry.Key, previous)
+
+	entry.Lock.Lock()
+	defer entry.Lock.Unlock()
+
+	entry.refreshing = false
+	if err != nil {
+		klog.V(2).Infof("TimedCache: failed to refresh the expired entry %s in the background: %v", entry.Key, err)
+		return
+	}
+	if !entry.CreatedOn.Equal(createdOn) {
+		return
+	}
+	entry.Data = data
+	entry.CreatedOn = time.Now().UTC()
+}
+
 // Delete removes an item from the cache.
 func (t *TimedCache) Delete(key string) error {
 	return t.Store.Delete(&AzureCacheEntry{
@@ -311,3 +311,197 @@ func TestCacheForceRefresh(t *testing.T) {
 	assert.Equal(t, 2, dataSource.called)
 	assert.Equal(t, val, v, "should refetch unexpired data as forced refresh")
 }
+
+func newStaleWhileRevalidateCache(t *testing.T, getter GetFunc) *TimedCache {
+	cache, err := NewTimedCache(fakeCacheTTL, getter, false, WithStaleWhileRevalidate(fakeCacheTTL))
+	assert.NoError(t, err)
+	return cache.(*TimedCache)
+}
+
+// expire sets the entry of the key as fetched at the given time ago.
+func expire(t *testing.T, cache *TimedCache, key string, ago time.Duration) {
+	entry, err := cache.getInternal(key)
+	assert.NoError(t, err)
+	entry.Lock.Lock()
+	defer entry.Lock.Unlock()
+	entry.CreatedOn = time.Now().UTC().Add(-ago)
+}
+
+// waitForRefresh waits until the background refresh of the entry of the key finishes.
+func waitForRefresh(t *testing.T, cache *TimedCache, key string) {
+	entry, err := cache.getInternal(key)
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		entry.Lock.Lock()
+		defer entry.Lock.Unlock()
+		return !entry.refreshing
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	var lock sync.Mutex
+	called := 0
+	block := make(chan struct{})
+	data := &fakeDataObj{Data: "original"}
+	getter := func(_ string) (interface{}, error) {
+		lock.Lock()
+		called++
+		first := called == 1
+		lock.Unlock()
+		if !first {
+			<-block
+			return &fakeDataObj{Data: "refreshed"}, nil
+		}
+		return data, nil
+	}
+	cache := newStaleWhileRevalidateCache(t, getter)
+
+	v, err := cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, data, v)
+
+	expire(t, cache, testKey, fakeCacheTTL+time.Second)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := cache.Get(testKey, CacheReadTypeDefault)
+			assert.NoError(t, err)
+			assert.Equal(t, data, v, "cache should return the expired data while refreshing it")
+		}()
+	}
+	wg.Wait()
+
+	close(block)
+	waitForRefresh(t, cache, testKey)
+	lock.Lock()
+	assert.Equal(t, 2, called, "cache should refresh the expired entry only once")
+	lock.Unlock()
+
+	v, err = cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, &fakeDataObj{Data: "refreshed"}, v)
+}
+
+func TestCacheStaleWhileRevalidateMaxStaleness(t *testing.T) {
+	val := &fakeDataObj{}
+	dataSource := &fakeDataSource{sem: *semaphore.NewWeighted(1)}
+	dataSource.set(map[string]*fakeDataObj{testKey: val})
+	cache := newStaleWhileRevalidateCache(t, dataSource.get)
+
+	_, err := cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+
+	expire(t, cache, testKey, 2*fakeCacheTTL+time.Second)
+	v, err := cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, dataSource.called, "cache should refresh the too stale entry synchronously")
+	assert.Equal(t, val, v)
+}
+
+func TestCacheStaleWhileRevalidateRefreshError(t *testing.T) {
+	var lock sync.Mutex
+	var getErr error
+	called := 0
+	data := &fakeDataObj{Data: "original"}
+	getter := func(_ string) (interface{}, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		called++
+		return data, getErr
+	}
+	cache := newStaleWhileRevalidateCache(t, getter)
+
+	_, err := cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+
+	lock.Lock()
+	getErr = fmt.Errorf("getError")
+	lock.Unlock()
+	expire(t, cache, testKey, fakeCacheTTL+time.Second)
+	v, err := cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, data, v)
+	waitForRefresh(t, cache, testKey)
+
+	v, err = cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, data, v, "cache should keep the expired data if the refresh fails")
+	waitForRefresh(t, cache, testKey)
+	lock.Lock()
+	assert.Equal(t, 3, called, "cache should retry the failed refresh on the next read")
+	lock.Unlock()
+}
+
+func TestCacheStaleWhileRevalidateWithUpdate(t *testing.T) {
+	var lock sync.Mutex
+	called := 0
+	block := make(chan struct{})
+	getter := func(_ string) (interface{}, error) {
+		lock.Lock()
+		called++
+		first := called == 1
+		lock.Unlock()
+		if !first {
+			<-block
+		}
+		return &fakeDataObj{Data: "fetched"}, nil
+	}
+	cache := newStaleWhileRevalidateCache(t, getter)
+
+	_, err := cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+
+	expire(t, cache, testKey, fakeCacheTTL+time.Second)
+	_, err = cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+
+	updated := &fakeDataObj{Data: "updated"}
+	cache.Update(testKey, updated)
+	close(block)
+	waitForRefresh(t, cache, testKey)
+
+	v, err := cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, v, "cache should not overwrite the updated data with the refreshed data")
+}
+
+func TestCacheGetWithPrevious(t *testing.T) {
+	var lock sync.Mutex
+	var previous []interface{}
+	called := 0
+	getter := func(_ string, p interface{}) (interface{}, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		previous = append(previous, p)
+		called++
+		return &fakeDataObj{Data: fmt.Sprint(called)}, nil
+	}
+	cache, err := NewTimedCacheWithPrevious(fakeCacheTTL, getter, false, WithStaleWhileRevalidate(fakeCacheTTL))
+	assert.NoError(t, err)
+	timedCache := cache.(*TimedCache)
+
+	first, err := cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	second, err := cache.Get(testKey, CacheReadTypeForceRefresh)
+	assert.NoError(t, err)
+
+	// the background refresh is passed the data cached when it started
+	expire(t, timedCache, testKey, fakeCacheTTL+time.Second)
+	_, err = cache.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	waitForRefresh(t, timedCache, testKey)
+
+	lock.Lock()
+	assert.Equal(t, []interface{}{nil, first, second}, previous)
+	previous = nil
+	lock.Unlock()
+
+	// the previous data is always nil if the cache is disabled
+	disabled, err := NewTimedCacheWithPrevious(fakeCacheTTL, getter, true)
+	assert.NoError(t, err)
+	_, err = disabled.Get(testKey, CacheReadTypeDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{nil}, previous)
+}
@@ -45,6 +45,22 @@ const (
 	LabelFailureDomainBetaRegion = "failure-domain.beta.kubernetes.io/region"
 	// LabelPlatformSubFaultDomain is the label key of platformSubFaultDomain
 	LabelPlatformSubFaultDomain = "topology.kubernetes.azure.com/sub-fault-domain"
+	// LabelPlatformFaultDomain is the label key of the platform fault domain
+	LabelPlatformFaultDomain = "topology.kubernetes.azure.com/fault-domain"
+	// LabelPlatformUpdateDomain is the label key of the platform update domain
+	LabelPlatformUpdateDomain = "topology.kubernetes.azure.com/update-domain"
+	// LabelProximityPlacementGroup is the label key of the proximity placement group name
+	LabelProximityPlacementGroup = "kubernetes.azure.com/proximity-placement-group"
+	// LabelCapacityReservationGroup is the label key of the capacity reservation group name
+	LabelCapacityReservationGroup = "kubernetes.azure.com/capacity-reservation-group"
+
+	// NodeAnnotationNodeInfoSource is the annotation key recording whether the node information
+	// was last retrieved from IMDS or ARM by cloud-node-manager.
+	NodeAnnotationNodeInfoSource = "node.kubernetes.azure.com/node-info-source"
+	// NodeInfoSourceIMDS means the node information was retrieved from the instance metadata service
+	NodeInfoSourceIMDS = "imds"
+	// NodeInfoSourceARM means the node information was retrieved from ARM
+	NodeInfoSourceARM = "arm"
 
 	// ADFSIdentitySystem is the override value for tenantID on Azure Stack clouds.
 	ADFSIdentitySystem = "adfs"
@@ -431,6 +447,9 @@ const (
 	DefaultNodeMaskCIDRIPv6 = 64
 	// DefaultNodeCIDRMaskSize is the default mask size for node cidr
 	DefaultNodeCIDRMaskSize = 24
+	// DefaultNodeCIDRCapacityWarningThreshold is the default percentage of free node cidrs in a
+	// cluster cidr below which warning events are emitted
+	DefaultNodeCIDRCapacityWarningThreshold = 10
 )
 
 // metadata service
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// accessTokenExpiryDelta is subtracted from the lifetime of the access token for the cache
+	// duration, so that kubelet doesn't pull with an access token about to expire.
+	accessTokenExpiryDelta = time.Minute
+)
+
+// errScopedTokenNotSupported indicates that the registry doesn't issue the repository scoped access tokens.
+var errScopedTokenNotSupported = errors.New("the registry doesn't support repository scoped access tokens")
+
+type acrAccessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// pullScope returns the scope of the access token pulling the repository.
+func pullScope(repository string) string {
+	return fmt.Sprintf("repository:%s:pull", repository)
+}
+
+// performScopedTokenExchange exchanges the ACR refresh token for an access token of the scope at the
+// token endpoint of the auth directive, and returns the access token and its expiry. The expiry is
+// zero if it is unknown. errScopedTokenNotSupported is returned if the registry rejects the exchange.
+func performScopedTokenExchange(directive *authDirective, refreshToken, scope string) (string, time.Time, error) {
+	data := url.Values{
+		"service":       []string{directive.service},
+		"scope":         []string{scope},
+		"grant_type":    []string{"refresh_token"},
+		"refresh_token": []string{refreshToken},
+	}
+	datac := data.Encode()
+	r, err := http.NewRequest("POST", directive.realm, bytes.NewBufferString(datac))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to construct request, got %w", err)
+	}
+	r.Header.Add(userAgentHeader, userAgent)
+	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Add("Content-Length", strconv.Itoa(len(datac)))
+
+	exchange, err := client.Do(r)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to reach token url %s: %w", directive.realm, err)
+	}
+	defer exchange.Body.Close()
+
+	switch exchange.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return "", time.Time{}, fmt.Errorf("%w: token url %s responded with status code %d", errScopedTokenNotSupported, directive.realm, exchange.StatusCode)
+	default:
+		return "", time.Time{}, fmt.Errorf("token url %s responded with status code %d", directive.realm, exchange.StatusCode)
+	}
+
+	limitedReader := &io.LimitedReader{R: exchange.Body, N: maxReadLength}
+	content, err := io.ReadAll(limitedReader)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error reading response from %s: %w", directive.realm, err)
+	}
+	if limitedReader.N <= 0 {
+		return "", time.Time{}, errors.New("the read limit is reached")
+	}
+
+	var tokenResp acrAccessTokenResponse
+	if err := json.Unmarshal(content, &tokenResp); err != nil || tokenResp.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("unable to read access token from response of %s", directive.realm)
+	}
+
+	return tokenResp.AccessToken, tokenExpiry(tokenResp.AccessToken), nil
+}
+
+// tokenExpiry returns the expiry in the "exp" claim of the JWT token, or zero if it is unknown.
+// The token is not verified, as it is only used to decide how long the token is cached.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// cacheDurationUntil returns how long kubelet may cache a token expiring at expiresOn.
+func cacheDurationUntil(expiresOn time.Time) time.Duration {
+	if expiresOn.IsZero() {
+		return 0
+	}
+	duration := time.Until(expiresOn) - accessTokenExpiryDelta
+	if duration < 0 {
+		return 0
+	}
+	return duration
+}
+
+// parseRepositoryFromImage returns the repository of the image in the registry of the login server,
+// e.g. "bar/image" for foo.azurecr.io/bar/image:version, or an empty string if it can't be parsed.
+func parseRepositoryFromImage(image, loginServer string) string {
+	repository, found := strings.CutPrefix(image, loginServer+"/")
+	if !found {
+		return ""
+	}
+	if index := strings.Index(repository, "@"); index != -1 {
+		repository = repository[:index]
+	}
+	if index := strings.LastIndex(repository, ":"); index != -1 && index > strings.LastIndex(repository, "/") {
+		repository = repository[:index]
+	}
+	return repository
+}
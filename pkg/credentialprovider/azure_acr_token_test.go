@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeJWT returns an unsigned JWT token expiring at expiresOn.
+func fakeJWT(expiresOn time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, expiresOn.Unix())))
+	return "eyJhbGciOiJub25lIn0." + payload + ".signature"
+}
+
+func TestPerformScopedTokenExchange(t *testing.T) {
+	expiresOn := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	tests := []struct {
+		name             string
+		httpStatusCode   int
+		body             string
+		wantToken        string
+		wantExpiresOn    time.Time
+		wantErr          string
+		wantNotSupported bool
+	}{
+		{
+			name:             "errScopedTokenNotSupported should be returned when the registry rejects the exchange",
+			httpStatusCode:   http.StatusNotFound,
+			wantErr:          "responded with status code 404",
+			wantNotSupported: true,
+		},
+		{
+			name:           "Error should be returned when the registry fails",
+			httpStatusCode: http.StatusInternalServerError,
+			wantErr:        "responded with status code 500",
+		},
+		{
+			name:           "Error should be returned when the response has no access token",
+			httpStatusCode: http.StatusOK,
+			body:           `{}`,
+			wantErr:        "unable to read access token",
+		},
+		{
+			name:           "Access token should be returned with its expiry",
+			httpStatusCode: http.StatusOK,
+			body:           fmt.Sprintf(`{"access_token": %q}`, fakeJWT(expiresOn)),
+			wantToken:      fakeJWT(expiresOn),
+			wantExpiresOn:  expiresOn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, "/oauth2/token", r.URL.Path)
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+				assert.Equal(t, "refresh", r.PostForm.Get("refresh_token"))
+				assert.Equal(t, "repository:bar/image:pull", r.PostForm.Get("scope"))
+				assert.Equal(t, "test.azurecr.io", r.PostForm.Get("service"))
+				w.WriteHeader(tt.httpStatusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			directive := &authDirective{realm: server.URL + "/oauth2/token", service: "test.azurecr.io"}
+			token, expiresOn, err := performScopedTokenExchange(directive, "refresh", pullScope("bar/image"))
+			assert.Equal(t, tt.wantToken, token)
+			assert.Equal(t, tt.wantExpiresOn, expiresOn)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, tt.wantNotSupported, errors.Is(err, errScopedTokenNotSupported))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	expiresOn := time.Unix(1700000000, 0)
+	assert.Equal(t, expiresOn, tokenExpiry(fakeJWT(expiresOn)))
+	assert.True(t, tokenExpiry("opaque-token").IsZero())
+	assert.True(t, tokenExpiry("a.!!!.c").IsZero())
+	assert.True(t, tokenExpiry("a."+base64.RawURLEncoding.EncodeToString([]byte(`{}`))+".c").IsZero())
+}
+
+func TestCacheDurationUntil(t *testing.T) {
+	assert.Equal(t, time.Duration(0), cacheDurationUntil(time.Time{}))
+	assert.Equal(t, time.Duration(0), cacheDurationUntil(time.Now().Add(time.Second)))
+	duration := cacheDurationUntil(time.Now().Add(time.Hour))
+	assert.True(t, duration > 58*time.Minute && duration <= 59*time.Minute, "unexpected duration %v", duration)
+}
+
+func TestParseRepositoryFromImage(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{image: "foo.azurecr.io/bar/image:version", expected: "bar/image"},
+		{image: "foo.azurecr.io/image", expected: "image"},
+		{image: "foo.azurecr.io/bar/image@sha256:abcdef", expected: "bar/image"},
+		{image: "foo.azurecr.io/bar/image:version@sha256:abcdef", expected: "bar/image"},
+		{image: "foo.azurecr.io:443/bar/image:version", expected: ""},
+		{image: "other.azurecr.io/bar/image:version", expected: ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, parseRepositoryFromImage(test.image, "foo.azurecr.io"), test.image)
+	}
+}
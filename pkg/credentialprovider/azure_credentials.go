@@ -17,22 +17,27 @@ limitations under the License.
 package credentialprovider
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
-	providerconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
-
-	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/go-autorest/autorest/azure"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	v1 "k8s.io/kubelet/pkg/apis/credentialprovider/v1"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient"
+	providerconfig "sigs.k8s.io/cloud-provider-azure/pkg/provider/config"
 )
 
 // Refer: https://github.com/kubernetes/kubernetes/blob/master/pkg/credentialprovider/azure/azure_credentials.go
@@ -43,8 +48,7 @@ const (
 )
 
 var (
-	containerRegistryUrls = []string{"*.azurecr.io", "*.azurecr.cn", "*.azurecr.de", "*.azurecr.us"}
-	acrRE                 = regexp.MustCompile(`^.+?\.(azurecr\.io|azurecr\.cn|azurecr\.de|azurecr\.us)`)
+	acrRE = regexp.MustCompile(`^.+?\.(azurecr\.io|azurecr\.cn|azurecr\.de|azurecr\.us)`)
 )
 
 // CredentialProvider is an interface implemented by the kubelet credential provider plugin to fetch
@@ -55,13 +59,46 @@ type CredentialProvider interface {
 
 // acrProvider implements the credential provider interface for Azure Container Registry.
 type acrProvider struct {
-	config                *providerconfig.AzureAuthConfig
-	environment           *azure.Environment
-	servicePrincipalToken *adal.ServicePrincipalToken
+	config      *providerconfig.AzureAuthConfig
+	environment *azure.Environment
+	// credential gets the ARM access tokens which are exchanged for the ACR refresh tokens
+	credential azcore.TokenCredential
+	// scope is the scope of the ARM access tokens
+	scope string
+	// clientOptions are the client options of the credentials of the service accounts
+	clientOptions *policy.ClientOptions
+	// newServiceAccountCredential creates the credentials of the service accounts
+	newServiceAccountCredential func(tenantID, clientID, serviceAccountToken string, clientOptions *policy.ClientOptions) (azcore.TokenCredential, error)
+	// repositoryScopedTokens enables returning the access tokens pulling the repositories of the images
+	repositoryScopedTokens bool
+	// registryMirrors maps the registry hosts to the ACRs backing them
+	registryMirrors []RegistryMirror
+	// tokenCache caches the tokens across the provider processes if not nil
+	tokenCache *tokenCache
+}
+
+// Option configures the ACR provider.
+type Option func(*acrProvider)
+
+// WithRepositoryScopedTokens makes the ACR provider return an access token which can only pull the
+// repository of the image, cached per image until it expires, instead of the registry-wide refresh
+// token. The refresh token is returned if the registry doesn't issue the repository scoped tokens.
+func WithRepositoryScopedTokens() Option {
+	return func(a *acrProvider) {
+		a.repositoryScopedTokens = true
+	}
+}
+
+// WithTokenCache makes the ACR provider cache the AAD access tokens, the auth challenges and the ACR
+// refresh tokens in the directory, shared by the provider processes executed by kubelet.
+func WithTokenCache(dir string) Option {
+	return func(a *acrProvider) {
+		a.tokenCache = newTokenCache(dir)
+	}
 }
 
 // NewAcrProvider creates a new instance of the ACR provider.
-func NewAcrProvider(configFile string) (CredentialProvider, error) {
+func NewAcrProvider(configFile string, opts ...Option) (CredentialProvider, error) {
 	if len(configFile) == 0 {
 		return nil, errors.New("no azure credential file is provided")
 	}
@@ -72,35 +109,129 @@ func NewAcrProvider(configFile string) (CredentialProvider, error) {
 	}
 	defer f.Close()
 
-	return newAcrProviderFromConfigReader(f)
+	return newAcrProviderFromConfigReader(f, opts...)
 }
 
-func newAcrProviderFromConfigReader(configReader io.Reader) (*acrProvider, error) {
-	config, env, err := providerconfig.ParseAzureAuthConfig(configReader)
+func newAcrProviderFromConfigReader(configReader io.Reader, opts ...Option) (*acrProvider, error) {
+	if configReader == nil {
+		return nil, errors.New("failed to load config: nil config is provided")
+	}
+	limitedReader := &io.LimitedReader{R: configReader, N: maxReadLength}
+	configContents, err := io.ReadAll(limitedReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	if limitedReader.N <= 0 {
+		return nil, errors.New("failed to load config: the read limit is reached")
+	}
 
-	servicePrincipalToken, err := providerconfig.GetServicePrincipalToken(config, env, env.ServiceManagementEndpoint)
+	config, env, err := providerconfig.ParseAzureAuthConfig(bytes.NewReader(configContents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	registryMirrors, err := parseRegistryMirrors(configContents)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create service principal token: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	return &acrProvider{
-		config:                config,
-		environment:           env,
-		servicePrincipalToken: servicePrincipalToken,
-	}, nil
+	// the credential is chosen in the order of the federated workload identity token file,
+	// the managed identity, the client secret and the client certificate
+	authProvider, err := azclient.NewAuthProvider(&config.ARMClientConfig, &config.AzureAuthConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the auth provider: %w", err)
+	}
+	credential := authProvider.GetAzIdentity()
+	if credential == nil {
+		return nil, providerconfig.ErrorNoAuth
+	}
+
+	provider := &acrProvider{
+		config:      config,
+		environment: env,
+		credential:  credential,
+		scope:       armTokenScope(authProvider.ClientOptions),
+
+		clientOptions:               authProvider.ClientOptions,
+		newServiceAccountCredential: newServiceAccountCredential,
+
+		registryMirrors: registryMirrors,
+	}
+	for _, opt := range opts {
+		opt(provider)
+	}
+	return provider, nil
 }
 
-func (a *acrProvider) GetCredentials(_ context.Context, image string, _ []string) (*v1.CredentialProviderResponse, error) {
-	loginServer := a.parseACRLoginServerFromImage(image)
+// armTokenScope returns the scope of the ARM access tokens of the cloud of the client options.
+func armTokenScope(clientOptions *policy.ClientOptions) string {
+	audience := cloud.AzurePublic.Services[cloud.ResourceManager].Audience
+	if clientOptions != nil {
+		if service, ok := clientOptions.Cloud.Services[cloud.ResourceManager]; ok && service.Audience != "" {
+			audience = service.Audience
+		}
+	}
+	return strings.TrimSuffix(audience, "/") + "/.default"
+}
+
+func (a *acrProvider) GetCredentials(ctx context.Context, image string, _ []string) (*v1.CredentialProviderResponse, error) {
+	registry, loginServer := a.parseLoginServerFromImage(image)
 	if loginServer == "" {
 		klog.V(2).Infof("image(%s) is not from ACR, return empty authentication", image)
+		return emptyCredentialsResponse(), nil
+	}
+
+	// the AAD credentials never leave the node, kubelet only gets the ACR tokens
+	username, password, directive, err := a.getFromACR(ctx, loginServer)
+	if err != nil {
+		klog.Errorf("error getting credentials from ACR for %s: %s", loginServer, err)
+		return nil, err
+	}
+	return a.credentialsResponse(image, registry, username, password, directive, false)
+}
+
+// emptyCredentialsResponse returns the response without credentials for the images not from ACR.
+func emptyCredentialsResponse() *v1.CredentialProviderResponse {
+	return &v1.CredentialProviderResponse{
+		CacheKeyType:  v1.RegistryPluginCacheKeyType,
+		CacheDuration: &metav1.Duration{Duration: 0},
+		Auth:          map[string]v1.AuthConfig{},
+	}
+}
+
+// credentialsResponse returns the response of the ACR refresh token of the registry, or of the
+// repository scoped access token if it's enabled. The refresh token is keyed by the image if perImage
+// is true, or by the registry otherwise.
+func (a *acrProvider) credentialsResponse(image, registry, username, password string, directive *authDirective, perImage bool) (*v1.CredentialProviderResponse, error) {
+	if a.repositoryScopedTokens {
+		response, err := a.getRepositoryScopedCredentials(image, registry, directive, password)
+		if err == nil {
+			return response, nil
+		}
+		if !errors.Is(err, errScopedTokenNotSupported) {
+			klog.Errorf("error getting repository scoped credentials from ACR for %s: %s", image, err)
+			return nil, err
+		}
+		klog.V(2).Infof("falling back to the registry-wide credentials for %s: %s", image, err)
+	}
+
+	if perImage {
+		repository := parseRepositoryFromImage(image, registry)
+		if repository == "" {
+			return nil, fmt.Errorf("failed to parse the repository of image %s", image)
+		}
+		cacheDuration := defaultCacheTTL
+		if expiresOn := tokenExpiry(password); !expiresOn.IsZero() {
+			cacheDuration = cacheDurationUntil(expiresOn)
+		}
 		return &v1.CredentialProviderResponse{
-			CacheKeyType:  v1.RegistryPluginCacheKeyType,
-			CacheDuration: &metav1.Duration{Duration: 0},
-			Auth:          map[string]v1.AuthConfig{},
+			CacheKeyType:  v1.ImagePluginCacheKeyType,
+			CacheDuration: &metav1.Duration{Duration: cacheDuration},
+			Auth: map[string]v1.AuthConfig{
+				registry + "/" + repository: {
+					Username: username,
+					Password: password,
+				},
+			},
 		}, nil
 	}
 
@@ -116,77 +247,137 @@ func (a *acrProvider) GetCredentials(_ context.Context, image string, _ []string
 		},
 	}
 
-	if a.config.UseManagedIdentityExtension {
-		username, password, err := a.getFromACR(loginServer)
-		if err != nil {
-			klog.Errorf("error getting credentials from ACR for %s: %s", loginServer, err)
-			return nil, err
-		}
+	response.Auth[registry] = v1.AuthConfig{
+		Username: username,
+		Password: password,
+	}
 
-		response.Auth[loginServer] = v1.AuthConfig{
-			Username: username,
-			Password: password,
-		}
-	} else {
-		// Add our entry for each of the supported container registry URLs
-		for _, url := range containerRegistryUrls {
-			cred := v1.AuthConfig{
-				Username: a.config.AADClientID,
-				Password: a.config.AADClientSecret,
-			}
-			response.Auth[url] = cred
-		}
+	return response, nil
+}
 
-		// Handle the custom cloud case
-		// In clouds where ACR is not yet deployed, the string will be empty
-		if a.environment != nil && strings.Contains(a.environment.ContainerRegistryDNSSuffix, ".azurecr.") {
-			customAcrSuffix := "*" + a.environment.ContainerRegistryDNSSuffix
-			hasBeenAdded := false
-			for _, url := range containerRegistryUrls {
-				if strings.EqualFold(url, customAcrSuffix) {
-					hasBeenAdded = true
-					break
-				}
-			}
+// getRepositoryScopedCredentials exchanges the ACR refresh token for an access token pulling the
+// repository of the image, and returns it keyed by the image until it expires.
+func (a *acrProvider) getRepositoryScopedCredentials(image, registry string, directive *authDirective, refreshToken string) (*v1.CredentialProviderResponse, error) {
+	repository := parseRepositoryFromImage(image, registry)
+	if repository == "" {
+		return nil, fmt.Errorf("%w: failed to parse the repository of image %s", errScopedTokenNotSupported, image)
+	}
 
-			if !hasBeenAdded {
-				cred := v1.AuthConfig{
-					Username: a.config.AADClientID,
-					Password: a.config.AADClientSecret,
-				}
-				response.Auth[customAcrSuffix] = cred
-			}
-		}
+	klog.V(4).Infof("exchanging an acr access_token for repository %s", repository)
+	accessToken, expiresOn, err := performScopedTokenExchange(directive, refreshToken, pullScope(repository))
+	if err != nil {
+		return nil, err
 	}
 
-	return response, nil
+	return &v1.CredentialProviderResponse{
+		CacheKeyType:  v1.ImagePluginCacheKeyType,
+		CacheDuration: &metav1.Duration{Duration: cacheDurationUntil(expiresOn)},
+		Auth: map[string]v1.AuthConfig{
+			registry + "/" + repository: {
+				Username: dockerTokenLoginUsernameGUID,
+				Password: accessToken,
+			},
+		},
+	}, nil
 }
 
-// getFromACR gets credentials from ACR.
-func (a *acrProvider) getFromACR(loginServer string) (string, string, error) {
-	// Run EnsureFresh to make sure the token is valid and does not expire
-	if err := a.servicePrincipalToken.EnsureFresh(); err != nil {
-		klog.Errorf("Failed to ensure fresh service principal token: %v", err)
-		return "", "", err
+// getFromACR gets credentials from ACR, and returns them with the auth directive of the login server.
+// The tokens and the auth directive are taken from the token cache if it's enabled, and the
+// credentials are fetched without it if the token cache fails.
+func (a *acrProvider) getFromACR(ctx context.Context, loginServer string) (string, string, *authDirective, error) {
+	if a.tokenCache == nil {
+		return a.getFromACRWithCache(ctx, loginServer, a.credential, a.config.TenantID, nil)
 	}
-	armAccessToken := a.servicePrincipalToken.OAuthToken()
 
-	klog.V(4).Infof("discovering auth redirects for: %s", loginServer)
-	directive, err := receiveChallengeFromLoginServer(loginServer, "https")
+	var username, password string
+	var directive *authDirective
+	var fetchErr error
+	err := a.tokenCache.update(func(contents *tokenCacheContents) error {
+		username, password, directive, fetchErr = a.getFromACRWithCache(ctx, loginServer, a.credential, a.config.TenantID, contents)
+		return fetchErr
+	})
+	if fetchErr != nil {
+		return "", "", nil, fetchErr
+	}
 	if err != nil {
-		klog.Errorf("failed to receive challenge: %s", err)
-		return "", "", err
+		klog.Warningf("failed to use the token cache, fetching the credentials without it: %v", err)
+		if password != "" {
+			return username, password, directive, nil
+		}
+		return a.getFromACRWithCache(ctx, loginServer, a.credential, a.config.TenantID, nil)
+	}
+	return username, password, directive, nil
+}
+
+// getFromACRWithCache gets credentials from ACR with the credential of the tenant, taking the tokens
+// and the auth directive from the cache contents and storing the fetched ones in it. The contents
+// are nil if the cache is disabled, and must be nil unless the credential is the one of the config.
+func (a *acrProvider) getFromACRWithCache(ctx context.Context, loginServer string, credential azcore.TokenCredential, tenantID string, contents *tokenCacheContents) (string, string, *authDirective, error) {
+	identity := a.identityKey()
+	refreshTokenKey := identity + "|" + loginServer
+
+	directive := contents.challenge(loginServer)
+	if directive == nil {
+		klog.V(4).Infof("discovering auth redirects for: %s", loginServer)
+		var err error
+		directive, err = receiveChallengeFromLoginServer(loginServer, "https")
+		if err != nil {
+			klog.Errorf("failed to receive challenge: %s", err)
+			return "", "", nil, err
+		}
+		contents.setChallenge(loginServer, directive)
+	}
+
+	if registryRefreshToken := contents.refreshToken(refreshTokenKey); registryRefreshToken != "" {
+		klog.V(4).Infof("using the cached acr refresh_token for %s", loginServer)
+		return dockerTokenLoginUsernameGUID, registryRefreshToken, directive, nil
+	}
+
+	accessTokenKey := identity + "|" + a.scope
+	armAccessToken := contents.accessToken(accessTokenKey)
+	if armAccessToken == "" {
+		token, err := credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{a.scope}})
+		if err != nil {
+			klog.Errorf("Failed to get the ARM access token: %v", err)
+			return "", "", nil, err
+		}
+		armAccessToken = token.Token
+		contents.setAccessToken(accessTokenKey, token.Token, token.ExpiresOn)
 	}
 
 	klog.V(4).Infof("exchanging an acr refresh_token")
 	registryRefreshToken, err := performTokenExchange(
-		loginServer, directive, a.config.TenantID, armAccessToken)
+		loginServer, directive, tenantID, armAccessToken)
 	if err != nil {
 		klog.Errorf("failed to perform token exchange: %s", err)
-		return "", "", err
+		return "", "", nil, err
 	}
+	contents.setRefreshToken(refreshTokenKey, registryRefreshToken)
+
+	return dockerTokenLoginUsernameGUID, registryRefreshToken, directive, nil
+}
+
+// identityKey identifies the identity the provider authenticates with in the token cache.
+func (a *acrProvider) identityKey() string {
+	return strings.Join([]string{
+		a.config.TenantID,
+		a.config.AADClientID,
+		a.config.AADClientCertPath,
+		strconv.FormatBool(a.config.UseManagedIdentityExtension),
+		a.config.UserAssignedIdentityID,
+		strconv.FormatBool(a.config.UseFederatedWorkloadIdentityExtension),
+	}, "|")
+}
 
-	return dockerTokenLoginUsernameGUID, registryRefreshToken, nil
+// parseLoginServerFromImage returns the registry host of the image and the login server of the ACR
+// backing it. The registry mirrors are matched before the ACR login servers. Empty strings are
+// returned if the image is not from ACR.
+func (a *acrProvider) parseLoginServerFromImage(image string) (string, string) {
+	if registry, loginServer := matchRegistryMirror(a.registryMirrors, image); loginServer != "" {
+		return registry, loginServer
+	}
+	loginServer := a.parseACRLoginServerFromImage(image)
+	return loginServer, loginServer
 }
 
 // parseACRLoginServerFromImage takes image as parameter and returns login server of it.
@@ -19,81 +19,244 @@ package credentialprovider
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"net/url"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/kubelet/pkg/apis/credentialprovider/v1"
 )
 
-const (
-	// msiEndpointEnv is the environment variable used to store the endpoint in go-autorest/adal.
-	msiEndpointEnv = "MSI_ENDPOINT"
-	// msiSecretEnv is the environment variable used to store the request secret in go-autorest/adal.
-	msiSecretEnv = "MSI_SECRET"
-)
+// fakeCredential returns the access token of the requested scope.
+type fakeCredential struct {
+	err error
+}
+
+func (c *fakeCredential) GetToken(_ context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if c.err != nil {
+		return azcore.AccessToken{}, c.err
+	}
+	return azcore.AccessToken{Token: "arm-token:" + options.Scopes[0], ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// newFakeRegistry starts a registry issuing an AAD challenge, exchanging the ARM access tokens for the
+// ACR refresh tokens and responding to the scoped token requests with the status code, and returns
+// its login server.
+func newFakeRegistry(t *testing.T, scopedTokenStatusCode int) string {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.Header().Set("Www-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/oauth2/token",service="%s"`, r.Host, r.Host))
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/oauth2/exchange":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "access_token_refresh_token", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "tenant", r.PostForm.Get("tenant"))
+			_, _ = fmt.Fprintf(w, `{"refresh_token":"acr-token-for-%s"}`, r.PostForm.Get("access_token"))
+		case "/oauth2/token":
+			assert.NoError(t, r.ParseForm())
+			w.WriteHeader(scopedTokenStatusCode)
+			_, _ = fmt.Fprintf(w, `{"access_token":%q}`, fakeJWT(time.Now().Add(time.Hour)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	originalClient := client
+	client = server.Client()
+	t.Cleanup(func() {
+		client = originalClient
+		server.Close()
+	})
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	return serverURL.Host
+}
 
 func TestGetCredentials(t *testing.T) {
-	configStr := `
+	loginServer := newFakeRegistry(t, http.StatusOK)
+
+	provider, err := newAcrProviderFromConfigReader(bytes.NewBufferString(`
     {
+        "tenantId": "tenant",
         "aadClientId": "foo",
         "aadClientSecret": "bar"
-    }`
-	result := []string{
-		"*.azurecr.io",
-		"*.azurecr.cn",
-		"*.azurecr.de",
-		"*.azurecr.us",
-	}
-
-	provider, err := newAcrProviderFromConfigReader(bytes.NewBufferString(configStr))
+    }`))
 	if err != nil {
 		t.Fatalf("Unexpected error when creating new acr provider: %v", err)
 	}
+	assert.Equal(t, "https://management.core.windows.net/.default", provider.scope)
+	provider.credential = &fakeCredential{}
+	provider.environment = &azure.Environment{
+		ContainerRegistryDNSSuffix: loginServer,
+	}
 
-	credResponse, err := provider.GetCredentials(context.TODO(), "foo.azurecr.io/nginx:v1", nil)
+	credResponse, err := provider.GetCredentials(context.TODO(), loginServer+"/nginx:v1", nil)
 	if err != nil {
 		t.Fatalf("Unexpected error when fetching acr credentials: %v", err)
 	}
 
-	if credResponse == nil || len(credResponse.Auth) != len(result)+1 {
-		t.Errorf("Unexpected credential response: %v, expected length %d", credResponse, len(result)+1)
+	assert.Equal(t, map[string]v1.AuthConfig{
+		"*.azurecr.*": {},
+		loginServer: {
+			Username: dockerTokenLoginUsernameGUID,
+			Password: "acr-token-for-arm-token:https://management.core.windows.net/.default",
+		},
+	}, credResponse.Auth, "the AAD client secret should never be returned")
+	assert.Equal(t, defaultCacheTTL, credResponse.CacheDuration.Duration)
+}
+
+func TestGetCredentialsWithRepositoryScopedTokens(t *testing.T) {
+	tests := []struct {
+		desc                  string
+		image                 string
+		scopedTokenStatusCode int
+		expectedCacheKeyType  v1.PluginCacheKeyType
+		expectedAuthKey       string
+		expectError           bool
+	}{
+		{
+			desc:                  "repository scoped access token should be returned keyed by the image",
+			image:                 "bar/image:v1",
+			scopedTokenStatusCode: http.StatusOK,
+			expectedCacheKeyType:  v1.ImagePluginCacheKeyType,
+			expectedAuthKey:       "/bar/image",
+		},
+		{
+			desc:                  "registry-wide refresh token should be returned if the registry doesn't issue scoped tokens",
+			image:                 "bar/image:v1",
+			scopedTokenStatusCode: http.StatusNotFound,
+			expectedCacheKeyType:  v1.RegistryPluginCacheKeyType,
+		},
+		{
+			desc:                  "error should be returned if the registry fails to issue the scoped token",
+			image:                 "bar/image:v1",
+			scopedTokenStatusCode: http.StatusUnauthorized,
+			expectError:           true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			loginServer := newFakeRegistry(t, test.scopedTokenStatusCode)
+			provider, err := newAcrProviderFromConfigReader(bytes.NewBufferString(`{"tenantId": "tenant", "useManagedIdentityExtension": true}`), WithRepositoryScopedTokens())
+			assert.NoError(t, err)
+			provider.credential = &fakeCredential{}
+			provider.environment = &azure.Environment{
+				ContainerRegistryDNSSuffix: loginServer,
+			}
+
+			credResponse, err := provider.GetCredentials(context.TODO(), loginServer+"/"+test.image, nil)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCacheKeyType, credResponse.CacheKeyType)
+			if test.expectedCacheKeyType == v1.ImagePluginCacheKeyType {
+				assert.Len(t, credResponse.Auth, 1)
+				auth, found := credResponse.Auth[loginServer+test.expectedAuthKey]
+				assert.True(t, found)
+				assert.Equal(t, dockerTokenLoginUsernameGUID, auth.Username)
+				assert.False(t, tokenExpiry(auth.Password).IsZero())
+				assert.True(t, credResponse.CacheDuration.Duration > 58*time.Minute && credResponse.CacheDuration.Duration <= 59*time.Minute)
+			} else {
+				assert.Equal(t, "acr-token-for-arm-token:https://management.core.windows.net/.default", credResponse.Auth[loginServer].Password)
+				assert.Equal(t, defaultCacheTTL, credResponse.CacheDuration.Duration)
+			}
+		})
+	}
+}
+
+func TestGetCredentialsWithRegistryMirrors(t *testing.T) {
+	for _, repositoryScopedTokens := range []bool{false, true} {
+		t.Run(fmt.Sprintf("repositoryScopedTokens=%t", repositoryScopedTokens), func(t *testing.T) {
+			loginServer := newFakeRegistry(t, http.StatusOK)
+			var opts []Option
+			if repositoryScopedTokens {
+				opts = append(opts, WithRepositoryScopedTokens())
+			}
+			provider, err := newAcrProviderFromConfigReader(bytes.NewBufferString(fmt.Sprintf(`
+    {
+        "tenantId": "tenant",
+        "useManagedIdentityExtension": true,
+        "registryMirrors": [{"host": "*.io", "loginServer": %q}]
+    }`, loginServer)), opts...)
+			assert.NoError(t, err)
+			provider.credential = &fakeCredential{}
+
+			credResponse, err := provider.GetCredentials(context.TODO(), "docker.io/library/nginx:latest", nil)
+			assert.NoError(t, err)
+			if repositoryScopedTokens {
+				assert.Equal(t, v1.ImagePluginCacheKeyType, credResponse.CacheKeyType)
+				assert.Len(t, credResponse.Auth, 1)
+				assert.Contains(t, credResponse.Auth, "docker.io/library/nginx")
+			} else {
+				assert.Equal(t, v1.RegistryPluginCacheKeyType, credResponse.CacheKeyType)
+				assert.Equal(t, "acr-token-for-arm-token:https://management.core.windows.net/.default", credResponse.Auth["docker.io"].Password)
+				assert.NotContains(t, credResponse.Auth, loginServer)
+			}
+		})
 	}
-	for _, cred := range credResponse.Auth {
-		if cred.Username != "" && cred.Username != "foo" {
-			t.Errorf("expected 'foo' for username, saw: %v", cred.Username)
+}
+
+func TestGetCredentialsWithTokenCache(t *testing.T) {
+	loginServer := newFakeRegistry(t, http.StatusOK)
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	newProvider := func(credential azcore.TokenCredential) *acrProvider {
+		provider, err := newAcrProviderFromConfigReader(bytes.NewBufferString(`{"tenantId": "tenant", "useManagedIdentityExtension": true}`), WithTokenCache(dir))
+		if err != nil {
+			t.Fatal(err)
 		}
-		if cred.Password != "" && cred.Password != "bar" {
-			t.Errorf("expected 'bar' for password, saw: %v", cred.Username)
+		provider.credential = credential
+		provider.environment = &azure.Environment{
+			ContainerRegistryDNSSuffix: loginServer,
 		}
+		return provider
 	}
-	for _, registryName := range result {
-		if _, found := credResponse.Auth[registryName]; !found {
-			t.Errorf("Missing expected registry: %s", registryName)
-		}
+
+	credResponse, err := newProvider(&fakeCredential{}).GetCredentials(context.TODO(), loginServer+"/nginx:v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "acr-token-for-arm-token:https://management.core.windows.net/.default", credResponse.Auth[loginServer].Password)
+
+	// the next provider process gets the cached refresh token without requesting AAD
+	credResponse, err = newProvider(&fakeCredential{err: errors.New("token error")}).GetCredentials(context.TODO(), loginServer+"/nginx:v1", nil)
+	if err != nil {
+		t.Fatal(err)
 	}
+	assert.Equal(t, "acr-token-for-arm-token:https://management.core.windows.net/.default", credResponse.Auth[loginServer].Password)
+
+	// the refresh token of a different identity is not shared
+	provider := newProvider(&fakeCredential{err: errors.New("token error")})
+	provider.config.UserAssignedIdentityID = "other"
+	_, err = provider.GetCredentials(context.TODO(), loginServer+"/nginx:v1", nil)
+	assert.EqualError(t, err, "token error")
 }
 
-func TestGetCredentialsConfig(t *testing.T) {
-	// msiEndpointEnv and msiSecretEnv are required because autorest/adal requires IMDS endpoint to be available.
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte("{}"))
-		assert.NoError(t, err)
-	}))
-	originalEnv := os.Getenv(msiEndpointEnv)
-	originalSecret := os.Getenv(msiSecretEnv)
-	os.Setenv(msiEndpointEnv, server.URL)
-	os.Setenv(msiSecretEnv, "secret")
-	defer func() {
-		server.Close()
-		os.Setenv(msiEndpointEnv, originalEnv)
-		os.Setenv(msiSecretEnv, originalSecret)
-	}()
+func TestGetCredentialsTokenError(t *testing.T) {
+	loginServer := newFakeRegistry(t, http.StatusOK)
 
+	provider, err := newAcrProviderFromConfigReader(bytes.NewBufferString(`{"useManagedIdentityExtension": true}`))
+	if err != nil {
+		t.Fatalf("Unexpected error when creating new acr provider: %v", err)
+	}
+	provider.credential = &fakeCredential{err: errors.New("token error")}
+
+	_, _, _, err = provider.getFromACR(context.TODO(), loginServer)
+	assert.EqualError(t, err, "token error")
+}
+
+func TestGetCredentialsConfig(t *testing.T) {
 	testCases := []struct {
 		desc                string
 		image               string
@@ -107,14 +270,25 @@ func TestGetCredentialsConfig(t *testing.T) {
 			expectError: true,
 		},
 		{
-			desc:  "Multiple credentials should be returned when using Service Principal",
-			image: "foo.azurecr.io/bar/image:v1",
+			desc:        "Error should be returned when no credential is configured",
+			configStr:   `{"tenantId": "tenant"}`,
+			expectError: true,
+		},
+		{
+			desc:        "Error should be returned when a registry mirror is invalid",
+			configStr:   `{"useManagedIdentityExtension": true, "registryMirrors": [{"host": "docker.io"}]}`,
+			expectError: true,
+		},
+		{
+			desc:  "0 credential should be returned for non-ACR image using Service Principal",
+			image: "busybox",
 			configStr: `
     {
+        "tenantId": "tenant",
         "aadClientId": "foo",
         "aadClientSecret": "bar"
     }`,
-			expectedCredsLength: 5,
+			expectedCredsLength: 0,
 		},
 		{
 			desc:  "0 credential should be returned for non-ACR image using Managed Identity",
@@ -122,6 +296,18 @@ func TestGetCredentialsConfig(t *testing.T) {
 			configStr: `
     {
 		"useManagedIdentityExtension": true
+    }`,
+			expectedCredsLength: 0,
+		},
+		{
+			desc:  "0 credential should be returned for non-ACR image using Workload Identity",
+			image: "busybox",
+			configStr: `
+    {
+        "tenantId": "tenant",
+        "aadClientId": "foo",
+        "useFederatedWorkloadIdentityExtension": true,
+        "aadFederatedTokenFile": "/var/run/secrets/azure/tokens/azure-identity-token"
     }`,
 			expectedCredsLength: 0,
 		},
@@ -132,7 +318,8 @@ func TestGetCredentialsConfig(t *testing.T) {
 		if err != nil && !test.expectError {
 			t.Fatalf("Unexpected error when creating new acr provider: %v", err)
 		}
-		if err != nil && test.expectError {
+		if test.expectError {
+			assert.Error(t, err, "TestCase[%d]: %s", i, test.desc)
 			continue
 		}
 
@@ -149,6 +336,7 @@ func TestGetCredentialsConfig(t *testing.T) {
 func TestParseACRLoginServerFromImage(t *testing.T) {
 	configStr := `
     {
+        "tenantId": "tenant",
         "aadClientId": "foo",
         "aadClientSecret": "bar"
     }`
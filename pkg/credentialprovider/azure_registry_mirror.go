@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// RegistryMirror maps the registry hosts to the ACR backing them, e.g. docker.io pulled through an
+// ACR pull-through cache, or a custom domain or a connected registry of an ACR. The images of the
+// hosts get the credentials of the ACR keyed by the requested host, so the hosts must also be in
+// the matchImages of the kubelet credential provider config.
+type RegistryMirror struct {
+	// Host is the registry host pattern matched with path.Match, e.g. docker.io or *.contoso.com.
+	Host string `json:"host" yaml:"host"`
+	// LoginServer is the login server of the ACR backing the registry, e.g. foo.azurecr.io.
+	LoginServer string `json:"loginServer" yaml:"loginServer"`
+}
+
+// acrProviderConfig is the config of the ACR provider besides the auth config in the same file.
+type acrProviderConfig struct {
+	// RegistryMirrors are matched in order against the host of the image.
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty" yaml:"registryMirrors,omitempty"`
+}
+
+// parseRegistryMirrors parses and validates the registry mirrors in the config contents.
+func parseRegistryMirrors(configContents []byte) ([]RegistryMirror, error) {
+	var config acrProviderConfig
+	if err := yaml.Unmarshal(configContents, &config); err != nil {
+		return nil, err
+	}
+
+	for i, mirror := range config.RegistryMirrors {
+		if mirror.Host == "" || mirror.LoginServer == "" {
+			return nil, fmt.Errorf("registryMirrors[%d]: both host and loginServer are required", i)
+		}
+		if _, err := path.Match(mirror.Host, ""); err != nil {
+			return nil, fmt.Errorf("registryMirrors[%d]: invalid host pattern %q: %w", i, mirror.Host, err)
+		}
+		if strings.Contains(mirror.LoginServer, "/") {
+			return nil, fmt.Errorf("registryMirrors[%d]: loginServer %q must be a host", i, mirror.LoginServer)
+		}
+	}
+	return config.RegistryMirrors, nil
+}
+
+// matchRegistryMirror returns the host of the image and the login server of the first registry
+// mirror matching it, or empty strings if no mirror matches. The image is expected with the
+// registry host, e.g. docker.io/library/nginx:latest.
+func matchRegistryMirror(mirrors []RegistryMirror, image string) (string, string) {
+	host, _, found := strings.Cut(image, "/")
+	if !found {
+		return "", ""
+	}
+	for _, mirror := range mirrors {
+		if matched, _ := path.Match(mirror.Host, host); matched {
+			return host, mirror.LoginServer
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRegistryMirrors(t *testing.T) {
+	tests := []struct {
+		desc            string
+		config          string
+		expectedMirrors []RegistryMirror
+		expectedErr     string
+	}{
+		{
+			desc:   "no registry mirrors",
+			config: `{"tenantId": "tenant"}`,
+		},
+		{
+			desc: "json registry mirrors",
+			config: `{"registryMirrors": [
+				{"host": "docker.io", "loginServer": "foo.azurecr.io"},
+				{"host": "*.contoso.com", "loginServer": "bar.azurecr.io"}
+			]}`,
+			expectedMirrors: []RegistryMirror{
+				{Host: "docker.io", LoginServer: "foo.azurecr.io"},
+				{Host: "*.contoso.com", LoginServer: "bar.azurecr.io"},
+			},
+		},
+		{
+			desc:            "yaml registry mirrors",
+			config:          "registryMirrors:\n- host: mcr.microsoft.com\n  loginServer: foo.azurecr.io\n",
+			expectedMirrors: []RegistryMirror{{Host: "mcr.microsoft.com", LoginServer: "foo.azurecr.io"}},
+		},
+		{
+			desc:        "missing login server",
+			config:      `{"registryMirrors": [{"host": "docker.io"}]}`,
+			expectedErr: "registryMirrors[0]: both host and loginServer are required",
+		},
+		{
+			desc:        "invalid host pattern",
+			config:      `{"registryMirrors": [{"host": "[docker.io", "loginServer": "foo.azurecr.io"}]}`,
+			expectedErr: `registryMirrors[0]: invalid host pattern "[docker.io": syntax error in pattern`,
+		},
+		{
+			desc:        "login server with repository",
+			config:      `{"registryMirrors": [{"host": "docker.io", "loginServer": "foo.azurecr.io/docker"}]}`,
+			expectedErr: `registryMirrors[0]: loginServer "foo.azurecr.io/docker" must be a host`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			mirrors, err := parseRegistryMirrors([]byte(test.config))
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedMirrors, mirrors)
+		})
+	}
+}
+
+func TestMatchRegistryMirror(t *testing.T) {
+	mirrors := []RegistryMirror{
+		{Host: "docker.io", LoginServer: "foo.azurecr.io"},
+		{Host: "*.contoso.com", LoginServer: "bar.azurecr.io"},
+		{Host: "registry.contoso.com", LoginServer: "baz.azurecr.io"},
+	}
+	tests := []struct {
+		image               string
+		expectedRegistry    string
+		expectedLoginServer string
+	}{
+		{
+			image:               "docker.io/library/nginx:latest",
+			expectedRegistry:    "docker.io",
+			expectedLoginServer: "foo.azurecr.io",
+		},
+		{
+			image:               "registry.contoso.com/app@sha256:abc",
+			expectedRegistry:    "registry.contoso.com",
+			expectedLoginServer: "bar.azurecr.io",
+		},
+		{
+			image: "contoso.com/app:v1",
+		},
+		{
+			image: "nginx:latest",
+		},
+		{
+			image: "mcr.microsoft.com/oss/nginx:v1",
+		},
+	}
+
+	for _, test := range tests {
+		registry, loginServer := matchRegistryMirror(mirrors, test.image)
+		assert.Equal(t, test.expectedRegistry, registry, test.image)
+		assert.Equal(t, test.expectedLoginServer, loginServer, test.image)
+	}
+}
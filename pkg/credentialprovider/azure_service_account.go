@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"k8s.io/klog/v2"
+	v1 "k8s.io/kubelet/pkg/apis/credentialprovider/v1"
+)
+
+const (
+	// ServiceAccountClientIDAnnotation is the service account annotation of the client ID of the
+	// identity federated with the service account, the same as the one of Azure workload identity.
+	ServiceAccountClientIDAnnotation = "azure.workload.identity/client-id"
+	// ServiceAccountTenantIDAnnotation is the service account annotation of the tenant ID of the
+	// identity federated with the service account. The tenant ID in the config is used if it's not set.
+	ServiceAccountTenantIDAnnotation = "azure.workload.identity/tenant-id"
+)
+
+// ServiceAccountCredentialProvider is implemented by the credential providers pulling the images with
+// the service account tokens of the pods, which kubelet passes to the plugins configured with the
+// service account token audience. The annotations of the service account are only passed if they
+// are in the required or optional service account annotation keys of the plugin.
+type ServiceAccountCredentialProvider interface {
+	GetCredentialsWithServiceAccount(ctx context.Context, image, serviceAccountToken string, serviceAccountAnnotations map[string]string) (response *v1.CredentialProviderResponse, err error)
+}
+
+// newServiceAccountCredential creates the credential exchanging the service account token for the
+// AAD access tokens of the identity federated with the service account.
+func newServiceAccountCredential(tenantID, clientID, serviceAccountToken string, clientOptions *policy.ClientOptions) (azcore.TokenCredential, error) {
+	options := &azidentity.ClientAssertionCredentialOptions{}
+	if clientOptions != nil {
+		options.ClientOptions = *clientOptions
+	}
+	return azidentity.NewClientAssertionCredential(tenantID, clientID, func(context.Context) (string, error) {
+		return serviceAccountToken, nil
+	}, options)
+}
+
+// GetCredentialsWithServiceAccount exchanges the service account token of the pod for the ACR tokens
+// of the identity in the service account annotations, and returns them keyed by the image. The token
+// cache is never used, so that the ACR tokens are only returned to the pods whose service account
+// tokens are federated with the identity.
+func (a *acrProvider) GetCredentialsWithServiceAccount(ctx context.Context, image, serviceAccountToken string, serviceAccountAnnotations map[string]string) (*v1.CredentialProviderResponse, error) {
+	registry, loginServer := a.parseLoginServerFromImage(image)
+	if loginServer == "" {
+		klog.V(2).Infof("image(%s) is not from ACR, return empty authentication", image)
+		return emptyCredentialsResponse(), nil
+	}
+
+	clientID := serviceAccountAnnotations[ServiceAccountClientIDAnnotation]
+	if clientID == "" {
+		return nil, fmt.Errorf("annotation %s of the service account is required to pull image %s with the service account token", ServiceAccountClientIDAnnotation, image)
+	}
+	tenantID := serviceAccountAnnotations[ServiceAccountTenantIDAnnotation]
+	if tenantID == "" {
+		tenantID = a.config.TenantID
+	}
+
+	credential, err := a.newServiceAccountCredential(tenantID, clientID, serviceAccountToken, a.clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the credential of client %s: %w", clientID, err)
+	}
+
+	username, password, directive, err := a.getFromACRWithCache(ctx, loginServer, credential, tenantID, nil)
+	if err != nil {
+		klog.Errorf("error getting credentials from ACR for %s with the identity of client %s: %s", loginServer, clientID, err)
+		return nil, err
+	}
+	return a.credentialsResponse(image, registry, username, password, directive, true)
+}
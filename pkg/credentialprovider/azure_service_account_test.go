@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/kubelet/pkg/apis/credentialprovider/v1"
+)
+
+func TestGetCredentialsWithServiceAccount(t *testing.T) {
+	tests := []struct {
+		desc            string
+		image           string
+		configTenantID  string
+		annotations     map[string]string
+		expectedAuthKey string
+		expectedErr     string
+	}{
+		{
+			desc:            "ACR refresh token of the service account identity should be returned keyed by the image",
+			image:           "bar/image:v1",
+			configTenantID:  "tenant",
+			annotations:     map[string]string{ServiceAccountClientIDAnnotation: "client"},
+			expectedAuthKey: "/bar/image",
+		},
+		{
+			desc:            "tenant in the service account annotations should be used",
+			image:           "bar/image@sha256:abc",
+			configTenantID:  "node-tenant",
+			annotations:     map[string]string{ServiceAccountClientIDAnnotation: "client", ServiceAccountTenantIDAnnotation: "tenant"},
+			expectedAuthKey: "/bar/image",
+		},
+		{
+			desc:           "error should be returned without the client ID annotation",
+			image:          "bar/image:v1",
+			configTenantID: "tenant",
+			annotations:    map[string]string{ServiceAccountTenantIDAnnotation: "tenant"},
+			expectedErr:    "annotation azure.workload.identity/client-id of the service account is required to pull image",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			loginServer := newFakeRegistry(t, http.StatusOK)
+			cacheDir := filepath.Join(t.TempDir(), "cache")
+			provider, err := newAcrProviderFromConfigReader(bytes.NewBufferString(fmt.Sprintf(`{"tenantId": %q, "useManagedIdentityExtension": true}`, test.configTenantID)), WithTokenCache(cacheDir))
+			if err != nil {
+				t.Fatal(err)
+			}
+			provider.environment = &azure.Environment{
+				ContainerRegistryDNSSuffix: loginServer,
+			}
+			var tenantID, clientID, serviceAccountToken string
+			provider.newServiceAccountCredential = func(tenant, client, token string, _ *policy.ClientOptions) (azcore.TokenCredential, error) {
+				tenantID, clientID, serviceAccountToken = tenant, client, token
+				return &fakeCredential{}, nil
+			}
+
+			credResponse, err := provider.GetCredentialsWithServiceAccount(context.TODO(), loginServer+"/"+test.image, "sa-token", test.annotations)
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, "tenant", tenantID)
+			assert.Equal(t, "client", clientID)
+			assert.Equal(t, "sa-token", serviceAccountToken)
+			assert.Equal(t, v1.ImagePluginCacheKeyType, credResponse.CacheKeyType)
+			assert.Equal(t, defaultCacheTTL, credResponse.CacheDuration.Duration)
+			assert.Equal(t, map[string]v1.AuthConfig{
+				loginServer + test.expectedAuthKey: {
+					Username: dockerTokenLoginUsernameGUID,
+					Password: "acr-token-for-arm-token:https://management.core.windows.net/.default",
+				},
+			}, credResponse.Auth)
+
+			_, err = os.Stat(cacheDir)
+			assert.True(t, os.IsNotExist(err), "the token cache should not be used for the service account tokens")
+		})
+	}
+}
+
+func TestGetCredentialsWithServiceAccountForNonACRImage(t *testing.T) {
+	provider, err := newAcrProviderFromConfigReader(bytes.NewBufferString(`{"tenantId": "tenant", "useManagedIdentityExtension": true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.newServiceAccountCredential = func(_, _, _ string, _ *policy.ClientOptions) (azcore.TokenCredential, error) {
+		t.Error("the credential should not be created for the images not from ACR")
+		return &fakeCredential{}, nil
+	}
+
+	credResponse, err := provider.GetCredentialsWithServiceAccount(context.TODO(), "docker.io/library/busybox", "sa-token", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, credResponse.Auth)
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	tokenCacheFileName     = "tokens.json"
+	tokenCacheLockFileName = "tokens.lock"
+
+	// tokenCacheExpiryDelta is how long a cached token must stay valid to be used, so that the
+	// refresh token returned to kubelet outlives the duration kubelet caches it.
+	tokenCacheExpiryDelta = defaultCacheTTL + time.Minute
+	// challengeCacheTTL is how long the auth challenge of a login server is cached.
+	challengeCacheTTL = time.Hour
+)
+
+// cachedToken is a token cached until it expires.
+type cachedToken struct {
+	Token     string    `json:"token"`
+	ExpiresOn time.Time `json:"expiresOn"`
+}
+
+// cachedChallenge is the auth challenge of a login server cached until it expires.
+type cachedChallenge struct {
+	Service   string    `json:"service"`
+	Realm     string    `json:"realm"`
+	ExpiresOn time.Time `json:"expiresOn"`
+}
+
+// tokenCacheContents is the contents of the token cache file. All the methods are no-op on nil
+// contents, so the credentials are fetched the same way whether the cache is enabled or not.
+type tokenCacheContents struct {
+	// AccessTokens are the AAD access tokens keyed by the identity and the scope.
+	AccessTokens map[string]cachedToken `json:"accessTokens,omitempty"`
+	// Challenges are the auth challenges keyed by the login server.
+	Challenges map[string]cachedChallenge `json:"challenges,omitempty"`
+	// RefreshTokens are the ACR refresh tokens keyed by the identity and the login server.
+	RefreshTokens map[string]cachedToken `json:"refreshTokens,omitempty"`
+
+	now     time.Time
+	changed bool
+}
+
+func (c *tokenCacheContents) accessToken(key string) string {
+	if c == nil {
+		return ""
+	}
+	return c.validToken(c.AccessTokens[key])
+}
+
+func (c *tokenCacheContents) setAccessToken(key, token string, expiresOn time.Time) {
+	if c == nil {
+		return
+	}
+	if c.AccessTokens == nil {
+		c.AccessTokens = map[string]cachedToken{}
+	}
+	c.AccessTokens[key] = cachedToken{Token: token, ExpiresOn: expiresOn}
+	c.changed = true
+}
+
+func (c *tokenCacheContents) challenge(loginServer string) *authDirective {
+	if c == nil {
+		return nil
+	}
+	challenge, found := c.Challenges[loginServer]
+	if !found || !c.now.Before(challenge.ExpiresOn) {
+		return nil
+	}
+	return &authDirective{service: challenge.Service, realm: challenge.Realm}
+}
+
+func (c *tokenCacheContents) setChallenge(loginServer string, directive *authDirective) {
+	if c == nil {
+		return
+	}
+	if c.Challenges == nil {
+		c.Challenges = map[string]cachedChallenge{}
+	}
+	c.Challenges[loginServer] = cachedChallenge{
+		Service:   directive.service,
+		Realm:     directive.realm,
+		ExpiresOn: c.now.Add(challengeCacheTTL),
+	}
+	c.changed = true
+}
+
+func (c *tokenCacheContents) refreshToken(key string) string {
+	if c == nil {
+		return ""
+	}
+	return c.validToken(c.RefreshTokens[key])
+}
+
+// setRefreshToken caches the refresh token until the expiry in it, or for the default cache TTL
+// of kubelet if the expiry is unknown.
+func (c *tokenCacheContents) setRefreshToken(key, token string) {
+	if c == nil {
+		return
+	}
+	expiresOn := tokenExpiry(token)
+	if expiresOn.IsZero() {
+		expiresOn = c.now.Add(tokenCacheExpiryDelta + defaultCacheTTL)
+	}
+	if c.RefreshTokens == nil {
+		c.RefreshTokens = map[string]cachedToken{}
+	}
+	c.RefreshTokens[key] = cachedToken{Token: token, ExpiresOn: expiresOn}
+	c.changed = true
+}
+
+func (c *tokenCacheContents) validToken(token cachedToken) string {
+	if token.Token == "" || !c.now.Add(tokenCacheExpiryDelta).Before(token.ExpiresOn) {
+		return ""
+	}
+	return token.Token
+}
+
+// prune removes the expired entries, so that the cache file doesn't grow with the stale identities
+// and login servers.
+func (c *tokenCacheContents) prune() {
+	for key, token := range c.AccessTokens {
+		if !c.now.Before(token.ExpiresOn) {
+			delete(c.AccessTokens, key)
+		}
+	}
+	for key, challenge := range c.Challenges {
+		if !c.now.Before(challenge.ExpiresOn) {
+			delete(c.Challenges, key)
+		}
+	}
+	for key, token := range c.RefreshTokens {
+		if !c.now.Before(token.ExpiresOn) {
+			delete(c.RefreshTokens, key)
+		}
+	}
+}
+
+// tokenCache is a token cache shared by the provider processes executed by kubelet, persisted in
+// a file only accessible by the owner and guarded by an exclusive file lock.
+type tokenCache struct {
+	dir string
+	now func() time.Time
+}
+
+func newTokenCache(dir string) *tokenCache {
+	return &tokenCache{
+		dir: dir,
+		now: time.Now,
+	}
+}
+
+// update runs fn with the cache contents, and persists the contents if fn changes them. The lock is
+// held while fn runs, so that the concurrent processes wait for the tokens fetched by the first one
+// instead of all fetching them from AAD and ACR.
+func (c *tokenCache) update(fn func(contents *tokenCacheContents) error) error {
+	if err := c.ensureDir(); err != nil {
+		return err
+	}
+
+	lock, err := os.OpenFile(filepath.Join(c.dir, tokenCacheLockFileName), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open the token cache lock file: %w", err)
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock the token cache: %w", err)
+	}
+	defer func() {
+		if err := unlockFile(lock); err != nil {
+			klog.Warningf("failed to unlock the token cache: %v", err)
+		}
+	}()
+
+	contents := c.read()
+	if err := fn(contents); err != nil {
+		return err
+	}
+	if !contents.changed {
+		return nil
+	}
+	contents.prune()
+	return c.write(contents)
+}
+
+// ensureDir creates the cache directory, and makes sure it's only accessible by the owner.
+func (c *tokenCache) ensureDir() error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create the token cache directory: %w", err)
+	}
+	info, err := os.Stat(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to stat the token cache directory: %w", err)
+	}
+	return checkTokenCachePermissions(c.dir, info)
+}
+
+// read returns the cached contents, or empty contents if the cache file doesn't exist or is corrupted.
+func (c *tokenCache) read() *tokenCacheContents {
+	contents := &tokenCacheContents{}
+	data, err := os.ReadFile(filepath.Join(c.dir, tokenCacheFileName))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			klog.Warningf("failed to read the token cache, ignoring it: %v", err)
+		}
+	} else if err := json.Unmarshal(data, contents); err != nil {
+		klog.Warningf("failed to parse the token cache, ignoring it: %v", err)
+		contents = &tokenCacheContents{}
+	}
+	contents.now = c.now()
+	return contents
+}
+
+// write replaces the cache file with the contents, so that the file is never partially written.
+func (c *tokenCache) write(contents *tokenCacheContents) error {
+	data, err := json.Marshal(contents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the token cache: %w", err)
+	}
+
+	f, err := os.CreateTemp(c.dir, tokenCacheFileName+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create the token cache file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write the token cache file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write the token cache file: %w", err)
+	}
+	if err := os.Rename(f.Name(), filepath.Join(c.dir, tokenCacheFileName)); err != nil {
+		return fmt.Errorf("failed to replace the token cache file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile blocks until it holds the exclusive lock of the file. The lock is released when the
+// process exits, so a provider killed by kubelet never leaves the cache locked.
+func lockFile(f *os.File) error {
+	for {
+		err := unix.Flock(int(f.Fd()), unix.LOCK_EX)
+		if err != unix.EINTR {
+			return err
+		}
+	}
+}
+
+func unlockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
+
+// checkTokenCachePermissions makes sure the token cache directory is owned by the current user and
+// not accessible by other users.
+func checkTokenCachePermissions(dir string, info fs.FileInfo) error {
+	if stat, ok := info.Sys().(*unix.Stat_t); ok && int(stat.Uid) != os.Geteuid() {
+		return fmt.Errorf("token cache directory %s is not owned by the current user", dir)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("token cache directory %s is accessible by other users with mode %s", dir, info.Mode().Perm())
+	}
+	return nil
+}
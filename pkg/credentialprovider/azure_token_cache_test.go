@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenCacheUpdate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	now := time.Now()
+	cache := newTokenCache(dir)
+	cache.now = func() time.Time { return now }
+
+	refreshToken := fakeJWT(now.Add(3 * time.Hour))
+	err := cache.update(func(contents *tokenCacheContents) error {
+		assert.Empty(t, contents.accessToken("identity|scope"))
+		assert.Nil(t, contents.challenge("foo.azurecr.io"))
+		assert.Empty(t, contents.refreshToken("identity|foo.azurecr.io"))
+
+		contents.setAccessToken("identity|scope", "access-token", now.Add(time.Hour))
+		contents.setAccessToken("identity|expiring", "expiring-token", now.Add(time.Minute))
+		contents.setAccessToken("identity|expired", "expired-token", now.Add(-time.Minute))
+		contents.setChallenge("foo.azurecr.io", &authDirective{service: "foo.azurecr.io", realm: "https://foo.azurecr.io/oauth2/token"})
+		contents.setRefreshToken("identity|foo.azurecr.io", refreshToken)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+		info, err = os.Stat(filepath.Join(dir, tokenCacheFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	err = newTokenCache(dir).update(func(contents *tokenCacheContents) error {
+		assert.Equal(t, "access-token", contents.accessToken("identity|scope"))
+		assert.Empty(t, contents.accessToken("identity|expiring"), "tokens expiring within the delta should not be used")
+		assert.NotContains(t, contents.AccessTokens, "identity|expired", "expired tokens should be pruned")
+		assert.Equal(t, &authDirective{service: "foo.azurecr.io", realm: "https://foo.azurecr.io/oauth2/token"}, contents.challenge("foo.azurecr.io"))
+		assert.Equal(t, refreshToken, contents.refreshToken("identity|foo.azurecr.io"))
+		assert.Empty(t, contents.refreshToken("other|foo.azurecr.io"))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.now = func() time.Time { return now.Add(2 * time.Hour) }
+	err = cache.update(func(contents *tokenCacheContents) error {
+		assert.Empty(t, contents.accessToken("identity|scope"))
+		assert.Nil(t, contents.challenge("foo.azurecr.io"))
+		assert.Equal(t, refreshToken, contents.refreshToken("identity|foo.azurecr.io"))
+		return errors.New("fetch error")
+	})
+	assert.EqualError(t, err, "fetch error")
+}
+
+func TestTokenCacheCorruptedFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	assert.NoError(t, os.Mkdir(dir, 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, tokenCacheFileName), []byte("{corrupted"), 0600))
+
+	cache := newTokenCache(dir)
+	err := cache.update(func(contents *tokenCacheContents) error {
+		contents.setAccessToken("identity|scope", "access-token", time.Now().Add(time.Hour))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cache.update(func(contents *tokenCacheContents) error {
+		assert.Equal(t, "access-token", contents.accessToken("identity|scope"))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTokenCachePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the permissions of the token cache directory are not checked on Windows")
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, os.Chmod(dir, 0755))
+	err := newTokenCache(dir).update(func(_ *tokenCacheContents) error {
+		t.Error("the cache should not be used")
+		return nil
+	})
+	assert.ErrorContains(t, err, "is accessible by other users")
+}
+
+func TestTokenCacheConcurrentUpdates(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	var lock sync.Mutex
+	fetched := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := newTokenCache(dir).update(func(contents *tokenCacheContents) error {
+				if contents.accessToken("identity|scope") != "" {
+					return nil
+				}
+				lock.Lock()
+				fetched++
+				lock.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				contents.setAccessToken("identity|scope", "access-token", time.Now().Add(time.Hour))
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, fetched, "the token should only be fetched by the first update")
+}
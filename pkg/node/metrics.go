@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const nodeProviderSubsystem = "cloud_node_manager"
+
+var (
+	nodeInfoRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      nodeProviderSubsystem,
+			Name:           "node_info_requests_total",
+			Help:           "Counter measuring node information requests by operation, source (imds or arm) and result.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"operation", "source", "result"},
+	)
+	imdsHealthy = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      nodeProviderSubsystem,
+			Name:           "imds_healthy",
+			Help:           "Gauge indicating whether IMDS is considered healthy (1) or skipped in favor of ARM (0).",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+var registerMetrics sync.Once
+
+// registerNodeProviderMetrics registers the metrics of the hybrid node provider.
+func registerNodeProviderMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(nodeInfoRequests)
+		legacyregistry.MustRegister(imdsHealthy)
+		imdsHealthy.Set(1)
+	})
+}
+
+func observeNodeInfoRequest(operation, source string, err error) {
+	result := "succeeded"
+	if err != nil {
+		result = "failed"
+	}
+	nodeInfoRequests.WithLabelValues(operation, source, result).Inc()
+}
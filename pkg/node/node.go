@@ -82,3 +82,12 @@ func (np *IMDSNodeProvider) GetZone(ctx context.Context, _ types.NodeName) (clou
 func (np *IMDSNodeProvider) GetPlatformSubFaultDomain() (string, error) {
 	return np.azure.GetPlatformSubFaultDomain()
 }
+
+// GetPlacementLabels returns the labels describing the placement of the instance.
+func (np *IMDSNodeProvider) GetPlacementLabels(ctx context.Context, name types.NodeName) (map[string]string, error) {
+	placement, err := np.azure.GetNodePlacement(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return placement.Labels(), nil
+}
@@ -87,3 +87,12 @@ func (np *ARMNodeProvider) GetZone(ctx context.Context, name types.NodeName) (cl
 func (np *ARMNodeProvider) GetPlatformSubFaultDomain() (string, error) {
 	return "", nil
 }
+
+// GetPlacementLabels returns the labels describing the placement of the instance.
+func (np *ARMNodeProvider) GetPlacementLabels(ctx context.Context, name types.NodeName) (map[string]string, error) {
+	placement, err := np.azure.GetNodePlacement(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return placement.Labels(), nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	nodemanager "sigs.k8s.io/cloud-provider-azure/pkg/nodemanager"
+)
+
+const (
+	// defaultIMDSRequestTimeout is the default timeout of a single IMDS request
+	// before falling back to ARM.
+	defaultIMDSRequestTimeout = 5 * time.Second
+	// defaultIMDSFailureThreshold is the number of consecutive IMDS failures after
+	// which IMDS is considered unhealthy and skipped.
+	defaultIMDSFailureThreshold = 3
+	// defaultIMDSUnhealthyCooldown is the duration for which IMDS is skipped after
+	// being marked unhealthy. IMDS is probed again once it elapses.
+	defaultIMDSUnhealthyCooldown = time.Minute
+)
+
+// errIMDSTimeout is returned when an IMDS request doesn't complete in time.
+var errIMDSTimeout = errors.New("timed out waiting for IMDS")
+
+// imdsHealth tracks the health of IMDS based on the results of recent requests.
+type imdsHealth struct {
+	lock                sync.Mutex
+	consecutiveFailures int
+	unhealthyUntil      time.Time
+
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+}
+
+// healthy returns true if IMDS should be tried for the next request.
+func (h *imdsHealth) healthy() bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return !h.now().Before(h.unhealthyUntil)
+}
+
+// recordSuccess resets the failure counter.
+func (h *imdsHealth) recordSuccess() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.consecutiveFailures = 0
+	h.unhealthyUntil = time.Time{}
+	imdsHealthy.Set(1)
+}
+
+// recordFailure increases the failure counter and marks IMDS unhealthy for the
+// cooldown period once the failure threshold is reached.
+func (h *imdsHealth) recordFailure() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.consecutiveFailures++
+	if h.consecutiveFailures >= h.failureThreshold {
+		klog.Warningf("IMDS failed %d times in a row, falling back to ARM for %s", h.consecutiveFailures, h.cooldown)
+		h.unhealthyUntil = h.now().Add(h.cooldown)
+		imdsHealthy.Set(0)
+	}
+}
+
+// HybridNodeProvider implements nodemanager.NodeProvider.
+// It prefers IMDS and falls back to ARM when IMDS is slow, unhealthy or fails.
+type HybridNodeProvider struct {
+	imds nodemanager.NodeProvider
+	arm  nodemanager.NodeProvider
+
+	imdsTimeout time.Duration
+	health      *imdsHealth
+
+	sourceLock sync.RWMutex
+	lastSource string
+}
+
+// NewHybridNodeProvider creates a new HybridNodeProvider.
+func NewHybridNodeProvider(imds, arm nodemanager.NodeProvider, imdsTimeout time.Duration) *HybridNodeProvider {
+	if imdsTimeout <= 0 {
+		imdsTimeout = defaultIMDSRequestTimeout
+	}
+	registerNodeProviderMetrics()
+
+	return &HybridNodeProvider{
+		imds:        imds,
+		arm:         arm,
+		imdsTimeout: imdsTimeout,
+		health: &imdsHealth{
+			failureThreshold: defaultIMDSFailureThreshold,
+			cooldown:         defaultIMDSUnhealthyCooldown,
+			now:              time.Now,
+		},
+	}
+}
+
+// NodeAddresses returns the addresses of the specified instance.
+func (np *HybridNodeProvider) NodeAddresses(ctx context.Context, name types.NodeName) ([]v1.NodeAddress, error) {
+	return do(ctx, np, "node_addresses", func(ctx context.Context, provider nodemanager.NodeProvider) ([]v1.NodeAddress, error) {
+		return provider.NodeAddresses(ctx, name)
+	})
+}
+
+// InstanceID returns the cloud provider ID of the specified instance.
+// Note that if the instance does not exist or is no longer running, we must return ("", cloudprovider.InstanceNotFound)
+func (np *HybridNodeProvider) InstanceID(ctx context.Context, name types.NodeName) (string, error) {
+	return do(ctx, np, "instance_id", func(ctx context.Context, provider nodemanager.NodeProvider) (string, error) {
+		return provider.InstanceID(ctx, name)
+	})
+}
+
+// InstanceType returns the type of the specified instance.
+// Note that if the instance does not exist or is no longer running, we must return ("", cloudprovider.InstanceNotFound)
+func (np *HybridNodeProvider) InstanceType(ctx context.Context, name types.NodeName) (string, error) {
+	return do(ctx, np, "instance_type", func(ctx context.Context, provider nodemanager.NodeProvider) (string, error) {
+		return provider.InstanceType(ctx, name)
+	})
+}
+
+// GetZone returns the Zone containing the current failure zone and locality region that the program is running in
+func (np *HybridNodeProvider) GetZone(ctx context.Context, name types.NodeName) (cloudprovider.Zone, error) {
+	return do(ctx, np, "get_zone", func(ctx context.Context, provider nodemanager.NodeProvider) (cloudprovider.Zone, error) {
+		return provider.GetZone(ctx, name)
+	})
+}
+
+// GetPlacementLabels returns the labels describing the placement of the instance.
+// Note that IMDS only provides the fault domain and the update domain.
+func (np *HybridNodeProvider) GetPlacementLabels(ctx context.Context, name types.NodeName) (map[string]string, error) {
+	return do(ctx, np, "get_placement_labels", func(ctx context.Context, provider nodemanager.NodeProvider) (map[string]string, error) {
+		return provider.GetPlacementLabels(ctx, name)
+	})
+}
+
+// GetPlatformSubFaultDomain returns the PlatformSubFaultDomain from IMDS if set.
+// The value is only available from IMDS, so an empty value is returned when IMDS can't be reached.
+func (np *HybridNodeProvider) GetPlatformSubFaultDomain() (string, error) {
+	if !np.health.healthy() {
+		return "", nil
+	}
+
+	subFD, err := callIMDS(context.Background(), np, func(_ context.Context, provider nodemanager.NodeProvider) (string, error) {
+		return provider.GetPlatformSubFaultDomain()
+	})
+	if err != nil {
+		klog.Warningf("HybridNodeProvider: failed to get platformSubFaultDomain from IMDS, ignoring: %v", err)
+		return "", nil
+	}
+	return subFD, nil
+}
+
+// GetNodeInfoSource returns the source which served the last successful request.
+func (np *HybridNodeProvider) GetNodeInfoSource() string {
+	np.sourceLock.RLock()
+	defer np.sourceLock.RUnlock()
+
+	return np.lastSource
+}
+
+func (np *HybridNodeProvider) setNodeInfoSource(source string) {
+	np.sourceLock.Lock()
+	defer np.sourceLock.Unlock()
+
+	np.lastSource = source
+}
+
+// do runs the given function against IMDS if it is healthy, and falls back to ARM
+// if IMDS is unhealthy, times out or returns an error other than cloudprovider.InstanceNotFound.
+// Each attempt returns its own result, so a late IMDS result never overwrites the ARM result.
+func do[T any](ctx context.Context, np *HybridNodeProvider, operation string, fn func(context.Context, nodemanager.NodeProvider) (T, error)) (T, error) {
+	if np.health.healthy() {
+		result, err := callIMDS(ctx, np, fn)
+		if err == nil || errors.Is(err, cloudprovider.InstanceNotFound) {
+			observeNodeInfoRequest(operation, consts.NodeInfoSourceIMDS, err)
+			if err == nil {
+				np.setNodeInfoSource(consts.NodeInfoSourceIMDS)
+			}
+			return result, err
+		}
+		observeNodeInfoRequest(operation, consts.NodeInfoSourceIMDS, err)
+		klog.Warningf("HybridNodeProvider: %s from IMDS failed, falling back to ARM: %v", operation, err)
+	} else {
+		klog.V(4).Infof("HybridNodeProvider: IMDS is unhealthy, using ARM for %s", operation)
+	}
+
+	result, err := fn(ctx, np.arm)
+	observeNodeInfoRequest(operation, consts.NodeInfoSourceARM, err)
+	if err != nil {
+		var empty T
+		return empty, fmt.Errorf("failed to %s from ARM: %w", operation, err)
+	}
+	np.setNodeInfoSource(consts.NodeInfoSourceARM)
+	return result, nil
+}
+
+// imdsResult is the result of a request to IMDS.
+type imdsResult[T any] struct {
+	value T
+	err   error
+}
+
+// callIMDS runs the given function against IMDS with the configured timeout and records the result.
+// IMDS requests are not cancellable, so the function keeps running in the background on timeout,
+// and its result is discarded.
+func callIMDS[T any](ctx context.Context, np *HybridNodeProvider, fn func(context.Context, nodemanager.NodeProvider) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, np.imdsTimeout)
+	defer cancel()
+
+	resultCh := make(chan imdsResult[T], 1)
+	go func() {
+		value, err := fn(ctx, np.imds)
+		resultCh <- imdsResult[T]{value: value, err: err}
+	}()
+
+	var result imdsResult[T]
+	select {
+	case result = <-resultCh:
+	case <-ctx.Done():
+		result.err = fmt.Errorf("%w after %s", errIMDSTimeout, np.imdsTimeout)
+	}
+
+	if result.err != nil && !errors.Is(result.err, cloudprovider.InstanceNotFound) {
+		np.health.recordFailure()
+	} else {
+		np.health.recordSuccess()
+	}
+	return result.value, result.err
+}
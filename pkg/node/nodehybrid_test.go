@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cloudprovider "k8s.io/cloud-provider"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	mocknodeprovider "sigs.k8s.io/cloud-provider-azure/pkg/nodemanager/mock"
+)
+
+func TestHybridNodeProviderPrefersIMDS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imds := mocknodeprovider.NewMockNodeProvider(ctrl)
+	arm := mocknodeprovider.NewMockNodeProvider(ctrl)
+	np := NewHybridNodeProvider(imds, arm, time.Second)
+
+	imds.EXPECT().InstanceID(gomock.Any(), types.NodeName("node0")).Return("azure://vm0", nil)
+	instanceID, err := np.InstanceID(context.Background(), "node0")
+	assert.NoError(t, err)
+	assert.Equal(t, "azure://vm0", instanceID)
+	assert.Equal(t, consts.NodeInfoSourceIMDS, np.GetNodeInfoSource())
+}
+
+func TestHybridNodeProviderFallsBackToARM(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imds := mocknodeprovider.NewMockNodeProvider(ctrl)
+	arm := mocknodeprovider.NewMockNodeProvider(ctrl)
+	np := NewHybridNodeProvider(imds, arm, time.Second)
+
+	addresses := []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}
+	imds.EXPECT().NodeAddresses(gomock.Any(), types.NodeName("node0")).Return(nil, errors.New("connection refused"))
+	arm.EXPECT().NodeAddresses(gomock.Any(), types.NodeName("node0")).Return(addresses, nil)
+	result, err := np.NodeAddresses(context.Background(), "node0")
+	assert.NoError(t, err)
+	assert.Equal(t, addresses, result)
+	assert.Equal(t, consts.NodeInfoSourceARM, np.GetNodeInfoSource())
+
+	imds.EXPECT().InstanceType(gomock.Any(), types.NodeName("node0")).Return("", errors.New("connection refused"))
+	arm.EXPECT().InstanceType(gomock.Any(), types.NodeName("node0")).Return("", errors.New("arm error"))
+	_, err = np.InstanceType(context.Background(), "node0")
+	assert.ErrorContains(t, err, "arm error")
+}
+
+func TestHybridNodeProviderIMDSTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imds := mocknodeprovider.NewMockNodeProvider(ctrl)
+	arm := mocknodeprovider.NewMockNodeProvider(ctrl)
+	np := NewHybridNodeProvider(imds, arm, 10*time.Millisecond)
+
+	unblock := make(chan struct{})
+	imdsDone := make(chan struct{})
+	imds.EXPECT().GetZone(gomock.Any(), types.NodeName("node0")).DoAndReturn(func(_ context.Context, _ types.NodeName) (cloudprovider.Zone, error) {
+		defer close(imdsDone)
+		<-unblock
+		return cloudprovider.Zone{FailureDomain: "late-imds", Region: "late-imds"}, nil
+	})
+	arm.EXPECT().GetZone(gomock.Any(), types.NodeName("node0")).Return(cloudprovider.Zone{FailureDomain: "eastus-1", Region: "eastus"}, nil)
+	zone, err := np.GetZone(context.Background(), "node0")
+	assert.NoError(t, err)
+
+	// IMDS returns after the ARM fallback, its result must not overwrite the returned zone
+	close(unblock)
+	<-imdsDone
+	assert.Equal(t, "eastus-1", zone.FailureDomain)
+	assert.Equal(t, "eastus", zone.Region)
+	assert.Equal(t, consts.NodeInfoSourceARM, np.GetNodeInfoSource())
+}
+
+func TestHybridNodeProviderInstanceNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imds := mocknodeprovider.NewMockNodeProvider(ctrl)
+	arm := mocknodeprovider.NewMockNodeProvider(ctrl)
+	np := NewHybridNodeProvider(imds, arm, time.Second)
+
+	imds.EXPECT().InstanceID(gomock.Any(), types.NodeName("node0")).Return("", cloudprovider.InstanceNotFound)
+	_, err := np.InstanceID(context.Background(), "node0")
+	assert.ErrorIs(t, err, cloudprovider.InstanceNotFound)
+	assert.Empty(t, np.GetNodeInfoSource())
+}
+
+func TestHybridNodeProviderHealthTracking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imds := mocknodeprovider.NewMockNodeProvider(ctrl)
+	arm := mocknodeprovider.NewMockNodeProvider(ctrl)
+	np := NewHybridNodeProvider(imds, arm, time.Second)
+	now := time.Now()
+	np.health.now = func() time.Time { return now }
+
+	imds.EXPECT().InstanceType(gomock.Any(), gomock.Any()).Return("", errors.New("connection refused")).Times(defaultIMDSFailureThreshold)
+	arm.EXPECT().InstanceType(gomock.Any(), gomock.Any()).Return("Standard_D2s_v3", nil).Times(defaultIMDSFailureThreshold + 1)
+	for i := 0; i < defaultIMDSFailureThreshold+1; i++ {
+		instanceType, err := np.InstanceType(context.Background(), "node0")
+		assert.NoError(t, err)
+		assert.Equal(t, "Standard_D2s_v3", instanceType)
+	}
+
+	// sub fault domain is only available from IMDS, so it should be skipped while IMDS is unhealthy
+	subFD, err := np.GetPlatformSubFaultDomain()
+	assert.NoError(t, err)
+	assert.Empty(t, subFD)
+
+	// IMDS should be probed again after the cooldown
+	now = now.Add(defaultIMDSUnhealthyCooldown)
+	imds.EXPECT().InstanceType(gomock.Any(), gomock.Any()).Return("Standard_D2s_v3", nil)
+	instanceType, err := np.InstanceType(context.Background(), "node0")
+	assert.NoError(t, err)
+	assert.Equal(t, "Standard_D2s_v3", instanceType)
+	assert.Equal(t, consts.NodeInfoSourceIMDS, np.GetNodeInfoSource())
+
+	imds.EXPECT().GetPlatformSubFaultDomain().Return("1", nil)
+	subFD, err = np.GetPlatformSubFaultDomain()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", subFD)
+}
@@ -18,15 +18,20 @@ package node
 
 import (
 	"context"
+	"time"
 
 	nodemanager "sigs.k8s.io/cloud-provider-azure/pkg/nodemanager"
 )
 
-// NewNodeProvider returns a node provider depending on the use case
-func NewNodeProvider(ctx context.Context, useMetadata bool, cloudConfigFilePath string) nodemanager.NodeProvider {
+// NewNodeProvider returns a node provider depending on the use case.
+// If both useMetadata and enableARMFallback are set, IMDS is preferred and ARM is used
+// when IMDS doesn't respond within imdsTimeout or is unhealthy.
+func NewNodeProvider(ctx context.Context, useMetadata, enableARMFallback bool, imdsTimeout time.Duration, cloudConfigFilePath string) nodemanager.NodeProvider {
 	var nodeProvider nodemanager.NodeProvider
 
-	if useMetadata {
+	if useMetadata && enableARMFallback {
+		nodeProvider = NewHybridNodeProvider(NewIMDSNodeProvider(ctx), NewARMNodeProvider(ctx, cloudConfigFilePath), imdsTimeout)
+	} else if useMetadata {
 		nodeProvider = NewIMDSNodeProvider(ctx)
 	} else {
 		nodeProvider = NewARMNodeProvider(ctx, cloudConfigFilePath)
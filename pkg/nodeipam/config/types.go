@@ -32,4 +32,10 @@ type NodeIPAMControllerConfiguration struct {
 	// NodeCIDRMaskSizeIPv6 is the mask size for IPv6 node cidr in dual-stack cluster.
 	// This can be used only with dual stack clusters and is incompatible with single stack clusters.
 	NodeCIDRMaskSizeIPv6 int32
+	// NodePoolCIDRsConfigMap is the <namespace>/<name> of the ConfigMap holding the cluster CIDRs
+	// dedicated to the node pools selected by node labels.
+	NodePoolCIDRsConfigMap string
+	// NodeCIDRCapacityWarningThreshold is the percentage of free node cidrs in a cluster cidr
+	// below which warning events are emitted. 0 disables the warnings.
+	NodeCIDRCapacityWarningThreshold int32
 }
@@ -84,6 +84,15 @@ type CIDRAllocatorParams struct {
 	SecondaryServiceCIDR *net.IPNet
 	// NodeCIDRMaskSizes is list of node cidr mask sizes
 	NodeCIDRMaskSizes []int
+	// NodePoolCIDRs is the list of cluster cidrs dedicated to the node pools matching their
+	// node selectors. Nodes matching none of them get cidrs from ClusterCIDRs.
+	NodePoolCIDRs []NodePoolCIDR
+	// NodePoolCIDRsConfigMap is the reference of the ConfigMap the node pool CIDRs are loaded
+	// from, on which the cluster-level capacity events are recorded.
+	NodePoolCIDRsConfigMap *corev1.ObjectReference
+	// CIDRCapacityWarningThreshold is the ratio of free CIDRs in a cluster cidr below which
+	// warning events are emitted. 0 disables the warnings.
+	CIDRCapacityWarningThreshold float64
 }
 
 // New creates a new CIDR range allocator.
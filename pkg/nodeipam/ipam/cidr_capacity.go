@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam/cidrset"
+)
+
+const (
+	// cidrCapacityCheckPeriod is the period to refresh the free capacity metrics and events.
+	cidrCapacityCheckPeriod = time.Minute
+
+	// reasons of the capacity events
+	cidrCapacityLowReason       = "CIDRCapacityLow"
+	cidrCapacityRecoveredReason = "CIDRCapacityRecovered"
+)
+
+var (
+	cidrSetFreeCIDRs = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "node_ipam_controller",
+			Name:           "cidrset_cidrs_free",
+			Help:           "Gauge measuring the number of CIDRs with the node mask size which can still be allocated.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"clusterCIDR", "nodeMaskSize"},
+	)
+	cidrSetFragmentation = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "node_ipam_controller",
+			Name:           "cidrset_fragmentation_ratio",
+			Help:           "Gauge measuring the ratio of the free addresses which cannot be allocated with the node mask size because they are split by the existing allocations.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"clusterCIDR", "nodeMaskSize"},
+	)
+
+	registerCIDRCapacityMetricsOnce sync.Once
+)
+
+// CIDRCapacityPlan is the number of nodes planned to be added to a node pool CIDR.
+type CIDRCapacityPlan struct {
+	// NodePoolCIDR is the name of the node pool CIDR, or DefaultNodePoolCIDRName for the
+	// nodes which match no node pool CIDR.
+	NodePoolCIDR string `json:"nodePoolCIDR"`
+	// NodeCount is the number of nodes planned to be added.
+	NodeCount int `json:"nodeCount"`
+	// NodeCIDRMaskSizes are the node cidr mask sizes of the planned nodes mapped to the cluster
+	// CIDRs by index. The current node cidr mask sizes of the cidr sets are used if empty.
+	NodeCIDRMaskSizes []int `json:"nodeCIDRMaskSizes,omitempty"`
+}
+
+// CIDRCapacityCheckResult reports whether the planned nodes fit in a cluster CIDR.
+type CIDRCapacityCheckResult struct {
+	NodePoolCIDR     string `json:"nodePoolCIDR"`
+	ClusterCIDR      string `json:"clusterCIDR"`
+	NodeCIDRMaskSize int    `json:"nodeCIDRMaskSize"`
+	// Free is the number of CIDRs with NodeCIDRMaskSize which can still be allocated.
+	Free int `json:"free"`
+	// Required is the number of CIDRs with NodeCIDRMaskSize the planned nodes need.
+	Required int  `json:"required"`
+	Fits     bool `json:"fits"`
+}
+
+// CIDRCapacityChecker is implemented by the CIDR allocators which can check, without
+// allocating anything, whether the planned nodes fit in the current CIDRs.
+type CIDRCapacityChecker interface {
+	CheckCIDRCapacity(plans []CIDRCapacityPlan) ([]CIDRCapacityCheckResult, error)
+}
+
+// checkCIDRCapacity checks whether the planned nodes fit in the cidr sets of the node pool CIDRs.
+func checkCIDRCapacity(pools []*nodePoolCIDRSets, plans []CIDRCapacityPlan) ([]CIDRCapacityCheckResult, error) {
+	// several plans on the same node pool CIDR share its capacity
+	required := make(map[*cidrset.CidrSet]map[int]int)
+	var results []CIDRCapacityCheckResult
+	for _, plan := range plans {
+		var pool *nodePoolCIDRSets
+		for i := range pools {
+			if pools[i].name == plan.NodePoolCIDR {
+				pool = pools[i]
+				break
+			}
+		}
+		if pool == nil {
+			return nil, fmt.Errorf("node pool CIDR %s not found", plan.NodePoolCIDR)
+		}
+		if len(plan.NodeCIDRMaskSizes) != 0 && len(plan.NodeCIDRMaskSizes) != len(pool.cidrSets) {
+			return nil, fmt.Errorf("planned node cidr mask sizes %v do not match the cluster CIDRs %v of node pool CIDR %s", plan.NodeCIDRMaskSizes, pool.clusterCIDRs, pool.name)
+		}
+
+		for idx, cidrSet := range pool.cidrSets {
+			maskSize := cidrSet.NodeMaskSize()
+			if len(plan.NodeCIDRMaskSizes) != 0 {
+				maskSize = plan.NodeCIDRMaskSizes[idx]
+			}
+			if required[cidrSet] == nil {
+				required[cidrSet] = make(map[int]int)
+			}
+			required[cidrSet][maskSize] += plan.NodeCount
+
+			free := cidrSet.FreeWithNodeMaskSize(maskSize)
+			results = append(results, CIDRCapacityCheckResult{
+				NodePoolCIDR:     pool.name,
+				ClusterCIDR:      pool.clusterCIDRs[idx].String(),
+				NodeCIDRMaskSize: maskSize,
+				Free:             free,
+				Required:         required[cidrSet][maskSize],
+				Fits:             required[cidrSet][maskSize] <= free,
+			})
+		}
+	}
+	return results, nil
+}
+
+// cidrCapacityMonitor exports the free capacity of the cidr sets and emits events when it
+// falls below the warning threshold.
+type cidrCapacityMonitor struct {
+	// threshold is the ratio of free CIDRs below which the warnings are emitted, 0 disables them.
+	threshold float64
+
+	// eventRef is the object the cluster-level events are recorded on, which is the node pool
+	// CIDRs ConfigMap. The cluster-level events are only logged if it's nil.
+	eventRef *v1.ObjectReference
+
+	lock sync.Mutex
+	// lowCapacity records the cluster CIDRs below the threshold to emit the cluster-level
+	// events only on transitions
+	lowCapacity map[string]bool
+}
+
+func newCIDRCapacityMonitor(threshold float64, eventRef *v1.ObjectReference) *cidrCapacityMonitor {
+	registerCIDRCapacityMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(cidrSetFreeCIDRs)
+		legacyregistry.MustRegister(cidrSetFragmentation)
+	})
+
+	return &cidrCapacityMonitor{
+		threshold:   threshold,
+		eventRef:    eventRef,
+		lowCapacity: make(map[string]bool),
+	}
+}
+
+// isLow returns true if the ratio of free CIDRs in the cidr set is below the threshold.
+func (m *cidrCapacityMonitor) isLow(cidrSet *cidrset.CidrSet) bool {
+	if m == nil || m.threshold <= 0 {
+		return false
+	}
+	total, allocated := cidrSet.Usage()
+	return total > 0 && float64(total-allocated)/float64(total) < m.threshold
+}
+
+// check refreshes the free capacity and fragmentation metrics of the cidr sets for each of the node cidr mask
+// sizes, which are mapped to the cluster CIDRs by index, and emits the cluster-level events on
+// the node pool CIDRs ConfigMap when a cluster CIDR falls below or recovers above the threshold.
+func (m *cidrCapacityMonitor) check(recorder record.EventRecorder, pools []*nodePoolCIDRSets, nodeMaskSizes [][]int) {
+	if m == nil {
+		return
+	}
+	for _, pool := range pools {
+		for idx, cidrSet := range pool.cidrSets {
+			clusterCIDR := pool.clusterCIDRs[idx].String()
+			if idx < len(nodeMaskSizes) {
+				for _, maskSize := range nodeMaskSizes[idx] {
+					cidrSetFreeCIDRs.WithLabelValues(clusterCIDR, strconv.Itoa(maskSize)).Set(float64(cidrSet.FreeWithNodeMaskSize(maskSize)))
+					cidrSetFragmentation.WithLabelValues(clusterCIDR, strconv.Itoa(maskSize)).Set(cidrSet.Fragmentation(maskSize))
+				}
+			}
+
+			low := m.isLow(cidrSet)
+			m.lock.Lock()
+			wasLow := m.lowCapacity[clusterCIDR]
+			m.lowCapacity[clusterCIDR] = low
+			m.lock.Unlock()
+			if low == wasLow {
+				continue
+			}
+
+			total, allocated := cidrSet.Usage()
+			if low {
+				klog.Warningf("Cluster CIDR %s of node pool CIDR %s has %d of %d CIDRs free", clusterCIDR, pool.name, total-allocated, total)
+			} else {
+				klog.Infof("Cluster CIDR %s of node pool CIDR %s has %d of %d CIDRs free", clusterCIDR, pool.name, total-allocated, total)
+			}
+			if m.eventRef == nil {
+				continue
+			}
+			if low {
+				recorder.Eventf(m.eventRef, v1.EventTypeWarning, cidrCapacityLowReason, "Cluster CIDR %s of node pool CIDR %s has %d of %d CIDRs free", clusterCIDR, pool.name, total-allocated, total)
+			} else {
+				recorder.Eventf(m.eventRef, v1.EventTypeNormal, cidrCapacityRecoveredReason, "Cluster CIDR %s of node pool CIDR %s has %d of %d CIDRs free", clusterCIDR, pool.name, total-allocated, total)
+			}
+		}
+	}
+}
+
+// warnNode emits a warning event on the node if any of the cidr sets the node got its
+// pod CIDRs from is below the threshold.
+func (m *cidrCapacityMonitor) warnNode(recorder record.EventRecorder, node *v1.Node, cidrSets []*cidrset.CidrSet) {
+	for _, cidrSet := range cidrSets {
+		if !m.isLow(cidrSet) {
+			continue
+		}
+		total, allocated := cidrSet.Usage()
+		recorder.Eventf(nodeReference(node), v1.EventTypeWarning, cidrCapacityLowReason, "Cluster CIDR %s has %d of %d CIDRs free", cidrSet.ClusterCIDR(), total-allocated, total)
+	}
+}
+
+// nodeReference returns the reference of the node to record events on.
+func nodeReference(node *v1.Node) *v1.ObjectReference {
+	return &v1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Node",
+		Name:       node.Name,
+		UID:        node.UID,
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	metricstestutil "k8s.io/component-base/metrics/testutil"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam/cidrset"
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam/test"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/controller/testutil"
+)
+
+func TestCheckCIDRCapacity(t *testing.T) {
+	fakeNodeHandler := &testutil.FakeNodeHandler{Clientset: fake.NewSimpleClientset()}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs:      []*net.IPNet{test.MustParseCIDR("10.244.0.0/22")},
+		NodeCIDRMaskSizes: []int{24},
+		NodePoolCIDRs: []NodePoolCIDR{
+			{Name: "routable", ClusterCIDRs: []string{"10.10.0.0/23"}},
+		},
+	}
+	nodeList := &v1.NodeList{
+		Items: []v1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "node0"},
+				Spec:       v1.NodeSpec{PodCIDRs: []string{"10.244.0.0/24"}},
+			},
+		},
+	}
+	allocator, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, nodeList)
+	assert.NoError(t, err)
+	checker, ok := allocator.(CIDRCapacityChecker)
+	assert.True(t, ok)
+
+	results, err := checker.CheckCIDRCapacity([]CIDRCapacityPlan{
+		{NodePoolCIDR: DefaultNodePoolCIDRName, NodeCount: 3},
+		{NodePoolCIDR: "routable", NodeCount: 3},
+		{NodePoolCIDR: "routable", NodeCount: 1, NodeCIDRMaskSizes: []int{23}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []CIDRCapacityCheckResult{
+		{NodePoolCIDR: DefaultNodePoolCIDRName, ClusterCIDR: "10.244.0.0/22", NodeCIDRMaskSize: 24, Free: 3, Required: 3, Fits: true},
+		{NodePoolCIDR: "routable", ClusterCIDR: "10.10.0.0/23", NodeCIDRMaskSize: 24, Free: 2, Required: 3, Fits: false},
+		{NodePoolCIDR: "routable", ClusterCIDR: "10.10.0.0/23", NodeCIDRMaskSize: 23, Free: 1, Required: 1, Fits: true},
+	}, results)
+
+	_, err = checker.CheckCIDRCapacity([]CIDRCapacityPlan{{NodePoolCIDR: "unknown", NodeCount: 1}})
+	assert.Error(t, err)
+
+	_, err = checker.CheckCIDRCapacity([]CIDRCapacityPlan{{NodePoolCIDR: "routable", NodeCount: 1, NodeCIDRMaskSizes: []int{24, 64}}})
+	assert.Error(t, err)
+}
+
+func TestCIDRCapacityMonitor(t *testing.T) {
+	clusterCIDR := test.MustParseCIDR("10.100.0.0/22")
+	cidrSet, err := cidrset.NewCIDRSet(clusterCIDR, 24)
+	assert.NoError(t, err)
+	pools := withDefaultNodePoolCIDRSets(nil, []*net.IPNet{clusterCIDR}, []*cidrset.CidrSet{cidrSet})
+	recorder := testutil.NewFakeRecorder()
+	monitor := newCIDRCapacityMonitor(0.3, &v1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "node-pool-cidrs"})
+
+	// 2 of 4 free
+	_ = cidrSet.Occupy(test.MustParseCIDR("10.100.0.0/23"))
+	monitor.check(recorder, pools, [][]int{{23, 24}})
+	assert.Empty(t, recorder.Events)
+	free, err := metricstestutil.GetGaugeMetricValue(cidrSetFreeCIDRs.WithLabelValues("10.100.0.0/22", "23"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), free)
+	free, err = metricstestutil.GetGaugeMetricValue(cidrSetFreeCIDRs.WithLabelValues("10.100.0.0/22", "24"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), free)
+
+	// 1 of 4 free, the warning is emitted only once
+	_ = cidrSet.Occupy(test.MustParseCIDR("10.100.2.0/24"))
+	monitor.check(recorder, pools, [][]int{{24}})
+	monitor.check(recorder, pools, [][]int{{24}})
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, cidrCapacityLowReason, recorder.Events[0].Reason)
+	assert.Equal(t, v1.EventTypeWarning, recorder.Events[0].Type)
+	assert.Equal(t, "ConfigMap", recorder.Events[0].InvolvedObject.Kind)
+	assert.Equal(t, "node-pool-cidrs", recorder.Events[0].InvolvedObject.Name)
+	assert.Contains(t, recorder.Events[0].Message, DefaultNodePoolCIDRName)
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node0"}}
+	monitor.warnNode(recorder, node, []*cidrset.CidrSet{cidrSet})
+	assert.Len(t, recorder.Events, 2)
+	assert.Equal(t, "Node", recorder.Events[1].InvolvedObject.Kind)
+	assert.Equal(t, "node0", recorder.Events[1].InvolvedObject.Name)
+
+	// 3 of 4 free
+	_ = cidrSet.Release(test.MustParseCIDR("10.100.0.0/23"))
+	monitor.check(recorder, pools, [][]int{{24}})
+	assert.Len(t, recorder.Events, 3)
+	assert.Equal(t, cidrCapacityRecoveredReason, recorder.Events[2].Reason)
+
+	// the warnings are disabled with a zero threshold
+	disabled := newCIDRCapacityMonitor(0, nil)
+	_ = cidrSet.Occupy(clusterCIDR)
+	disabled.check(recorder, pools, [][]int{{24}})
+	disabled.warnNode(recorder, node, []*cidrset.CidrSet{cidrSet})
+	assert.Len(t, recorder.Events, 3)
+
+	// the cluster-level events are only logged without the node pool CIDRs ConfigMap
+	noConfigMap := newCIDRCapacityMonitor(0.3, nil)
+	noConfigMap.check(recorder, pools, [][]int{{24}})
+	assert.Len(t, recorder.Events, 3)
+}
@@ -90,14 +90,16 @@ func NewCIDRSet(clusterCIDR *net.IPNet, subNetMaskSize int) (*CidrSet, error) {
 	registerCidrsetMetrics()
 
 	maxCIDRs = 1 << uint32(subNetMaskSize-clusterMaskSize)
-	return &CidrSet{
+	cidrSet := &CidrSet{
 		clusterCIDR:     clusterCIDR,
 		nodeMask:        net.CIDRMask(subNetMaskSize, bits),
 		clusterMaskSize: clusterMaskSize,
 		maxCIDRs:        maxCIDRs,
 		nodeMaskSize:    subNetMaskSize,
 		label:           clusterCIDR.String(),
-	}, nil
+	}
+	cidrSet.updateUsageMetrics()
+	return cidrSet, nil
 }
 
 // UpdateSubnetMaskSize updates the node subnet mask sizes to the new value
@@ -133,6 +135,10 @@ func (s *CidrSet) UpdateSubnetMaskSize(newNodeMaskSize int, nodeNamePodCIDRMap m
 			if netutils.IsIPv6CIDR(cidr) != isIPV6ClusterCIDR {
 				continue
 			}
+			// skip the pod CIDRs allocated from other cluster CIDRs, e.g. node pool CIDRs
+			if !s.clusterCIDR.Contains(cidr.IP) {
+				continue
+			}
 
 			begin, end, err := s.getBeginningAndEndIndices(cidr)
 			if err != nil {
@@ -145,9 +151,92 @@ func (s *CidrSet) UpdateSubnetMaskSize(newNodeMaskSize int, nodeNamePodCIDRMap m
 		}
 	}
 
+	s.updateUsageMetrics()
 	return nil
 }
 
+// updateUsageMetrics updates the usage metrics of the set. It must be called with the lock held.
+func (s *CidrSet) updateUsageMetrics() {
+	cidrSetTotalCIDRs.WithLabelValues(s.label).Set(float64(s.maxCIDRs))
+	cidrSetAllocatedCIDRs.WithLabelValues(s.label).Set(float64(s.allocatedCIDRs))
+	if s.maxCIDRs > 0 {
+		cidrSetUsage.WithLabelValues(s.label).Set(float64(s.allocatedCIDRs) / float64(s.maxCIDRs))
+	}
+}
+
+// ClusterCIDR returns the cluster CIDR of the set.
+func (s *CidrSet) ClusterCIDR() *net.IPNet {
+	return s.clusterCIDR
+}
+
+// NodeMaskSize returns the node subnet mask size the set tracks the allocations with.
+func (s *CidrSet) NodeMaskSize() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.nodeMaskSize
+}
+
+// Usage returns the number of CIDRs with the node subnet mask size of the set which can be
+// allocated in total and which are allocated.
+func (s *CidrSet) Usage() (total, allocated int) {
+	s.Lock()
+	defer s.Unlock()
+	return s.maxCIDRs, s.allocatedCIDRs
+}
+
+// FreeWithNodeMaskSize returns the number of CIDRs with the given node subnet mask size
+// which can still be allocated by AllocateNextWithNodeMaskSize.
+func (s *CidrSet) FreeWithNodeMaskSize(nodeMaskSize int) int {
+	s.Lock()
+	defer s.Unlock()
+
+	free, _ := s.freeWithNodeMaskSize(nodeMaskSize)
+	return free
+}
+
+// Fragmentation returns the ratio of the free addresses which cannot be allocated with the
+// given node subnet mask size because the existing allocations, e.g. the ones with smaller
+// node subnet mask sizes, split them into unaligned blocks. It returns 0 if nothing is free.
+func (s *CidrSet) Fragmentation(nodeMaskSize int) float64 {
+	s.Lock()
+	defer s.Unlock()
+
+	freeCIDRs := s.maxCIDRs - s.allocatedCIDRs
+	if freeCIDRs <= 0 {
+		return 0
+	}
+	free, relativeSize := s.freeWithNodeMaskSize(nodeMaskSize)
+	return 1 - float64(free*relativeSize)/float64(freeCIDRs)
+}
+
+// freeWithNodeMaskSize returns the number of free aligned blocks with the given node subnet
+// mask size and the number of CIDRs of the set each block spans. It must be called with the
+// lock held.
+func (s *CidrSet) freeWithNodeMaskSize(nodeMaskSize int) (free, relativeSize int) {
+	if nodeMaskSize < s.clusterMaskSize {
+		return 0, 1
+	}
+	// the set cannot allocate CIDRs smaller than its node subnet mask size
+	if nodeMaskSize > s.nodeMaskSize {
+		nodeMaskSize = s.nodeMaskSize
+	}
+
+	relativeSize = 1 << (s.nodeMaskSize - nodeMaskSize)
+	for i := 0; i+relativeSize <= s.maxCIDRs; i += relativeSize {
+		used := false
+		for j := 0; j < relativeSize; j++ {
+			if s.used.Bit(i+j) == 1 {
+				used = true
+				break
+			}
+		}
+		if !used {
+			free++
+		}
+	}
+	return free, relativeSize
+}
+
 func (s *CidrSet) indexToCIDRBlock(index, nodeMaskSize int) *net.IPNet {
 	if nodeMaskSize == 0 {
 		nodeMaskSize = s.nodeMaskSize
@@ -228,7 +317,7 @@ func (s *CidrSet) AllocateNext() (*net.IPNet, error) {
 	// Update metrics
 	cidrSetAllocations.WithLabelValues(s.label).Inc()
 	cidrSetAllocationTriesPerRequest.WithLabelValues(s.label).Observe(float64(i))
-	cidrSetUsage.WithLabelValues(s.label).Set(float64(s.allocatedCIDRs) / float64(s.maxCIDRs))
+	s.updateUsageMetrics()
 
 	return s.indexToCIDRBlock(candidate, 0), nil
 }
@@ -285,7 +374,7 @@ func (s *CidrSet) AllocateNextWithNodeMaskSize(nodeMaskSize int) (*net.IPNet, er
 	// Update metrics
 	cidrSetAllocations.WithLabelValues(s.label).Inc()
 	cidrSetAllocationTriesPerRequest.WithLabelValues(s.label).Observe(float64(tries))
-	cidrSetUsage.WithLabelValues(s.label).Set(float64(s.allocatedCIDRs) / float64(s.maxCIDRs))
+	s.updateUsageMetrics()
 
 	return s.indexToCIDRBlock(i, nodeMaskSize), nil
 }
@@ -357,7 +446,7 @@ func (s *CidrSet) Release(cidr *net.IPNet) error {
 		}
 	}
 
-	cidrSetUsage.WithLabelValues(s.label).Set(float64(s.allocatedCIDRs) / float64(s.maxCIDRs))
+	s.updateUsageMetrics()
 	return nil
 }
 
@@ -380,7 +469,7 @@ func (s *CidrSet) Occupy(cidr *net.IPNet) (err error) {
 		}
 	}
 
-	cidrSetUsage.WithLabelValues(s.label).Set(float64(s.allocatedCIDRs) / float64(s.maxCIDRs))
+	s.updateUsageMetrics()
 	return nil
 }
 
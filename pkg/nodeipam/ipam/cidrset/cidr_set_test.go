@@ -1300,11 +1300,116 @@ func TestCidrSetMetricsDual(t *testing.T) {
 
 }
 
+func TestCidrSetUsageMetrics(t *testing.T) {
+	_, clusterCIDR, _ := net.ParseCIDR(cidr)
+	clearMetrics(map[string]string{"clusterCIDR": cidr})
+	a, err := NewCIDRSet(clusterCIDR, 24)
+	if err != nil {
+		t.Fatalf("unexpected error creating CidrSet: %v", err)
+	}
+
+	_, halfClusterCIDR, _ := net.ParseCIDR("10.0.0.0/17")
+	_ = a.Occupy(halfClusterCIDR)
+	total, err := testutil.GetGaugeMetricValue(cidrSetTotalCIDRs.WithLabelValues(cidr))
+	if err != nil {
+		t.Fatalf("failed to get %s value, err: %v", cidrSetTotalCIDRs.Name, err)
+	}
+	allocated, err := testutil.GetGaugeMetricValue(cidrSetAllocatedCIDRs.WithLabelValues(cidr))
+	if err != nil {
+		t.Fatalf("failed to get %s value, err: %v", cidrSetAllocatedCIDRs.Name, err)
+	}
+	if total != 256 || allocated != 128 {
+		t.Fatalf("expected 256 total and 128 allocated CIDRs, got %v and %v", total, allocated)
+	}
+}
+
+func TestFreeWithNodeMaskSize(t *testing.T) {
+	_, clusterCIDR, _ := net.ParseCIDR("10.0.0.0/22")
+	a, err := NewCIDRSet(clusterCIDR, 26)
+	if err != nil {
+		t.Fatalf("unexpected error creating CidrSet: %v", err)
+	}
+	// occupy one /26 in each of the first two /24s
+	for _, occupied := range []string{"10.0.0.64/26", "10.0.1.0/26"} {
+		_, occupiedCIDR, _ := net.ParseCIDR(occupied)
+		if err := a.Occupy(occupiedCIDR); err != nil {
+			t.Fatalf("unexpected error occupying %s: %v", occupied, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		nodeMaskSize int
+		expected     int
+	}{
+		{nodeMaskSize: 26, expected: 14},
+		{nodeMaskSize: 27, expected: 14},
+		{nodeMaskSize: 25, expected: 6},
+		{nodeMaskSize: 24, expected: 2},
+		{nodeMaskSize: 23, expected: 1},
+		{nodeMaskSize: 22, expected: 0},
+		{nodeMaskSize: 21, expected: 0},
+	} {
+		if free := a.FreeWithNodeMaskSize(tc.nodeMaskSize); free != tc.expected {
+			t.Errorf("expected %d free CIDRs with node mask size %d, got %d", tc.expected, tc.nodeMaskSize, free)
+		}
+	}
+
+	total, allocated := a.Usage()
+	if total != 16 || allocated != 2 {
+		t.Errorf("expected 16 total and 2 allocated CIDRs, got %d and %d", total, allocated)
+	}
+}
+
+func TestFragmentation(t *testing.T) {
+	_, clusterCIDR, _ := net.ParseCIDR("10.0.0.0/22")
+	a, err := NewCIDRSet(clusterCIDR, 26)
+	if err != nil {
+		t.Fatalf("unexpected error creating CidrSet: %v", err)
+	}
+	if fragmentation := a.Fragmentation(24); fragmentation != 0 {
+		t.Errorf("expected no fragmentation on an empty set, got %v", fragmentation)
+	}
+
+	// occupy one /26 in each of the first two /24s, 14 /26s remain free
+	for _, occupied := range []string{"10.0.0.64/26", "10.0.1.0/26"} {
+		_, occupiedCIDR, _ := net.ParseCIDR(occupied)
+		if err := a.Occupy(occupiedCIDR); err != nil {
+			t.Fatalf("unexpected error occupying %s: %v", occupied, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		nodeMaskSize int
+		expected     float64
+	}{
+		{nodeMaskSize: 27, expected: 0},
+		{nodeMaskSize: 26, expected: 0},
+		// 3 free /25s span 12 of the 14 free /26s
+		{nodeMaskSize: 25, expected: 1 - float64(12)/float64(14)},
+		// 2 free /24s span 8 of the 14 free /26s
+		{nodeMaskSize: 24, expected: 1 - float64(8)/float64(14)},
+		{nodeMaskSize: 22, expected: 1},
+	} {
+		if fragmentation := a.Fragmentation(tc.nodeMaskSize); fragmentation != tc.expected {
+			t.Errorf("expected fragmentation %v with node mask size %d, got %v", tc.expected, tc.nodeMaskSize, fragmentation)
+		}
+	}
+
+	if err := a.Occupy(clusterCIDR); err != nil {
+		t.Fatalf("unexpected error occupying %s: %v", clusterCIDR, err)
+	}
+	if fragmentation := a.Fragmentation(24); fragmentation != 0 {
+		t.Errorf("expected no fragmentation on a full set, got %v", fragmentation)
+	}
+}
+
 // Metrics helpers
 func clearMetrics(labels map[string]string) {
 	cidrSetAllocations.Delete(labels)
 	cidrSetReleases.Delete(labels)
 	cidrSetUsage.Delete(labels)
+	cidrSetTotalCIDRs.Delete(labels)
+	cidrSetAllocatedCIDRs.Delete(labels)
 	cidrSetAllocationTriesPerRequest.Delete(labels)
 }
 
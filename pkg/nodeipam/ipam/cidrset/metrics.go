@@ -53,6 +53,24 @@ var (
 		},
 		[]string{"clusterCIDR"},
 	)
+	cidrSetTotalCIDRs = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      nodeIpamSubsystem,
+			Name:           "cidrset_cidrs_total",
+			Help:           "Gauge measuring the total number of CIDRs with the node mask size of the cidr set.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"clusterCIDR"},
+	)
+	cidrSetAllocatedCIDRs = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      nodeIpamSubsystem,
+			Name:           "cidrset_cidrs_allocated",
+			Help:           "Gauge measuring the number of allocated CIDRs with the node mask size of the cidr set.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"clusterCIDR"},
+	)
 	cidrSetAllocationTriesPerRequest = metrics.NewHistogramVec(
 		&metrics.HistogramOpts{
 			Subsystem:      nodeIpamSubsystem,
@@ -73,6 +91,8 @@ func registerCidrsetMetrics() {
 		legacyregistry.MustRegister(cidrSetAllocations)
 		legacyregistry.MustRegister(cidrSetReleases)
 		legacyregistry.MustRegister(cidrSetUsage)
+		legacyregistry.MustRegister(cidrSetTotalCIDRs)
+		legacyregistry.MustRegister(cidrSetAllocatedCIDRs)
 		legacyregistry.MustRegister(cidrSetAllocationTriesPerRequest)
 	})
 }
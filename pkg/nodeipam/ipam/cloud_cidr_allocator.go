@@ -19,6 +19,8 @@ package ipam
 import (
 	"fmt"
 	"net"
+	"slices"
+	"sort"
 	"sync"
 
 	v1 "k8s.io/api/core/v1"
@@ -26,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	informers "k8s.io/client-go/informers/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -45,8 +48,16 @@ import (
 	utiltaints "sigs.k8s.io/cloud-provider-azure/pkg/util/taints"
 )
 
+const (
+	// reasons of the node subnet mask size events
+	nodeCIDRMaskSizeChangedReason  = "NodeCIDRMaskSizeChanged"
+	nodeCIDRMaskSizeConflictReason = "NodeCIDRMaskSizeConflict"
+)
+
 // cloudCIDRAllocator allocates node CIDRs according to the node subnet mask size
-// tagged on each VMSS/VMAS.
+// tagged on each VMSS/VMAS. When the tag on a VMSS/VMAS changes, the new nodes get
+// pod CIDRs with the new mask size while the existing ones keep theirs, so one
+// cluster CIDR may hold pod CIDRs with mixed mask sizes.
 type cloudCIDRAllocator struct {
 	client clientset.Interface
 	cloud  *providerazure.Cloud
@@ -73,11 +84,24 @@ type cloudCIDRAllocator struct {
 	maxSubnetMaskSizes         []int
 	cidrSets                   []*cidrset.CidrSet
 	clusterCIDRs               []*net.IPNet
-
+	// nodePoolCIDRSets are the cidr sets of the node pools, the first one matching the node
+	// is used before falling back to cidrSets
+	nodePoolCIDRSets []*nodePoolCIDRSets
+	// capacityMonitor exports the free capacity of the cidr sets and warns when it is low
+	capacityMonitor *cidrCapacityMonitor
+	// serviceCIDRs are filtered out of the cidr sets again after they are rebuilt with a
+	// new node subnet mask size
+	serviceCIDRs []*net.IPNet
+
+	// cidrSetsLock serializes the node subnet mask size updates, which rebuild the cidr sets
+	// from nodeNamePodCIDRsMap, with the allocations and releases updating both of them
+	cidrSetsLock sync.RWMutex
+	// nodeName -> pod CIDRs allocated to or reserved for the node, mapped to the cluster CIDRs by index
 	nodeNamePodCIDRsMap map[string][]string
 }
 
 var _ CIDRAllocator = (*cloudCIDRAllocator)(nil)
+var _ CIDRCapacityChecker = (*cloudCIDRAllocator)(nil)
 
 // NewCloudCIDRAllocator creates a new cloud CIDR allocator.
 func NewCloudCIDRAllocator(
@@ -115,11 +139,17 @@ func NewCloudCIDRAllocator(
 		maxSubnetMaskSizes:         make([]int, len(allocatorParams.ClusterCIDRs)),
 		clusterCIDRs:               allocatorParams.ClusterCIDRs,
 		nodeNamePodCIDRsMap:        make(map[string][]string),
+		capacityMonitor:            newCIDRCapacityMonitor(allocatorParams.CIDRCapacityWarningThreshold, allocatorParams.NodePoolCIDRsConfigMap),
 	}
 
 	// update the node subnet mask size
 	if nodeList != nil {
 		for _, node := range nodeList.Items {
+			// the node subnet mask sizes of the cidr sets must be able to track the existing
+			// allocations even if the tags on their VMSS/VMAS have changed since
+			if len(node.Spec.PodCIDRs) > 0 {
+				ca.nodeNamePodCIDRsMap[node.Name] = append([]string{}, node.Spec.PodCIDRs...)
+			}
 			if node.Spec.ProviderID == "" {
 				klog.Warningf("NewCloudCIDRAllocator: failed when trying to read the node mask size on node %s: no provider ID", node.Name)
 				continue
@@ -145,14 +175,34 @@ func NewCloudCIDRAllocator(
 	}
 	ca.cidrSets = cidrSets
 
+	nodePoolCIDRSets, err := newNodePoolCIDRSets(allocatorParams.NodePoolCIDRs, allocatorParams.ClusterCIDRs, ca.maxSubnetMaskSizes)
+	if err != nil {
+		return nil, err
+	}
+	// for each node pool cidr, the node mask sizes of the existing nodes must be <= cidr mask
+	for nodeName, maskSizes := range ca.nodeNameSubnetMaskSizesMap {
+		if err := validateNodePoolMaskSizes(nodePoolCIDRSets, maskSizes); err != nil {
+			return nil, fmt.Errorf("invalid mask sizes %v of node %s: %w", maskSizes, nodeName, err)
+		}
+	}
+	ca.nodePoolCIDRSets = nodePoolCIDRSets
+
 	if allocatorParams.ServiceCIDR != nil {
+		ca.serviceCIDRs = append(ca.serviceCIDRs, allocatorParams.ServiceCIDR)
 		filterOutServiceRange(ca.clusterCIDRs, ca.cidrSets, allocatorParams.ServiceCIDR)
+		for _, pool := range ca.nodePoolCIDRSets {
+			filterOutServiceRange(pool.clusterCIDRs, pool.cidrSets, allocatorParams.ServiceCIDR)
+		}
 	} else {
 		klog.V(0).Info("No Service CIDR provided. Skipping filtering out service addresses.")
 	}
 
 	if allocatorParams.SecondaryServiceCIDR != nil {
+		ca.serviceCIDRs = append(ca.serviceCIDRs, allocatorParams.SecondaryServiceCIDR)
 		filterOutServiceRange(ca.clusterCIDRs, ca.cidrSets, allocatorParams.SecondaryServiceCIDR)
+		for _, pool := range ca.nodePoolCIDRSets {
+			filterOutServiceRange(pool.clusterCIDRs, pool.cidrSets, allocatorParams.SecondaryServiceCIDR)
+		}
 	} else {
 		klog.V(0).Info("No Secondary Service CIDR provided. Skipping filtering out secondary service addresses.")
 	}
@@ -198,7 +248,8 @@ func NewCloudCIDRAllocator(
 	return ca, nil
 }
 
-// updateMaxSubnetMaskSizes finds the max subnet size on all existing nodes
+// updateMaxSubnetMaskSizes finds the max subnet size on all existing nodes, including the
+// mask sizes of their allocated pod CIDRs
 func (ca *cloudCIDRAllocator) updateMaxSubnetMaskSizes() {
 	ca.lock.Lock()
 	defer ca.lock.Unlock()
@@ -217,10 +268,88 @@ func (ca *cloudCIDRAllocator) updateMaxSubnetMaskSizes() {
 		}
 	}
 
+	// a pod CIDR allocated before the tag on its VMSS/VMAS changed may be smaller than
+	// the current mask size, and the cidr sets must still be able to release it alone
+	for _, podCIDRs := range ca.nodeNamePodCIDRsMap {
+		for i := 0; i < len(ca.clusterCIDRs) && i < len(podCIDRs); i++ {
+			_, podCIDR, err := net.ParseCIDR(podCIDRs[i])
+			if err != nil {
+				continue
+			}
+			if size, _ := podCIDR.Mask.Size(); size > maxNodeSubnetMaskSizes[i] {
+				maxNodeSubnetMaskSizes[i] = size
+			}
+		}
+	}
+
 	// update them to the cloud allocator
 	ca.maxSubnetMaskSizes = maxNodeSubnetMaskSizes
 }
 
+// getNodeSubnetMaskSizes returns the node subnet mask sizes last read from the VMSS/VMAS of
+// the node, or nil if they have not been read.
+func (ca *cloudCIDRAllocator) getNodeSubnetMaskSizes(nodeName string) []int {
+	ca.lock.Lock()
+	defer ca.lock.Unlock()
+	return ca.nodeNameSubnetMaskSizesMap[nodeName]
+}
+
+// setNodeSubnetMaskSizes sets the node subnet mask sizes of the node, or removes them if nil.
+func (ca *cloudCIDRAllocator) setNodeSubnetMaskSizes(nodeName string, maskSizes []int) {
+	ca.lock.Lock()
+	defer ca.lock.Unlock()
+	if maskSizes == nil {
+		delete(ca.nodeNameSubnetMaskSizesMap, nodeName)
+		return
+	}
+	ca.nodeNameSubnetMaskSizesMap[nodeName] = maskSizes
+}
+
+// setNodePodCIDRs records the pod CIDRs allocated to or reserved for the node, or removes them if empty.
+func (ca *cloudCIDRAllocator) setNodePodCIDRs(nodeName string, podCIDRs []string) {
+	ca.lock.Lock()
+	defer ca.lock.Unlock()
+	if len(podCIDRs) == 0 {
+		delete(ca.nodeNamePodCIDRsMap, nodeName)
+		return
+	}
+	ca.nodeNamePodCIDRsMap[nodeName] = podCIDRs
+}
+
+// updateCIDRSetsMaskSizes updates the node subnet mask sizes of the cidr sets to the max ones.
+// A cidr set only moves to larger mask sizes, i.e. smaller blocks: it is rebuilt from the pod
+// CIDRs allocated so far, which remain valid whatever their mask sizes.
+func (ca *cloudCIDRAllocator) updateCIDRSetsMaskSizes() error {
+	ca.cidrSetsLock.Lock()
+	defer ca.cidrSetsLock.Unlock()
+
+	ca.lock.Lock()
+	maxSubnetMaskSizes := append([]int{}, ca.maxSubnetMaskSizes...)
+	nodeNamePodCIDRsMap := make(map[string][]string, len(ca.nodeNamePodCIDRsMap))
+	for nodeName, podCIDRs := range ca.nodeNamePodCIDRsMap {
+		nodeNamePodCIDRsMap[nodeName] = podCIDRs
+	}
+	ca.lock.Unlock()
+
+	for _, pool := range withDefaultNodePoolCIDRSets(ca.nodePoolCIDRSets, ca.clusterCIDRs, ca.cidrSets) {
+		for i, cidrSet := range pool.cidrSets {
+			oldMaskSize := cidrSet.NodeMaskSize()
+			if oldMaskSize >= maxSubnetMaskSizes[i] {
+				continue
+			}
+			if err := cidrSet.UpdateSubnetMaskSize(maxSubnetMaskSizes[i], nodeNamePodCIDRsMap); err != nil {
+				return fmt.Errorf("failed to update the node subnet mask size of cluster CIDR %v from %d to %d: %w", pool.clusterCIDRs[i], oldMaskSize, maxSubnetMaskSizes[i], err)
+			}
+			// the service ranges are not part of the allocations the cidr set is rebuilt from
+			for _, serviceCIDR := range ca.serviceCIDRs {
+				filterOutServiceRange(pool.clusterCIDRs[i:i+1], pool.cidrSets[i:i+1], serviceCIDR)
+			}
+			klog.Infof("Updated the node subnet mask size of cluster CIDR %v of node pool CIDR %s from %d to %d", pool.clusterCIDRs[i], pool.name, oldMaskSize, maxSubnetMaskSizes[i])
+		}
+	}
+	return nil
+}
+
 // updateNodeSubnetMaskSizes gets the node's VMSS/VMAS, reads the mask size tag on it and updates them into the map
 func (ca *cloudCIDRAllocator) updateNodeSubnetMaskSizes(nodeName, providerID string) error {
 	ca.lock.Lock()
@@ -230,7 +359,7 @@ func (ca *cloudCIDRAllocator) updateNodeSubnetMaskSizes(nodeName, providerID str
 		klog.Warningf("updateNodeSubnetMaskSizes(%s): empty providerID", providerID)
 	}
 
-	ipv4Mask, ipv6Mask, err := ca.cloud.VMSet.GetNodeCIDRMasksByProviderID(providerID)
+	ipv4Mask, ipv6Mask, err := ca.cloud.GetNodeCIDRMasksByProviderID(providerID)
 	if err != nil {
 		klog.Warningf("updateNodeSubnetMaskSizes(%s): cannot get node subnet mask size by providerID: %v", providerID, err)
 	}
@@ -258,10 +387,46 @@ func (ca *cloudCIDRAllocator) updateNodeSubnetMaskSizes(nodeName, providerID str
 		}
 	}
 
+	// the node pool cidr sets are validated against the existing nodes once they're created
+	if err := validateNodePoolMaskSizes(ca.nodePoolCIDRSets, maskSizes); err != nil {
+		return fmt.Errorf("updateNodeSubnetMaskSizes: invalid mask sizes %v of node %s: %w", maskSizes, nodeName, err)
+	}
+
 	ca.nodeNameSubnetMaskSizesMap[nodeName] = maskSizes
 	return nil
 }
 
+// monitorCIDRCapacity refreshes the free capacity metrics of the cidr sets for each node
+// subnet mask size in use and emits events when the capacity is low.
+func (ca *cloudCIDRAllocator) monitorCIDRCapacity() {
+	ca.lock.Lock()
+	maskSizes := make([]map[int]struct{}, len(ca.clusterCIDRs))
+	for i := range ca.clusterCIDRs {
+		maskSizes[i] = map[int]struct{}{ca.maxSubnetMaskSizes[i]: {}}
+	}
+	for _, sizes := range ca.nodeNameSubnetMaskSizesMap {
+		for i := 0; i < len(ca.clusterCIDRs) && i < len(sizes); i++ {
+			maskSizes[i][sizes[i]] = struct{}{}
+		}
+	}
+	ca.lock.Unlock()
+
+	nodeMaskSizes := make([][]int, len(maskSizes))
+	for i, sizes := range maskSizes {
+		for size := range sizes {
+			nodeMaskSizes[i] = append(nodeMaskSizes[i], size)
+		}
+		sort.Ints(nodeMaskSizes[i])
+	}
+	ca.capacityMonitor.check(ca.recorder, withDefaultNodePoolCIDRSets(ca.nodePoolCIDRSets, ca.clusterCIDRs, ca.cidrSets), nodeMaskSizes)
+}
+
+// CheckCIDRCapacity checks whether the planned nodes fit in the current CIDRs without
+// allocating anything.
+func (ca *cloudCIDRAllocator) CheckCIDRCapacity(plans []CIDRCapacityPlan) ([]CIDRCapacityCheckResult, error) {
+	return checkCIDRCapacity(withDefaultNodePoolCIDRSets(ca.nodePoolCIDRSets, ca.clusterCIDRs, ca.cidrSets), plans)
+}
+
 func (ca *cloudCIDRAllocator) Run(stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 
@@ -275,6 +440,7 @@ func (ca *cloudCIDRAllocator) Run(stopCh <-chan struct{}) {
 	for i := 0; i < cidrUpdateWorkers; i++ {
 		go ca.worker(stopCh)
 	}
+	go wait.Until(ca.monitorCIDRCapacity, cidrCapacityCheckPeriod, stopCh)
 
 	<-stopCh
 }
@@ -319,28 +485,33 @@ func (ca *cloudCIDRAllocator) occupyCIDRs(node *v1.Node) error {
 	if len(node.Spec.PodCIDRs) == 0 {
 		return nil
 	}
+	ca.cidrSetsLock.RLock()
+	defer ca.cidrSetsLock.RUnlock()
+
 	podCIDRs := make([]string, len(ca.clusterCIDRs))
+	var cidrSets []*cidrset.CidrSet
 	for i, cidr := range node.Spec.PodCIDRs {
 		_, podCIDR, err := net.ParseCIDR(cidr)
 		if err != nil {
 			return fmt.Errorf("failed to parse node %s, CIDR %s", node.Name, node.Spec.PodCIDR)
 		}
+		if cidrSets == nil {
+			cidrSets = cidrSetsForPodCIDR(ca.nodePoolCIDRSets, ca.cidrSets, podCIDR)
+		}
 		// If node has a pre allocate cidr that does not exist in our cidrs.
 		// This will happen if cluster went from dualstack(multi cidrs) to non-dualstack
 		// then we have now way of locking it
-		if i >= len(ca.cidrSets) {
+		if i >= len(cidrSets) {
 			return fmt.Errorf("node:%s has an allocated cidr: %v at index:%v that does not exist in cluster cidrs configuration", node.Name, cidr, i)
 		}
 
-		if err := ca.cidrSets[i].Occupy(podCIDR); err != nil {
+		if err := cidrSets[i].Occupy(podCIDR); err != nil {
 			return fmt.Errorf("failed to mark cidr[%v] at i [%v] as occupied for node %s: %w", podCIDR, i, node.Name, err)
 		}
 
 		podCIDRs[i] = cidr
 	}
-	ca.lock.Lock()
-	ca.nodeNamePodCIDRsMap[node.Name] = podCIDRs
-	ca.lock.Unlock()
+	ca.setNodePodCIDRs(node.Name, podCIDRs)
 
 	return nil
 }
@@ -357,56 +528,91 @@ func (ca *cloudCIDRAllocator) AllocateOrOccupyCIDR(node *v1.Node) error {
 		return nil
 	}
 
+	prevMaskSizes := ca.getNodeSubnetMaskSizes(node.Name)
 	err := ca.updateNodeSubnetMaskSizes(node.Name, node.Spec.ProviderID)
 	if err != nil {
 		klog.Errorf("AllocateOrOccupyCIDR(%s): failed to update node subnet mask sizes: %v", node.Name, err)
+		ca.removeNodeFromProcessing(node.Name)
+		ca.recorder.Eventf(nodeReference(node), v1.EventTypeWarning, nodeCIDRMaskSizeConflictReason, "Invalid node CIDR mask sizes: %v", err)
 		return err
 	}
+	maskSizes := ca.getNodeSubnetMaskSizes(node.Name)
 	ca.updateMaxSubnetMaskSizes()
 
 	// Keep the mask size in each cidr set the max one when new node added in.
 	// The mask size would not change unless the new node is from a new VMSS/VMAS
-	// and the mask value tagging on it is different from the existing ones.
-	for i, cidrSet := range ca.cidrSets {
-		cidrSet := cidrSet
-		err := cidrSet.UpdateSubnetMaskSize(ca.maxSubnetMaskSizes[i], ca.nodeNamePodCIDRsMap)
-		if err != nil {
-			ca.removeNodeFromProcessing(node.Name)
-			return err
-		}
-		ca.cidrSets[i] = cidrSet
+	// or the mask value tagging on its VMSS/VMAS has changed.
+	if err := ca.updateCIDRSetsMaskSizes(); err != nil {
+		klog.Errorf("AllocateOrOccupyCIDR(%s): %v", node.Name, err)
+		// the mask sizes of this node must not block the allocations of the others
+		ca.setNodeSubnetMaskSizes(node.Name, prevMaskSizes)
+		ca.updateMaxSubnetMaskSizes()
+		ca.removeNodeFromProcessing(node.Name)
+		ca.recorder.Eventf(nodeReference(node), v1.EventTypeWarning, nodeCIDRMaskSizeConflictReason, "Node CIDR mask sizes %v conflict with the cluster CIDRs: %v", maskSizes, err)
+		return err
+	}
+	if prevMaskSizes != nil && !slices.Equal(prevMaskSizes, maskSizes) {
+		ca.recordNodeSubnetMaskSizesChange(node, prevMaskSizes, maskSizes)
 	}
 
 	if len(node.Spec.PodCIDRs) > 0 {
 		return ca.occupyCIDRs(node)
 	}
 
+	cidrSets := cidrSetsForNode(ca.nodePoolCIDRSets, ca.cidrSets, node)
 	allocated := nodeReservedCIDRs{
 		nodeName:       node.Name,
-		allocatedCIDRs: make([]*net.IPNet, len(ca.cidrSets)),
+		allocatedCIDRs: make([]*net.IPNet, len(cidrSets)),
 	}
 
-	for i := range ca.cidrSets {
-		podCIDR, err := ca.cidrSets[i].AllocateNextWithNodeMaskSize(ca.nodeNameSubnetMaskSizesMap[node.Name][i])
+	ca.cidrSetsLock.RLock()
+	for i := range cidrSets {
+		podCIDR, err := cidrSets[i].AllocateNextWithNodeMaskSize(maskSizes[i])
 		if err != nil {
+			for j := 0; j < i; j++ {
+				if releaseErr := cidrSets[j].Release(allocated.allocatedCIDRs[j]); releaseErr != nil {
+					klog.Errorf("Error when releasing CIDR idx:%v value: %v err:%v", j, allocated.allocatedCIDRs[j], releaseErr)
+				}
+			}
+			ca.cidrSetsLock.RUnlock()
 			ca.removeNodeFromProcessing(node.Name)
 			nodeutil.RecordNodeStatusChange(ca.recorder, node, "CIDRNotAvailable")
 			return fmt.Errorf("failed to allocate cidr from cluster cidr at idx:%v: %w", i, err)
 		}
 		allocated.allocatedCIDRs[i] = podCIDR
 	}
+	// the reserved pod CIDRs must survive the cidr sets being rebuilt before the node is patched
+	ca.setNodePodCIDRs(node.Name, cidrsAsString(allocated.allocatedCIDRs))
+	ca.cidrSetsLock.RUnlock()
+
+	ca.capacityMonitor.warnNode(ca.recorder, node, cidrSets)
 
 	klog.V(4).Infof("Putting node %s into the work queue", node.Name)
 	ca.nodeUpdateChannel <- allocated
 	return nil
 }
 
+// recordNodeSubnetMaskSizesChange reports that the mask size tags on the VMSS/VMAS of the node
+// have changed. The existing pod CIDRs of the node are kept.
+func (ca *cloudCIDRAllocator) recordNodeSubnetMaskSizesChange(node *v1.Node, oldMaskSizes, newMaskSizes []int) {
+	klog.Infof("Node subnet mask sizes of node %s changed from %v to %v", node.Name, oldMaskSizes, newMaskSizes)
+	if len(node.Spec.PodCIDRs) == 0 {
+		ca.recorder.Eventf(nodeReference(node), v1.EventTypeNormal, nodeCIDRMaskSizeChangedReason, "Node CIDR mask sizes changed from %v to %v", oldMaskSizes, newMaskSizes)
+		return
+	}
+	ca.recorder.Eventf(nodeReference(node), v1.EventTypeNormal, nodeCIDRMaskSizeChangedReason, "Node CIDR mask sizes changed from %v to %v, keeping the existing pod CIDRs %v", oldMaskSizes, newMaskSizes, node.Spec.PodCIDRs)
+}
+
 // updateCIDRsAllocation assigns CIDR to Node and sends an update to the API server.
 func (ca *cloudCIDRAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) error {
 	var err error
 	var node *v1.Node
 	defer ca.removeNodeFromProcessing(data.nodeName)
 	cidrsString := cidrsAsString(data.allocatedCIDRs)
+	cidrSets := ca.cidrSets
+	if len(data.allocatedCIDRs) > 0 {
+		cidrSets = cidrSetsForPodCIDR(ca.nodePoolCIDRSets, ca.cidrSets, data.allocatedCIDRs[0])
+	}
 	node, err = ca.nodeLister.Get(data.nodeName)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -436,11 +642,14 @@ func (ca *cloudCIDRAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) erro
 	// node has cidrs, release the reserved
 	if len(node.Spec.PodCIDRs) != 0 {
 		klog.Errorf("Node %v already has a CIDR allocated %v. Releasing the new one.", node.Name, node.Spec.PodCIDRs)
+		ca.cidrSetsLock.RLock()
+		defer ca.cidrSetsLock.RUnlock()
 		for idx, cidr := range data.allocatedCIDRs {
-			if releaseErr := ca.cidrSets[idx].Release(cidr); releaseErr != nil {
+			if releaseErr := cidrSets[idx].Release(cidr); releaseErr != nil {
 				klog.Errorf("Error when releasing CIDR idx:%v value: %v err:%v", idx, cidr, releaseErr)
 			}
 		}
+		ca.setNodePodCIDRs(node.Name, append([]string{}, node.Spec.PodCIDRs...))
 		return nil
 	}
 
@@ -458,11 +667,14 @@ func (ca *cloudCIDRAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) erro
 	// NodeController restart will return all falsely allocated CIDRs to the pool.
 	if !apierrors.IsServerTimeout(err) {
 		klog.Errorf("CIDR assignment for node %v failed: %v. Releasing allocated CIDR", node.Name, err)
+		ca.cidrSetsLock.RLock()
 		for idx, cidr := range data.allocatedCIDRs {
-			if releaseErr := ca.cidrSets[idx].Release(cidr); releaseErr != nil {
+			if releaseErr := cidrSets[idx].Release(cidr); releaseErr != nil {
 				klog.Errorf("Error releasing allocated CIDR for node %v: %v", node.Name, releaseErr)
 			}
 		}
+		ca.setNodePodCIDRs(node.Name, nil)
+		ca.cidrSetsLock.RUnlock()
 	}
 
 	err = utilnode.SetNodeCondition(ca.client, types.NodeName(node.Name), v1.NodeCondition{
@@ -480,27 +692,38 @@ func (ca *cloudCIDRAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) erro
 }
 
 func (ca *cloudCIDRAllocator) ReleaseCIDR(node *v1.Node) error {
-	if node == nil || len(node.Spec.PodCIDRs) == 0 {
+	if node == nil {
+		return nil
+	}
+	// the deleted node must not keep the mask sizes of its VMSS/VMAS in use
+	ca.setNodeSubnetMaskSizes(node.Name, nil)
+	if len(node.Spec.PodCIDRs) == 0 {
 		return nil
 	}
 
+	ca.cidrSetsLock.RLock()
+	defer ca.cidrSetsLock.RUnlock()
+	var cidrSets []*cidrset.CidrSet
 	for i, cidr := range node.Spec.PodCIDRs {
 		_, podCIDR, err := net.ParseCIDR(cidr)
 		if err != nil {
 			return fmt.Errorf("failed to parse CIDR %s on Node %v: %w", cidr, node.Name, err)
 		}
+		if cidrSets == nil {
+			cidrSets = cidrSetsForPodCIDR(ca.nodePoolCIDRSets, ca.cidrSets, podCIDR)
+		}
 
-		if i >= len(ca.cidrSets) {
+		if i >= len(cidrSets) {
 			return fmt.Errorf("node:%s has an allocated cidr: %v at index:%v that does not exist in cluster cidrs configuration", node.Name, cidr, i)
 		}
 
 		klog.V(4).Infof("release CIDR %s for node:%v", cidr, node.Name)
-		if err = ca.cidrSets[i].Release(podCIDR); err != nil {
+		if err = cidrSets[i].Release(podCIDR); err != nil {
 			return fmt.Errorf("error when releasing CIDR %v: %w", cidr, err)
 		}
 
 	}
-	delete(ca.nodeNamePodCIDRsMap, node.Name)
+	ca.setNodePodCIDRs(node.Name, nil)
 
 	return nil
 }
@@ -19,6 +19,7 @@ package ipam
 import (
 	"fmt"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,11 +31,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
+	metricstestutil "k8s.io/component-base/metrics/testutil"
 	"k8s.io/utils/pointer"
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/vmclient/mockvmclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/vmssclient/mockvmssclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam/test"
 	azureprovider "sigs.k8s.io/cloud-provider-azure/pkg/provider"
 	"sigs.k8s.io/cloud-provider-azure/pkg/util/controller/testutil"
 )
@@ -238,3 +241,203 @@ func TestUpdateNodeSubnetMaskSizes(t *testing.T) {
 		})
 	}
 }
+
+func newTestVMSSNode(name, vmssName string, podCIDRs ...string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1.NodeSpec{
+			ProviderID: fmt.Sprintf("azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/%s/virtualMachines/0", vmssName),
+			PodCIDRs:   podCIDRs,
+		},
+	}
+}
+
+// newTestCloudCIDRAllocator creates a cloud CIDR allocator reading the ipv4 node subnet mask
+// sizes from vmssMaskSizes, which maps the VMSS names to their tag values.
+func newTestCloudCIDRAllocator(t *testing.T, ctrl *gomock.Controller, vmssMaskSizes map[string]int, allocatorParams CIDRAllocatorParams, nodes ...*v1.Node) *cloudCIDRAllocator {
+	cloud := azureprovider.GetTestCloud(ctrl)
+	mockVMSet := azureprovider.NewMockVMSet(ctrl)
+	mockVMSet.EXPECT().GetNodeCIDRMasksByProviderID(gomock.Any()).DoAndReturn(func(providerID string) (int, int, error) {
+		parts := strings.Split(providerID, "/")
+		return vmssMaskSizes[parts[len(parts)-3]], 0, nil
+	}).AnyTimes()
+	cloud.VMSet = mockVMSet
+
+	clientSet := fake.NewSimpleClientset()
+	nodeList := &v1.NodeList{}
+	for _, node := range nodes {
+		nodeList.Items = append(nodeList.Items, *node)
+	}
+	allocator, err := NewCloudCIDRAllocator(clientSet, cloud, getFakeNodeInformer(&testutil.FakeNodeHandler{Clientset: clientSet}), allocatorParams, nodeList)
+	assert.NoError(t, err)
+	ca := allocator.(*cloudCIDRAllocator)
+	ca.recorder = testutil.NewFakeRecorder()
+	return ca
+}
+
+// allocateTestNodeCIDRs allocates the pod CIDRs of the node and takes the reservation off the
+// work queue without patching the node, so that the cidr sets may be rebuilt in between.
+func allocateTestNodeCIDRs(t *testing.T, ca *cloudCIDRAllocator, node *v1.Node) []string {
+	assert.NoError(t, ca.AllocateOrOccupyCIDR(node))
+	reserved := <-ca.nodeUpdateChannel
+	ca.removeNodeFromProcessing(node.Name)
+	node.Spec.PodCIDRs = cidrsAsString(reserved.allocatedCIDRs)
+	return node.Spec.PodCIDRs
+}
+
+func eventsWithReason(recorder *testutil.FakeRecorder, reason string) []*v1.Event {
+	var events []*v1.Event
+	for _, event := range recorder.Events {
+		if event.Reason == reason {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestCloudCIDRAllocatorMixedMaskSizes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vmssMaskSizes := map[string]int{"vmss1": 24, "vmss2": 26}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs: []*net.IPNet{test.MustParseCIDR("10.240.0.0/22")},
+		ServiceCIDR:  test.MustParseCIDR("10.240.3.0/24"),
+	}
+	ca := newTestCloudCIDRAllocator(t, ctrl, vmssMaskSizes, allocatorParams, newTestVMSSNode("vmss1-0", "vmss1", "10.240.0.0/24"))
+	recorder := ca.recorder.(*testutil.FakeRecorder)
+	cidrSet := ca.cidrSets[0]
+	assert.Equal(t, 24, cidrSet.NodeMaskSize())
+
+	// the first node of vmss2 moves the cidr set to /26 while the existing /24s stay allocated
+	assert.Equal(t, []string{"10.240.1.0/26"}, allocateTestNodeCIDRs(t, ca, newTestVMSSNode("vmss2-0", "vmss2")))
+	assert.Equal(t, 26, cidrSet.NodeMaskSize())
+	assert.Equal(t, []string{"10.240.2.0/24"}, allocateTestNodeCIDRs(t, ca, newTestVMSSNode("vmss1-1", "vmss1")))
+	assert.Equal(t, []string{"10.240.1.64/26"}, allocateTestNodeCIDRs(t, ca, newTestVMSSNode("vmss2-1", "vmss2")))
+
+	// the service range is still filtered out after the cidr set is rebuilt
+	assert.Equal(t, 0, cidrSet.FreeWithNodeMaskSize(24))
+	assert.Equal(t, 1, cidrSet.FreeWithNodeMaskSize(25))
+	assert.Equal(t, 2, cidrSet.FreeWithNodeMaskSize(26))
+	// the two free /26s can hold a /25 but no /24
+	assert.Equal(t, float64(1), cidrSet.Fragmentation(24))
+	assert.Equal(t, float64(0), cidrSet.Fragmentation(25))
+	ca.monitorCIDRCapacity()
+	fragmentation, err := metricstestutil.GetGaugeMetricValue(cidrSetFragmentation.WithLabelValues("10.240.0.0/22", "24"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), fragmentation)
+
+	// changing the tag on vmss1 keeps the pod CIDR of its existing node and reports the change
+	vmssMaskSizes["vmss1"] = 25
+	existing := newTestVMSSNode("vmss1-0", "vmss1", "10.240.0.0/24")
+	assert.NoError(t, ca.AllocateOrOccupyCIDR(existing))
+	events := eventsWithReason(recorder, nodeCIDRMaskSizeChangedReason)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "vmss1-0", events[0].InvolvedObject.Name)
+	assert.Contains(t, events[0].Message, "10.240.0.0/24")
+	assert.Equal(t, []string{"10.240.0.0/24"}, ca.nodeNamePodCIDRsMap["vmss1-0"])
+
+	// the new nodes of vmss1 get /25s
+	assert.Equal(t, []string{"10.240.1.128/25"}, allocateTestNodeCIDRs(t, ca, newTestVMSSNode("vmss1-2", "vmss1")))
+	assert.Error(t, ca.AllocateOrOccupyCIDR(newTestVMSSNode("vmss1-3", "vmss1")))
+	assert.False(t, hasNodeInProcessing(ca, "vmss1-3"))
+
+	// releasing a /26 frees only that /26 even if the cidr set holds larger pod CIDRs
+	assert.NoError(t, ca.ReleaseCIDR(newTestVMSSNode("vmss2-0", "vmss2", "10.240.1.0/26")))
+	assert.Equal(t, 1, cidrSet.FreeWithNodeMaskSize(26))
+	assert.Equal(t, []string{"10.240.1.0/26"}, allocateTestNodeCIDRs(t, ca, newTestVMSSNode("vmss2-2", "vmss2")))
+	assert.Equal(t, 0, cidrSet.FreeWithNodeMaskSize(26))
+}
+
+func TestCloudCIDRAllocatorMaskSizeConflicts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vmssMaskSizes := map[string]int{"vmss1": 24, "vmss2": 23}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs: []*net.IPNet{test.MustParseCIDR("10.240.0.0/22")},
+	}
+	ca := newTestCloudCIDRAllocator(t, ctrl, vmssMaskSizes, allocatorParams)
+	recorder := ca.recorder.(*testutil.FakeRecorder)
+
+	// a mask size out of the range of the cluster CIDR is reported on the node
+	vmssMaskSizes["vmss3"] = 16
+	assert.Error(t, ca.AllocateOrOccupyCIDR(newTestVMSSNode("vmss3-0", "vmss3")))
+	assert.False(t, hasNodeInProcessing(ca, "vmss3-0"))
+	events := eventsWithReason(recorder, nodeCIDRMaskSizeConflictReason)
+	assert.Len(t, events, 1)
+	assert.Equal(t, v1.EventTypeWarning, events[0].Type)
+	assert.Equal(t, "vmss3-0", events[0].InvolvedObject.Name)
+
+	// a /24 splits the cluster CIDR so that only one /23 remains
+	assert.Equal(t, []string{"10.240.0.0/24"}, allocateTestNodeCIDRs(t, ca, newTestVMSSNode("vmss1-0", "vmss1")))
+	assert.Equal(t, []string{"10.240.2.0/23"}, allocateTestNodeCIDRs(t, ca, newTestVMSSNode("vmss2-0", "vmss2")))
+	assert.Error(t, ca.AllocateOrOccupyCIDR(newTestVMSSNode("vmss2-1", "vmss2")))
+	assert.Len(t, eventsWithReason(recorder, "CIDRNotAvailable"), 1)
+	assert.Equal(t, []string{"10.240.1.0/24"}, allocateTestNodeCIDRs(t, ca, newTestVMSSNode("vmss1-1", "vmss1")))
+}
+
+func TestCloudCIDRAllocatorMixedMaskSizesOnRestart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// vmss2 was tagged with /26 when its nodes were allocated and is now tagged with /24
+	vmssMaskSizes := map[string]int{"vmss1": 24, "vmss2": 24}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs: []*net.IPNet{test.MustParseCIDR("10.240.0.0/22")},
+	}
+	ca := newTestCloudCIDRAllocator(t, ctrl, vmssMaskSizes, allocatorParams,
+		newTestVMSSNode("vmss1-0", "vmss1", "10.240.0.0/24"),
+		newTestVMSSNode("vmss2-0", "vmss2", "10.240.1.0/26"),
+		newTestVMSSNode("vmss2-1", "vmss2", "10.240.1.64/26"),
+	)
+	cidrSet := ca.cidrSets[0]
+
+	// the cidr set keeps tracking the /26s separately
+	assert.Equal(t, 26, cidrSet.NodeMaskSize())
+	assert.Equal(t, []int{26}, ca.maxSubnetMaskSizes)
+	assert.Equal(t, 10, cidrSet.FreeWithNodeMaskSize(26))
+
+	assert.NoError(t, ca.ReleaseCIDR(newTestVMSSNode("vmss2-0", "vmss2", "10.240.1.0/26")))
+	assert.Equal(t, 11, cidrSet.FreeWithNodeMaskSize(26))
+	assert.Equal(t, []string{"10.240.2.0/24"}, allocateTestNodeCIDRs(t, ca, newTestVMSSNode("vmss2-2", "vmss2")))
+
+	// the mask sizes of the deleted nodes no longer count
+	assert.NotContains(t, ca.nodeNameSubnetMaskSizesMap, "vmss2-0")
+}
+
+func TestCloudCIDRAllocatorNodePoolCIDRsMaskSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vmssMaskSizes := map[string]int{"vmss1": 26, "vmss2": 24}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs: []*net.IPNet{test.MustParseCIDR("10.240.0.0/16")},
+		NodePoolCIDRs: []NodePoolCIDR{
+			{Name: "small", ClusterCIDRs: []string{"10.10.0.0/25"}},
+		},
+	}
+
+	// an existing node with a node mask larger than the node pool CIDR fails the allocator
+	cloud := azureprovider.GetTestCloud(ctrl)
+	mockVMSet := azureprovider.NewMockVMSet(ctrl)
+	mockVMSet.EXPECT().GetNodeCIDRMasksByProviderID(gomock.Any()).DoAndReturn(func(providerID string) (int, int, error) {
+		parts := strings.Split(providerID, "/")
+		return vmssMaskSizes[parts[len(parts)-3]], 0, nil
+	}).AnyTimes()
+	cloud.VMSet = mockVMSet
+	clientSet := fake.NewSimpleClientset()
+	nodeList := &v1.NodeList{Items: []v1.Node{*newTestVMSSNode("vmss1-0", "vmss1"), *newTestVMSSNode("vmss2-0", "vmss2")}}
+	_, err := NewCloudCIDRAllocator(clientSet, cloud, getFakeNodeInformer(&testutil.FakeNodeHandler{Clientset: clientSet}), allocatorParams, nodeList)
+	assert.ErrorContains(t, err, "node pool CIDR small must be less than or equal to the node cidr mask size 24")
+
+	// a new node with a node mask larger than the node pool CIDR is rejected
+	ca := newTestCloudCIDRAllocator(t, ctrl, vmssMaskSizes, allocatorParams, newTestVMSSNode("vmss1-0", "vmss1"))
+	err = ca.updateNodeSubnetMaskSizes("vmss2-0", newTestVMSSNode("vmss2-0", "vmss2").Spec.ProviderID)
+	assert.ErrorContains(t, err, "invalid mask sizes [24] of node vmss2-0")
+	assert.NotContains(t, ca.nodeNameSubnetMaskSizesMap, "vmss2-0")
+	assert.Error(t, ca.AllocateOrOccupyCIDR(newTestVMSSNode("vmss2-1", "vmss2")))
+	assert.NoError(t, ca.updateNodeSubnetMaskSizes("vmss1-1", newTestVMSSNode("vmss1-1", "vmss1").Spec.ProviderID))
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam/cidrset"
+)
+
+const (
+	// NodePoolCIDRsConfigMapKey is the key in the node pool CIDRs ConfigMap holding the configuration.
+	NodePoolCIDRsConfigMapKey = "nodePoolCIDRs"
+	// DefaultNodePoolCIDRName is the name reserved for the cluster CIDRs used by the nodes
+	// matching no node pool CIDR.
+	DefaultNodePoolCIDRName = "default"
+)
+
+// NodePoolCIDR maps the nodes selected by NodeSelector to a dedicated list of cluster CIDRs,
+// e.g. to allocate the pod CIDRs of one node pool from a routable range.
+type NodePoolCIDR struct {
+	// Name identifies the node pool CIDR in logs and events.
+	Name string `json:"name"`
+	// NodeSelector selects the nodes whose pod CIDRs are allocated from ClusterCIDRs.
+	// An empty selector matches all nodes.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	// ClusterCIDRs is the list of cluster CIDRs of the node pool. It must contain the same
+	// IP families in the same order as --cluster-cidr.
+	ClusterCIDRs []string `json:"clusterCIDRs"`
+}
+
+// ParseNodePoolCIDRs parses the node pool CIDRs from the YAML or JSON data.
+func ParseNodePoolCIDRs(data []byte) ([]NodePoolCIDR, error) {
+	var nodePoolCIDRs []NodePoolCIDR
+	if err := yaml.UnmarshalStrict(data, &nodePoolCIDRs); err != nil {
+		return nil, fmt.Errorf("failed to parse node pool CIDRs: %w", err)
+	}
+	return nodePoolCIDRs, nil
+}
+
+// LoadNodePoolCIDRs reads the node pool CIDRs from the given ConfigMap and returns them with the
+// reference of the ConfigMap. It returns nil if the ConfigMap does not exist.
+func LoadNodePoolCIDRs(kubeClient clientset.Interface, namespace, name string) ([]NodePoolCIDR, *v1.ObjectReference, error) {
+	var configMap *v1.ConfigMap
+	// We must poll because apiserver might not be up.
+	if pollErr := wait.Poll(10*time.Second, apiserverStartupGracePeriod, func() (bool, error) {
+		var err error
+		configMap, err = kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			klog.Errorf("Failed to get node pool CIDRs ConfigMap %s/%s: %v", namespace, name, err)
+			return false, nil
+		}
+		return true, nil
+	}); pollErr != nil {
+		return nil, nil, fmt.Errorf("failed to get node pool CIDRs ConfigMap %s/%s in %v", namespace, name, apiserverStartupGracePeriod)
+	}
+
+	if configMap == nil {
+		klog.Warningf("Node pool CIDRs ConfigMap %s/%s not found, allocating from the cluster CIDRs only", namespace, name)
+		return nil, nil, nil
+	}
+	data, ok := configMap.Data[NodePoolCIDRsConfigMapKey]
+	if !ok {
+		return nil, nil, fmt.Errorf("node pool CIDRs ConfigMap %s/%s does not contain the key %s", namespace, name, NodePoolCIDRsConfigMapKey)
+	}
+	nodePoolCIDRs, err := ParseNodePoolCIDRs([]byte(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	ref := &v1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Namespace:  configMap.Namespace,
+		Name:       configMap.Name,
+		UID:        configMap.UID,
+	}
+	return nodePoolCIDRs, ref, nil
+}
+
+// nodePoolCIDRSets holds the cidr sets of a node pool CIDR.
+type nodePoolCIDRSets struct {
+	name     string
+	selector labels.Selector
+	// cidrSets are mapped to clusterCIDRs by index
+	clusterCIDRs []*net.IPNet
+	cidrSets     []*cidrset.CidrSet
+}
+
+// newNodePoolCIDRSets validates the node pool CIDRs and creates their cidr sets. Each node pool
+// CIDR must have the same IP families as clusterCIDRs and must not overlap with clusterCIDRs or
+// any other node pool CIDR. maskSizes are the node cidr mask sizes mapped to clusterCIDRs by index.
+func newNodePoolCIDRSets(nodePoolCIDRs []NodePoolCIDR, clusterCIDRs []*net.IPNet, maskSizes []int) ([]*nodePoolCIDRSets, error) {
+	allCIDRs := append([]*net.IPNet{}, clusterCIDRs...)
+	names := make(map[string]struct{})
+	pools := make([]*nodePoolCIDRSets, 0, len(nodePoolCIDRs))
+	for _, nodePoolCIDR := range nodePoolCIDRs {
+		if errs := validation.IsDNS1123Subdomain(nodePoolCIDR.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid node pool CIDR name %q: %s", nodePoolCIDR.Name, strings.Join(errs, ", "))
+		}
+		if nodePoolCIDR.Name == DefaultNodePoolCIDRName {
+			return nil, fmt.Errorf("node pool CIDR name %s is reserved", DefaultNodePoolCIDRName)
+		}
+		if _, found := names[nodePoolCIDR.Name]; found {
+			return nil, fmt.Errorf("duplicated node pool CIDR name %s", nodePoolCIDR.Name)
+		}
+		names[nodePoolCIDR.Name] = struct{}{}
+
+		selector := labels.Everything()
+		if nodePoolCIDR.NodeSelector != nil {
+			var err error
+			selector, err = metav1.LabelSelectorAsSelector(nodePoolCIDR.NodeSelector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid node selector of node pool CIDR %s: %w", nodePoolCIDR.Name, err)
+			}
+		}
+
+		cidrs, err := netutils.ParseCIDRs(nodePoolCIDR.ClusterCIDRs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster CIDRs of node pool CIDR %s: %w", nodePoolCIDR.Name, err)
+		}
+		if len(cidrs) != len(clusterCIDRs) {
+			return nil, fmt.Errorf("node pool CIDR %s has %d cluster CIDRs, expected %d", nodePoolCIDR.Name, len(cidrs), len(clusterCIDRs))
+		}
+
+		cidrSets := make([]*cidrset.CidrSet, len(cidrs))
+		for idx, cidr := range cidrs {
+			if netutils.IsIPv6CIDR(cidr) != netutils.IsIPv6CIDR(clusterCIDRs[idx]) {
+				return nil, fmt.Errorf("cluster CIDR %v at index %d of node pool CIDR %s has a different IP family than the cluster CIDR %v", cidr, idx, nodePoolCIDR.Name, clusterCIDRs[idx])
+			}
+			for _, existing := range allCIDRs {
+				if cidrsOverlap(cidr, existing) {
+					return nil, fmt.Errorf("cluster CIDR %v of node pool CIDR %s overlaps with %v", cidr, nodePoolCIDR.Name, existing)
+				}
+			}
+			allCIDRs = append(allCIDRs, cidr)
+
+			cidrSet, err := cidrset.NewCIDRSet(cidr, maskSizes[idx])
+			if err != nil {
+				return nil, fmt.Errorf("failed to create cidr set for node pool CIDR %s: %w", nodePoolCIDR.Name, err)
+			}
+			cidrSets[idx] = cidrSet
+		}
+
+		pools = append(pools, &nodePoolCIDRSets{
+			name:         nodePoolCIDR.Name,
+			selector:     selector,
+			clusterCIDRs: cidrs,
+			cidrSets:     cidrSets,
+		})
+	}
+	return pools, nil
+}
+
+// validateNodePoolMaskSizes validates that the mask size of each cluster CIDR of the node pool CIDRs
+// is less than or equal to the node cidr mask size mapped to it by index.
+func validateNodePoolMaskSizes(pools []*nodePoolCIDRSets, maskSizes []int) error {
+	for _, pool := range pools {
+		for idx, cidr := range pool.clusterCIDRs {
+			if maskSize, _ := cidr.Mask.Size(); maskSize > maskSizes[idx] {
+				return fmt.Errorf("mask size of cluster CIDR %v of node pool CIDR %s must be less than or equal to the node cidr mask size %d", cidr, pool.name, maskSizes[idx])
+			}
+		}
+	}
+	return nil
+}
+
+// withDefaultNodePoolCIDRSets returns the node pool CIDRs followed by the default one made
+// of the cluster CIDRs.
+func withDefaultNodePoolCIDRSets(pools []*nodePoolCIDRSets, clusterCIDRs []*net.IPNet, cidrSets []*cidrset.CidrSet) []*nodePoolCIDRSets {
+	return append(append([]*nodePoolCIDRSets{}, pools...), &nodePoolCIDRSets{
+		name:         DefaultNodePoolCIDRName,
+		selector:     labels.Everything(),
+		clusterCIDRs: clusterCIDRs,
+		cidrSets:     cidrSets,
+	})
+}
+
+// cidrsOverlap returns true if one of the two cidrs contains the other.
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP.Mask(a.Mask)) || b.Contains(a.IP.Mask(b.Mask))
+}
+
+// cidrSetsForNode returns the cidr sets of the first node pool CIDR whose selector matches
+// the node, or defaultCIDRSets if none matches.
+func cidrSetsForNode(pools []*nodePoolCIDRSets, defaultCIDRSets []*cidrset.CidrSet, node *v1.Node) []*cidrset.CidrSet {
+	for _, pool := range pools {
+		if pool.selector.Matches(labels.Set(node.Labels)) {
+			klog.V(4).Infof("Node %s matches node pool CIDR %s", node.Name, pool.name)
+			return pool.cidrSets
+		}
+	}
+	return defaultCIDRSets
+}
+
+// cidrSetsForPodCIDR returns the cidr sets of the node pool CIDR containing the pod cidr, or
+// defaultCIDRSets if none contains it. It is used to occupy and release existing allocations
+// regardless of the current labels on the node.
+func cidrSetsForPodCIDR(pools []*nodePoolCIDRSets, defaultCIDRSets []*cidrset.CidrSet, podCIDR *net.IPNet) []*cidrset.CidrSet {
+	for _, pool := range pools {
+		for _, clusterCIDR := range pool.clusterCIDRs {
+			if clusterCIDR.Contains(podCIDR.IP) {
+				return pool.cidrSets
+			}
+		}
+	}
+	return defaultCIDRSets
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam/test"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/controller/testutil"
+)
+
+func TestParseNodePoolCIDRs(t *testing.T) {
+	data := `
+- name: routable
+  nodeSelector:
+    matchLabels:
+      agentpool: pool1
+  clusterCIDRs:
+  - 10.10.0.0/16
+`
+	nodePoolCIDRs, err := ParseNodePoolCIDRs([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []NodePoolCIDR{
+		{
+			Name: "routable",
+			NodeSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"agentpool": "pool1"},
+			},
+			ClusterCIDRs: []string{"10.10.0.0/16"},
+		},
+	}, nodePoolCIDRs)
+
+	_, err = ParseNodePoolCIDRs([]byte(`- name: routable
+  unknownField: true`))
+	assert.Error(t, err)
+}
+
+func TestNewNodePoolCIDRSets(t *testing.T) {
+	clusterCIDRs := []*net.IPNet{test.MustParseCIDR("10.244.0.0/16")}
+
+	for _, tc := range []struct {
+		description   string
+		nodePoolCIDRs []NodePoolCIDR
+		expectedErr   bool
+	}{
+		{
+			description: "should create the cidr sets of the node pool CIDRs",
+			nodePoolCIDRs: []NodePoolCIDR{
+				{Name: "pool1", ClusterCIDRs: []string{"10.10.0.0/16"}},
+				{Name: "pool2", ClusterCIDRs: []string{"10.11.0.0/16"}},
+			},
+		},
+		{
+			description:   "should refuse empty names",
+			nodePoolCIDRs: []NodePoolCIDR{{ClusterCIDRs: []string{"10.10.0.0/16"}}},
+			expectedErr:   true,
+		},
+		{
+			description: "should refuse duplicated names",
+			nodePoolCIDRs: []NodePoolCIDR{
+				{Name: "pool1", ClusterCIDRs: []string{"10.10.0.0/16"}},
+				{Name: "pool1", ClusterCIDRs: []string{"10.11.0.0/16"}},
+			},
+			expectedErr: true,
+		},
+		{
+			description:   "should refuse overlaps with the cluster CIDRs",
+			nodePoolCIDRs: []NodePoolCIDR{{Name: "pool1", ClusterCIDRs: []string{"10.244.16.0/20"}}},
+			expectedErr:   true,
+		},
+		{
+			description: "should refuse overlaps between node pool CIDRs",
+			nodePoolCIDRs: []NodePoolCIDR{
+				{Name: "pool1", ClusterCIDRs: []string{"10.10.0.0/16"}},
+				{Name: "pool2", ClusterCIDRs: []string{"10.0.0.0/12"}},
+			},
+			expectedErr: true,
+		},
+		{
+			description:   "should refuse different IP families",
+			nodePoolCIDRs: []NodePoolCIDR{{Name: "pool1", ClusterCIDRs: []string{"fd00::/48"}}},
+			expectedErr:   true,
+		},
+		{
+			description:   "should refuse different number of cluster CIDRs",
+			nodePoolCIDRs: []NodePoolCIDR{{Name: "pool1", ClusterCIDRs: []string{"10.10.0.0/16", "fd00::/48"}}},
+			expectedErr:   true,
+		},
+		{
+			description: "should refuse invalid node selectors",
+			nodePoolCIDRs: []NodePoolCIDR{{
+				Name:         "pool1",
+				NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"agentpool": "invalid value"}},
+				ClusterCIDRs: []string{"10.10.0.0/16"},
+			}},
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			pools, err := newNodePoolCIDRSets(tc.nodePoolCIDRs, clusterCIDRs, []int{24})
+			assert.Equal(t, tc.expectedErr, err != nil, err)
+			if !tc.expectedErr {
+				assert.Equal(t, len(tc.nodePoolCIDRs), len(pools))
+			}
+		})
+	}
+}
+
+func TestRangeAllocatorNodePoolCIDRs(t *testing.T) {
+	fakeNodeHandler := &testutil.FakeNodeHandler{
+		Existing: []*v1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node0",
+					Labels: map[string]string{"agentpool": "pool1"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node1",
+					Labels: map[string]string{"agentpool": "pool2"},
+				},
+			},
+		},
+		Clientset: fake.NewSimpleClientset(),
+	}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs:      []*net.IPNet{test.MustParseCIDR("10.244.0.0/16")},
+		NodeCIDRMaskSizes: []int{24},
+		NodePoolCIDRs: []NodePoolCIDR{
+			{
+				Name: "routable",
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"agentpool": "pool1"},
+				},
+				ClusterCIDRs: []string{"10.10.0.0/16"},
+			},
+		},
+	}
+	// the existing allocation from the node pool CIDR should be occupied on restart
+	nodeList := &v1.NodeList{
+		Items: []v1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+				Spec: v1.NodeSpec{
+					PodCIDR:  "10.10.0.0/24",
+					PodCIDRs: []string{"10.10.0.0/24"},
+				},
+			},
+		},
+	}
+
+	allocator, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, nodeList)
+	assert.NoError(t, err)
+	rangeAllocator, ok := allocator.(*rangeAllocator)
+	assert.True(t, ok)
+	rangeAllocator.nodesSynced = alwaysReady
+	rangeAllocator.recorder = testutil.NewFakeRecorder()
+	go allocator.Run(wait.NeverStop)
+
+	for _, node := range fakeNodeHandler.Existing {
+		assert.NoError(t, allocator.AllocateOrOccupyCIDR(node))
+	}
+	assert.NoError(t, waitForUpdatedNodeWithTimeout(fakeNodeHandler, 2, wait.ForeverTestTimeout))
+
+	podCIDRs := map[string][]string{}
+	for _, node := range fakeNodeHandler.GetUpdatedNodesCopy() {
+		podCIDRs[node.Name] = node.Spec.PodCIDRs
+	}
+	assert.Equal(t, []string{"10.10.1.0/24"}, podCIDRs["node0"])
+	assert.Equal(t, []string{"10.244.0.0/24"}, podCIDRs["node1"])
+
+	// releasing the existing allocation should free it in the node pool CIDR
+	assert.NoError(t, allocator.ReleaseCIDR(&nodeList.Items[0]))
+	podCIDR, err := rangeAllocator.nodePoolCIDRSets[0].cidrSets[0].AllocateNextWithNodeMaskSize(24)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.10.0.0/24", podCIDR.String())
+}
+
+func TestRangeAllocatorNodePoolCIDRsMaskSize(t *testing.T) {
+	fakeNodeHandler := &testutil.FakeNodeHandler{Clientset: fake.NewSimpleClientset()}
+	allocatorParams := CIDRAllocatorParams{
+		ClusterCIDRs:      []*net.IPNet{test.MustParseCIDR("10.244.0.0/16")},
+		NodeCIDRMaskSizes: []int{24},
+		NodePoolCIDRs: []NodePoolCIDR{
+			{Name: "small", ClusterCIDRs: []string{"10.10.0.0/26"}},
+		},
+	}
+
+	_, err := NewCIDRRangeAllocator(fakeNodeHandler, getFakeNodeInformer(fakeNodeHandler), allocatorParams, nil)
+	assert.Error(t, err)
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	informers "k8s.io/client-go/informers/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -47,12 +48,19 @@ type nodeReservedCIDRs struct {
 	nodeName       string
 }
 
+var _ CIDRCapacityChecker = (*rangeAllocator)(nil)
+
 type rangeAllocator struct {
 	client clientset.Interface
 	// cluster cidrs as passed in during controller creation
 	clusterCIDRs []*net.IPNet
 	// for each entry in clusterCIDRs we maintain a list of what is used and what is not
 	cidrSets []*cidrset.CidrSet
+	// nodePoolCIDRSets are the cidr sets of the node pools, the first one matching the node
+	// is used before falling back to cidrSets
+	nodePoolCIDRSets []*nodePoolCIDRSets
+	// capacityMonitor exports the free capacity of the cidr sets and warns when it is low
+	capacityMonitor *cidrCapacityMonitor
 	// nodeLister is able to list/get nodes and is populated by the shared informer passed to controller
 	nodeLister corelisters.NodeLister
 	// nodesSynced returns true if the node shared informer has been synced at least once.
@@ -93,10 +101,21 @@ func NewCIDRRangeAllocator(client clientset.Interface, nodeInformer informers.No
 		cidrSets[idx] = cidrSet
 	}
 
+	nodePoolCIDRSets, err := newNodePoolCIDRSets(allocatorParams.NodePoolCIDRs, allocatorParams.ClusterCIDRs, allocatorParams.NodeCIDRMaskSizes)
+	if err != nil {
+		return nil, err
+	}
+	// for each node pool cidr, node mask size must be <= cidr mask
+	if err := validateNodePoolMaskSizes(nodePoolCIDRSets, allocatorParams.NodeCIDRMaskSizes); err != nil {
+		return nil, err
+	}
+
 	ra := &rangeAllocator{
 		client:                client,
 		clusterCIDRs:          allocatorParams.ClusterCIDRs,
 		cidrSets:              cidrSets,
+		nodePoolCIDRSets:      nodePoolCIDRSets,
+		capacityMonitor:       newCIDRCapacityMonitor(allocatorParams.CIDRCapacityWarningThreshold, allocatorParams.NodePoolCIDRsConfigMap),
 		nodeLister:            nodeInformer.Lister(),
 		nodesSynced:           nodeInformer.Informer().HasSynced,
 		nodeCIDRUpdateChannel: make(chan nodeReservedCIDRs, cidrUpdateQueueSize),
@@ -106,12 +125,18 @@ func NewCIDRRangeAllocator(client clientset.Interface, nodeInformer informers.No
 
 	if allocatorParams.ServiceCIDR != nil {
 		filterOutServiceRange(ra.clusterCIDRs, ra.cidrSets, allocatorParams.ServiceCIDR)
+		for _, pool := range ra.nodePoolCIDRSets {
+			filterOutServiceRange(pool.clusterCIDRs, pool.cidrSets, allocatorParams.ServiceCIDR)
+		}
 	} else {
 		klog.V(0).Info("No Service CIDR provided. Skipping filtering out service addresses.")
 	}
 
 	if allocatorParams.SecondaryServiceCIDR != nil {
 		filterOutServiceRange(ra.clusterCIDRs, ra.cidrSets, allocatorParams.SecondaryServiceCIDR)
+		for _, pool := range ra.nodePoolCIDRSets {
+			filterOutServiceRange(pool.clusterCIDRs, pool.cidrSets, allocatorParams.SecondaryServiceCIDR)
+		}
 	} else {
 		klog.V(0).Info("No Secondary Service CIDR provided. Skipping filtering out secondary service addresses.")
 	}
@@ -179,6 +204,7 @@ func (r *rangeAllocator) Run(stopCh <-chan struct{}) {
 	for i := 0; i < cidrUpdateWorkers; i++ {
 		go r.worker(stopCh)
 	}
+	go wait.Until(r.monitorCIDRCapacity, cidrCapacityCheckPeriod, stopCh)
 
 	<-stopCh
 }
@@ -223,19 +249,23 @@ func (r *rangeAllocator) occupyCIDRs(node *v1.Node) error {
 	if len(node.Spec.PodCIDRs) == 0 {
 		return nil
 	}
+	var cidrSets []*cidrset.CidrSet
 	for idx, cidr := range node.Spec.PodCIDRs {
 		_, podCIDR, err := net.ParseCIDR(cidr)
 		if err != nil {
 			return fmt.Errorf("failed to parse node %s, CIDR %s", node.Name, node.Spec.PodCIDR)
 		}
+		if cidrSets == nil {
+			cidrSets = cidrSetsForPodCIDR(r.nodePoolCIDRSets, r.cidrSets, podCIDR)
+		}
 		// If node has a pre allocate cidr that does not exist in our cidrs.
 		// This will happen if cluster went from dualstack(multi cidrs) to non-dualstack
 		// then we have now way of locking it
-		if idx >= len(r.cidrSets) {
+		if idx >= len(cidrSets) {
 			return fmt.Errorf("node:%s has an allocated cidr: %v at index:%v that does not exist in cluster cidrs configuration", node.Name, cidr, idx)
 		}
 
-		if err := r.cidrSets[idx].Occupy(podCIDR); err != nil {
+		if err := cidrSets[idx].Occupy(podCIDR); err != nil {
 			return fmt.Errorf("failed to mark cidr[%v] at idx [%v] as occupied for node: %v: %w", podCIDR, idx, node.Name, err)
 		}
 	}
@@ -259,12 +289,14 @@ func (r *rangeAllocator) AllocateOrOccupyCIDR(node *v1.Node) error {
 	}
 
 	// allocate pod cidrs
-	allocatedCIDRs, err := r.allocatePodCIDRs()
+	cidrSets := cidrSetsForNode(r.nodePoolCIDRSets, r.cidrSets, node)
+	allocatedCIDRs, err := r.allocatePodCIDRs(cidrSets)
 	if err != nil {
 		r.removeNodeFromProcessing(node.Name)
 		nodeutil.RecordNodeStatusChange(r.recorder, node, "CIDRNotAvailable")
 		return fmt.Errorf("failed to allocate cidr for node %s: %w", node.Name, err)
 	}
+	r.capacityMonitor.warnNode(r.recorder, node, cidrSets)
 	allocated := nodeReservedCIDRs{
 		nodeName:       node.Name,
 		allocatedCIDRs: allocatedCIDRs,
@@ -282,27 +314,47 @@ func (r *rangeAllocator) ReleaseCIDR(node *v1.Node) error {
 		return nil
 	}
 
+	var cidrSets []*cidrset.CidrSet
 	for idx, cidr := range node.Spec.PodCIDRs {
 		_, podCIDR, err := net.ParseCIDR(cidr)
 		if err != nil {
 			return fmt.Errorf("failed to parse CIDR %s on Node %v: %w", cidr, node.Name, err)
 		}
+		if cidrSets == nil {
+			cidrSets = cidrSetsForPodCIDR(r.nodePoolCIDRSets, r.cidrSets, podCIDR)
+		}
 
 		// If node has a pre allocate cidr that does not exist in our cidrs.
 		// This will happen if cluster went from dualstack(multi cidrs) to non-dualstack
 		// then we have now way of locking it
-		if idx >= len(r.cidrSets) {
+		if idx >= len(cidrSets) {
 			return fmt.Errorf("node:%s has an allocated cidr: %v at index:%v that does not exist in cluster cidrs configuration", node.Name, cidr, idx)
 		}
 
 		klog.V(4).Infof("release CIDR %s for node:%v", cidr, node.Name)
-		if err = r.cidrSets[idx].Release(podCIDR); err != nil {
+		if err = cidrSets[idx].Release(podCIDR); err != nil {
 			return fmt.Errorf("error when releasing CIDR %v: %w", cidr, err)
 		}
 	}
 	return nil
 }
 
+// monitorCIDRCapacity refreshes the free capacity metrics of the cidr sets and emits events
+// when the capacity is low.
+func (r *rangeAllocator) monitorCIDRCapacity() {
+	nodeMaskSizes := make([][]int, len(r.cidrSets))
+	for idx, cidrSet := range r.cidrSets {
+		nodeMaskSizes[idx] = []int{cidrSet.NodeMaskSize()}
+	}
+	r.capacityMonitor.check(r.recorder, withDefaultNodePoolCIDRSets(r.nodePoolCIDRSets, r.clusterCIDRs, r.cidrSets), nodeMaskSizes)
+}
+
+// CheckCIDRCapacity checks whether the planned nodes fit in the current CIDRs without
+// allocating anything.
+func (r *rangeAllocator) CheckCIDRCapacity(plans []CIDRCapacityPlan) ([]CIDRCapacityCheckResult, error) {
+	return checkCIDRCapacity(withDefaultNodePoolCIDRSets(r.nodePoolCIDRSets, r.clusterCIDRs, r.cidrSets), plans)
+}
+
 // Marks all CIDRs with subNetMaskSize that belongs to serviceCIDR as used across all cidrs
 // so that they won't be assignable.
 func filterOutServiceRange(clusterCIDRs []*net.IPNet, cidrSets []*cidrset.CidrSet, serviceCIDR *net.IPNet) {
@@ -324,13 +376,14 @@ func filterOutServiceRange(clusterCIDRs []*net.IPNet, cidrSets []*cidrset.CidrSe
 	}
 }
 
-func (r *rangeAllocator) allocatePodCIDRs() ([]*net.IPNet, error) {
-	allocatedCIDRs := make([]*net.IPNet, len(r.cidrSets))
-	for idx := range r.cidrSets {
-		podCIDR, err := r.cidrSets[idx].AllocateNext()
+// allocatePodCIDRs allocates one pod cidr from each of the given cidr sets.
+func (r *rangeAllocator) allocatePodCIDRs(cidrSets []*cidrset.CidrSet) ([]*net.IPNet, error) {
+	allocatedCIDRs := make([]*net.IPNet, len(cidrSets))
+	for idx := range cidrSets {
+		podCIDR, err := cidrSets[idx].AllocateNext()
 		if err != nil {
 			for i := 0; i < idx; i++ {
-				if releaseErr := r.cidrSets[idx].Release(allocatedCIDRs[i]); releaseErr != nil {
+				if releaseErr := cidrSets[i].Release(allocatedCIDRs[i]); releaseErr != nil {
 					// continue releasing the rest
 					klog.Errorf("Error releasing allocated CIDR at index %d for node: %v", i, releaseErr)
 				}
@@ -365,7 +418,7 @@ func (r *rangeAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) (dataToRe
 
 	// this happens when node patch fails, we release the CIDRs allocated and retry
 	if data.allocatedCIDRs == nil {
-		allocatedCIDRs, err := r.allocatePodCIDRs()
+		allocatedCIDRs, err := r.allocatePodCIDRs(cidrSetsForNode(r.nodePoolCIDRSets, r.cidrSets, node))
 		if err != nil {
 			nodeutil.RecordNodeStatusChange(r.recorder, node, "CIDRNotAvailable")
 			return data, fmt.Errorf("failed to allocate cidr for node %s: %w", data.nodeName, err)
@@ -373,6 +426,10 @@ func (r *rangeAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) (dataToRe
 		data.allocatedCIDRs = allocatedCIDRs
 	}
 	cidrsString := cidrsAsString(data.allocatedCIDRs)
+	cidrSets := r.cidrSets
+	if len(data.allocatedCIDRs) > 0 {
+		cidrSets = cidrSetsForPodCIDR(r.nodePoolCIDRSets, r.cidrSets, data.allocatedCIDRs[0])
+	}
 
 	// if cidr list matches the proposed.
 	// then we possibly updated this node
@@ -395,7 +452,7 @@ func (r *rangeAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) (dataToRe
 	if len(node.Spec.PodCIDRs) != 0 {
 		klog.Errorf("Node %v already has a CIDR allocated %v. Releasing the new one.", node.Name, node.Spec.PodCIDRs)
 		for idx, cidr := range data.allocatedCIDRs {
-			if releaseErr := r.cidrSets[idx].Release(cidr); releaseErr != nil {
+			if releaseErr := cidrSets[idx].Release(cidr); releaseErr != nil {
 				klog.Errorf("Error when releasing CIDR idx:%v value: %v err:%v", idx, cidr, releaseErr)
 			}
 		}
@@ -417,7 +474,7 @@ func (r *rangeAllocator) updateCIDRsAllocation(data nodeReservedCIDRs) (dataToRe
 	if !apierrors.IsServerTimeout(err) {
 		klog.Errorf("CIDR assignment for node %v failed: %v. Releasing allocated CIDR", node.Name, err)
 		for idx, cidr := range data.allocatedCIDRs {
-			if releaseErr := r.cidrSets[idx].Release(cidr); releaseErr != nil {
+			if releaseErr := cidrSets[idx].Release(cidr); releaseErr != nil {
 				klog.Errorf("Error releasing allocated CIDR for node %v: %v", node.Name, releaseErr)
 			}
 		}
@@ -17,10 +17,13 @@ limitations under the License.
 package nodeipam
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"sync"
 
+	v1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
@@ -37,6 +40,9 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/nodeipam/ipam"
 )
 
+// cidrCapacityPath is the path of the debugging endpoint checking the CIDR capacity.
+const cidrCapacityPath = "/cidr-capacity"
+
 var (
 	metricsLock        sync.Mutex
 	rateLimiterMetrics = make(map[string]*rateLimiterMetric)
@@ -119,6 +125,9 @@ func NewNodeIpamController(
 	serviceCIDR *net.IPNet,
 	secondaryServiceCIDR *net.IPNet,
 	nodeCIDRMaskSizes []int,
+	nodePoolCIDRs []ipam.NodePoolCIDR,
+	nodePoolCIDRsConfigMap *v1.ObjectReference,
+	cidrCapacityWarningThreshold float64,
 	allocatorType ipam.CIDRAllocatorType) (*Controller, error) {
 
 	if kubeClient == nil {
@@ -175,6 +184,10 @@ func NewNodeIpamController(
 		ServiceCIDR:          ic.serviceCIDR,
 		SecondaryServiceCIDR: ic.secondaryServiceCIDR,
 		NodeCIDRMaskSizes:    nodeCIDRMaskSizes,
+		NodePoolCIDRs:        nodePoolCIDRs,
+
+		NodePoolCIDRsConfigMap:       nodePoolCIDRsConfigMap,
+		CIDRCapacityWarningThreshold: cidrCapacityWarningThreshold,
 	}
 
 	ic.cidrAllocator, err = ipam.New(kubeClient, cloud, nodeInformer, ic.allocatorType, allocatorParams)
@@ -188,6 +201,45 @@ func NewNodeIpamController(
 	return ic, nil
 }
 
+// CheckCIDRCapacity checks whether the planned nodes fit in the current CIDRs without
+// allocating anything.
+func (nc *Controller) CheckCIDRCapacity(plans []ipam.CIDRCapacityPlan) ([]ipam.CIDRCapacityCheckResult, error) {
+	checker, ok := nc.cidrAllocator.(ipam.CIDRCapacityChecker)
+	if !ok {
+		return nil, fmt.Errorf("CIDR allocator %s does not support capacity checks", nc.allocatorType)
+	}
+	return checker.CheckCIDRCapacity(plans)
+}
+
+// ServeHTTP serves the debugging endpoints of the controller. POST /cidr-capacity takes a JSON
+// list of ipam.CIDRCapacityPlan and returns the ipam.CIDRCapacityCheckResult of CheckCIDRCapacity.
+func (nc *Controller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != cidrCapacityPath {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, fmt.Sprintf("method %s is not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var plans []ipam.CIDRCapacityPlan
+	if err := json.NewDecoder(r.Body).Decode(&plans); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode the CIDR capacity plans: %v", err), http.StatusBadRequest)
+		return
+	}
+	results, err := nc.CheckCIDRCapacity(plans)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		klog.Errorf("failed to write the CIDR capacity check results: %v", err)
+	}
+}
+
 // Run starts an asynchronous loop that monitors the status of cluster nodes.
 func (nc *Controller) Run(stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
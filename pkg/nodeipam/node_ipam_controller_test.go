@@ -17,14 +17,19 @@ limitations under the License.
 package nodeipam
 
 import (
+	"encoding/json"
 	"errors"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
@@ -55,7 +60,7 @@ func newTestNodeIpamController(clusterCIDR []*net.IPNet, serviceCIDR *net.IPNet,
 	fakeAZ := &providerazure.Cloud{}
 	return NewNodeIpamController(
 		fakeNodeInformer, fakeAZ, clientSet,
-		clusterCIDR, serviceCIDR, secondaryServiceCIDR, nodeCIDRMaskSizes, allocatorType,
+		clusterCIDR, serviceCIDR, secondaryServiceCIDR, nodeCIDRMaskSizes, nil, nil, 0, allocatorType,
 	)
 }
 
@@ -112,3 +117,63 @@ func TestNewNodeIpamControllerWithCIDRMasks(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeIpamControllerCIDRCapacityEndpoint(t *testing.T) {
+	clusterCIDRs, _ := netutils.ParseCIDRs([]string{"10.0.0.0/22"})
+	nc, err := newTestNodeIpamController(clusterCIDRs, nil, nil, []int{24}, ipam.RangeAllocatorType)
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		desc, method, path, body string
+		expectedStatus           int
+		expectedResults          []ipam.CIDRCapacityCheckResult
+	}{
+		{
+			desc:           "the planned nodes should be checked against the cluster CIDR",
+			method:         http.MethodPost,
+			path:           "/cidr-capacity",
+			body:           `[{"nodePoolCIDR": "default", "nodeCount": 5}]`,
+			expectedStatus: http.StatusOK,
+			expectedResults: []ipam.CIDRCapacityCheckResult{
+				{NodePoolCIDR: ipam.DefaultNodePoolCIDRName, ClusterCIDR: "10.0.0.0/22", NodeCIDRMaskSize: 24, Free: 4, Required: 5},
+			},
+		},
+		{
+			desc:           "an unknown node pool CIDR should be rejected",
+			method:         http.MethodPost,
+			path:           "/cidr-capacity",
+			body:           `[{"nodePoolCIDR": "unknown", "nodeCount": 1}]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "an invalid body should be rejected",
+			method:         http.MethodPost,
+			path:           "/cidr-capacity",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "only POST should be allowed",
+			method:         http.MethodGet,
+			path:           "/cidr-capacity",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			desc:           "unknown paths should not be found",
+			method:         http.MethodPost,
+			path:           "/unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			nc.ServeHTTP(recorder, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedResults != nil {
+				var results []ipam.CIDRCapacityCheckResult
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &results))
+				assert.Equal(t, tc.expectedResults, results)
+			}
+		})
+	}
+}
@@ -123,3 +123,18 @@ func (mr *NodeProviderMockRecorder) NodeAddresses(arg0, arg1 interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeAddresses", reflect.TypeOf((*NodeProvider)(nil).NodeAddresses), arg0, arg1)
 }
+
+// GetPlacementLabels mocks base method.
+func (m *NodeProvider) GetPlacementLabels(arg0 context.Context, arg1 types.NodeName) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlacementLabels", arg0, arg1)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlacementLabels indicates an expected call of GetPlacementLabels.
+func (mr *NodeProviderMockRecorder) GetPlacementLabels(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlacementLabels", reflect.TypeOf((*NodeProvider)(nil).GetPlacementLabels), arg0, arg1)
+}
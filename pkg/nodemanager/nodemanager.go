@@ -61,6 +61,16 @@ type NodeProvider interface {
 	GetZone(ctx context.Context, name types.NodeName) (cloudprovider.Zone, error)
 	// GetPlatformSubFaultDomain returns the PlatformSubFaultDomain from IMDS if set.
 	GetPlatformSubFaultDomain() (string, error)
+	// GetPlacementLabels returns the labels describing the placement of the instance, e.g. its
+	// fault domain, update domain, proximity placement group and capacity reservation group.
+	GetPlacementLabels(ctx context.Context, name types.NodeName) (map[string]string, error)
+}
+
+// NodeInfoSourceProvider is implemented by node providers that serve node information
+// from more than one source, e.g. IMDS with a fallback to ARM.
+type NodeInfoSourceProvider interface {
+	// GetNodeInfoSource returns the source which served the last successful request.
+	GetNodeInfoSource() string
 }
 
 // labelReconcile holds information about a label to reconcile and how to reconcile it.
@@ -212,6 +222,74 @@ func (cnc *CloudNodeController) UpdateNodeStatus(ctx context.Context) {
 	if err != nil {
 		klog.Errorf("Error reconciling node labels for node %q, err: %v", node.Name, err)
 	}
+
+	err = cnc.reconcilePlacementLabels(ctx, node)
+	if err != nil {
+		klog.Errorf("Error reconciling placement labels for node %q, err: %v", node.Name, err)
+	}
+
+	err = cnc.reconcileNodeInfoSource(ctx, node)
+	if err != nil {
+		klog.Errorf("Error reconciling node info source annotation for node %q, err: %v", node.Name, err)
+	}
+}
+
+// reconcileNodeInfoSource records the source of the node information on the node annotation
+// if the node provider is able to report it.
+func (cnc *CloudNodeController) reconcileNodeInfoSource(ctx context.Context, node *v1.Node) error {
+	sourceProvider, ok := cnc.nodeProvider.(NodeInfoSourceProvider)
+	if !ok {
+		return nil
+	}
+	source := sourceProvider.GetNodeInfoSource()
+	if source == "" || node.Annotations[consts.NodeAnnotationNodeInfoSource] == source {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				consts.NodeAnnotationNodeInfoSource: source,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = cnc.kubeClient.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch annotation %s for node %q: %w", consts.NodeAnnotationNodeInfoSource, node.Name, err)
+	}
+	return nil
+}
+
+// reconcilePlacementLabels updates the placement labels of the initialized node, e.g. after the
+// VM is moved to another fault domain or proximity placement group.
+func (cnc *CloudNodeController) reconcilePlacementLabels(ctx context.Context, node *v1.Node) error {
+	if GetCloudTaint(node.Spec.Taints) != nil {
+		// the labels are added when the node is initialized
+		return nil
+	}
+
+	placementLabels, err := cnc.nodeProvider.GetPlacementLabels(ctx, types.NodeName(node.Name))
+	if err != nil {
+		return fmt.Errorf("failed to get placement labels from cloud provider: %w", err)
+	}
+	labelsToUpdate := map[string]string{}
+	for key, value := range placementLabels {
+		if current, ok := node.Labels[key]; !ok || current != value {
+			labelsToUpdate[key] = value
+		}
+	}
+	if len(labelsToUpdate) == 0 {
+		return nil
+	}
+
+	klog.V(2).Infof("Updating placement labels %v of node %s", labelsToUpdate, node.Name)
+	if !cloudnodeutil.AddOrUpdateLabelsOnNode(cnc.kubeClient, labelsToUpdate, node) {
+		return fmt.Errorf("failed update labels for node %+v", node)
+	}
+	return nil
 }
 
 // reconcileNodeLabels reconciles node labels transitioning from beta to GA
@@ -504,9 +582,36 @@ func (cnc *CloudNodeController) getNodeModifiersFromCloudProvider(ctx context.Co
 		nodeModifiers = append(nodeModifiers, addCloudNodeLabel(consts.LabelPlatformSubFaultDomain, platformSubFaultDomain))
 	}
 
+	// placement labels are informational, so the node is initialized without them on failure,
+	// and they're added when the node status is updated
+	placementLabels, err := cnc.nodeProvider.GetPlacementLabels(ctx, types.NodeName(node.Name))
+	if err != nil {
+		klog.Warningf("failed to get placement labels of node %s from cloud provider, skip them: %v", node.Name, err)
+	}
+	for key, value := range placementLabels {
+		nodeModifiers = append(nodeModifiers, addCloudNodeLabel(key, value))
+	}
+
+	if sourceProvider, ok := cnc.nodeProvider.(NodeInfoSourceProvider); ok {
+		if source := sourceProvider.GetNodeInfoSource(); source != "" {
+			nodeModifiers = append(nodeModifiers, addCloudNodeAnnotation(consts.NodeAnnotationNodeInfoSource, source))
+		}
+	}
+
 	return nodeModifiers, nil
 }
 
+// addCloudNodeAnnotation creates a nodeModifier that adds an annotation to a node.
+func addCloudNodeAnnotation(key, value string) func(*v1.Node) {
+	klog.V(2).Infof("Adding node annotation from cloud provider: %s=%s", key, value)
+	return func(node *v1.Node) {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[key] = value
+	}
+}
+
 // addCloudNodeLabel creates a nodeModifier that adds a label to a node.
 func addCloudNodeLabel(key, value string) func(*v1.Node) {
 	klog.V(2).Infof("Adding node label from cloud provider: %s=%s", key, value)
@@ -183,6 +183,10 @@ func TestNodeInitialized(t *testing.T) {
 		},
 	}, nil).AnyTimes()
 	mockNP.EXPECT().GetPlatformSubFaultDomain().Return("1", nil)
+	mockNP.EXPECT().GetPlacementLabels(gomock.Any(), types.NodeName("node0")).Return(map[string]string{
+		consts.LabelPlatformFaultDomain:     "2",
+		consts.LabelProximityPlacementGroup: "ppg",
+	}, nil)
 
 	cloudNodeController := NewCloudNodeController(
 		"node0",
@@ -199,6 +203,89 @@ func TestNodeInitialized(t *testing.T) {
 	assert.Equal(t, "node0", fnh.UpdatedNodes[0].Name, "Node was not updated")
 	assert.Equal(t, 0, len(fnh.UpdatedNodes[0].Spec.Taints), "Node Taint was not removed after cloud init")
 	assert.Equal(t, "1", fnh.UpdatedNodes[0].Labels[consts.LabelPlatformSubFaultDomain])
+	assert.Equal(t, "2", fnh.UpdatedNodes[0].Labels[consts.LabelPlatformFaultDomain])
+	assert.Equal(t, "ppg", fnh.UpdatedNodes[0].Labels[consts.LabelProximityPlacementGroup])
+}
+
+// This test checks that a node is initialized when its placement labels can't be got
+func TestNodeInitializedWithoutPlacementLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fnh := &testutil.FakeNodeHandler{
+		Existing: []*v1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "node0",
+					CreationTimestamp: metav1.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				Status: v1.NodeStatus{
+					Conditions: []v1.NodeCondition{
+						{
+							Type:               v1.NodeReady,
+							Status:             v1.ConditionUnknown,
+							LastHeartbeatTime:  metav1.Date(2015, 1, 1, 12, 0, 0, 0, time.UTC),
+							LastTransitionTime: metav1.Date(2015, 1, 1, 12, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+				Spec: v1.NodeSpec{
+					Taints: []v1.Taint{
+						{
+							Key:    cloudproviderapi.TaintExternalCloudProvider,
+							Value:  "true",
+							Effect: v1.TaintEffectNoSchedule,
+						},
+					},
+				},
+			},
+		},
+		Clientset:      fake.NewSimpleClientset(&v1.PodList{}),
+		DeleteWaitChan: make(chan struct{}),
+	}
+
+	ctx := context.TODO()
+	factory := informers.NewSharedInformerFactory(fnh, 0)
+	mockNP := mocknodeprovider.NewMockNodeProvider(ctrl)
+	mockNP.EXPECT().InstanceID(ctx, types.NodeName("node0")).Return("node0", nil)
+	mockNP.EXPECT().InstanceType(ctx, types.NodeName("node0")).Return("Standard_D2_v3", nil)
+	mockNP.EXPECT().GetZone(ctx, gomock.Any()).Return(cloudprovider.Zone{
+		Region:        "eastus",
+		FailureDomain: "1",
+	}, nil)
+	mockNP.EXPECT().NodeAddresses(ctx, types.NodeName("node0")).Return([]v1.NodeAddress{
+		{
+			Type:    v1.NodeHostName,
+			Address: "node0.cloud.internal",
+		},
+		{
+			Type:    v1.NodeInternalIP,
+			Address: "10.0.0.1",
+		},
+		{
+			Type:    v1.NodeExternalIP,
+			Address: "132.143.154.163",
+		},
+	}, nil).AnyTimes()
+	mockNP.EXPECT().GetPlatformSubFaultDomain().Return("1", nil)
+	mockNP.EXPECT().GetPlacementLabels(gomock.Any(), types.NodeName("node0")).Return(nil, errors.New("placement error"))
+
+	cloudNodeController := NewCloudNodeController(
+		"node0",
+		factory.Core().V1().Nodes(),
+		fnh,
+		mockNP,
+		time.Second,
+		false,
+		false)
+
+	cloudNodeController.AddCloudNode(ctx, fnh.Existing[0])
+
+	assert.Equal(t, 1, len(fnh.UpdatedNodes), "Node was not updated")
+	assert.Equal(t, "node0", fnh.UpdatedNodes[0].Name, "Node was not updated")
+	assert.Equal(t, 0, len(fnh.UpdatedNodes[0].Spec.Taints), "Node Taint was not removed after cloud init")
+	assert.Equal(t, "1", fnh.UpdatedNodes[0].Labels[consts.LabelPlatformSubFaultDomain])
+	assert.NotContains(t, fnh.UpdatedNodes[0].Labels, consts.LabelPlatformFaultDomain)
 }
 
 func TestUpdateCloudNode(t *testing.T) {
@@ -261,6 +348,7 @@ func TestUpdateCloudNode(t *testing.T) {
 		},
 	}, nil).AnyTimes()
 	mockNP.EXPECT().GetPlatformSubFaultDomain().Return("1", nil)
+	mockNP.EXPECT().GetPlacementLabels(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 	eventBroadcaster := record.NewBroadcaster()
 	cloudNodeController := NewCloudNodeController(
@@ -394,6 +482,7 @@ func TestZoneInitialized(t *testing.T) {
 			},
 		}, nil).AnyTimes()
 		mockNP.EXPECT().GetPlatformSubFaultDomain().Return("", nil)
+		mockNP.EXPECT().GetPlacementLabels(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 		eventBroadcaster := record.NewBroadcaster()
 		cloudNodeController := &CloudNodeController{
@@ -475,6 +564,7 @@ func TestZoneInitialized(t *testing.T) {
 			},
 		}, nil).AnyTimes()
 		mockNP.EXPECT().GetPlatformSubFaultDomain().Return("", nil)
+		mockNP.EXPECT().GetPlacementLabels(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 		eventBroadcaster := record.NewBroadcaster()
 		cloudNodeController := &CloudNodeController{
@@ -572,6 +662,7 @@ func TestAddCloudNode(t *testing.T) {
 		},
 	}, nil).AnyTimes()
 	mockNP.EXPECT().GetPlatformSubFaultDomain().Return("", nil)
+	mockNP.EXPECT().GetPlacementLabels(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 	factory := informers.NewSharedInformerFactory(fnh, 0)
 	nodeInformer := factory.Core().V1().Nodes()
@@ -658,9 +749,11 @@ func TestUpdateNodeAddresses(t *testing.T) {
 			Address: "10.0.0.1",
 		},
 	}, nil)
+	mockNP.EXPECT().GetPlacementLabels(ctx, types.NodeName("node0")).Return(map[string]string{consts.LabelPlatformFaultDomain: "1"}, nil)
 	cloudNodeController.UpdateNodeStatus(ctx)
 	updatedNodes := fnh.GetUpdatedNodesCopy()
 	assert.Equal(t, 2, len(updatedNodes[0].Status.Addresses), "Node Addresses not correctly updated")
+	assert.Equal(t, "1", updatedNodes[len(updatedNodes)-1].Labels[consts.LabelPlatformFaultDomain], "Placement labels not updated")
 }
 
 // This test checks that a node with the external cloud provider taint is cloudprovider initialized and
@@ -736,6 +829,7 @@ func TestNodeProvidedIPAddresses(t *testing.T) {
 		},
 	}, nil).AnyTimes()
 	mockNP.EXPECT().GetPlatformSubFaultDomain().Return("", nil)
+	mockNP.EXPECT().GetPlacementLabels(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 	eventBroadcaster := record.NewBroadcaster()
 	cloudNodeController := NewCloudNodeController(
@@ -1158,6 +1252,7 @@ func TestNodeProviderID(t *testing.T) {
 		},
 	}, nil).AnyTimes()
 	mockNP.EXPECT().GetPlatformSubFaultDomain().Return("", nil).AnyTimes()
+	mockNP.EXPECT().GetPlacementLabels(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	eventBroadcaster := record.NewBroadcaster()
 	cloudNodeController := &CloudNodeController{
@@ -1244,6 +1339,7 @@ func TestNodeProviderIDAlreadySet(t *testing.T) {
 		},
 	}, nil).AnyTimes()
 	mockNP.EXPECT().GetPlatformSubFaultDomain().Return("", nil).AnyTimes()
+	mockNP.EXPECT().GetPlacementLabels(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	eventBroadcaster := record.NewBroadcaster()
 	cloudNodeController := &CloudNodeController{
@@ -1508,3 +1604,79 @@ func Test_ensureNodeProvidedIPsExists(t *testing.T) {
 		})
 	}
 }
+
+type fakeNodeInfoSourceProvider struct {
+	*mocknodeprovider.NodeProvider
+	source string
+}
+
+func (p *fakeNodeInfoSourceProvider) GetNodeInfoSource() string {
+	return p.source
+}
+
+func TestReconcileNodeInfoSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tc := range []struct {
+		desc               string
+		annotations        map[string]string
+		source             string
+		expectedAnnotation string
+		expectUpdate       bool
+	}{
+		{
+			desc:               "should add the annotation if it doesn't exist",
+			source:             consts.NodeInfoSourceARM,
+			expectedAnnotation: consts.NodeInfoSourceARM,
+			expectUpdate:       true,
+		},
+		{
+			desc:               "should update the annotation if the source changes",
+			annotations:        map[string]string{consts.NodeAnnotationNodeInfoSource: consts.NodeInfoSourceARM},
+			source:             consts.NodeInfoSourceIMDS,
+			expectedAnnotation: consts.NodeInfoSourceIMDS,
+			expectUpdate:       true,
+		},
+		{
+			desc:         "should not patch the node if the source is unchanged",
+			annotations:  map[string]string{consts.NodeAnnotationNodeInfoSource: consts.NodeInfoSourceIMDS},
+			source:       consts.NodeInfoSourceIMDS,
+			expectUpdate: false,
+		},
+		{
+			desc:         "should not patch the node if the source is unknown",
+			expectUpdate: false,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "node0",
+					Annotations: tc.annotations,
+				},
+			}
+			fnh := &testutil.FakeNodeHandler{
+				Existing:  []*v1.Node{node},
+				Clientset: fake.NewSimpleClientset(),
+			}
+			cnc := &CloudNodeController{
+				kubeClient: fnh,
+				nodeProvider: &fakeNodeInfoSourceProvider{
+					NodeProvider: mocknodeprovider.NewMockNodeProvider(ctrl),
+					source:       tc.source,
+				},
+			}
+
+			err := cnc.reconcileNodeInfoSource(context.Background(), node)
+			assert.NoError(t, err)
+			updatedNodes := fnh.GetUpdatedNodesCopy()
+			if !tc.expectUpdate {
+				assert.Empty(t, updatedNodes)
+				return
+			}
+			assert.Equal(t, 1, len(updatedNodes))
+			assert.Equal(t, tc.expectedAnnotation, updatedNodes[0].Annotations[consts.NodeAnnotationNodeInfoSource])
+		})
+	}
+}
@@ -25,9 +25,11 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/adal"
 	"github.com/Azure/go-autorest/autorest/azure"
@@ -56,6 +58,7 @@ import (
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/containerserviceclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/deploymentclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/diskclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/faultinjection"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/fileclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/interfaceclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/loadbalancerclient"
@@ -243,11 +246,18 @@ type Config struct {
 	AvailabilitySetsCacheTTLInSeconds int `json:"availabilitySetsCacheTTLInSeconds,omitempty" yaml:"availabilitySetsCacheTTLInSeconds,omitempty"`
 	// PublicIPCacheTTLInSeconds sets the cache TTL for public ip
 	PublicIPCacheTTLInSeconds int `json:"publicIPCacheTTLInSeconds,omitempty" yaml:"publicIPCacheTTLInSeconds,omitempty"`
+	// CacheMaxStalenessInSeconds enables the stale-while-revalidate mode of the read-mostly caches (VMSS, VMSS VMs,
+	// availability sets and storage accounts) if positive: the entries expired for less than it are returned immediately
+	// and refreshed in the background. Default is 0, which means the expired entries are always refreshed synchronously.
+	CacheMaxStalenessInSeconds int `json:"cacheMaxStalenessInSeconds,omitempty" yaml:"cacheMaxStalenessInSeconds,omitempty"`
 	// RouteUpdateWaitingInSeconds is the delay time for waiting route updates to take effect. This waiting delay is added
 	// because the routes are not taken effect when the async route updating operation returns success. Default is 30 seconds.
 	RouteUpdateWaitingInSeconds int `json:"routeUpdateWaitingInSeconds,omitempty" yaml:"routeUpdateWaitingInSeconds,omitempty"`
 	// The user agent for Azure customer usage attribution
 	UserAgent string `json:"userAgent,omitempty" yaml:"userAgent,omitempty"`
+	// FaultInjectionConfigFile is the path of the fault injection config file, e.g. a mounted ConfigMap. Faults are
+	// injected into the responses of the Azure clients when set. For chaos testing only, never set it in production.
+	FaultInjectionConfigFile string `json:"faultInjectionConfigFile,omitempty" yaml:"faultInjectionConfigFile,omitempty"`
 	// LoadBalancerBackendPoolConfigurationType defines how vms join the load balancer backend pools. Supported values
 	// are `nodeIPConfiguration`, `nodeIP` and `podIP`.
 	// `nodeIPConfiguration`: vm network interfaces will be attached to the inbound backend pool of the load balancer (default);
@@ -418,6 +428,14 @@ type Cloud struct {
 	// regionZonesMap stores all available zones for the subscription by region
 	regionZonesMap   map[string][]string
 	refreshZonesLock sync.RWMutex
+	refreshZonesOnce sync.Once
+
+	// reloadLock is held for writing while ReloadConfig re-initializes the cloud in place, and for reading
+	// by the interfaces returned to the controllers, the informer handlers and the background routines.
+	reloadLock sync.RWMutex
+
+	// faultInjectionPolicy injects the faults of FaultInjectionConfigFile into the responses of the clients
+	faultInjectionPolicy *faultinjection.Policy
 
 	KubeClient         clientset.Interface
 	eventBroadcaster   record.EventBroadcaster
@@ -437,6 +455,10 @@ type Cloud struct {
 	plsCache azcache.Resource
 	// a timed cache storing storage account properties to avoid querying storage account frequently
 	storageAccountCache azcache.Resource
+	// a timed cache storing the file share usage of storage accounts to avoid listing file shares frequently
+	fileShareUsageCache azcache.Resource
+	// the next turn of the round-robin storage account selection
+	accountRoundRobinIndex atomic.Uint64
 
 	// Add service lister to always get latest service
 	serviceLister corelisters.ServiceLister
@@ -514,6 +536,8 @@ func NewCloudFromConfigFile(ctx context.Context, configFilePath string, calFromC
 }
 
 func NewCloudFromSecret(ctx context.Context, clientBuilder cloudprovider.ControllerClientBuilder, secretName, secretNamespace, cloudConfigKey string) (cloudprovider.Interface, error) {
+	// TODO: load the Key Vault and environment variable overrides with configloader.LoadWithOverrides once the
+	// vendored configloader release includes it.
 	config, err := configloader.Load[Config](ctx, &configloader.K8sSecretLoaderConfig{
 		K8sSecretConfig: configloader.K8sSecretConfig{
 			SecretName:      secretName,
@@ -703,6 +727,14 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *Config,
 	if err != nil {
 		return err
 	}
+	az.faultInjectionPolicy = nil
+	if az.FaultInjectionConfigFile != "" {
+		klog.Warningf("fault injection is enabled with %s, it must never be enabled in production", az.FaultInjectionConfigFile)
+		az.faultInjectionPolicy, err = faultinjection.NewPolicyFromFile(az.FaultInjectionConfigFile)
+		if err != nil {
+			return err
+		}
+	}
 	az.configAzureClients(servicePrincipalToken, multiTenantServicePrincipalToken, networkResourceServicePrincipalToken)
 
 	if az.ComputeClientFactory == nil {
@@ -712,7 +744,7 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *Config,
 			networkTenantCred := authProvider.GetNetworkAzIdentity()
 			az.NetworkClientFactory, err = azclient.NewClientFactory(&azclient.ClientFactoryConfig{
 				SubscriptionID: az.NetworkResourceSubscriptionID,
-			}, &az.ARMClientConfig, networkTenantCred)
+			}, &az.ARMClientConfig, networkTenantCred, az.clientOptionsMutFns()...)
 			if err != nil {
 				return err
 			}
@@ -722,7 +754,7 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *Config,
 		}
 		az.ComputeClientFactory, err = azclient.NewClientFactory(&azclient.ClientFactoryConfig{
 			SubscriptionID: az.SubscriptionID,
-		}, &az.ARMClientConfig, cred)
+		}, &az.ARMClientConfig, cred, az.clientOptionsMutFns()...)
 		if err != nil {
 			return err
 		}
@@ -749,11 +781,15 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *Config,
 		if az.RouteUpdateIntervalInSeconds == 0 {
 			az.RouteUpdateIntervalInSeconds = consts.DefaultRouteUpdateIntervalInSeconds
 		}
-		az.routeUpdater = newDelayedRouteUpdater(az, time.Duration(az.RouteUpdateIntervalInSeconds)*time.Second)
-		go az.routeUpdater.run(ctx)
+		// the updaters started by a previous initialization are kept when the config is reloaded,
+		// so that their pending operations are not lost.
+		if az.routeUpdater == nil {
+			az.routeUpdater = newDelayedRouteUpdater(az, time.Duration(az.RouteUpdateIntervalInSeconds)*time.Second)
+			go az.routeUpdater.run(ctx)
+		}
 
 		// start backend pool updater.
-		if az.useMultipleStandardLoadBalancers() {
+		if az.useMultipleStandardLoadBalancers() && az.backendPoolUpdater == nil {
 			az.backendPoolUpdater = newLoadBalancerBackendPoolUpdater(az, time.Duration(az.LoadBalancerBackendPoolUpdateIntervalInSeconds)*time.Second)
 			go az.backendPoolUpdater.run(ctx)
 		}
@@ -768,7 +804,9 @@ func (az *Cloud) InitializeCloudFromConfig(ctx context.Context, config *Config,
 				return err
 			}
 
-			go az.refreshZones(ctx, az.syncRegionZonesMap)
+			az.refreshZonesOnce.Do(func() {
+				go az.refreshZones(ctx, az.syncRegionZonesMap)
+			})
 		}
 	}
 
@@ -783,27 +821,11 @@ func (az *Cloud) useSingleStandardLoadBalancer() bool {
 	return az.useStandardLoadBalancer() && len(az.MultipleStandardLoadBalancerConfigurations) == 0
 }
 
-// Multiple standard load balancer mode only supports IP-based load balancers.
+// checkEnableMultipleStandardLoadBalancers validates the multiple standard load balancer configurations
+// and sets the defaults of the multiple standard load balancer mode.
 func (az *Cloud) checkEnableMultipleStandardLoadBalancers() error {
-	if az.isLBBackendPoolTypeNodeIPConfig() {
-		return fmt.Errorf("multiple standard load balancers cannot be used with backend pool type %s", consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration)
-	}
-
-	names := utilsets.NewString()
-	primaryVMSets := utilsets.NewString()
-	for _, multiSLBConfig := range az.MultipleStandardLoadBalancerConfigurations {
-		if names.Has(multiSLBConfig.Name) {
-			return fmt.Errorf("duplicated multiple standard load balancer configuration name %s", multiSLBConfig.Name)
-		}
-		names.Insert(multiSLBConfig.Name)
-
-		if multiSLBConfig.PrimaryVMSet == "" {
-			return fmt.Errorf("multiple standard load balancer configuration %s must have primary VMSet", multiSLBConfig.Name)
-		}
-		if primaryVMSets.Has(multiSLBConfig.PrimaryVMSet) {
-			return fmt.Errorf("duplicated primary VMSet %s in multiple standard load balancer configurations %s", multiSLBConfig.PrimaryVMSet, multiSLBConfig.Name)
-		}
-		primaryVMSets.Insert(multiSLBConfig.PrimaryVMSet)
+	if err := az.Config.validateMultipleStandardLoadBalancerConfigurations().ToAggregate(); err != nil {
+		return err
 	}
 
 	if az.LoadBalancerBackendPoolUpdateIntervalInSeconds == 0 {
@@ -863,9 +885,21 @@ func (az *Cloud) initCaches() (err error) {
 	if az.storageAccountCache, err = az.newStorageAccountCache(); err != nil {
 		return err
 	}
+
+	if az.fileShareUsageCache, err = az.newFileShareUsageCache(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// readMostlyCacheOptions returns the options of the caches of the resources which rarely change.
+func (az *Cloud) readMostlyCacheOptions() []azcache.CacheOption {
+	if az.Config.CacheMaxStalenessInSeconds <= 0 {
+		return nil
+	}
+	return []azcache.CacheOption{azcache.WithStaleWhileRevalidate(time.Duration(az.Config.CacheMaxStalenessInSeconds) * time.Second)}
+}
+
 func (az *Cloud) setLBDefaults(config *Config) error {
 	if config.LoadBalancerSku == "" {
 		config.LoadBalancerSku = consts.LoadBalancerSkuStandard
@@ -1040,6 +1074,21 @@ func (az *Cloud) configAzureClients(
 	}
 }
 
+// clientOptionsMutFns returns the functions customizing the options of the clients of the client factories.
+// TODO: set the FaultInjectionConfigFile of the client factory config instead once the vendored azclient release
+// includes it.
+func (az *Cloud) clientOptionsMutFns() []func(option *arm.ClientOptions) {
+	if az.faultInjectionPolicy == nil {
+		return nil
+	}
+	return []func(option *arm.ClientOptions){
+		func(option *arm.ClientOptions) {
+			// the faults are injected closest to the transport so that the retry policies handle them
+			option.PerRetryPolicies = append(option.PerRetryPolicies, az.faultInjectionPolicy)
+		},
+	}
+}
+
 func (az *Cloud) getAzureClientConfig(servicePrincipalToken *adal.ServicePrincipalToken) *azclients.ClientConfig {
 	azClientConfig := &azclients.ClientConfig{
 		CloudName:               az.Config.Cloud,
@@ -1050,6 +1099,7 @@ func (az *Cloud) getAzureClientConfig(servicePrincipalToken *adal.ServicePrincip
 		Backoff:                 &retry.Backoff{Steps: 1},
 		DisableAzureStackCloud:  az.Config.DisableAzureStackCloud,
 		UserAgent:               az.Config.UserAgent,
+		FaultInjectionPolicy:    az.faultInjectionPolicy,
 	}
 
 	if az.Config.CloudProviderBackoff {
@@ -1120,12 +1170,12 @@ func (az *Cloud) Initialize(clientBuilder cloudprovider.ControllerClientBuilder,
 
 // LoadBalancer returns a balancer interface. Also returns true if the interface is supported, false otherwise.
 func (az *Cloud) LoadBalancer() (cloudprovider.LoadBalancer, bool) {
-	return az, true
+	return &reloadLockedCloud{az: az}, true
 }
 
 // Instances returns an instances interface. Also returns true if the interface is supported, false otherwise.
 func (az *Cloud) Instances() (cloudprovider.Instances, bool) {
-	return az, true
+	return &reloadLockedCloud{az: az}, true
 }
 
 // InstancesV2 is an implementation for instances and should only be implemented by external cloud providers.
@@ -1133,7 +1183,7 @@ func (az *Cloud) Instances() (cloudprovider.Instances, bool) {
 // API calls to the cloud provider when registering and syncing nodes. Implementation of this interface will
 // disable calls to the Zones interface. Also returns true if the interface is supported, false otherwise.
 func (az *Cloud) InstancesV2() (cloudprovider.InstancesV2, bool) {
-	return az, true
+	return &reloadLockedCloud{az: az}, true
 }
 
 // Zones returns a zones interface. Also returns true if the interface is supported, false otherwise.
@@ -1145,7 +1195,7 @@ func (az *Cloud) Zones() (cloudprovider.Zones, bool) {
 		// https://docs.microsoft.com/en-us/azure-stack/user/azure-stack-network-differences?view=azs-2102
 		return nil, false
 	}
-	return az, true
+	return &reloadLockedCloud{az: az}, true
 }
 
 // Clusters returns a clusters interface.  Also returns true if the interface is supported, false otherwise.
@@ -1155,7 +1205,7 @@ func (az *Cloud) Clusters() (cloudprovider.Clusters, bool) {
 
 // Routes returns a routes interface along with whether the interface is supported.
 func (az *Cloud) Routes() (cloudprovider.Routes, bool) {
-	return az, true
+	return &reloadLockedCloud{az: az}, true
 }
 
 // HasClusterID returns true if the cluster has a clusterID
@@ -1174,17 +1224,23 @@ func (az *Cloud) SetInformers(informerFactory informers.SharedInformerFactory) {
 	nodeInformer := informerFactory.Core().V1().Nodes().Informer()
 	_, _ = nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
+			az.reloadLock.RLock()
+			defer az.reloadLock.RUnlock()
 			node := obj.(*v1.Node)
 			az.updateNodeCaches(nil, node)
 			az.updateNodeTaint(node)
 		},
 		UpdateFunc: func(prev, obj interface{}) {
+			az.reloadLock.RLock()
+			defer az.reloadLock.RUnlock()
 			prevNode := prev.(*v1.Node)
 			newNode := obj.(*v1.Node)
 			az.updateNodeCaches(prevNode, newNode)
 			az.updateNodeTaint(newNode)
 		},
 		DeleteFunc: func(obj interface{}) {
+			az.reloadLock.RLock()
+			defer az.reloadLock.RUnlock()
 			node, isNode := obj.(*v1.Node)
 			// We can get DeletedFinalStateUnknown instead of *v1.Node here
 			// and we need to handle that correctly.
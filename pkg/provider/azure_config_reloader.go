@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/metrics"
+)
+
+const (
+	// CloudConfigReloaded is the reason of the event emitted when the cloud config file is reloaded.
+	CloudConfigReloaded = "CloudConfigReloaded"
+	// CloudConfigReloadFailed is the reason of the event emitted when the cloud config file is rejected.
+	CloudConfigReloadFailed = "CloudConfigReloadFailed"
+)
+
+// ConfigFileReloader watches the cloud config file and re-initializes the cloud in place when the file changes.
+// The directory of the file is watched rather than the file itself, so that the atomic symlink swaps of
+// projected volumes, which replace the ..data symlink of the directory, are detected.
+// TODO: watch the file with configloader.Watch once the vendored configloader includes it.
+type ConfigFileReloader struct {
+	cloud       *Cloud
+	path        string
+	callFromCCM bool
+	// eventObject is the object the reload events are emitted for, e.g. the pod of the controller manager.
+	eventObject runtime.Object
+
+	lock sync.Mutex
+	// hash of the last file content that was reloaded or rejected
+	lastHash [sha256.Size]byte
+}
+
+// NewConfigFileReloader creates a reloader of the cloud config file. The cloud must have been initialized
+// from the current content of the file. eventObject may be nil to disable the events.
+func NewConfigFileReloader(cloud *Cloud, path string, callFromCCM bool, eventObject runtime.Object) (*ConfigFileReloader, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("NewConfigFileReloader: failed to read %s: %w", path, err)
+	}
+	return &ConfigFileReloader{
+		cloud:       cloud,
+		path:        path,
+		callFromCCM: callFromCCM,
+		eventObject: eventObject,
+		lastHash:    sha256.Sum256(content),
+	}, nil
+}
+
+// Run watches the cloud config file and reloads it until the context is done.
+func (r *ConfigFileReloader) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("ConfigFileReloader: failed to initialize file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, dir := range r.watchedDirs() {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("ConfigFileReloader: failed to watch %s: %w", dir, err)
+		}
+	}
+	klog.V(2).Infof("ConfigFileReloader: watching cloud config file %s", r.path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return fmt.Errorf("ConfigFileReloader: events channel closed unexpectedly")
+			}
+			klog.V(4).Infof("ConfigFileReloader: found file event: %v", event)
+			// the events of the other files in the directories are not filtered out, the swaps of
+			// projected volumes only touch the ..data symlink. Unchanged content is skipped by Reload.
+			_, _ = r.Reload(ctx)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return fmt.Errorf("ConfigFileReloader: errors channel closed unexpectedly")
+			}
+			klog.Errorf("ConfigFileReloader: failed to watch %s: %v", r.path, err)
+		}
+	}
+}
+
+// watchedDirs returns the directory of the file, and the directory of its target if the file is a symlink.
+func (r *ConfigFileReloader) watchedDirs() []string {
+	dirs := []string{filepath.Dir(r.path)}
+	if target, err := filepath.EvalSymlinks(r.path); err == nil && filepath.Dir(target) != dirs[0] {
+		dirs = append(dirs, filepath.Dir(target))
+	}
+	return dirs
+}
+
+// Reload reloads the cloud config file if its content changed, and returns true if the cloud was re-initialized.
+// An invalid config is rejected and the cloud keeps its working config.
+func (r *ConfigFileReloader) Reload(ctx context.Context) (bool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		// the file may be missing in the middle of a swap, it is reloaded on the next event
+		klog.V(4).Infof("ConfigFileReloader: failed to read %s: %v", r.path, err)
+		return false, nil
+	}
+	hash := sha256.Sum256(content)
+	if hash == r.lastHash {
+		return false, nil
+	}
+	r.lastHash = hash
+
+	mc := metrics.NewMetricContext("cloud", "reload_config_file", r.cloud.ResourceGroup, r.cloud.SubscriptionID, r.path)
+	err = r.reload(ctx, content)
+	mc.ObserveOperationWithResult(err == nil)
+	if err != nil {
+		klog.Errorf("ConfigFileReloader: rejected cloud config file %s, keeping the working config: %v", r.path, err)
+		r.event(v1.EventTypeWarning, CloudConfigReloadFailed, fmt.Sprintf("Rejected cloud config file %s, keeping the working config: %v", r.path, err))
+		return false, err
+	}
+	klog.Infof("ConfigFileReloader: reloaded cloud config file %s", r.path)
+	r.event(v1.EventTypeNormal, CloudConfigReloaded, fmt.Sprintf("Reloaded cloud config file %s", r.path))
+	return true, nil
+}
+
+func (r *ConfigFileReloader) reload(ctx context.Context, content []byte) error {
+	unknownFields, errs := ValidateConfigContent(content)
+	for _, unknownField := range unknownFields {
+		klog.Warningf("ConfigFileReloader: %s", unknownField.Error())
+	}
+	if len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	config, err := ParseConfig(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("failed to parse the config: %w", err)
+	}
+	return r.cloud.ReloadConfig(ctx, config, r.callFromCCM)
+}
+
+func (r *ConfigFileReloader) event(eventType, reason, message string) {
+	// the event recorder is set when the cloud is initialized with a client builder
+	if r.cloud.eventRecorder == nil {
+		return
+	}
+	r.cloud.Event(r.eventObject, eventType, reason, message)
+}
+
+// ReloadConfig re-initializes the cloud and its client factories in place from the config. The config is
+// validated on a new cloud first, and the cloud is restored to its previous config if the re-initialization
+// fails, so that an invalid config never replaces a working one. The cloud is re-initialized once the calls
+// of the controllers in progress return, and the new calls wait for it.
+func (az *Cloud) ReloadConfig(ctx context.Context, config *Config, callFromCCM bool) error {
+	if config == nil {
+		return fmt.Errorf("ReloadConfig: cannot reload from nil config")
+	}
+	// the validation cloud does not start the background routines of the controller manager
+	validationConfig := *config
+	validationCloud, err := NewCloud(ctx, &validationConfig, false)
+	if err != nil {
+		return fmt.Errorf("invalid cloud config: %w", err)
+	}
+	// the controller manager cannot manage Azure resources without credentials
+	if callFromCCM && validationCloud.(*Cloud).ComputeClientFactory == nil {
+		return fmt.Errorf("invalid cloud config: no credentials provided for Azure cloud provider")
+	}
+
+	// the controllers and the background routines hold the lock for reading while they use the cloud
+	az.reloadLock.Lock()
+	defer az.reloadLock.Unlock()
+
+	previousConfig := az.Config
+	previousComputeClientFactory, previousNetworkClientFactory := az.ComputeClientFactory, az.NetworkClientFactory
+	// the client factories are re-created from the new credentials and ARM config
+	az.ComputeClientFactory, az.NetworkClientFactory = nil, nil
+	err = az.InitializeCloudFromConfig(ctx, config, false, callFromCCM)
+	if err == nil {
+		return nil
+	}
+
+	az.ComputeClientFactory, az.NetworkClientFactory = previousComputeClientFactory, previousNetworkClientFactory
+	if restoreErr := az.InitializeCloudFromConfig(ctx, &previousConfig, false, callFromCCM); restoreErr != nil {
+		klog.Errorf("ReloadConfig: failed to restore the previous config: %v", restoreErr)
+	}
+	return fmt.Errorf("failed to initialize the cloud: %w", err)
+}
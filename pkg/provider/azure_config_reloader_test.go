@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+const (
+	testReloadConfig = `{
+		"tenantId": "tenant",
+		"subscriptionId": "subscription",
+		"aadClientId": "client",
+		"aadClientSecret": "secret",
+		"resourceGroup": "rg2",
+		"location": "eastus",
+		"vmType": "vmss"
+	}`
+)
+
+func TestConfigFileReloaderReload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	recorder := record.NewFakeRecorder(10)
+	az.eventRecorder = recorder
+	previousFactory := az.ComputeClientFactory
+
+	path := filepath.Join(t.TempDir(), "azure.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"resourceGroup": "rg", "useInstanceMetadata": true}`), 0600))
+	reloader, err := NewConfigFileReloader(az, path, false, &v1.ObjectReference{Kind: "Pod", Name: "ccm", Namespace: "kube-system"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := reloader.Reload(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, reloaded, "the unchanged file should not be reloaded")
+
+	assert.NoError(t, os.WriteFile(path, []byte(testReloadConfig), 0600))
+	reloaded, err = reloader.Reload(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, "rg2", az.ResourceGroup)
+	assert.Equal(t, "subscription", az.SubscriptionID)
+	assert.NotSame(t, previousFactory, az.ComputeClientFactory, "the client factory should be re-created")
+	assert.Contains(t, <-recorder.Events, CloudConfigReloaded)
+
+	for _, invalid := range []string{
+		`{"resourceGroup": `,
+		`{"resourceGroup": "rg3", "vmType": "standard", "disableAvailabilitySetNodes": true}`,
+	} {
+		assert.NoError(t, os.WriteFile(path, []byte(invalid), 0600))
+		reloaded, err = reloader.Reload(context.Background())
+		assert.Error(t, err)
+		assert.False(t, reloaded)
+		assert.Equal(t, "rg2", az.ResourceGroup, "the invalid config should not replace the working config")
+		assert.Contains(t, <-recorder.Events, CloudConfigReloadFailed)
+
+		// the rejected content is not reloaded again
+		reloaded, err = reloader.Reload(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, reloaded)
+	}
+}
+
+func TestConfigFileReloaderRunSymlinkSwap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+
+	// the layout of projected volumes: azure.json -> ..data/azure.json, ..data -> ..2024_01_01
+	dir := t.TempDir()
+	writeVersion := func(version, content string) {
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, version), 0700))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, version, "azure.json"), []byte(content), 0600))
+		assert.NoError(t, os.Symlink(version, filepath.Join(dir, "..data_tmp")))
+		assert.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	}
+	writeVersion("..2024_01_01", `{"resourceGroup": "rg", "useInstanceMetadata": true}`)
+	path := filepath.Join(dir, "azure.json")
+	assert.NoError(t, os.Symlink(filepath.Join("..data", "azure.json"), path))
+
+	reloader, err := NewConfigFileReloader(az, path, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- reloader.Run(ctx)
+	}()
+
+	version := 1
+	assert.Eventually(t, func() bool {
+		// the watcher may not be ready before the first swap, swap until the config is reloaded
+		version++
+		writeVersion(fmt.Sprintf("..2024_01_%02d", version), testReloadConfig)
+		time.Sleep(50 * time.Millisecond)
+		reloader.lock.Lock()
+		defer reloader.lock.Unlock()
+		return az.ResourceGroup == "rg2"
+	}, 10*time.Second, 100*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestReloadConfigWithoutCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	resourceGroup := az.ResourceGroup
+
+	err := az.ReloadConfig(context.Background(), &Config{UseInstanceMetadata: true}, true)
+	assert.ErrorContains(t, err, "no credentials provided")
+	assert.Equal(t, resourceGroup, az.ResourceGroup)
+}
+
+func TestReloadConfigConcurrentCalls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	az := GetTestCloud(ctrl)
+	config, err := ParseConfig(strings.NewReader(testReloadConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the availability set resolves the invalid provider ID without calling ARM
+	config.VMType = consts.VMTypeStandard
+	zones, _ := az.Zones()
+	providerID := "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/invalid"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				_, _ = zones.GetZoneByProviderID(ctx, providerID)
+				_, _, _ = az.GetNodeCIDRMasksByProviderID(providerID)
+			}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		reloaded := *config
+		assert.NoError(t, az.ReloadConfig(ctx, &reloaded, false))
+	}
+	cancel()
+	wg.Wait()
+	assert.Equal(t, "rg2", az.ResourceGroup)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/configloader"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/util/jsonschema"
+	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
+)
+
+var (
+	supportedVMTypes          = []string{consts.VMTypeStandard, consts.VMTypeVMSS, consts.VMTypeVmssFlex}
+	supportedLoadBalancerSkus = []string{consts.LoadBalancerSkuBasic, consts.LoadBalancerSkuStandard}
+	supportedCloudConfigTypes = []string{
+		string(configloader.CloudConfigTypeFile),
+		string(configloader.CloudConfigTypeSecret),
+		string(configloader.CloudConfigTypeMerge),
+	}
+	supportedLoadBalancerBackendPoolConfigurationTypes = []string{
+		consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration,
+		consts.LoadBalancerBackendPoolConfigurationTypeNodeIP,
+		consts.LoadBalancerBackendPoolConfigurationTypePODIP,
+	}
+	supportedClusterServiceLoadBalancerHealthProbeModes = []string{
+		consts.ClusterServiceLoadBalancerHealthProbeModeServiceNodePort,
+		consts.ClusterServiceLoadBalancerHealthProbeModeShared,
+	}
+)
+
+// ConfigSchema returns the JSON Schema of the cloud config, generated from Config and the embedded azclient
+// config structs. The enum values are matched case-insensitively by the cloud provider.
+func ConfigSchema() *jsonschema.Schema {
+	schema := jsonschema.Reflect("Azure cloud provider config", Config{})
+	for name, values := range map[string][]string{
+		"vmType":          supportedVMTypes,
+		"loadBalancerSku": supportedLoadBalancerSkus,
+		"cloudConfigType": supportedCloudConfigTypes,
+		"loadBalancerBackendPoolConfigurationType":  supportedLoadBalancerBackendPoolConfigurationTypes,
+		"clusterServiceLoadBalancerHealthProbeMode": supportedClusterServiceLoadBalancerHealthProbeModes,
+	} {
+		schema.Properties[name].Enum = values
+	}
+	return schema
+}
+
+// ValidateConfigContent decodes the cloud config strictly and validates it. The unknown fields, including
+// the fields only matching a known field case-insensitively, are returned separately from the errors,
+// as the config file may be shared with other components having their own fields.
+func ValidateConfigContent(content []byte) (unknownFields field.ErrorList, errs field.ErrorList) {
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 {
+		return nil, nil
+	}
+	jsonContent, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, field.ErrorList{field.Invalid(nil, nil, err.Error())}
+	}
+	var doc interface{}
+	if err := json.Unmarshal(jsonContent, &doc); err != nil {
+		return nil, field.ErrorList{field.Invalid(nil, nil, err.Error())}
+	}
+	unknownFields, errs = ConfigSchema().Validate(doc)
+
+	config, err := ParseConfig(bytes.NewReader(content))
+	if err != nil {
+		// the type errors are already reported by the schema
+		if len(errs) == 0 {
+			errs = append(errs, field.Invalid(nil, nil, err.Error()))
+		}
+		return unknownFields, errs
+	}
+	// the enum values are checked by both the schema and the semantic validation
+	reported := utilsets.NewString()
+	for _, e := range errs {
+		reported.Insert(e.Error())
+	}
+	for _, e := range config.Validate() {
+		if !reported.Has(e.Error()) {
+			errs = append(errs, e)
+		}
+	}
+	return unknownFields, errs
+}
+
+// Validate checks the semantics of the config: the enum values, the mutually exclusive options and
+// the multiple standard load balancer configurations, which would otherwise fail at reconcile time.
+func (config *Config) Validate() field.ErrorList {
+	var errs field.ErrorList
+
+	for _, enum := range []struct {
+		name      string
+		value     string
+		supported []string
+	}{
+		{"vmType", config.VMType, supportedVMTypes},
+		{"loadBalancerSku", config.LoadBalancerSku, supportedLoadBalancerSkus},
+		{"cloudConfigType", string(config.CloudConfigType), supportedCloudConfigTypes},
+		{"loadBalancerBackendPoolConfigurationType", config.LoadBalancerBackendPoolConfigurationType, supportedLoadBalancerBackendPoolConfigurationTypes},
+		{"clusterServiceLoadBalancerHealthProbeMode", config.ClusterServiceLoadBalancerHealthProbeMode, supportedClusterServiceLoadBalancerHealthProbeModes},
+	} {
+		if enum.value != "" && !utilsets.NewString(enum.supported...).Has(enum.value) {
+			errs = append(errs, field.NotSupported(field.NewPath(enum.name), enum.value, enum.supported))
+		}
+	}
+
+	if config.DisableAvailabilitySetNodes && config.VMType != "" && !strings.EqualFold(config.VMType, consts.VMTypeVMSS) {
+		errs = append(errs, field.Invalid(field.NewPath("disableAvailabilitySetNodes"), true, "only supported when vmType is 'vmss'"))
+	}
+	if config.UseManagedIdentityExtension && config.UseFederatedWorkloadIdentityExtension {
+		errs = append(errs, field.Forbidden(field.NewPath("useFederatedWorkloadIdentityExtension"), "may not be set together with useManagedIdentityExtension"))
+	}
+	if config.ClusterServiceSharedLoadBalancerHealthProbePort < 0 || config.ClusterServiceSharedLoadBalancerHealthProbePort > 65535 {
+		errs = append(errs, field.Invalid(field.NewPath("clusterServiceSharedLoadBalancerHealthProbePort"), config.ClusterServiceSharedLoadBalancerHealthProbePort, "must be between 0 and 65535"))
+	}
+
+	if config.CacheMaxStalenessInSeconds < 0 {
+		errs = append(errs, field.Invalid(field.NewPath("cacheMaxStalenessInSeconds"), config.CacheMaxStalenessInSeconds, "must be non-negative"))
+	}
+
+	return append(errs, config.validateMultipleStandardLoadBalancerConfigurations()...)
+}
+
+// validateMultipleStandardLoadBalancerConfigurations checks the multiple standard load balancer
+// configurations, which only support IP-based load balancers.
+func (config *Config) validateMultipleStandardLoadBalancerConfigurations() field.ErrorList {
+	if len(config.MultipleStandardLoadBalancerConfigurations) == 0 {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("multipleStandardLoadBalancerConfigurations")
+	// the load balancer sku defaults to standard
+	if config.LoadBalancerSku != "" && !strings.EqualFold(config.LoadBalancerSku, consts.LoadBalancerSkuStandard) {
+		errs = append(errs, field.Invalid(field.NewPath("loadBalancerSku"), config.LoadBalancerSku, "must be 'standard' to use multiple standard load balancers"))
+	}
+	// the backend pool type defaults to nodeIPConfiguration
+	if config.LoadBalancerBackendPoolConfigurationType == "" ||
+		strings.EqualFold(config.LoadBalancerBackendPoolConfigurationType, consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration) {
+		errs = append(errs, field.Invalid(field.NewPath("loadBalancerBackendPoolConfigurationType"), config.LoadBalancerBackendPoolConfigurationType,
+			"multiple standard load balancers cannot be used with backend pool type "+consts.LoadBalancerBackendPoolConfigurationTypeNodeIPConfiguration))
+	}
+
+	names := utilsets.NewString()
+	primaryVMSets := utilsets.NewString()
+	for i, multiSLBConfig := range config.MultipleStandardLoadBalancerConfigurations {
+		if multiSLBConfig.Name == "" {
+			errs = append(errs, field.Required(path.Index(i).Child("name"), ""))
+		} else if names.Has(multiSLBConfig.Name) {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), multiSLBConfig.Name))
+		}
+		names.Insert(multiSLBConfig.Name)
+
+		if multiSLBConfig.PrimaryVMSet == "" {
+			errs = append(errs, field.Required(path.Index(i).Child("primaryVMSet"), ""))
+		} else if primaryVMSets.Has(multiSLBConfig.PrimaryVMSet) {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("primaryVMSet"), multiSLBConfig.PrimaryVMSet))
+		}
+		primaryVMSets.Insert(multiSLBConfig.PrimaryVMSet)
+	}
+	return errs
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateConfigContent(t *testing.T) {
+	for _, tc := range []struct {
+		desc            string
+		content         string
+		expectedUnknown []string
+		expectedErrs    []string
+	}{
+		{
+			desc: "empty config is valid",
+		},
+		{
+			desc: "valid config",
+			content: `{
+	"cloud": "AzurePublicCloud",
+	"tenantId": "tenant",
+	"subscriptionId": "subscription",
+	"resourceGroup": "rg",
+	"vmType": "VMSS",
+	"loadBalancerSku": "standard",
+	"useManagedIdentityExtension": true,
+	"loadBalancerBackendPoolConfigurationType": "nodeIP",
+	"multipleStandardLoadBalancerConfigurations": [
+		{"name": "lb1", "primaryVMSet": "vmss1"},
+		{"name": "lb2", "primaryVMSet": "vmss2"}
+	]
+}`,
+		},
+		{
+			desc: "multiple standard load balancers with the default sku",
+			content: `{
+	"loadBalancerBackendPoolConfigurationType": "nodeIP",
+	"multipleStandardLoadBalancerConfigurations": [
+		{"name": "lb1", "primaryVMSet": "vmss1"}
+	]
+}`,
+		},
+		{
+			desc:    "yaml config with unknown fields",
+			content: "vmtype: vmss\nunknownField: 1\n",
+			expectedUnknown: []string{
+				`unknownField: Invalid value: "unknownField": unknown field`,
+				`vmtype: Invalid value: "vmtype": unknown field, did you mean "vmType"?`,
+			},
+		},
+		{
+			desc:    "invalid types and enums",
+			content: `{"vmType": "vm", "useInstanceMetadata": "true"}`,
+			expectedErrs: []string{
+				`useInstanceMetadata: Invalid value: "true": must be a boolean`,
+				`vmType: Unsupported value: "vm": supported values: "standard", "vmss", "vmssflex"`,
+			},
+		},
+		{
+			desc:    "mutually exclusive options",
+			content: `{"vmType": "standard", "disableAvailabilitySetNodes": true, "useManagedIdentityExtension": true, "useFederatedWorkloadIdentityExtension": true}`,
+			expectedErrs: []string{
+				`disableAvailabilitySetNodes: Invalid value: true: only supported when vmType is 'vmss'`,
+				`useFederatedWorkloadIdentityExtension: Forbidden: may not be set together with useManagedIdentityExtension`,
+			},
+		},
+		{
+			desc:    "negative cache max staleness",
+			content: `{"cacheMaxStalenessInSeconds": -1}`,
+			expectedErrs: []string{
+				`cacheMaxStalenessInSeconds: Invalid value: -1: must be non-negative`,
+			},
+		},
+		{
+			desc: "invalid multiple standard load balancer configurations",
+			content: `{
+	"loadBalancerSku": "basic",
+	"multipleStandardLoadBalancerConfigurations": [
+		{"name": "lb1", "primaryVMSet": "vmss1"},
+		{"name": "lb1", "primaryVMSet": "vmss1"},
+		{}
+	]
+}`,
+			expectedErrs: []string{
+				`loadBalancerSku: Invalid value: "basic": must be 'standard' to use multiple standard load balancers`,
+				`loadBalancerBackendPoolConfigurationType: Invalid value: "": multiple standard load balancers cannot be used with backend pool type nodeIPConfiguration`,
+				`multipleStandardLoadBalancerConfigurations[1].name: Duplicate value: "lb1"`,
+				`multipleStandardLoadBalancerConfigurations[1].primaryVMSet: Duplicate value: "vmss1"`,
+				`multipleStandardLoadBalancerConfigurations[2].name: Required value`,
+				`multipleStandardLoadBalancerConfigurations[2].primaryVMSet: Required value`,
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			unknownFields, errs := ValidateConfigContent([]byte(tc.content))
+			assert.Equal(t, tc.expectedUnknown, errorStrings(unknownFields))
+			assert.Equal(t, tc.expectedErrs, errorStrings(errs))
+		})
+	}
+}
+
+func errorStrings(errs field.ErrorList) []string {
+	var result []string
+	for _, err := range errs {
+		result = append(result, err.Error())
+	}
+	return result
+}
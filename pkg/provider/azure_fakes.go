@@ -130,6 +130,7 @@ func GetTestCloud(ctrl *gomock.Controller) (az *Cloud) {
 	az.plsCache, _ = az.newPLSCache()
 	az.LoadBalancerBackendPool = NewMockBackendPool(ctrl)
 	az.storageAccountCache, _ = az.newStorageAccountCache()
+	az.fileShareUsageCache, _ = az.newFileShareUsageCache()
 
 	az.regionZonesMap = map[string][]string{az.Location: {"1", "2", "3"}}
 
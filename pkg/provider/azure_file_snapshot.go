@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/fileclient"
+)
+
+const (
+	// The metadata names of the file shares must be valid C# identifiers, so they can't have the
+	// dashes in the tags of the storage accounts.
+
+	// FileShareSnapshotCreatedByMetadata is the metadata of the snapshots created by the cloud provider.
+	FileShareSnapshotCreatedByMetadata = "k8sazurecreatedby"
+	// FileShareSnapshotOwnerMetadata is the metadata of the owner of the snapshot, e.g. the backup tool.
+	FileShareSnapshotOwnerMetadata = "k8sazureowner"
+	// FileShareSnapshotExpiresOnMetadata is the metadata of the time after which the snapshot is pruned.
+	FileShareSnapshotExpiresOnMetadata = "k8sazureexpireson"
+	// FileShareRestoredFromMetadata is the metadata of the snapshot a file share is restored from.
+	FileShareRestoredFromMetadata = "k8sazurerestoredfrom"
+
+	fileShareSnapshotCreatedBy = "azure"
+	fileShareSnapshotsExpand   = "snapshots"
+	// fileShareSnapshotTimeFormat is the format of the snapshot time identifying the share snapshot,
+	// e.g. "2017-05-10T17:52:33.9551861Z".
+	fileShareSnapshotTimeFormat = "2006-01-02T15:04:05.0000000Z"
+)
+
+// FileShareSnapshotOptions contains the fields which are used to create file share snapshot.
+type FileShareSnapshotOptions struct {
+	// Owner is recorded in the snapshot metadata, so that the owner only lists and prunes its own snapshots.
+	Owner string
+	// Retention is how long the snapshot is kept before it's pruned, 0 means it's never pruned.
+	Retention time.Duration
+	// Metadata is the additional metadata of the snapshot.
+	Metadata map[string]string
+}
+
+// getFileShareSnapshotTime returns the snapshot time identifying the share snapshot, or an empty
+// string if the file share is not a snapshot.
+func getFileShareSnapshotTime(properties *storage.FileShareProperties) string {
+	if properties == nil || properties.SnapshotTime == nil {
+		return ""
+	}
+	return properties.SnapshotTime.UTC().Format(fileShareSnapshotTimeFormat)
+}
+
+// CreateFileShareSnapshot creates a snapshot of the file share, and returns the snapshot time
+// identifying the snapshot.
+//
+// The snapshots are managed with the file client like the other file shares of the cloud provider:
+// the file share client of azclient can't create, list or delete snapshots, since it has no expand
+// and snapshot parameters, and it is not in the azclient release vendored by the cloud provider yet.
+func (az *Cloud) CreateFileShareSnapshot(ctx context.Context, subsID, resourceGroup, accountName, shareName string, snapshotOptions *FileShareSnapshotOptions) (string, error) {
+	if snapshotOptions == nil {
+		snapshotOptions = &FileShareSnapshotOptions{}
+	}
+	metadata := make(map[string]*string, len(snapshotOptions.Metadata)+3)
+	for k, v := range snapshotOptions.Metadata {
+		metadata[k] = pointer.String(v)
+	}
+	metadata[FileShareSnapshotCreatedByMetadata] = pointer.String(fileShareSnapshotCreatedBy)
+	if snapshotOptions.Owner != "" {
+		metadata[FileShareSnapshotOwnerMetadata] = pointer.String(snapshotOptions.Owner)
+	}
+	if snapshotOptions.Retention > 0 {
+		metadata[FileShareSnapshotExpiresOnMetadata] = pointer.String(time.Now().Add(snapshotOptions.Retention).UTC().Format(time.RFC3339))
+	}
+
+	shareOptions := &fileclient.ShareOptions{Name: shareName, Metadata: metadata}
+	snapshot, err := az.FileClient.WithSubscriptionID(subsID).CreateFileShare(ctx, resourceGroup, accountName, shareOptions, fileShareSnapshotsExpand)
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot of share %s in account %s: %w", shareName, accountName, err)
+	}
+	snapshotTime := getFileShareSnapshotTime(snapshot.FileShareProperties)
+	if snapshotTime == "" {
+		return "", fmt.Errorf("snapshot time of share %s in account %s is not returned", shareName, accountName)
+	}
+	klog.V(2).Infof("created snapshot %s of share %s in account %s", snapshotTime, shareName, accountName)
+	return snapshotTime, nil
+}
+
+// ListFileShareSnapshots lists the snapshots of the file share created by the cloud provider. Only
+// the snapshots of the owner are listed if owner is not empty.
+func (az *Cloud) ListFileShareSnapshots(ctx context.Context, subsID, resourceGroup, accountName, shareName, owner string) ([]storage.FileShareItem, error) {
+	// the filter matches the prefix of the share names, the other shares with the prefix are skipped below
+	items, err := az.FileClient.WithSubscriptionID(subsID).ListFileShare(ctx, resourceGroup, accountName, shareName, fileShareSnapshotsExpand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots of share %s in account %s: %w", shareName, accountName, err)
+	}
+
+	snapshots := make([]storage.FileShareItem, 0)
+	for _, item := range items {
+		if !strings.EqualFold(pointer.StringDeref(item.Name, ""), shareName) || getFileShareSnapshotTime(item.FileShareProperties) == "" {
+			continue
+		}
+		metadata := item.FileShareProperties.Metadata
+		if pointer.StringDeref(metadata[FileShareSnapshotCreatedByMetadata], "") != fileShareSnapshotCreatedBy {
+			continue
+		}
+		if owner != "" && pointer.StringDeref(metadata[FileShareSnapshotOwnerMetadata], "") != owner {
+			continue
+		}
+		snapshots = append(snapshots, item)
+	}
+	return snapshots, nil
+}
+
+// DeleteFileShareSnapshot deletes the snapshot of the file share identified by the snapshot time.
+func (az *Cloud) DeleteFileShareSnapshot(ctx context.Context, subsID, resourceGroup, accountName, shareName, snapshotTime string) error {
+	if snapshotTime == "" {
+		// an empty snapshot time would delete the file share itself
+		return fmt.Errorf("snapshot time is required to delete snapshot of share %s in account %s", shareName, accountName)
+	}
+	if err := az.FileClient.WithSubscriptionID(subsID).DeleteFileShare(ctx, resourceGroup, accountName, shareName, snapshotTime); err != nil {
+		return fmt.Errorf("failed to delete snapshot %s of share %s in account %s: %w", snapshotTime, shareName, accountName, err)
+	}
+	klog.V(2).Infof("deleted snapshot %s of share %s in account %s", snapshotTime, shareName, accountName)
+	return nil
+}
+
+// PruneFileShareSnapshots deletes the expired snapshots of the file share created by the cloud
+// provider for the owner, and returns the snapshot times of the deleted snapshots. The snapshots
+// without retention are never pruned.
+func (az *Cloud) PruneFileShareSnapshots(ctx context.Context, subsID, resourceGroup, accountName, shareName, owner string) ([]string, error) {
+	snapshots, err := az.ListFileShareSnapshots(ctx, subsID, resourceGroup, accountName, shareName, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pruned := []string{}
+	for _, snapshot := range snapshots {
+		snapshotTime := getFileShareSnapshotTime(snapshot.FileShareProperties)
+		expiresOn := pointer.StringDeref(snapshot.FileShareProperties.Metadata[FileShareSnapshotExpiresOnMetadata], "")
+		if expiresOn == "" {
+			continue
+		}
+		expiry, err := time.Parse(time.RFC3339, expiresOn)
+		if err != nil {
+			klog.Warningf("skip pruning snapshot %s of share %s in account %s with invalid expiry %q: %v", snapshotTime, shareName, accountName, expiresOn, err)
+			continue
+		}
+		if now.Before(expiry) {
+			continue
+		}
+		if err := az.DeleteFileShareSnapshot(ctx, subsID, resourceGroup, accountName, shareName, snapshotTime); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, snapshotTime)
+	}
+	return pruned, nil
+}
+
+// CreateShareForSnapshotRestore creates an empty file share to restore the snapshot of the file share to,
+// and returns the URL of the snapshot to copy the data from. The quota, protocol and access tier of the
+// new share are the same as the snapshot unless set in the share options. The data is not copied,
+// since the share snapshots can only be copied with the data plane APIs, e.g. by AzCopy.
+func (az *Cloud) CreateShareForSnapshotRestore(ctx context.Context, subsID, resourceGroup, accountName, shareName, snapshotTime string, shareOptions *fileclient.ShareOptions) (string, error) {
+	if snapshotTime == "" {
+		return "", fmt.Errorf("snapshot time is required to restore snapshot of share %s in account %s", shareName, accountName)
+	}
+	if shareOptions == nil || shareOptions.Name == "" {
+		return "", fmt.Errorf("name of the new share is required to restore snapshot %s of share %s", snapshotTime, shareName)
+	}
+
+	snapshot, err := az.FileClient.WithSubscriptionID(subsID).GetFileShare(ctx, resourceGroup, accountName, shareName, snapshotTime)
+	if err != nil {
+		return "", fmt.Errorf("failed to get snapshot %s of share %s in account %s: %w", snapshotTime, shareName, accountName, err)
+	}
+	restoreOptions := *shareOptions
+	if properties := snapshot.FileShareProperties; properties != nil {
+		if restoreOptions.RequestGiB == 0 {
+			restoreOptions.RequestGiB = int(pointer.Int32Deref(properties.ShareQuota, 0))
+		}
+		if restoreOptions.Protocol == "" {
+			restoreOptions.Protocol = properties.EnabledProtocols
+		}
+		if restoreOptions.AccessTier == "" {
+			restoreOptions.AccessTier = string(properties.AccessTier)
+		}
+	}
+	restoreOptions.Metadata = make(map[string]*string, len(shareOptions.Metadata)+1)
+	for k, v := range shareOptions.Metadata {
+		restoreOptions.Metadata[k] = v
+	}
+	restoreOptions.Metadata[FileShareRestoredFromMetadata] = pointer.String(fmt.Sprintf("%s@%s", shareName, snapshotTime))
+
+	if err := az.createFileShare(ctx, subsID, resourceGroup, accountName, &restoreOptions); err != nil {
+		return "", fmt.Errorf("failed to create share %s to restore snapshot %s of share %s: %w", restoreOptions.Name, snapshotTime, shareName, err)
+	}
+	az.addFileShareUsage(subsID, resourceGroup, accountName, restoreOptions.RequestGiB)
+	klog.V(2).Infof("created share %s in account %s to restore snapshot %s of share %s", restoreOptions.Name, accountName, snapshotTime, shareName)
+
+	sourceURL := url.URL{
+		Scheme:   "https",
+		Host:     fmt.Sprintf("%s.file.%s", accountName, az.Environment.StorageEndpointSuffix),
+		Path:     shareName,
+		RawQuery: url.Values{"sharesnapshot": []string{snapshotTime}}.Encode(),
+	}
+	return sourceURL.String(), nil
+}
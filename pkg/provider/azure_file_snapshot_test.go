@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/fileclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/fileclient/mockfileclient"
+)
+
+var testSnapshotTime = time.Date(2024, 5, 10, 17, 52, 33, 955186100, time.UTC)
+
+// fileShareSnapshot returns a snapshot of the share with the metadata.
+func fileShareSnapshot(name string, snapshotTime time.Time, metadata map[string]string) storage.FileShareItem {
+	properties := &storage.FileShareProperties{
+		SnapshotTime: &date.Time{Time: snapshotTime},
+		Metadata:     map[string]*string{},
+	}
+	for k, v := range metadata {
+		properties.Metadata[k] = pointer.String(v)
+	}
+	return storage.FileShareItem{Name: pointer.String(name), FileShareProperties: properties}
+}
+
+func TestCreateFileShareSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	mockFileClient := mockfileclient.NewMockInterface(ctrl)
+	cloud.FileClient = mockFileClient
+	mockFileClient.EXPECT().WithSubscriptionID("subs").Return(mockFileClient).AnyTimes()
+	mockFileClient.EXPECT().CreateFileShare(gomock.Any(), "rg", "account", gomock.Any(), "snapshots").DoAndReturn(
+		func(_ context.Context, _, _ string, shareOptions *fileclient.ShareOptions, _ string) (storage.FileShare, error) {
+			assert.Equal(t, "share", shareOptions.Name)
+			assert.Equal(t, pointer.String("azure"), shareOptions.Metadata[FileShareSnapshotCreatedByMetadata])
+			assert.Equal(t, pointer.String("backup"), shareOptions.Metadata[FileShareSnapshotOwnerMetadata])
+			assert.Equal(t, pointer.String("bar"), shareOptions.Metadata["foo"])
+			expiry, err := time.Parse(time.RFC3339, pointer.StringDeref(shareOptions.Metadata[FileShareSnapshotExpiresOnMetadata], ""))
+			assert.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), expiry, time.Minute)
+			return storage.FileShare{FileShareProperties: &storage.FileShareProperties{SnapshotTime: &date.Time{Time: testSnapshotTime}}}, nil
+		}).Times(1)
+
+	snapshotTime, err := cloud.CreateFileShareSnapshot(context.TODO(), "subs", "rg", "account", "share", &FileShareSnapshotOptions{
+		Owner:     "backup",
+		Retention: time.Hour,
+		Metadata:  map[string]string{"foo": "bar"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-05-10T17:52:33.9551861Z", snapshotTime)
+
+	mockFileClient.EXPECT().CreateFileShare(gomock.Any(), "rg", "account", gomock.Any(), "snapshots").Return(storage.FileShare{}, errors.New("create error")).Times(1)
+	_, err = cloud.CreateFileShareSnapshot(context.TODO(), "subs", "rg", "account", "share", nil)
+	assert.EqualError(t, err, "failed to create snapshot of share share in account account: create error")
+}
+
+func TestListAndPruneFileShareSnapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	unexpired := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	items := []storage.FileShareItem{
+		{Name: pointer.String("share"), FileShareProperties: &storage.FileShareProperties{}},
+		fileShareSnapshot("share", testSnapshotTime, map[string]string{FileShareSnapshotCreatedByMetadata: "azure", FileShareSnapshotOwnerMetadata: "backup", FileShareSnapshotExpiresOnMetadata: expired}),
+		fileShareSnapshot("share", testSnapshotTime.Add(time.Second), map[string]string{FileShareSnapshotCreatedByMetadata: "azure", FileShareSnapshotOwnerMetadata: "backup", FileShareSnapshotExpiresOnMetadata: unexpired}),
+		fileShareSnapshot("share", testSnapshotTime.Add(2*time.Second), map[string]string{FileShareSnapshotCreatedByMetadata: "azure", FileShareSnapshotOwnerMetadata: "backup"}),
+		fileShareSnapshot("share", testSnapshotTime.Add(3*time.Second), map[string]string{FileShareSnapshotCreatedByMetadata: "azure", FileShareSnapshotOwnerMetadata: "other", FileShareSnapshotExpiresOnMetadata: expired}),
+		fileShareSnapshot("share", testSnapshotTime.Add(4*time.Second), map[string]string{FileShareSnapshotExpiresOnMetadata: expired}),
+		fileShareSnapshot("share2", testSnapshotTime, map[string]string{FileShareSnapshotCreatedByMetadata: "azure", FileShareSnapshotOwnerMetadata: "backup", FileShareSnapshotExpiresOnMetadata: expired}),
+	}
+
+	mockFileClient := mockfileclient.NewMockInterface(ctrl)
+	cloud.FileClient = mockFileClient
+	mockFileClient.EXPECT().WithSubscriptionID("subs").Return(mockFileClient).AnyTimes()
+	mockFileClient.EXPECT().ListFileShare(gomock.Any(), "rg", "account", "share", "snapshots").Return(items, nil).Times(3)
+	mockFileClient.EXPECT().DeleteFileShare(gomock.Any(), "rg", "account", "share", "2024-05-10T17:52:33.9551861Z").Return(nil).Times(1)
+
+	snapshots, err := cloud.ListFileShareSnapshots(context.TODO(), "subs", "rg", "account", "share", "")
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 4)
+
+	snapshots, err = cloud.ListFileShareSnapshots(context.TODO(), "subs", "rg", "account", "share", "backup")
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 3)
+
+	pruned, err := cloud.PruneFileShareSnapshots(context.TODO(), "subs", "rg", "account", "share", "backup")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-05-10T17:52:33.9551861Z"}, pruned)
+}
+
+func TestDeleteFileShareSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	mockFileClient := mockfileclient.NewMockInterface(ctrl)
+	cloud.FileClient = mockFileClient
+	mockFileClient.EXPECT().WithSubscriptionID("subs").Return(mockFileClient).AnyTimes()
+	mockFileClient.EXPECT().DeleteFileShare(gomock.Any(), "rg", "account", "share", "2024-05-10T17:52:33.9551861Z").Return(nil).Times(1)
+
+	assert.NoError(t, cloud.DeleteFileShareSnapshot(context.TODO(), "subs", "rg", "account", "share", "2024-05-10T17:52:33.9551861Z"))
+	assert.EqualError(t, cloud.DeleteFileShareSnapshot(context.TODO(), "subs", "rg", "account", "share", ""),
+		"snapshot time is required to delete snapshot of share share in account account")
+}
+
+func TestCreateShareForSnapshotRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+	cloud.Environment.StorageEndpointSuffix = "core.windows.net"
+
+	snapshotTime := "2024-05-10T17:52:33.9551861Z"
+	mockFileClient := mockfileclient.NewMockInterface(ctrl)
+	cloud.FileClient = mockFileClient
+	mockFileClient.EXPECT().WithSubscriptionID("subs").Return(mockFileClient).AnyTimes()
+	mockFileClient.EXPECT().GetFileShare(gomock.Any(), "rg", "account", "share", snapshotTime).Return(storage.FileShare{
+		FileShareProperties: &storage.FileShareProperties{
+			ShareQuota:       pointer.Int32(100),
+			EnabledProtocols: storage.EnabledProtocolsNFS,
+			AccessTier:       storage.ShareAccessTierPremium,
+		},
+	}, nil).Times(1)
+	mockFileClient.EXPECT().CreateFileShare(gomock.Any(), "rg", "account", gomock.Any(), "").DoAndReturn(
+		func(_ context.Context, _, _ string, shareOptions *fileclient.ShareOptions, _ string) (storage.FileShare, error) {
+			assert.Equal(t, "restored", shareOptions.Name)
+			assert.Equal(t, 200, shareOptions.RequestGiB)
+			assert.Equal(t, storage.EnabledProtocolsNFS, shareOptions.Protocol)
+			assert.Equal(t, string(storage.ShareAccessTierPremium), shareOptions.AccessTier)
+			assert.Equal(t, pointer.String("share@"+snapshotTime), shareOptions.Metadata[FileShareRestoredFromMetadata])
+			return storage.FileShare{}, nil
+		}).Times(1)
+
+	sourceURL, err := cloud.CreateShareForSnapshotRestore(context.TODO(), "subs", "rg", "account", "share", snapshotTime, &fileclient.ShareOptions{Name: "restored", RequestGiB: 200})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://account.file.core.windows.net/share?sharesnapshot=2024-05-10T17%3A52%3A33.9551861Z", sourceURL)
+
+	_, err = cloud.CreateShareForSnapshotRestore(context.TODO(), "subs", "rg", "account", "share", snapshotTime, nil)
+	assert.EqualError(t, err, "name of the new share is required to restore snapshot 2024-05-10T17:52:33.9551861Z of share share")
+}
@@ -39,8 +39,8 @@ import (
 )
 
 // DeleteLB invokes az.LoadBalancerClient.Delete with exponential backoff retry
-func (az *Cloud) DeleteLB(service *v1.Service, lbName string) *retry.Error {
-	ctx, cancelFunc := context.WithTimeout(context.Background(), 60*time.Second)
+func (az *Cloud) DeleteLB(ctx context.Context, service *v1.Service, lbName string) *retry.Error {
+	ctx, cancelFunc := context.WithTimeout(ctx, 60*time.Second)
 	defer cancelFunc()
 	rgName := az.getLoadBalancerResourceGroup()
 	rerr := az.LoadBalancerClient.Delete(ctx, rgName, lbName)
@@ -56,8 +56,8 @@ func (az *Cloud) DeleteLB(service *v1.Service, lbName string) *retry.Error {
 }
 
 // ListLB invokes az.LoadBalancerClient.List with exponential backoff retry
-func (az *Cloud) ListLB(service *v1.Service) ([]network.LoadBalancer, error) {
-	ctx, cancelFunc := context.WithTimeout(context.Background(), 60*time.Second)
+func (az *Cloud) ListLB(ctx context.Context, service *v1.Service) ([]network.LoadBalancer, error) {
+	ctx, cancelFunc := context.WithTimeout(ctx, 60*time.Second)
 	defer cancelFunc()
 	rgName := az.getLoadBalancerResourceGroup()
 	allLBs, rerr := az.LoadBalancerClient.List(ctx, rgName)
@@ -75,8 +75,8 @@ func (az *Cloud) ListLB(service *v1.Service) ([]network.LoadBalancer, error) {
 
 // ListManagedLBs invokes az.LoadBalancerClient.List and filter out
 // those that are not managed by cloud provider azure or not associated to a managed VMSet.
-func (az *Cloud) ListManagedLBs(service *v1.Service, nodes []*v1.Node, clusterName string) (*[]network.LoadBalancer, error) {
-	allLBs, err := az.ListLB(service)
+func (az *Cloud) ListManagedLBs(ctx context.Context, service *v1.Service, nodes []*v1.Node, clusterName string) (*[]network.LoadBalancer, error) {
+	allLBs, err := az.ListLB(ctx, service)
 	if err != nil {
 		return nil, err
 	}
@@ -130,8 +130,8 @@ func (az *Cloud) ListManagedLBs(service *v1.Service, nodes []*v1.Node, clusterNa
 }
 
 // CreateOrUpdateLB invokes az.LoadBalancerClient.CreateOrUpdate with exponential backoff retry
-func (az *Cloud) CreateOrUpdateLB(service *v1.Service, lb network.LoadBalancer) error {
-	ctx, cancelFunc := context.WithTimeout(context.Background(), 60*time.Second)
+func (az *Cloud) CreateOrUpdateLB(ctx context.Context, service *v1.Service, lb network.LoadBalancer) error {
+	ctx, cancelFunc := context.WithTimeout(ctx, 60*time.Second)
 	defer cancelFunc()
 	lb = cleanupSubnetInFrontendIPConfigurations(&lb)
 
@@ -175,7 +175,7 @@ func (az *Cloud) CreateOrUpdateLB(service *v1.Service, lb network.LoadBalancer)
 			return rerr.Error()
 		}
 		// Perform a dummy update to fix the provisioning state
-		err = az.CreateOrUpdatePIP(service, pipRG, pip)
+		err = az.CreateOrUpdatePIP(ctx, service, pipRG, pip)
 		if err != nil {
 			klog.Errorf("Failed to update the public IP %s in resource group %s: %v", pipName, pipRG, err)
 			return rerr.Error()
@@ -188,8 +188,8 @@ func (az *Cloud) CreateOrUpdateLB(service *v1.Service, lb network.LoadBalancer)
 	return rerr.Error()
 }
 
-func (az *Cloud) CreateOrUpdateLBBackendPool(lbName string, backendPool network.BackendAddressPool) error {
-	ctx, cancelFunc := context.WithTimeout(context.Background(), 60*time.Second)
+func (az *Cloud) CreateOrUpdateLBBackendPool(ctx context.Context, lbName string, backendPool network.BackendAddressPool) error {
+	ctx, cancelFunc := context.WithTimeout(ctx, 60*time.Second)
 	defer cancelFunc()
 	klog.V(4).Infof("CreateOrUpdateLBBackendPool: updating backend pool %s in LB %s", pointer.StringDeref(backendPool.Name, ""), lbName)
 	rerr := az.LoadBalancerClient.CreateOrUpdateBackendPools(ctx, az.getLoadBalancerResourceGroup(), lbName, pointer.StringDeref(backendPool.Name, ""), backendPool, pointer.StringDeref(backendPool.Etag, ""))
@@ -215,8 +215,8 @@ func (az *Cloud) CreateOrUpdateLBBackendPool(lbName string, backendPool network.
 	return rerr.Error()
 }
 
-func (az *Cloud) DeleteLBBackendPool(lbName, backendPoolName string) error {
-	ctx, cancelFunc := context.WithTimeout(context.Background(), 60*time.Second)
+func (az *Cloud) DeleteLBBackendPool(ctx context.Context, lbName, backendPoolName string) error {
+	ctx, cancelFunc := context.WithTimeout(ctx, 60*time.Second)
 	defer cancelFunc()
 	klog.V(4).Infof("DeleteLBBackendPool: deleting backend pool %s in LB %s", backendPoolName, lbName)
 	rerr := az.LoadBalancerClient.DeleteLBBackendPool(ctx, az.getLoadBalancerResourceGroup(), lbName, backendPoolName)
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -43,7 +44,7 @@ func TestDeleteLB(t *testing.T) {
 	mockLBClient := az.LoadBalancerClient.(*mockloadbalancerclient.MockInterface)
 	mockLBClient.EXPECT().Delete(gomock.Any(), az.ResourceGroup, "lb").Return(&retry.Error{HTTPStatusCode: http.StatusInternalServerError})
 
-	err := az.DeleteLB(&v1.Service{}, "lb")
+	err := az.DeleteLB(context.TODO(), &v1.Service{}, "lb")
 	assert.EqualError(t, fmt.Errorf("Retriable: false, RetryAfter: 0s, HTTPStatusCode: 500, RawError: %w", error(nil)), fmt.Sprintf("%s", err.Error()))
 }
 
@@ -118,7 +119,7 @@ func TestListManagedLBs(t *testing.T) {
 		mockVMSet.EXPECT().GetPrimaryVMSetName().Return("vmas-0").AnyTimes()
 		az.VMSet = mockVMSet
 
-		lbs, err := az.ListManagedLBs(&v1.Service{}, []*v1.Node{}, "kubernetes")
+		lbs, err := az.ListManagedLBs(context.TODO(), &v1.Service{}, []*v1.Node{}, "kubernetes")
 		assert.Equal(t, test.expectedErr, err)
 		assert.Equal(t, test.expectedLBs, lbs)
 	}
@@ -165,7 +166,7 @@ func TestCreateOrUpdateLB(t *testing.T) {
 			},
 		}}, nil).MaxTimes(2)
 
-		err := az.CreateOrUpdateLB(&v1.Service{}, network.LoadBalancer{
+		err := az.CreateOrUpdateLB(context.TODO(), &v1.Service{}, network.LoadBalancer{
 			Name: pointer.String("lb"),
 			Etag: pointer.String("etag"),
 		})
@@ -209,7 +210,7 @@ func TestCreateOrUpdateLBBackendPool(t *testing.T) {
 		lbClient.EXPECT().CreateOrUpdateBackendPools(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(tc.createOrUpdateErr)
 		az.LoadBalancerClient = lbClient
 
-		err := az.CreateOrUpdateLBBackendPool("kubernetes", network.BackendAddressPool{})
+		err := az.CreateOrUpdateLBBackendPool(context.TODO(), "kubernetes", network.BackendAddressPool{})
 		assert.Equal(t, tc.expectedErr, err != nil)
 	}
 }
@@ -240,7 +241,7 @@ func TestDeleteLBBackendPool(t *testing.T) {
 		lbClient.EXPECT().DeleteLBBackendPool(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(tc.deleteErr)
 		az.LoadBalancerClient = lbClient
 
-		err := az.DeleteLBBackendPool("kubernetes", "kubernetes")
+		err := az.DeleteLBBackendPool(context.TODO(), "kubernetes", "kubernetes")
 		assert.Equal(t, tc.expectedErr, err != nil)
 	}
 }
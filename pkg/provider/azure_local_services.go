@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2022-07-01/network"
+	"go.opentelemetry.io/otel/attribute"
 
 	v1 "k8s.io/api/core/v1"
 	discovery_v1 "k8s.io/api/discovery/v1"
@@ -38,6 +39,7 @@ import (
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/retry"
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace"
 	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
 )
 
@@ -65,6 +67,8 @@ type loadBalancerBackendPoolUpdateOperation struct {
 	backendPoolName  string
 	kind             consts.LoadBalancerBackendPoolUpdateOperation
 	nodeIPs          []string
+	// link to the span requesting the operation
+	link trace.Link
 }
 
 func (op *loadBalancerBackendPoolUpdateOperation) wait() batchOperationResult {
@@ -100,25 +104,27 @@ func (updater *loadBalancerBackendPoolUpdater) run(ctx context.Context) {
 
 // getAddIPsToBackendPoolOperation creates a new loadBalancerBackendPoolUpdateOperation
 // that adds nodeIPs to the backend pool.
-func getAddIPsToBackendPoolOperation(serviceName, loadBalancerName, backendPoolName string, nodeIPs []string) *loadBalancerBackendPoolUpdateOperation {
+func getAddIPsToBackendPoolOperation(ctx context.Context, serviceName, loadBalancerName, backendPoolName string, nodeIPs []string) *loadBalancerBackendPoolUpdateOperation {
 	return &loadBalancerBackendPoolUpdateOperation{
 		serviceName:      serviceName,
 		loadBalancerName: loadBalancerName,
 		backendPoolName:  backendPoolName,
 		kind:             consts.LoadBalancerBackendPoolUpdateOperationAdd,
 		nodeIPs:          nodeIPs,
+		link:             trace.LinkFromContext(ctx),
 	}
 }
 
 // getRemoveIPsFromBackendPoolOperation creates a new loadBalancerBackendPoolUpdateOperation
 // that removes nodeIPs from the backend pool.
-func getRemoveIPsFromBackendPoolOperation(serviceName, loadBalancerName, backendPoolName string, nodeIPs []string) *loadBalancerBackendPoolUpdateOperation {
+func getRemoveIPsFromBackendPoolOperation(ctx context.Context, serviceName, loadBalancerName, backendPoolName string, nodeIPs []string) *loadBalancerBackendPoolUpdateOperation {
 	return &loadBalancerBackendPoolUpdateOperation{
 		serviceName:      serviceName,
 		loadBalancerName: loadBalancerName,
 		backendPoolName:  backendPoolName,
 		kind:             consts.LoadBalancerBackendPoolUpdateOperationRemove,
 		nodeIPs:          nodeIPs,
+		link:             trace.LinkFromContext(ctx),
 	}
 }
 
@@ -163,6 +169,9 @@ func (updater *loadBalancerBackendPoolUpdater) removeOperation(serviceName strin
 // if it is retriable, otherwise all operations in the batch targeting to
 // this backend pool will fail.
 func (updater *loadBalancerBackendPoolUpdater) process() {
+	// the reload lock is taken before the updater lock, which the controllers take while holding the reload lock
+	updater.az.reloadLock.RLock()
+	defer updater.az.reloadLock.RUnlock()
 	updater.lock.Lock()
 	defer updater.lock.Unlock()
 
@@ -199,8 +208,18 @@ func (updater *loadBalancerBackendPoolUpdater) process() {
 		parts := strings.Split(key, ":")
 		lbName, poolName := parts[0], parts[1]
 		operationName := fmt.Sprintf("%s/%s", lbName, poolName)
-		bp, rerr := updater.az.LoadBalancerClient.GetLBBackendPool(context.Background(), updater.az.ResourceGroup, lbName, poolName, "")
+		links := make([]trace.Link, 0, len(ops))
+		for _, op := range ops {
+			links = append(links, op.(*loadBalancerBackendPoolUpdateOperation).link)
+		}
+		ctx, span := trace.StartBatchSpan(context.Background(), "loadBalancerBackendPoolUpdater.process", links,
+			attribute.Int("batch_size", len(ops)),
+			trace.LoadBalancerNameKey.String(lbName),
+			trace.BackendPoolNameKey.String(poolName),
+		)
+		bp, rerr := updater.az.LoadBalancerClient.GetLBBackendPool(ctx, updater.az.ResourceGroup, lbName, poolName, "")
 		if rerr != nil {
+			trace.EndSpan(span, rerr.Error())
 			updater.processError(rerr, operationName, ops...)
 			continue
 		}
@@ -223,12 +242,14 @@ func (updater *loadBalancerBackendPoolUpdater) process() {
 		// but the backend pool object is not changed after multiple times of removal and re-adding.
 		if changed {
 			klog.V(2).Infof("loadBalancerBackendPoolUpdater.process: updating backend pool %s/%s", lbName, poolName)
-			rerr = updater.az.LoadBalancerClient.CreateOrUpdateBackendPools(context.Background(), updater.az.ResourceGroup, lbName, poolName, bp, pointer.StringDeref(bp.Etag, ""))
+			rerr = updater.az.LoadBalancerClient.CreateOrUpdateBackendPools(ctx, updater.az.ResourceGroup, lbName, poolName, bp, pointer.StringDeref(bp.Etag, ""))
 			if rerr != nil {
+				trace.EndSpan(span, rerr.Error())
 				updater.processError(rerr, operationName, ops...)
 				continue
 			}
 		}
+		trace.EndSpan(span, nil)
 		updater.notify(newBatchOperationResult(operationName, true, nil), ops...)
 	}
 }
@@ -297,6 +318,8 @@ func (az *Cloud) setUpEndpointSlicesInformer(informerFactory informers.SharedInf
 				az.endpointSlicesCache.Store(strings.ToLower(fmt.Sprintf("%s/%s", es.Namespace, es.Name)), es)
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
+				az.reloadLock.RLock()
+				defer az.reloadLock.RUnlock()
 				previousES := oldObj.(*discovery_v1.EndpointSlice)
 				newES := newObj.(*discovery_v1.EndpointSlice)
 
@@ -353,7 +376,7 @@ func (az *Cloud) setUpEndpointSlicesInformer(informerFactory informers.SharedInf
 					for _, bpName := range bpNames {
 						currentIPsInBackendPools[bpName] = previousIPs
 					}
-					az.applyIPChangesAmongLocalServiceBackendPoolsByIPFamily(lbName, key, currentIPsInBackendPools, currentIPs)
+					az.applyIPChangesAmongLocalServiceBackendPoolsByIPFamily(context.Background(), lbName, key, currentIPsInBackendPools, currentIPs)
 				}
 			},
 			DeleteFunc: func(obj interface{}) {
@@ -512,6 +535,7 @@ func (az *Cloud) getLocalServiceEndpointsNodeNames(service *v1.Service) (*utilse
 // cleanupLocalServiceBackendPool cleans up the backend pool of
 // a local service among given load balancers.
 func (az *Cloud) cleanupLocalServiceBackendPool(
+	ctx context.Context,
 	svc *v1.Service,
 	nodes []*v1.Node,
 	lbs *[]network.LoadBalancer,
@@ -525,7 +549,7 @@ func (az *Cloud) cleanupLocalServiceBackendPool(
 				for _, bp := range *lb.BackendAddressPools {
 					bpName := pointer.StringDeref(bp.Name, "")
 					if localServiceOwnsBackendPool(getServiceName(svc), bpName) {
-						if err := az.DeleteLBBackendPool(lbName, bpName); err != nil {
+						if err := az.DeleteLBBackendPool(ctx, lbName, bpName); err != nil {
 							return nil, err
 						}
 						changed = true
@@ -537,7 +561,7 @@ func (az *Cloud) cleanupLocalServiceBackendPool(
 	if changed {
 		// Refresh the list of existing LBs after cleanup to update etags for the LBs.
 		klog.V(4).Info("Refreshing the list of existing LBs")
-		lbs, err = az.ListManagedLBs(svc, nodes, clusterName)
+		lbs, err = az.ListManagedLBs(ctx, svc, nodes, clusterName)
 		if err != nil {
 			return nil, fmt.Errorf("reconcileLoadBalancer: failed to list managed LB: %w", err)
 		}
@@ -547,7 +571,7 @@ func (az *Cloud) cleanupLocalServiceBackendPool(
 
 // checkAndApplyLocalServiceBackendPoolUpdates if the IPs in the backend pool are aligned
 // with the corresponding endpointslice, and update the backend pool if necessary.
-func (az *Cloud) checkAndApplyLocalServiceBackendPoolUpdates(lb network.LoadBalancer, service *v1.Service) error {
+func (az *Cloud) checkAndApplyLocalServiceBackendPoolUpdates(ctx context.Context, lb network.LoadBalancer, service *v1.Service) error {
 	serviceName := getServiceName(service)
 	endpointsNodeNames, err := az.getLocalServiceEndpointsNodeNames(service)
 	if err != nil {
@@ -569,7 +593,7 @@ func (az *Cloud) checkAndApplyLocalServiceBackendPoolUpdates(lb network.LoadBala
 			currentIPsInBackendPools[bpName] = currentIPs
 		}
 	}
-	az.applyIPChangesAmongLocalServiceBackendPoolsByIPFamily(*lb.Name, serviceName, currentIPsInBackendPools, expectedIPs)
+	az.applyIPChangesAmongLocalServiceBackendPoolsByIPFamily(ctx, *lb.Name, serviceName, currentIPsInBackendPools, expectedIPs)
 
 	return nil
 }
@@ -577,6 +601,7 @@ func (az *Cloud) checkAndApplyLocalServiceBackendPoolUpdates(lb network.LoadBala
 // applyIPChangesAmongLocalServiceBackendPoolsByIPFamily reconciles IPs by IP family
 // amone the backend pools of a local service.
 func (az *Cloud) applyIPChangesAmongLocalServiceBackendPoolsByIPFamily(
+	ctx context.Context,
 	lbName, serviceName string,
 	currentIPsInBackendPools map[string][]string,
 	expectedIPs []string,
@@ -599,12 +624,13 @@ func (az *Cloud) applyIPChangesAmongLocalServiceBackendPoolsByIPFamily(
 			ipv4 = append(ipv4, ip)
 		}
 	}
-	az.reconcileIPsInLocalServiceBackendPoolsAsync(lbName, serviceName, currentIPsInBackendPoolsIPv6, ipv6)
-	az.reconcileIPsInLocalServiceBackendPoolsAsync(lbName, serviceName, currentIPsInBackendPoolsIPv4, ipv4)
+	az.reconcileIPsInLocalServiceBackendPoolsAsync(ctx, lbName, serviceName, currentIPsInBackendPoolsIPv6, ipv6)
+	az.reconcileIPsInLocalServiceBackendPoolsAsync(ctx, lbName, serviceName, currentIPsInBackendPoolsIPv4, ipv4)
 }
 
 // reconcileIPsInLocalServiceBackendPoolsAsync reconciles IPs in the backend pools of a local service.
 func (az *Cloud) reconcileIPsInLocalServiceBackendPoolsAsync(
+	ctx context.Context,
 	lbName, serviceName string,
 	currentIPsInBackendPools map[string][]string,
 	expectedIPs []string,
@@ -616,10 +642,10 @@ func (az *Cloud) reconcileIPsInLocalServiceBackendPoolsAsync(
 			return
 		}
 		if len(ipsToBeDeleted) > 0 {
-			az.backendPoolUpdater.addOperation(getRemoveIPsFromBackendPoolOperation(serviceName, lbName, bpName, ipsToBeDeleted))
+			az.backendPoolUpdater.addOperation(getRemoveIPsFromBackendPoolOperation(ctx, serviceName, lbName, bpName, ipsToBeDeleted))
 		}
 		if len(expectedIPs) > 0 {
-			az.backendPoolUpdater.addOperation(getAddIPsToBackendPoolOperation(serviceName, lbName, bpName, expectedIPs))
+			az.backendPoolUpdater.addOperation(getAddIPsToBackendPoolOperation(ctx, serviceName, lbName, bpName, expectedIPs))
 		}
 	}
 }
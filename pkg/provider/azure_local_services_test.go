@@ -48,9 +48,9 @@ func TestLoadBalancerBackendPoolUpdater(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	addOperationPool1 := getAddIPsToBackendPoolOperation("ns1/svc1", "lb1", "pool1", []string{"10.0.0.1", "10.0.0.2"})
-	removeOperationPool1 := getRemoveIPsFromBackendPoolOperation("ns1/svc1", "lb1", "pool1", []string{"10.0.0.1", "10.0.0.2"})
-	addOperationPool2 := getAddIPsToBackendPoolOperation("ns1/svc1", "lb1", "pool2", []string{"10.0.0.1", "10.0.0.2"})
+	addOperationPool1 := getAddIPsToBackendPoolOperation(context.TODO(), "ns1/svc1", "lb1", "pool1", []string{"10.0.0.1", "10.0.0.2"})
+	removeOperationPool1 := getRemoveIPsFromBackendPoolOperation(context.TODO(), "ns1/svc1", "lb1", "pool1", []string{"10.0.0.1", "10.0.0.2"})
+	addOperationPool2 := getAddIPsToBackendPoolOperation(context.TODO(), "ns1/svc1", "lb1", "pool2", []string{"10.0.0.1", "10.0.0.2"})
 
 	testCases := []struct {
 		name                               string
@@ -233,7 +233,7 @@ func TestLoadBalancerBackendPoolUpdaterFailed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	addOperationPool1 := getAddIPsToBackendPoolOperation("ns1/svc1", "lb1", "pool1", []string{"10.0.0.1", "10.0.0.2"})
+	addOperationPool1 := getAddIPsToBackendPoolOperation(context.TODO(), "ns1/svc1", "lb1", "pool1", []string{"10.0.0.1", "10.0.0.2"})
 
 	testCases := []struct {
 		name                               string
@@ -611,7 +611,7 @@ func TestCheckAndApplyLocalServiceBackendPoolUpdates(t *testing.T) {
 			cloud.backendPoolUpdater = u
 			go cloud.backendPoolUpdater.run(ctx)
 
-			err := cloud.checkAndApplyLocalServiceBackendPoolUpdates(existingLB, &svc)
+			err := cloud.checkAndApplyLocalServiceBackendPoolUpdates(context.TODO(), existingLB, &svc)
 			if tc.expectedErr != nil {
 				assert.Equal(t, tc.expectedErr, err)
 			} else {
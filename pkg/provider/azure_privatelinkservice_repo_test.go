@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -60,7 +61,7 @@ func TestCreateOrUpdatePLS(t *testing.T) {
 		mockPLSClient.EXPECT().CreateOrUpdate(gomock.Any(), "rg", gomock.Any(), gomock.Any(), gomock.Any()).Return(test.clientErr)
 		mockPLSClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]network.PrivateLinkService{}, nil)
 
-		err := az.CreateOrUpdatePLS(&v1.Service{}, "rg", network.PrivateLinkService{
+		err := az.CreateOrUpdatePLS(context.TODO(), &v1.Service{}, "rg", network.PrivateLinkService{
 			Name: pointer.String("pls"),
 			Etag: pointer.String("etag"),
 			PrivateLinkServiceProperties: &network.PrivateLinkServiceProperties{
@@ -88,7 +89,7 @@ func TestDeletePLS(t *testing.T) {
 	mockPLSClient := az.PrivateLinkServiceClient.(*mockprivatelinkserviceclient.MockInterface)
 	mockPLSClient.EXPECT().Delete(gomock.Any(), "rg", "pls").Return(&retry.Error{HTTPStatusCode: http.StatusInternalServerError})
 
-	err := az.DeletePLS(&v1.Service{}, "rg", "pls", "frontendID")
+	err := az.DeletePLS(context.TODO(), &v1.Service{}, "rg", "pls", "frontendID")
 	assert.EqualError(t, fmt.Errorf("Retriable: false, RetryAfter: 0s, HTTPStatusCode: 500, RawError: %w", error(nil)), fmt.Sprintf("%s", err.Error()))
 }
 
@@ -100,7 +101,7 @@ func TestDeletePEConn(t *testing.T) {
 	mockPLSClient := az.PrivateLinkServiceClient.(*mockprivatelinkserviceclient.MockInterface)
 	mockPLSClient.EXPECT().DeletePEConnection(gomock.Any(), "rg", "pls", "peConn").Return(&retry.Error{HTTPStatusCode: http.StatusInternalServerError})
 
-	err := az.DeletePEConn(&v1.Service{}, "rg", "pls", "peConn")
+	err := az.DeletePEConn(context.TODO(), &v1.Service{}, "rg", "pls", "peConn")
 	assert.EqualError(t, fmt.Errorf("Retriable: false, RetryAfter: 0s, HTTPStatusCode: 500, RawError: %w", error(nil)), fmt.Sprintf("%s", err.Error()))
 }
 
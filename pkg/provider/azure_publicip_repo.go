@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -36,8 +37,8 @@ import (
 )
 
 // CreateOrUpdatePIP invokes az.PublicIPAddressesClient.CreateOrUpdate with exponential backoff retry
-func (az *Cloud) CreateOrUpdatePIP(service *v1.Service, pipResourceGroup string, pip network.PublicIPAddress) error {
-	ctx, cancel := getContextWithCancel()
+func (az *Cloud) CreateOrUpdatePIP(ctx context.Context, service *v1.Service, pipResourceGroup string, pip network.PublicIPAddress) error {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	rerr := az.PublicIPAddressesClient.CreateOrUpdate(ctx, pipResourceGroup, pointer.StringDeref(pip.Name, ""), pip)
@@ -69,8 +70,8 @@ func (az *Cloud) CreateOrUpdatePIP(service *v1.Service, pipResourceGroup string,
 }
 
 // DeletePublicIP invokes az.PublicIPAddressesClient.Delete with exponential backoff retry
-func (az *Cloud) DeletePublicIP(service *v1.Service, pipResourceGroup string, pipName string) error {
-	ctx, cancel := getContextWithCancel()
+func (az *Cloud) DeletePublicIP(ctx context.Context, service *v1.Service, pipResourceGroup string, pipName string) error {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	rerr := az.PublicIPAddressesClient.Delete(ctx, pipResourceGroup, pipName)
@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cloudprovider "k8s.io/cloud-provider"
+)
+
+// reloadLockedCloud implements the interfaces the controllers get from the cloud. Each call holds the reload
+// lock of the cloud for reading, so that ReloadConfig never re-initializes the cloud in the middle of a call.
+// The calls between the methods of the cloud do not go through it, the read lock is never taken recursively.
+type reloadLockedCloud struct {
+	az *Cloud
+}
+
+var (
+	_ cloudprovider.LoadBalancer = &reloadLockedCloud{}
+	_ cloudprovider.Instances    = &reloadLockedCloud{}
+	_ cloudprovider.InstancesV2  = &reloadLockedCloud{}
+	_ cloudprovider.Zones        = &reloadLockedCloud{}
+	_ cloudprovider.Routes       = &reloadLockedCloud{}
+)
+
+// GetLoadBalancer implements cloudprovider.LoadBalancer.
+func (c *reloadLockedCloud) GetLoadBalancer(ctx context.Context, clusterName string, service *v1.Service) (*v1.LoadBalancerStatus, bool, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.GetLoadBalancer(ctx, clusterName, service)
+}
+
+// GetLoadBalancerName implements cloudprovider.LoadBalancer.
+func (c *reloadLockedCloud) GetLoadBalancerName(ctx context.Context, clusterName string, service *v1.Service) string {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.GetLoadBalancerName(ctx, clusterName, service)
+}
+
+// EnsureLoadBalancer implements cloudprovider.LoadBalancer.
+func (c *reloadLockedCloud) EnsureLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) (*v1.LoadBalancerStatus, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.EnsureLoadBalancer(ctx, clusterName, service, nodes)
+}
+
+// UpdateLoadBalancer implements cloudprovider.LoadBalancer.
+func (c *reloadLockedCloud) UpdateLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) error {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.UpdateLoadBalancer(ctx, clusterName, service, nodes)
+}
+
+// EnsureLoadBalancerDeleted implements cloudprovider.LoadBalancer.
+func (c *reloadLockedCloud) EnsureLoadBalancerDeleted(ctx context.Context, clusterName string, service *v1.Service) error {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.EnsureLoadBalancerDeleted(ctx, clusterName, service)
+}
+
+// NodeAddresses implements cloudprovider.Instances.
+func (c *reloadLockedCloud) NodeAddresses(ctx context.Context, name types.NodeName) ([]v1.NodeAddress, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.NodeAddresses(ctx, name)
+}
+
+// NodeAddressesByProviderID implements cloudprovider.Instances.
+func (c *reloadLockedCloud) NodeAddressesByProviderID(ctx context.Context, providerID string) ([]v1.NodeAddress, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.NodeAddressesByProviderID(ctx, providerID)
+}
+
+// InstanceID implements cloudprovider.Instances.
+func (c *reloadLockedCloud) InstanceID(ctx context.Context, nodeName types.NodeName) (string, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.InstanceID(ctx, nodeName)
+}
+
+// InstanceType implements cloudprovider.Instances.
+func (c *reloadLockedCloud) InstanceType(ctx context.Context, name types.NodeName) (string, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.InstanceType(ctx, name)
+}
+
+// InstanceTypeByProviderID implements cloudprovider.Instances.
+func (c *reloadLockedCloud) InstanceTypeByProviderID(ctx context.Context, providerID string) (string, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.InstanceTypeByProviderID(ctx, providerID)
+}
+
+// AddSSHKeyToAllInstances implements cloudprovider.Instances.
+func (c *reloadLockedCloud) AddSSHKeyToAllInstances(ctx context.Context, user string, keyData []byte) error {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.AddSSHKeyToAllInstances(ctx, user, keyData)
+}
+
+// CurrentNodeName implements cloudprovider.Instances.
+func (c *reloadLockedCloud) CurrentNodeName(ctx context.Context, hostname string) (types.NodeName, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.CurrentNodeName(ctx, hostname)
+}
+
+// InstanceExistsByProviderID implements cloudprovider.Instances.
+func (c *reloadLockedCloud) InstanceExistsByProviderID(ctx context.Context, providerID string) (bool, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.InstanceExistsByProviderID(ctx, providerID)
+}
+
+// InstanceShutdownByProviderID implements cloudprovider.Instances.
+func (c *reloadLockedCloud) InstanceShutdownByProviderID(ctx context.Context, providerID string) (bool, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.InstanceShutdownByProviderID(ctx, providerID)
+}
+
+// InstanceExists implements cloudprovider.InstancesV2.
+func (c *reloadLockedCloud) InstanceExists(ctx context.Context, node *v1.Node) (bool, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.InstanceExists(ctx, node)
+}
+
+// InstanceShutdown implements cloudprovider.InstancesV2.
+func (c *reloadLockedCloud) InstanceShutdown(ctx context.Context, node *v1.Node) (bool, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.InstanceShutdown(ctx, node)
+}
+
+// InstanceMetadata implements cloudprovider.InstancesV2.
+func (c *reloadLockedCloud) InstanceMetadata(ctx context.Context, node *v1.Node) (*cloudprovider.InstanceMetadata, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.InstanceMetadata(ctx, node)
+}
+
+// GetZone implements cloudprovider.Zones.
+func (c *reloadLockedCloud) GetZone(ctx context.Context) (cloudprovider.Zone, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.GetZone(ctx)
+}
+
+// GetZoneByProviderID implements cloudprovider.Zones.
+func (c *reloadLockedCloud) GetZoneByProviderID(ctx context.Context, providerID string) (cloudprovider.Zone, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.GetZoneByProviderID(ctx, providerID)
+}
+
+// GetZoneByNodeName implements cloudprovider.Zones.
+func (c *reloadLockedCloud) GetZoneByNodeName(ctx context.Context, nodeName types.NodeName) (cloudprovider.Zone, error) {
+	c.az.reloadLock.RLock()
+	defer c.az.reloadLock.RUnlock()
+	return c.az.GetZoneByNodeName(ctx, nodeName)
+}
+
+// ListRoutes implements cloudprovider.Routes. The route methods of the cloud take the lock themselves, they
+// release it while waiting for the delayed route updater, which takes it for each batch.
+func (c *reloadLockedCloud) ListRoutes(ctx context.Context, clusterName string) ([]*cloudprovider.Route, error) {
+	return c.az.ListRoutes(ctx, clusterName)
+}
+
+// CreateRoute implements cloudprovider.Routes.
+func (c *reloadLockedCloud) CreateRoute(ctx context.Context, clusterName string, nameHint string, route *cloudprovider.Route) error {
+	return c.az.CreateRoute(ctx, clusterName, nameHint, route)
+}
+
+// DeleteRoute implements cloudprovider.Routes.
+func (c *reloadLockedCloud) DeleteRoute(ctx context.Context, clusterName string, route *cloudprovider.Route) error {
+	return c.az.DeleteRoute(ctx, clusterName, route)
+}
+
+// rLockReload holds the reload lock for reading and returns the function releasing it, which may be called
+// more than once, so that the lock can be released before waiting for a batch and still be released on return.
+func (az *Cloud) rLockReload() func() {
+	az.reloadLock.RLock()
+	return sync.OnceFunc(az.reloadLock.RUnlock)
+}
+
+// GetNodeCIDRMasksByProviderID returns the node CIDR mask sizes of the VM set of the node, holding the
+// reload lock for reading like the interfaces of the controllers.
+func (az *Cloud) GetNodeCIDRMasksByProviderID(providerID string) (int, int, error) {
+	az.reloadLock.RLock()
+	defer az.reloadLock.RUnlock()
+	return az.VMSet.GetNodeCIDRMasksByProviderID(providerID)
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2022-07-01/network"
+	"go.opentelemetry.io/otel/attribute"
 
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -35,6 +36,7 @@ import (
 	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/metrics"
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace"
 )
 
 var _ cloudprovider.Routes = (*Cloud)(nil)
@@ -57,6 +59,8 @@ type delayedRouteOperation struct {
 	routeTableTags map[string]*string
 	operation      routeOperation
 	result         chan batchOperationResult
+	// link to the span requesting the operation
+	link trace.Link
 }
 
 // wait waits for the operation completion and returns the result.
@@ -98,6 +102,10 @@ func (d *delayedRouteUpdater) run(ctx context.Context) {
 
 // updateRoutes invokes route table client to update all routes.
 func (d *delayedRouteUpdater) updateRoutes() {
+	// the reload lock is taken before the updater lock, the route methods of the cloud release it before
+	// waiting for the batch, so that a reload waiting for the lock doesn't block the batch
+	d.az.reloadLock.RLock()
+	defer d.az.reloadLock.RUnlock()
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
@@ -107,8 +115,18 @@ func (d *delayedRouteUpdater) updateRoutes() {
 		return
 	}
 
+	links := make([]trace.Link, 0, len(d.routesToUpdate))
+	for _, op := range d.routesToUpdate {
+		links = append(links, op.(*delayedRouteOperation).link)
+	}
+	ctx, span := trace.StartBatchSpan(context.Background(), "delayedRouteUpdater.updateRoutes", links,
+		attribute.Int("batch_size", len(d.routesToUpdate)),
+		trace.ResourceGroupKey.String(d.az.RouteTableResourceGroup),
+	)
+
 	var err error
 	defer func() {
+		trace.EndSpan(span, err)
 		// Notify all the goroutines.
 		for _, op := range d.routesToUpdate {
 			rt := op.(*delayedRouteOperation)
@@ -130,7 +148,7 @@ func (d *delayedRouteUpdater) updateRoutes() {
 
 	// create route table if it doesn't exists yet.
 	if !existsRouteTable {
-		err = d.az.createRouteTable()
+		err = d.az.createRouteTable(ctx)
 		if err != nil {
 			klog.Errorf("createRouteTable() failed with error: %v", err)
 			return
@@ -196,14 +214,21 @@ func (d *delayedRouteUpdater) updateRoutes() {
 	}
 
 	if dirty {
-		if !onlyUpdateTags {
+		if onlyUpdateTags {
+			// the routes are not sent if only the tags are changed
+			err = d.az.UpdateRouteTableTags(ctx, routeTable)
+			if err != nil {
+				klog.Errorf("UpdateRouteTableTags() failed with error: %v", err)
+				return
+			}
+		} else {
 			klog.V(2).Infof("updateRoutes: updating routes")
 			routeTable.Routes = &routes
-		}
-		err = d.az.CreateOrUpdateRouteTable(routeTable)
-		if err != nil {
-			klog.Errorf("CreateOrUpdateRouteTable() failed with error: %v", err)
-			return
+			err = d.az.CreateOrUpdateRouteTable(ctx, routeTable)
+			if err != nil {
+				klog.Errorf("CreateOrUpdateRouteTable() failed with error: %v", err)
+				return
+			}
 		}
 
 		// wait a while for route updates to take effect.
@@ -241,27 +266,30 @@ func (d *delayedRouteUpdater) cleanupOutdatedRoutes(existingRoutes []network.Rou
 	return existingRoutes, changed
 }
 
-func getAddRouteOperation(route network.Route) batchOperation {
+func getAddRouteOperation(ctx context.Context, route network.Route) batchOperation {
 	return &delayedRouteOperation{
 		route:     route,
 		operation: routeOperationAdd,
 		result:    make(chan batchOperationResult),
+		link:      trace.LinkFromContext(ctx),
 	}
 }
 
-func getDeleteRouteOperation(route network.Route) batchOperation {
+func getDeleteRouteOperation(ctx context.Context, route network.Route) batchOperation {
 	return &delayedRouteOperation{
 		route:     route,
 		operation: routeOperationDelete,
 		result:    make(chan batchOperationResult),
+		link:      trace.LinkFromContext(ctx),
 	}
 }
 
-func getUpdateRouteTableTagsOperation(tags map[string]*string) batchOperation {
+func getUpdateRouteTableTagsOperation(ctx context.Context, tags map[string]*string) batchOperation {
 	return &delayedRouteOperation{
 		routeTableTags: tags,
 		operation:      routeTableOperationUpdateTags,
 		result:         make(chan batchOperationResult),
+		link:           trace.LinkFromContext(ctx),
 	}
 }
 
@@ -278,7 +306,12 @@ func (d *delayedRouteUpdater) removeOperation(_ string) {}
 
 // ListRoutes lists all managed routes that belong to the specified clusterName
 // implements cloudprovider.Routes.ListRoutes
-func (az *Cloud) ListRoutes(_ context.Context, clusterName string) ([]*cloudprovider.Route, error) {
+func (az *Cloud) ListRoutes(ctx context.Context, clusterName string) (_ []*cloudprovider.Route, err error) {
+	ctx, span := trace.StartSpan(ctx, "Cloud.ListRoutes", trace.ClusterNameKey.String(clusterName))
+	defer func() { trace.EndSpan(span, err) }()
+	unlock := az.rLockReload()
+	defer unlock()
+
 	klog.V(10).Infof("ListRoutes: START clusterName=%q", clusterName)
 	routeTable, existsRouteTable, err := az.getRouteTable(azcache.CacheReadTypeDefault)
 	routes, err := processRoutes(az.ipv6DualStackEnabled, routeTable, existsRouteTable, err)
@@ -307,9 +340,10 @@ func (az *Cloud) ListRoutes(_ context.Context, clusterName string) ([]*cloudprov
 	tags, changed := az.ensureRouteTableTagged(&routeTable)
 	if changed {
 		klog.V(2).Infof("ListRoutes: updating tags on route table %s", pointer.StringDeref(routeTable.Name, ""))
-		op := az.routeUpdater.addOperation(getUpdateRouteTableTagsOperation(tags))
+		op := az.routeUpdater.addOperation(getUpdateRouteTableTagsOperation(ctx, tags))
 
 		// Wait for operation complete.
+		unlock()
 		err = op.wait().err
 		if err != nil {
 			klog.Errorf("ListRoutes: failed to update route table tags with error: %v", err)
@@ -349,7 +383,7 @@ func processRoutes(ipv6DualStackEnabled bool, routeTable network.RouteTable, exi
 	return kubeRoutes, nil
 }
 
-func (az *Cloud) createRouteTable() error {
+func (az *Cloud) createRouteTable(ctx context.Context) error {
 	routeTable := network.RouteTable{
 		Name:                       pointer.String(az.RouteTableName),
 		Location:                   pointer.String(az.Location),
@@ -357,7 +391,7 @@ func (az *Cloud) createRouteTable() error {
 	}
 
 	klog.V(3).Infof("createRouteTableIfNotExists: creating routetable. routeTableName=%q", az.RouteTableName)
-	err := az.CreateOrUpdateRouteTable(routeTable)
+	err := az.CreateOrUpdateRouteTable(ctx, routeTable)
 	if err != nil {
 		return err
 	}
@@ -371,7 +405,15 @@ func (az *Cloud) createRouteTable() error {
 // route.Name will be ignored, although the cloud-provider may use nameHint
 // to create a more user-meaningful name.
 // implements cloudprovider.Routes.CreateRoute
-func (az *Cloud) CreateRoute(_ context.Context, clusterName string, _ string, kubeRoute *cloudprovider.Route) error {
+func (az *Cloud) CreateRoute(ctx context.Context, clusterName string, _ string, kubeRoute *cloudprovider.Route) (err error) {
+	ctx, span := trace.StartSpan(ctx, "Cloud.CreateRoute",
+		trace.ClusterNameKey.String(clusterName),
+		trace.NodeNameKey.String(string(kubeRoute.TargetNode)),
+	)
+	defer func() { trace.EndSpan(span, err) }()
+	unlock := az.rLockReload()
+	defer unlock()
+
 	mc := metrics.NewMetricContext("routes", "create_route", az.ResourceGroup, az.getNetworkResourceSubscriptionID(), string(kubeRoute.TargetNode))
 	isOperationSucceeded := false
 	defer func() {
@@ -398,7 +440,7 @@ func (az *Cloud) CreateRoute(_ context.Context, clusterName string, _ string, ku
 	// single stack IPv6 is supported on dual stack host. So the IPv6 IP is secondary IP for both single stack IPv6 and dual stack
 	// Get all private IPs for the machine and find the first one that matches the IPv6 family
 	if !az.ipv6DualStackEnabled && !CIDRv6 {
-		targetIP, _, err = az.getIPForMachine(kubeRoute.TargetNode)
+		targetIP, _, err = az.getIPForMachine(ctx, kubeRoute.TargetNode)
 		if err != nil {
 			return err
 		}
@@ -406,7 +448,7 @@ func (az *Cloud) CreateRoute(_ context.Context, clusterName string, _ string, ku
 		// for dual stack and single stack IPv6 we need to select
 		// a private ip that matches family of the cidr
 		klog.V(4).Infof("CreateRoute: create route instance=%q cidr=%q is in dual stack mode", kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
-		nodePrivateIPs, err := az.getPrivateIPsForMachine(kubeRoute.TargetNode)
+		nodePrivateIPs, err := az.getPrivateIPsForMachine(ctx, kubeRoute.TargetNode)
 		if nil != err {
 			klog.V(3).Infof("CreateRoute: create route: failed(GetPrivateIPsByNodeName) instance=%q cidr=%q with error=%v", kubeRoute.TargetNode, kubeRoute.DestinationCIDR, err)
 			return err
@@ -419,6 +461,7 @@ func (az *Cloud) CreateRoute(_ context.Context, clusterName string, _ string, ku
 		}
 	}
 	routeName := mapNodeNameToRouteName(az.ipv6DualStackEnabled, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
+	span.SetAttributes(trace.RouteNameKey.String(routeName))
 	route := network.Route{
 		Name: pointer.String(routeName),
 		RoutePropertiesFormat: &network.RoutePropertiesFormat{
@@ -429,9 +472,10 @@ func (az *Cloud) CreateRoute(_ context.Context, clusterName string, _ string, ku
 	}
 
 	klog.V(2).Infof("CreateRoute: creating route for clusterName=%q instance=%q cidr=%q", clusterName, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
-	op := az.routeUpdater.addOperation(getAddRouteOperation(route))
+	op := az.routeUpdater.addOperation(getAddRouteOperation(ctx, route))
 
 	// Wait for operation complete.
+	unlock()
 	err = op.wait().err
 	if err != nil {
 		klog.Errorf("CreateRoute failed for node %q with error: %v", kubeRoute.TargetNode, err)
@@ -447,7 +491,15 @@ func (az *Cloud) CreateRoute(_ context.Context, clusterName string, _ string, ku
 // DeleteRoute deletes the specified managed route
 // Route should be as returned by ListRoutes
 // implements cloudprovider.Routes.DeleteRoute
-func (az *Cloud) DeleteRoute(_ context.Context, clusterName string, kubeRoute *cloudprovider.Route) error {
+func (az *Cloud) DeleteRoute(ctx context.Context, clusterName string, kubeRoute *cloudprovider.Route) (err error) {
+	ctx, span := trace.StartSpan(ctx, "Cloud.DeleteRoute",
+		trace.ClusterNameKey.String(clusterName),
+		trace.NodeNameKey.String(string(kubeRoute.TargetNode)),
+	)
+	defer func() { trace.EndSpan(span, err) }()
+	unlock := az.rLockReload()
+	defer unlock()
+
 	mc := metrics.NewMetricContext("routes", "delete_route", az.ResourceGroup, az.getNetworkResourceSubscriptionID(), string(kubeRoute.TargetNode))
 	isOperationSucceeded := false
 	defer func() {
@@ -469,14 +521,17 @@ func (az *Cloud) DeleteRoute(_ context.Context, clusterName string, kubeRoute *c
 	}
 
 	routeName := mapNodeNameToRouteName(az.ipv6DualStackEnabled, kubeRoute.TargetNode, kubeRoute.DestinationCIDR)
+	span.SetAttributes(trace.RouteNameKey.String(routeName))
 	klog.V(2).Infof("DeleteRoute: deleting route. clusterName=%q instance=%q cidr=%q routeName=%q", clusterName, kubeRoute.TargetNode, kubeRoute.DestinationCIDR, routeName)
 	route := network.Route{
 		Name:                  pointer.String(routeName),
 		RoutePropertiesFormat: &network.RoutePropertiesFormat{},
 	}
-	op := az.routeUpdater.addOperation(getDeleteRouteOperation(route))
+	op := az.routeUpdater.addOperation(getDeleteRouteOperation(ctx, route))
 
 	// Wait for operation complete.
+	ipv6DualStackEnabled, routeUpdater := az.ipv6DualStackEnabled, az.routeUpdater
+	unlock()
 	err = op.wait().err
 	if err != nil {
 		klog.Errorf("DeleteRoute failed for node %q with error: %v", kubeRoute.TargetNode, err)
@@ -484,14 +539,14 @@ func (az *Cloud) DeleteRoute(_ context.Context, clusterName string, kubeRoute *c
 	}
 
 	// Remove outdated ipv4 routes as well
-	if az.ipv6DualStackEnabled {
+	if ipv6DualStackEnabled {
 		routeNameWithoutIPV6Suffix := strings.Split(routeName, consts.RouteNameSeparator)[0]
 		klog.V(2).Infof("DeleteRoute: deleting route. clusterName=%q instance=%q cidr=%q routeName=%q", clusterName, kubeRoute.TargetNode, kubeRoute.DestinationCIDR, routeNameWithoutIPV6Suffix)
 		route := network.Route{
 			Name:                  pointer.String(routeNameWithoutIPV6Suffix),
 			RoutePropertiesFormat: &network.RoutePropertiesFormat{},
 		}
-		op := az.routeUpdater.addOperation(getDeleteRouteOperation(route))
+		op := routeUpdater.addOperation(getDeleteRouteOperation(ctx, route))
 
 		// Wait for operation complete.
 		err = op.wait().err
@@ -362,8 +362,8 @@ func TestCreateRoute(t *testing.T) {
 			cloud.nodeInformerSynced = func() bool { return true }
 		}
 
-		mockVMSet.EXPECT().GetIPByNodeName(gomock.Any()).Return(nodePrivateIP, "", test.getIPError).MaxTimes(1)
-		mockVMSet.EXPECT().GetPrivateIPsByNodeName("node").Return([]string{nodePrivateIP, "10.10.10.10"}, nil).MaxTimes(1)
+		mockVMSet.EXPECT().GetIPByNodeName(gomock.Any(), gomock.Any()).Return(nodePrivateIP, "", test.getIPError).MaxTimes(1)
+		mockVMSet.EXPECT().GetPrivateIPsByNodeName(gomock.Any(), "node").Return([]string{nodePrivateIP, "10.10.10.10"}, nil).MaxTimes(1)
 		routeTableClient.EXPECT().Get(gomock.Any(), cloud.RouteTableResourceGroup, cloud.RouteTableName, "").Return(initialTable, test.getErr).MaxTimes(1)
 		routeTableClient.EXPECT().CreateOrUpdate(gomock.Any(), cloud.RouteTableResourceGroup, cloud.RouteTableName, updatedTable, "").Return(test.createOrUpdateErr).MaxTimes(1)
 
@@ -378,6 +378,63 @@ func TestCreateRoute(t *testing.T) {
 	}
 }
 
+func TestCreateRouteWhileReloading(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	routeTableClient := mockroutetableclient.NewMockInterface(ctrl)
+	mockVMSet := NewMockVMSet(ctrl)
+
+	cloud := &Cloud{
+		RouteTablesClient: routeTableClient,
+		VMSet:             mockVMSet,
+		Config: Config{
+			RouteTableResourceGroup: "foo",
+			RouteTableName:          "bar",
+			Location:                "location",
+		},
+		unmanagedNodes:     utilsets.NewString(),
+		nodeInformerSynced: func() bool { return true },
+	}
+	cache, _ := cloud.newRouteTableCache()
+	cloud.rtCache = cache
+	cloud.routeUpdater = newDelayedRouteUpdater(cloud, 100*time.Millisecond)
+	go cloud.routeUpdater.run(context.Background())
+
+	// a reload waits for the lock while the route is being queued, the route
+	// updater must still be able to process the batch.
+	reloaded := make(chan struct{})
+	mockVMSet.EXPECT().GetIPByNodeName(gomock.Any(), "node").DoAndReturn(func(_ context.Context, _ string) (string, string, error) {
+		go func() {
+			cloud.reloadLock.Lock()
+			defer cloud.reloadLock.Unlock()
+			close(reloaded)
+		}()
+		return "2.4.6.8", "", nil
+	})
+	routeTableClient.EXPECT().Get(gomock.Any(), "foo", "bar", "").Return(network.RouteTable{
+		Name:                       pointer.String("bar"),
+		Location:                   &cloud.Location,
+		RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
+	}, nil)
+	routeTableClient.EXPECT().CreateOrUpdate(gomock.Any(), "foo", "bar", gomock.Any(), "").Return(nil)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- cloud.CreateRoute(context.TODO(), "cluster", "unused", &cloudprovider.Route{TargetNode: "node", DestinationCIDR: "1.2.3.4/24"})
+	}()
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out creating the route while the config is being reloaded")
+	}
+	select {
+	case <-reloaded:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out reloading the config")
+	}
+}
+
 func TestCreateRouteTable(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -400,7 +457,7 @@ func TestCreateRouteTable(t *testing.T) {
 		RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
 	}
 	routeTableClient.EXPECT().CreateOrUpdate(gomock.Any(), cloud.RouteTableResourceGroup, cloud.RouteTableName, expectedTable, "").Return(nil)
-	err := cloud.createRouteTable()
+	err := cloud.createRouteTable(context.TODO())
 	if err != nil {
 		t.Errorf("unexpected error in creating route table: %v", err)
 		t.FailNow()
@@ -834,3 +891,45 @@ func TestEnsureRouteTableTagged(t *testing.T) {
 	assert.Nil(t, tags)
 	assert.False(t, changed)
 }
+
+func TestListRoutesUpdatesRouteTableTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	routeTableClient := mockroutetableclient.NewMockInterface(ctrl)
+
+	cloud := &Cloud{
+		RouteTablesClient: routeTableClient,
+		Config: Config{
+			ResourceGroup:           "foo",
+			RouteTableResourceGroup: "foo",
+			RouteTableName:          "bar",
+			Location:                "location",
+			Tags:                    "a=b",
+		},
+		unmanagedNodes:     utilsets.NewString(),
+		nodeInformerSynced: func() bool { return true },
+	}
+	cache, _ := cloud.newRouteTableCache()
+	cloud.rtCache = cache
+	cloud.routeUpdater = newDelayedRouteUpdater(cloud, 100*time.Millisecond)
+	go cloud.routeUpdater.run(context.Background())
+
+	routes := &[]network.Route{
+		{
+			Name:                  pointer.String("node"),
+			RoutePropertiesFormat: &network.RoutePropertiesFormat{AddressPrefix: pointer.String("1.2.3.4/24")},
+		},
+	}
+	routeTable := network.RouteTable{
+		Name:                       pointer.String("bar"),
+		Location:                   &cloud.Location,
+		RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{Routes: routes},
+	}
+	routeTableClient.EXPECT().Get(gomock.Any(), "foo", "bar", "").Return(routeTable, nil).AnyTimes()
+	// only the tags are sent, the routes are left as they are
+	routeTableClient.EXPECT().UpdateTags(gomock.Any(), "foo", "bar", network.TagsObject{Tags: map[string]*string{"a": pointer.String("b")}}).Return(nil)
+
+	result, err := cloud.ListRoutes(context.TODO(), "cluster")
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -31,8 +32,8 @@ import (
 )
 
 // CreateOrUpdateRouteTable invokes az.RouteTablesClient.CreateOrUpdate with exponential backoff retry
-func (az *Cloud) CreateOrUpdateRouteTable(routeTable network.RouteTable) error {
-	ctx, cancel := getContextWithCancel()
+func (az *Cloud) CreateOrUpdateRouteTable(ctx context.Context, routeTable network.RouteTable) error {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	rerr := az.RouteTablesClient.CreateOrUpdate(ctx, az.RouteTableResourceGroup, az.RouteTableName, routeTable, pointer.StringDeref(routeTable.Etag, ""))
@@ -59,6 +60,21 @@ func (az *Cloud) CreateOrUpdateRouteTable(routeTable network.RouteTable) error {
 	return rerr.Error()
 }
 
+// UpdateRouteTableTags updates the tags of the route table without sending its routes.
+func (az *Cloud) UpdateRouteTableTags(ctx context.Context, routeTable network.RouteTable) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rerr := az.RouteTablesClient.UpdateTags(ctx, az.RouteTableResourceGroup, az.RouteTableName, network.TagsObject{Tags: routeTable.Tags})
+	// Invalidate the cache since the tags may have been updated even if the request failed.
+	_ = az.rtCache.Delete(az.RouteTableName)
+	if rerr != nil {
+		klog.Errorf("RouteTablesClient.UpdateTags(%s) failed: %v", az.RouteTableName, rerr.Error())
+		return rerr.Error()
+	}
+	return nil
+}
+
 func (az *Cloud) newRouteTableCache() (azcache.Resource, error) {
 	getter := func(key string) (interface{}, error) {
 		ctx, cancel := getContextWithCancel()
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -58,7 +59,7 @@ func TestCreateOrUpdateRouteTable(t *testing.T) {
 		mockRTClient.EXPECT().CreateOrUpdate(gomock.Any(), az.ResourceGroup, gomock.Any(), gomock.Any(), gomock.Any()).Return(test.clientErr)
 		mockRTClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, "rt", gomock.Any()).Return(network.RouteTable{}, nil)
 
-		err := az.CreateOrUpdateRouteTable(network.RouteTable{
+		err := az.CreateOrUpdateRouteTable(context.TODO(), network.RouteTable{
 			Name: pointer.String("rt"),
 			Etag: pointer.String("etag"),
 		})
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -32,8 +33,8 @@ import (
 )
 
 // CreateOrUpdateSecurityGroup invokes az.SecurityGroupsClient.CreateOrUpdate with exponential backoff retry
-func (az *Cloud) CreateOrUpdateSecurityGroup(sg network.SecurityGroup) error {
-	ctx, cancel := getContextWithCancel()
+func (az *Cloud) CreateOrUpdateSecurityGroup(ctx context.Context, sg network.SecurityGroup) error {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	rerr := az.SecurityGroupsClient.CreateOrUpdate(ctx, az.SecurityGroupResourceGroup, *sg.Name, sg, pointer.StringDeref(sg.Etag, ""))
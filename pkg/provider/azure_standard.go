@@ -43,6 +43,7 @@ import (
 	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
 	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/metrics"
+	"sigs.k8s.io/cloud-provider-azure/pkg/trace"
 	vmutil "sigs.k8s.io/cloud-provider-azure/pkg/util/vm"
 )
 
@@ -444,7 +445,7 @@ func (as *availabilitySet) newVMASCache() (azcache.Resource, error) {
 		as.Config.AvailabilitySetsCacheTTLInSeconds = consts.VMASCacheTTLDefaultInSeconds
 	}
 
-	return azcache.NewTimedCache(time.Duration(as.Config.AvailabilitySetsCacheTTLInSeconds)*time.Second, getter, as.Cloud.Config.DisableAPICallCache)
+	return azcache.NewTimedCache(time.Duration(as.Config.AvailabilitySetsCacheTTLInSeconds)*time.Second, getter, as.Cloud.Config.DisableAPICallCache, as.readMostlyCacheOptions()...)
 }
 
 // newStandardSet creates a new availabilitySet.
@@ -582,6 +583,41 @@ func (as *availabilitySet) GetZoneByNodeName(name string) (cloudprovider.Zone, e
 	return zone, nil
 }
 
+// GetPlacementByNodeName gets the fault domain, update domain, proximity placement group
+// and capacity reservation group of the node by node name.
+func (as *availabilitySet) GetPlacementByNodeName(name string) (*NodePlacement, error) {
+	vm, err := as.getVirtualMachine(types.NodeName(name), azcache.CacheReadTypeUnsafe)
+	if err != nil {
+		return nil, err
+	}
+
+	return getPlacementFromVMProperties(vm.VirtualMachineProperties), nil
+}
+
+// getPlacementFromVMProperties gets the placement of a VM from its properties.
+func getPlacementFromVMProperties(props *compute.VirtualMachineProperties) *NodePlacement {
+	placement := &NodePlacement{}
+	if props == nil {
+		return placement
+	}
+
+	if props.InstanceView != nil {
+		if props.InstanceView.PlatformFaultDomain != nil {
+			placement.FaultDomain = strconv.Itoa(int(*props.InstanceView.PlatformFaultDomain))
+		}
+		if props.InstanceView.PlatformUpdateDomain != nil {
+			placement.UpdateDomain = strconv.Itoa(int(*props.InstanceView.PlatformUpdateDomain))
+		}
+	}
+	if props.ProximityPlacementGroup != nil {
+		placement.ProximityPlacementGroup, _ = getLastSegment(strings.ToLower(pointer.StringDeref(props.ProximityPlacementGroup.ID, "")), "/")
+	}
+	if props.CapacityReservation != nil && props.CapacityReservation.CapacityReservationGroup != nil {
+		placement.CapacityReservationGroup, _ = getLastSegment(strings.ToLower(pointer.StringDeref(props.CapacityReservation.CapacityReservationGroup.ID, "")), "/")
+	}
+	return placement
+}
+
 // GetPrimaryVMSetName returns the VM set name depending on the configured vmType.
 // It returns config.PrimaryScaleSetName for vmss and config.PrimaryAvailabilitySetName for standard vmType.
 func (as *availabilitySet) GetPrimaryVMSetName() string {
@@ -589,8 +625,8 @@ func (as *availabilitySet) GetPrimaryVMSetName() string {
 }
 
 // GetIPByNodeName gets machine private IP and public IP by node name.
-func (as *availabilitySet) GetIPByNodeName(name string) (string, string, error) {
-	nic, err := as.GetPrimaryInterface(name)
+func (as *availabilitySet) GetIPByNodeName(ctx context.Context, name string) (string, string, error) {
+	nic, err := as.GetPrimaryInterface(ctx, name)
 	if err != nil {
 		return "", "", err
 	}
@@ -624,9 +660,9 @@ func (as *availabilitySet) GetIPByNodeName(name string) (string, string, error)
 // returns a list of private ips assigned to node
 // TODO (khenidak): This should read all nics, not just the primary
 // allowing users to split ipv4/v6 on multiple nics
-func (as *availabilitySet) GetPrivateIPsByNodeName(name string) ([]string, error) {
+func (as *availabilitySet) GetPrivateIPsByNodeName(ctx context.Context, name string) ([]string, error) {
 	ips := make([]string, 0)
-	nic, err := as.GetPrimaryInterface(name)
+	nic, err := as.GetPrimaryInterface(ctx, name)
 	if err != nil {
 		return ips, err
 	}
@@ -771,8 +807,8 @@ func (as *availabilitySet) GetNodeVMSetName(node *v1.Node) (string, error) {
 }
 
 // GetPrimaryInterface gets machine primary network interface by node name.
-func (as *availabilitySet) GetPrimaryInterface(nodeName string) (network.Interface, error) {
-	nic, _, err := as.getPrimaryInterfaceWithVMSet(nodeName, "")
+func (as *availabilitySet) GetPrimaryInterface(ctx context.Context, nodeName string) (network.Interface, error) {
+	nic, _, err := as.getPrimaryInterfaceWithVMSet(ctx, nodeName, "")
 	return nic, err
 }
 
@@ -787,7 +823,7 @@ func extractResourceGroupByNicID(nicID string) (string, error) {
 }
 
 // getPrimaryInterfaceWithVMSet gets machine primary network interface by node name and vmSet.
-func (as *availabilitySet) getPrimaryInterfaceWithVMSet(nodeName, vmSetName string) (network.Interface, string, error) {
+func (as *availabilitySet) getPrimaryInterfaceWithVMSet(ctx context.Context, nodeName, vmSetName string) (network.Interface, string, error) {
 	var machine compute.VirtualMachine
 
 	machine, err := as.GetVirtualMachineWithRetry(types.NodeName(nodeName), azcache.CacheReadTypeDefault)
@@ -836,8 +872,6 @@ func (as *availabilitySet) getPrimaryInterfaceWithVMSet(nodeName, vmSetName stri
 		return network.Interface{}, "", err
 	}
 
-	ctx, cancel := getContextWithCancel()
-	defer cancel()
 	nic, rerr := as.InterfacesClient.Get(ctx, nicResourceGroup, nicName, "")
 	if rerr != nil {
 		return network.Interface{}, "", rerr.Error()
@@ -852,10 +886,10 @@ func (as *availabilitySet) getPrimaryInterfaceWithVMSet(nodeName, vmSetName stri
 
 // EnsureHostInPool ensures the given VM's Primary NIC's Primary IP Configuration is
 // participating in the specified LoadBalancer Backend Pool.
-func (as *availabilitySet) EnsureHostInPool(service *v1.Service, nodeName types.NodeName, backendPoolID string, vmSetName string) (string, string, string, *compute.VirtualMachineScaleSetVM, error) {
+func (as *availabilitySet) EnsureHostInPool(ctx context.Context, service *v1.Service, nodeName types.NodeName, backendPoolID string, vmSetName string) (string, string, string, *compute.VirtualMachineScaleSetVM, error) {
 	vmName := mapNodeNameToVMName(nodeName)
 	serviceName := getServiceName(service)
-	nic, _, err := as.getPrimaryInterfaceWithVMSet(vmName, vmSetName)
+	nic, _, err := as.getPrimaryInterfaceWithVMSet(ctx, vmName, vmSetName)
 	if err != nil {
 		if errors.Is(err, errNotInVMSet) {
 			klog.V(3).Infof("EnsureHostInPool skips node %s because it is not in the vmSet %s", nodeName, vmSetName)
@@ -927,7 +961,7 @@ func (as *availabilitySet) EnsureHostInPool(service *v1.Service, nodeName types.
 
 		nicName := *nic.Name
 		klog.V(3).Infof("nicupdate(%s): nic(%s) - updating", serviceName, nicName)
-		err := as.CreateOrUpdateInterface(service, nic)
+		err := as.CreateOrUpdateInterface(ctx, service, nic)
 		if err != nil {
 			return "", "", "", nil, err
 		}
@@ -937,7 +971,10 @@ func (as *availabilitySet) EnsureHostInPool(service *v1.Service, nodeName types.
 
 // EnsureHostsInPool ensures the given Node's primary IP configurations are
 // participating in the specified LoadBalancer Backend Pool.
-func (as *availabilitySet) EnsureHostsInPool(service *v1.Service, nodes []*v1.Node, backendPoolID string, vmSetName string) error {
+func (as *availabilitySet) EnsureHostsInPool(ctx context.Context, service *v1.Service, nodes []*v1.Node, backendPoolID string, vmSetName string) (err error) {
+	ctx, span := trace.StartSpan(ctx, "availabilitySet.EnsureHostsInPool", trace.ServiceKey.String(getServiceName(service)), trace.VMSetNameKey.String(vmSetName))
+	defer func() { trace.EndSpan(span, err) }()
+
 	mc := metrics.NewMetricContext("services", "vmas_ensure_hosts_in_pool", as.ResourceGroup, as.SubscriptionID, getServiceName(service))
 	isOperationSucceeded := false
 	defer func() {
@@ -963,7 +1000,7 @@ func (as *availabilitySet) EnsureHostsInPool(service *v1.Service, nodes []*v1.No
 		}
 
 		f := func() error {
-			_, _, _, _, err := as.EnsureHostInPool(service, types.NodeName(localNodeName), backendPoolID, vmSetName)
+			_, _, _, _, err := as.EnsureHostInPool(ctx, service, types.NodeName(localNodeName), backendPoolID, vmSetName)
 			if err != nil {
 				return fmt.Errorf("ensure(%s): backendPoolID(%s) - failed to ensure host in pool: %w", getServiceName(service), backendPoolID, err)
 			}
@@ -983,7 +1020,10 @@ func (as *availabilitySet) EnsureHostsInPool(service *v1.Service, nodes []*v1.No
 
 // EnsureBackendPoolDeleted ensures the loadBalancer backendAddressPools deleted from the specified nodes.
 // backendPoolIDs are the IDs of the backendpools to be deleted.
-func (as *availabilitySet) EnsureBackendPoolDeleted(service *v1.Service, backendPoolIDs []string, vmSetName string, backendAddressPools *[]network.BackendAddressPool, _ bool) (bool, error) {
+func (as *availabilitySet) EnsureBackendPoolDeleted(ctx context.Context, service *v1.Service, backendPoolIDs []string, vmSetName string, backendAddressPools *[]network.BackendAddressPool, _ bool) (_ bool, err error) {
+	ctx, span := trace.StartSpan(ctx, "availabilitySet.EnsureBackendPoolDeleted", trace.ServiceKey.String(getServiceName(service)), trace.VMSetNameKey.String(vmSetName))
+	defer func() { trace.EndSpan(span, err) }()
+
 	// Returns nil if backend address pools already deleted.
 	if backendAddressPools == nil {
 		return false, nil
@@ -1034,7 +1074,7 @@ func (as *availabilitySet) EnsureBackendPoolDeleted(service *v1.Service, backend
 		}
 
 		vmName := mapNodeNameToVMName(types.NodeName(nodeName))
-		nic, vmasID, err := as.getPrimaryInterfaceWithVMSet(vmName, vmSetName)
+		nic, vmasID, err := as.getPrimaryInterfaceWithVMSet(ctx, vmName, vmSetName)
 		if err != nil {
 			if errors.Is(err, errNotInVMSet) {
 				klog.V(3).Infof("EnsureBackendPoolDeleted skips node %s because it is not in the vmSet %s", nodeName, vmSetName)
@@ -1093,7 +1133,7 @@ func (as *availabilitySet) EnsureBackendPoolDeleted(service *v1.Service, backend
 		}
 		nic.IPConfigurations = &newIPConfigs
 		nicUpdaters = append(nicUpdaters, func() error {
-			ctx, cancel := getContextWithCancel()
+			ctx, cancel := context.WithCancel(ctx)
 			defer cancel()
 			klog.V(2).Infof("EnsureBackendPoolDeleted begins to CreateOrUpdate for NIC(%s, %s) with backendPoolIDs %q", as.ResourceGroup, pointer.StringDeref(nic.Name, ""), backendPoolIDs)
 			rerr := as.InterfacesClient.CreateOrUpdate(ctx, as.ResourceGroup, pointer.StringDeref(nic.Name, ""), nic)
@@ -1275,7 +1315,7 @@ func (as *availabilitySet) GetNodeCIDRMasksByProviderID(providerID string) (int,
 }
 
 // EnsureBackendPoolDeletedFromVMSets ensures the loadBalancer backendAddressPools deleted from the specified VMAS
-func (as *availabilitySet) EnsureBackendPoolDeletedFromVMSets(_ map[string]bool, _ []string) error {
+func (as *availabilitySet) EnsureBackendPoolDeletedFromVMSets(_ context.Context, _ map[string]bool, _ []string) error {
 	return nil
 }
 
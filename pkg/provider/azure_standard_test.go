@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -1225,6 +1226,75 @@ func TestGetStandardVMZoneByNodeName(t *testing.T) {
 	}
 }
 
+func TestGetStandardVMPlacementByNodeName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	testcases := []struct {
+		name              string
+		nodeName          string
+		vm                compute.VirtualMachine
+		getErr            *retry.Error
+		expectedPlacement *NodePlacement
+		expectedErr       bool
+	}{
+		{
+			name:     "GetPlacementByNodeName should report error if node don't exist",
+			nodeName: "vm1",
+			getErr: &retry.Error{
+				HTTPStatusCode: http.StatusNotFound,
+				RawError:       cloudprovider.InstanceNotFound,
+			},
+			expectedErr: true,
+		},
+		{
+			name:     "GetPlacementByNodeName should get the placement as expected",
+			nodeName: "vm2",
+			vm: compute.VirtualMachine{
+				Name: pointer.String("vm2"),
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					InstanceView: &compute.VirtualMachineInstanceView{
+						PlatformFaultDomain:  pointer.Int32(1),
+						PlatformUpdateDomain: pointer.Int32(4),
+					},
+					ProximityPlacementGroup: &compute.SubResource{
+						ID: pointer.String("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/proximityPlacementGroups/PPG1"),
+					},
+					CapacityReservation: &compute.CapacityReservationProfile{
+						CapacityReservationGroup: &compute.SubResource{
+							ID: pointer.String("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/capacityReservationGroups/crg1"),
+						},
+					},
+				},
+			},
+			expectedPlacement: &NodePlacement{
+				FaultDomain:              "1",
+				UpdateDomain:             "4",
+				ProximityPlacementGroup:  "ppg1",
+				CapacityReservationGroup: "crg1",
+			},
+		},
+		{
+			name:     "GetPlacementByNodeName should leave unknown fields empty",
+			nodeName: "vm3",
+			vm: compute.VirtualMachine{
+				Name:                     pointer.String("vm3"),
+				VirtualMachineProperties: &compute.VirtualMachineProperties{},
+			},
+			expectedPlacement: &NodePlacement{},
+		},
+	}
+	for _, test := range testcases {
+		mockVMClient := cloud.VirtualMachinesClient.(*mockvmclient.MockInterface)
+		mockVMClient.EXPECT().Get(gomock.Any(), cloud.ResourceGroup, test.nodeName, gomock.Any()).Return(test.vm, test.getErr).AnyTimes()
+
+		placement, err := cloud.VMSet.GetPlacementByNodeName(test.nodeName)
+		assert.Equal(t, test.expectedErr, err != nil, test.name)
+		assert.Equal(t, test.expectedPlacement, placement, test.name)
+	}
+}
+
 func TestGetStandardVMSetNames(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1471,7 +1541,7 @@ func TestStandardEnsureHostInPool(t *testing.T) {
 		mockInterfaceClient.EXPECT().Get(gomock.Any(), cloud.ResourceGroup, test.nicName, gomock.Any()).Return(testNIC, nil).AnyTimes()
 		mockInterfaceClient.EXPECT().CreateOrUpdate(gomock.Any(), cloud.ResourceGroup, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
-		_, _, _, vm, err := cloud.VMSet.EnsureHostInPool(test.service, test.nodeName, test.backendPoolID, test.vmSetName)
+		_, _, _, vm, err := cloud.VMSet.EnsureHostInPool(context.TODO(), test.service, test.nodeName, test.backendPoolID, test.vmSetName)
 		assert.Equal(t, test.expectedErrMsg, err, test.name)
 		assert.Nil(t, vm, test.name)
 	}
@@ -1586,7 +1656,7 @@ func TestStandardEnsureHostsInPool(t *testing.T) {
 			mockInterfaceClient.EXPECT().Get(gomock.Any(), cloud.ResourceGroup, test.nicName, gomock.Any()).Return(testNIC, nil).AnyTimes()
 			mockInterfaceClient.EXPECT().CreateOrUpdate(gomock.Any(), cloud.ResourceGroup, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
-			err := cloud.VMSet.EnsureHostsInPool(test.service, test.nodes, test.backendPoolID, test.vmSetName)
+			err := cloud.VMSet.EnsureHostsInPool(context.TODO(), test.service, test.nodes, test.backendPoolID, test.vmSetName)
 			if test.expectedErr {
 				assert.EqualError(t, test.expectedErrMsg, err.Error(), test.name)
 			} else {
@@ -1645,7 +1715,7 @@ func TestStandardEnsureBackendPoolDeleted(t *testing.T) {
 		mockNICClient.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		cloud.InterfacesClient = mockNICClient
 
-		nicUpdated, err := cloud.VMSet.EnsureBackendPoolDeleted(&service, []string{backendPoolID}, vmSetName, test.backendAddressPools, true)
+		nicUpdated, err := cloud.VMSet.EnsureBackendPoolDeleted(context.TODO(), &service, []string{backendPoolID}, vmSetName, test.backendAddressPools, true)
 		assert.NoError(t, err, test.desc)
 		assert.True(t, nicUpdated)
 	}
@@ -57,6 +57,7 @@ func (az *Cloud) CreateFileShare(ctx context.Context, accountOptions *AccountOpt
 	if err := az.createFileShare(ctx, accountOptions.SubscriptionID, accountOptions.ResourceGroup, accountName, shareOptions); err != nil {
 		return "", "", fmt.Errorf("failed to create share %s in account %s: %w", shareOptions.Name, accountName, err)
 	}
+	az.addFileShareUsage(accountOptions.SubscriptionID, accountOptions.ResourceGroup, accountName, shareOptions.RequestGiB)
 	klog.V(4).Infof("created share %s in account %s", shareOptions.Name, accountOptions.Name)
 	return accountName, accountKey, nil
 }
@@ -66,6 +67,7 @@ func (az *Cloud) DeleteFileShare(ctx context.Context, subsID, resourceGroup, acc
 	if err := az.deleteFileShare(ctx, subsID, resourceGroup, accountName, shareName); err != nil {
 		return err
 	}
+	az.removeFileShareUsage(subsID, resourceGroup, accountName)
 	klog.V(4).Infof("share %s deleted", shareName)
 	return nil
 }
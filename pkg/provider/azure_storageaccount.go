@@ -18,10 +18,8 @@ package provider
 
 import (
 	"context"
-	"crypto/rand"
 	"encoding/json"
 	"fmt"
-	"math/big"
 	"strings"
 	"time"
 
@@ -88,6 +86,34 @@ type AccountOptions struct {
 	SoftDeleteContainers                    int32
 	// indicate whether to get a random matching account, if false, will get the first matching account
 	PickRandomMatchingAccount bool
+	// AccountSelectionPolicy decides which matching account is picked when Name is empty
+	AccountSelectionPolicy AccountSelectionPolicy
+	// MaxSharesPerAccount skips the matching accounts with as many file shares, and creates a new
+	// account if all the matching accounts are skipped. 0 means no limit.
+	MaxSharesPerAccount int
+	// EncryptionUserAssignedIdentity is the resource ID of the user-assigned identity assigned to the
+	// account to access the customer-managed key, the system-assigned identity of the account is enabled
+	// and used if it's empty.
+	// An empty KeyVersion uses the versionless key URI, which rotates the key version automatically.
+	EncryptionUserAssignedIdentity *string
+	// BlobLifecycleRules are the lifecycle management rules of the block blobs in the account, which
+	// replace any existing rules of the account. The rules of the existing accounts are not checked if
+	// it's empty, so an account with rules may be picked.
+	BlobLifecycleRules []BlobLifecycleRule
+	// PrivateEndpointTargets are the virtual networks linked to the private DNS zones when
+	// CreatePrivateEndpoint is true, the subnet of VNetName and SubnetName is used if it's empty.
+	// The private endpoints are created in the subnet of the first target only, so that the zones
+	// hold a single record of the account, and the other virtual networks must reach that subnet.
+	// The SubnetName of the other targets must be empty.
+	PrivateEndpointTargets []PrivateEndpointTarget
+	// PrivateEndpointStorageTypes are the storage types of the private endpoints, StorageType is
+	// used if it's empty.
+	PrivateEndpointStorageTypes []StorageType
+	// PrivateDNSZoneIDs are the resource IDs of the existing private DNS zones of the storage types,
+	// which may be in another subscription. These zones and their virtual network links are managed
+	// by their owners, and the zones of the other storage types are created in the resource group of
+	// the first target.
+	PrivateDNSZoneIDs []string
 }
 
 type accountWithLocation struct {
@@ -119,6 +145,7 @@ func (az *Cloud) getStorageAccounts(ctx context.Context, accountOptions *Account
 				isEnableHTTPSTrafficOnlyEqual(acct, accountOptions) &&
 				isAllowBlobPublicAccessEqual(acct, accountOptions) &&
 				isRequireInfrastructureEncryptionEqual(acct, accountOptions) &&
+				isEncryptionEqual(acct, accountOptions) &&
 				isAllowSharedKeyAccessEqual(acct, accountOptions) &&
 				isAccessTierEqual(acct, accountOptions) &&
 				az.isMultichannelEnabledEqual(ctx, acct, accountOptions) &&
@@ -127,6 +154,13 @@ func (az *Cloud) getStorageAccounts(ctx context.Context, accountOptions *Account
 				isPrivateEndpointAsExpected(acct, accountOptions)) {
 				continue
 			}
+			equal, err := az.isBlobLifecycleRulesEqual(ctx, acct, accountOptions)
+			if err != nil {
+				return nil, err
+			}
+			if !equal {
+				continue
+			}
 
 			accounts = append(accounts, accountWithLocation{Name: *acct.Name, StorageType: string((*acct.Sku).Name), Location: *acct.Location})
 		}
@@ -246,6 +280,12 @@ func (az *Cloud) EnsureStorageAccount(ctx context.Context, accountOptions *Accou
 	if accountOptions == nil {
 		return "", "", fmt.Errorf("account options is nil")
 	}
+	if err := validateEncryptionOptions(accountOptions); err != nil {
+		return "", "", err
+	}
+	if err := validateBlobLifecycleRules(accountOptions.BlobLifecycleRules); err != nil {
+		return "", "", err
+	}
 
 	accountName := accountOptions.Name
 	accountType := accountOptions.Type
@@ -277,7 +317,14 @@ func (az *Cloud) EnsureStorageAccount(ctx context.Context, accountOptions *Accou
 		location = az.Location
 	}
 
-	var privateDNSZoneName string
+	if vnetResourceGroup == "" {
+		vnetResourceGroup = az.ResourceGroup
+		if len(az.VnetResourceGroup) > 0 {
+			vnetResourceGroup = az.VnetResourceGroup
+		}
+	}
+
+	var privateEndpointOptions *privateEndpointOptions
 	if pointer.BoolDeref(accountOptions.CreatePrivateEndpoint, false) {
 		if accountOptions.StorageType == "" {
 			klog.V(2).Info("set StorageType as file when not specified")
@@ -287,7 +334,10 @@ func (az *Cloud) EnsureStorageAccount(ctx context.Context, accountOptions *Accou
 		if len(accountOptions.StorageEndpointSuffix) == 0 {
 			accountOptions.StorageEndpointSuffix = az.Environment.StorageEndpointSuffix
 		}
-		privateDNSZoneName = fmt.Sprintf(privateDNSZoneNameFmt, accountOptions.StorageType, accountOptions.StorageEndpointSuffix)
+		var err error
+		if privateEndpointOptions, err = az.getPrivateEndpointOptions(accountOptions, vnetResourceGroup, vnetName, subnetName); err != nil {
+			return "", "", err
+		}
 	}
 
 	if len(accountOptions.Tags) == 0 {
@@ -307,19 +357,15 @@ func (az *Cloud) EnsureStorageAccount(ctx context.Context, accountOptions *Accou
 			}
 
 			if len(accounts) > 0 {
-				index := 0
-				if accountOptions.PickRandomMatchingAccount {
-					// randomly pick one matching account
-					n, err := rand.Int(rand.Reader, big.NewInt(int64(len(accounts))))
-					if err != nil || n == nil {
-						return "", "", err
-					}
-					index = int(n.Int64())
-					klog.V(4).Infof("randomly pick one matching account, index: %d, matching accounts: %s", index, accounts)
+				account, err := az.selectStorageAccount(accountOptions, subsID, resourceGroup, accounts)
+				if err != nil {
+					return "", "", err
+				}
+				if account.Name != "" {
+					accountName = account.Name
+					createNewAccount = false
+					klog.V(4).Infof("found a matching account %s type %s location %s", account.Name, account.StorageType, account.Location)
 				}
-				accountName = accounts[index].Name
-				createNewAccount = false
-				klog.V(4).Infof("found a matching account %s type %s location %s", accounts[index].Name, accounts[index].StorageType, accounts[index].Location)
 			}
 		}
 
@@ -337,40 +383,9 @@ func (az *Cloud) EnsureStorageAccount(ctx context.Context, accountOptions *Accou
 		}
 	}
 
-	if vnetResourceGroup == "" {
-		vnetResourceGroup = az.ResourceGroup
-		if len(az.VnetResourceGroup) > 0 {
-			vnetResourceGroup = az.VnetResourceGroup
-		}
-	}
-
 	if pointer.BoolDeref(accountOptions.CreatePrivateEndpoint, false) {
-		clientFactory := az.NetworkClientFactory
-		if clientFactory == nil {
-			// multi-tenant support
-			clientFactory = az.ComputeClientFactory
-		}
-		if _, err := clientFactory.GetPrivateZoneClient().Get(ctx, vnetResourceGroup, privateDNSZoneName); err != nil {
-			if strings.Contains(err.Error(), consts.ResourceNotFoundMessageCode) {
-				// Create DNS zone first, this could make sure driver has write permission on vnetResourceGroup
-				if err := az.createPrivateDNSZone(ctx, vnetResourceGroup, privateDNSZoneName); err != nil {
-					return "", "", fmt.Errorf("create private DNS zone(%s) in resourceGroup(%s): %w", privateDNSZoneName, vnetResourceGroup, err)
-				}
-			} else {
-				return "", "", fmt.Errorf("get private dns zone %s returned with %v", privateDNSZoneName, err.Error())
-			}
-		}
-
-		// Create virtual link to the private DNS zone
-		vNetLinkName := vnetName + "-vnetlink"
-		if _, err := clientFactory.GetVirtualNetworkLinkClient().Get(ctx, vnetResourceGroup, privateDNSZoneName, vNetLinkName); err != nil {
-			if strings.Contains(err.Error(), consts.ResourceNotFoundMessageCode) {
-				if err := az.createVNetLink(ctx, vNetLinkName, vnetResourceGroup, vnetName, privateDNSZoneName); err != nil {
-					return "", "", fmt.Errorf("create virtual link for vnet(%s) and DNS Zone(%s) in resourceGroup(%s): %w", vnetName, privateDNSZoneName, vnetResourceGroup, err)
-				}
-			} else {
-				return "", "", fmt.Errorf("get virtual link for vnet(%s) and DNS Zone(%s) in resourceGroup(%s) returned with %w", vnetName, privateDNSZoneName, vnetResourceGroup, err)
-			}
+		if err := az.ensurePrivateDNSZones(ctx, privateEndpointOptions); err != nil {
+			return "", "", err
 		}
 	}
 
@@ -440,34 +455,17 @@ func (az *Cloud) EnsureStorageAccount(ctx context.Context, accountOptions *Accou
 		}
 		if accountOptions.RequireInfrastructureEncryption != nil {
 			klog.V(2).Infof("set RequireInfrastructureEncryption(%v) for storage account(%s)", *accountOptions.RequireInfrastructureEncryption, accountName)
-			cp.AccountPropertiesCreateParameters.Encryption = &storage.Encryption{
-				RequireInfrastructureEncryption: accountOptions.RequireInfrastructureEncryption,
-				KeySource:                       storage.KeySourceMicrosoftStorage,
-				Services: &storage.EncryptionServices{
-					File: &storage.EncryptionService{Enabled: pointer.Bool(true)},
-					Blob: &storage.EncryptionService{Enabled: pointer.Bool(true)},
-				},
-			}
 		}
+		if isCustomerManagedKeyEnabled(accountOptions) {
+			klog.V(2).Infof("set KeyVault(%s) key(%s) version(%s) identity(%s) for storage account(%s)", *accountOptions.KeyVaultURI, pointer.StringDeref(accountOptions.KeyName, ""),
+				pointer.StringDeref(accountOptions.KeyVersion, ""), pointer.StringDeref(accountOptions.EncryptionUserAssignedIdentity, ""), accountName)
+			cp.Identity = getStorageAccountIdentity(accountOptions)
+		}
+		cp.AccountPropertiesCreateParameters.Encryption = getStorageAccountEncryption(accountOptions)
 		if accountOptions.AllowSharedKeyAccess != nil {
 			klog.V(2).Infof("set Allow SharedKeyAccess (%v) for storage account (%s)", *accountOptions.AllowSharedKeyAccess, accountName)
 			cp.AccountPropertiesCreateParameters.AllowSharedKeyAccess = accountOptions.AllowSharedKeyAccess
 		}
-		if accountOptions.KeyVaultURI != nil {
-			klog.V(2).Infof("set KeyVault(%v) for storage account(%s)", accountOptions.KeyVaultURI, accountName)
-			cp.AccountPropertiesCreateParameters.Encryption = &storage.Encryption{
-				KeyVaultProperties: &storage.KeyVaultProperties{
-					KeyName:     accountOptions.KeyName,
-					KeyVersion:  accountOptions.KeyVersion,
-					KeyVaultURI: accountOptions.KeyVaultURI,
-				},
-				KeySource: storage.KeySourceMicrosoftKeyvault,
-				Services: &storage.EncryptionServices{
-					File: &storage.EncryptionService{Enabled: pointer.Bool(true)},
-					Blob: &storage.EncryptionService{Enabled: pointer.Bool(true)},
-				},
-			}
-		}
 		if az.StorageAccountClient == nil {
 			return "", "", fmt.Errorf("StorageAccountClient is nil")
 		}
@@ -512,6 +510,14 @@ func (az *Cloud) EnsureStorageAccount(ctx context.Context, accountOptions *Accou
 			}
 		}
 
+		if len(accountOptions.BlobLifecycleRules) > 0 {
+			klog.V(2).Infof("set blob lifecycle rules(%+v) on account(%s), subscription(%s), resource group(%s)", accountOptions.BlobLifecycleRules, accountName, subsID, resourceGroup)
+			policy := getBlobLifecyclePolicy(accountOptions.BlobLifecycleRules)
+			if _, err := az.BlobClient.CreateOrUpdateManagementPolicy(ctx, subsID, resourceGroup, accountName, policy); err != nil {
+				return "", "", fmt.Errorf("failed to set management policy for storage account %s, error: %w", accountName, err)
+			}
+		}
+
 		if accountOptions.DisableFileServiceDeleteRetentionPolicy != nil || accountOptions.IsMultichannelEnabled != nil {
 			prop, err := az.FileClient.WithSubscriptionID(subsID).GetServiceProperties(ctx, resourceGroup, accountName)
 			if err != nil {
@@ -551,22 +557,8 @@ func (az *Cloud) EnsureStorageAccount(ctx context.Context, accountOptions *Accou
 			return "", "", fmt.Errorf("failed to get the properties of storage account(%s), resourceGroup(%s), error: %v", accountName, resourceGroup, err)
 		}
 
-		// Create private endpoint
-		privateEndpointName := accountName + "-pvtendpoint"
-		if accountOptions.StorageType == StorageTypeBlob {
-			privateEndpointName = privateEndpointName + blobNameSuffix
-		}
-		if err := az.createPrivateEndpoint(ctx, accountName, storageAccount.ID, privateEndpointName, vnetResourceGroup, vnetName, subnetName, location, accountOptions.StorageType); err != nil {
-			return "", "", fmt.Errorf("create private endpoint for storage account(%s), resourceGroup(%s): %w", accountName, vnetResourceGroup, err)
-		}
-
-		// Create dns zone group
-		dnsZoneGroupName := accountName + "-dnszonegroup"
-		if accountOptions.StorageType == StorageTypeBlob {
-			dnsZoneGroupName = dnsZoneGroupName + blobNameSuffix
-		}
-		if err := az.createPrivateDNSZoneGroup(ctx, dnsZoneGroupName, privateEndpointName, vnetResourceGroup, vnetName, privateDNSZoneName); err != nil {
-			return "", "", fmt.Errorf("create private DNS zone group - privateEndpoint(%s), vNetName(%s), resourceGroup(%s): %w", privateEndpointName, vnetName, vnetResourceGroup, err)
+		if err := az.ensurePrivateEndpoints(ctx, accountName, pointer.StringDeref(storageAccount.ID, ""), location, privateEndpointOptions); err != nil {
+			return "", "", err
 		}
 	}
 
@@ -582,9 +574,9 @@ func (az *Cloud) EnsureStorageAccount(ctx context.Context, accountOptions *Accou
 func (az *Cloud) createPrivateEndpoint(ctx context.Context, accountName string, accountID *string, privateEndpointName, vnetResourceGroup, vnetName, subnetName, location string, storageType StorageType) error {
 	klog.V(2).Infof("Creating private endpoint(%s) for account (%s)", privateEndpointName, accountName)
 
-	subnet, _, err := az.getSubnet(vnetName, subnetName)
-	if err != nil {
-		return err
+	subnet, rerr := az.SubnetsClient.Get(ctx, vnetResourceGroup, vnetName, subnetName, "")
+	if rerr != nil {
+		return rerr.Error()
 	}
 	if subnet.SubnetPropertiesFormat == nil {
 		klog.Errorf("SubnetPropertiesFormat of (%s, %s) is nil", vnetName, subnetName)
@@ -638,8 +630,8 @@ func (az *Cloud) createPrivateDNSZone(ctx context.Context, vnetResourceGroup, pr
 	return nil
 }
 
-func (az *Cloud) createVNetLink(ctx context.Context, vNetLinkName, vnetResourceGroup, vnetName, privateDNSZoneName string) error {
-	klog.V(2).Infof("Creating virtual link for vnet(%s) and DNS Zone(%s) in resourceGroup(%s)", vNetLinkName, privateDNSZoneName, vnetResourceGroup)
+func (az *Cloud) createVNetLink(ctx context.Context, vNetLinkName, vnetResourceGroup, vnetName, privateDNSZoneResourceGroup, privateDNSZoneName string) error {
+	klog.V(2).Infof("Creating virtual link for vnet(%s) and DNS Zone(%s) in resourceGroup(%s)", vNetLinkName, privateDNSZoneName, privateDNSZoneResourceGroup)
 	clientFactory := az.NetworkClientFactory
 	if clientFactory == nil {
 		// multi-tenant support
@@ -655,13 +647,12 @@ func (az *Cloud) createVNetLink(ctx context.Context, vNetLinkName, vnetResourceG
 			VirtualNetwork:      &privatedns.SubResource{ID: &vnetID},
 			RegistrationEnabled: pointer.Bool(false)},
 	}
-	_, err := vnetLinkClient.CreateOrUpdate(ctx, vnetResourceGroup, privateDNSZoneName, vNetLinkName, parameters)
+	_, err := vnetLinkClient.CreateOrUpdate(ctx, privateDNSZoneResourceGroup, privateDNSZoneName, vNetLinkName, parameters)
 	return err
 }
 
-func (az *Cloud) createPrivateDNSZoneGroup(ctx context.Context, dnsZoneGroupName, privateEndpointName, vnetResourceGroup, vnetName, privateDNSZoneName string) error {
+func (az *Cloud) createPrivateDNSZoneGroup(ctx context.Context, dnsZoneGroupName, privateEndpointName, vnetResourceGroup, vnetName, privateDNSZoneName, privateDNSZoneID string) error {
 	klog.V(2).Infof("Creating private DNS zone group(%s) with privateEndpoint(%s), vNetName(%s), resourceGroup(%s)", dnsZoneGroupName, privateEndpointName, vnetName, vnetResourceGroup)
-	privateDNSZoneID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/privateDnsZones/%s", az.SubscriptionID, vnetResourceGroup, privateDNSZoneName)
 	privateDNSZoneConfig := network.PrivateDNSZoneConfig{
 		Name: &privateDNSZoneName,
 		PrivateDNSZonePropertiesFormat: &network.PrivateDNSZonePropertiesFormat{
@@ -677,8 +668,35 @@ func (az *Cloud) createPrivateDNSZoneGroup(ctx context.Context, dnsZoneGroupName
 }
 
 func (az *Cloud) newStorageAccountCache() (azcache.Resource, error) {
-	getter := func(key string) (interface{}, error) { return nil, nil }
-	return azcache.NewTimedCache(time.Minute, getter, az.Config.DisableAPICallCache)
+	// the key of the storage account carries its subscription and resource group, so that the getter
+	// can get the storage account without reading the cache entry
+	getter := func(key string) (interface{}, error) {
+		parts := strings.Split(key, "/")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid storage account cache key (%s)", key)
+		}
+		if az.StorageAccountClient == nil {
+			return nil, fmt.Errorf("StorageAccountClient is nil")
+		}
+
+		ctx, cancel := getContextWithCancel()
+		defer cancel()
+		result, rerr := az.StorageAccountClient.GetProperties(ctx, parts[0], parts[1], parts[2])
+		if rerr != nil {
+			return nil, rerr.Error()
+		}
+		return result, nil
+	}
+	if az.Config.DisableAPICallCache {
+		// getStorageAccountWithCache gets the storage accounts with the context of the caller
+		getter = func(_ string) (interface{}, error) { return nil, nil }
+	}
+	return azcache.NewTimedCache(time.Minute, getter, az.Config.DisableAPICallCache, az.readMostlyCacheOptions()...)
+}
+
+// getStorageAccountCacheKey returns the key of the storage account in the storage account cache.
+func getStorageAccountCacheKey(subsID, resourceGroup, account string) string {
+	return strings.Join([]string{subsID, resourceGroup, account}, "/")
 }
 
 func (az *Cloud) getStorageAccountWithCache(ctx context.Context, subsID, resourceGroup, account string) (storage.Account, *retry.Error) {
@@ -691,7 +709,8 @@ func (az *Cloud) getStorageAccountWithCache(ctx context.Context, subsID, resourc
 	}
 
 	// search in cache first
-	cache, err := az.storageAccountCache.Get(account, cache.CacheReadTypeDefault)
+	cacheKey := getStorageAccountCacheKey(subsID, resourceGroup, account)
+	cache, err := az.storageAccountCache.Get(cacheKey, cache.CacheReadTypeDefault)
 	if err != nil {
 		return storage.Account{}, retry.NewError(false, err)
 	}
@@ -705,7 +724,7 @@ func (az *Cloud) getStorageAccountWithCache(ctx context.Context, subsID, resourc
 		if rerr != nil {
 			return storage.Account{}, rerr
 		}
-		az.storageAccountCache.Set(account, result)
+		az.storageAccountCache.Set(cacheKey, result)
 	}
 
 	return result, nil
@@ -733,7 +752,7 @@ func (az *Cloud) AddStorageAccountTags(ctx context.Context, subsID, resourceGrou
 
 	if len(newTags) > len(result.Tags) {
 		// only update when newTags is different from old tags
-		_ = az.storageAccountCache.Delete(account) // clean cache
+		_ = az.storageAccountCache.Delete(getStorageAccountCacheKey(subsID, resourceGroup, account)) // clean cache
 		updateParams := storage.AccountUpdateParameters{Tags: newTags}
 		klog.V(2).Infof("add storage account(%s) with tags(%+v)", account, newTags)
 		return az.StorageAccountClient.Update(ctx, subsID, resourceGroup, account, updateParams)
@@ -760,7 +779,7 @@ func (az *Cloud) RemoveStorageAccountTag(ctx context.Context, subsID, resourceGr
 	delete(result.Tags, key)
 	if originalLen != len(result.Tags) {
 		// only update when newTags is different from old tags
-		_ = az.storageAccountCache.Delete(account) // clean cache
+		_ = az.storageAccountCache.Delete(getStorageAccountCacheKey(subsID, resourceGroup, account)) // clean cache
 		updateParams := storage.AccountUpdateParameters{Tags: result.Tags}
 		klog.V(2).Infof("remove tag(%s) from storage account(%s)", key, account)
 		return az.StorageAccountClient.Update(ctx, subsID, resourceGroup, account, updateParams)
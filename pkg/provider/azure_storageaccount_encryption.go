@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+// isCustomerManagedKeyEnabled returns whether the account options encrypt the account with a
+// customer-managed key in the key vault.
+func isCustomerManagedKeyEnabled(accountOptions *AccountOptions) bool {
+	return pointer.StringDeref(accountOptions.KeyVaultURI, "") != ""
+}
+
+// validateEncryptionOptions validates the customer-managed key settings of the account options.
+func validateEncryptionOptions(accountOptions *AccountOptions) error {
+	if !isCustomerManagedKeyEnabled(accountOptions) {
+		if pointer.StringDeref(accountOptions.EncryptionUserAssignedIdentity, "") != "" {
+			return fmt.Errorf("KeyVaultURI is required when EncryptionUserAssignedIdentity is set")
+		}
+		return nil
+	}
+	if pointer.StringDeref(accountOptions.KeyName, "") == "" {
+		return fmt.Errorf("KeyName is required when KeyVaultURI is set")
+	}
+	return nil
+}
+
+// getStorageAccountEncryption returns the encryption settings of the account options. The key
+// version is left empty for a versionless key, so that the account is automatically re-keyed
+// with the latest key version in the key vault.
+func getStorageAccountEncryption(accountOptions *AccountOptions) *storage.Encryption {
+	services := &storage.EncryptionServices{
+		File: &storage.EncryptionService{Enabled: pointer.Bool(true)},
+		Blob: &storage.EncryptionService{Enabled: pointer.Bool(true)},
+	}
+	if !isCustomerManagedKeyEnabled(accountOptions) {
+		if accountOptions.RequireInfrastructureEncryption == nil {
+			return nil
+		}
+		return &storage.Encryption{
+			RequireInfrastructureEncryption: accountOptions.RequireInfrastructureEncryption,
+			KeySource:                       storage.KeySourceMicrosoftStorage,
+			Services:                        services,
+		}
+	}
+
+	encryption := &storage.Encryption{
+		RequireInfrastructureEncryption: accountOptions.RequireInfrastructureEncryption,
+		KeyVaultProperties: &storage.KeyVaultProperties{
+			KeyName:     accountOptions.KeyName,
+			KeyVersion:  pointer.String(pointer.StringDeref(accountOptions.KeyVersion, "")),
+			KeyVaultURI: accountOptions.KeyVaultURI,
+		},
+		KeySource: storage.KeySourceMicrosoftKeyvault,
+		Services:  services,
+	}
+	if identity := pointer.StringDeref(accountOptions.EncryptionUserAssignedIdentity, ""); identity != "" {
+		encryption.EncryptionIdentity = &storage.EncryptionIdentity{
+			EncryptionUserAssignedIdentity: pointer.String(identity),
+		}
+	}
+	return encryption
+}
+
+// getStorageAccountIdentity returns the identity of the account accessing the key vault, which is
+// the system-assigned identity if no user-assigned identity is set, or nil if no customer-managed
+// key is set.
+func getStorageAccountIdentity(accountOptions *AccountOptions) *storage.Identity {
+	if !isCustomerManagedKeyEnabled(accountOptions) {
+		return nil
+	}
+	identity := pointer.StringDeref(accountOptions.EncryptionUserAssignedIdentity, "")
+	if identity == "" {
+		return &storage.Identity{Type: storage.IdentityTypeSystemAssigned}
+	}
+	return &storage.Identity{
+		Type: storage.IdentityTypeUserAssigned,
+		UserAssignedIdentities: map[string]*storage.UserAssignedIdentity{
+			identity: {},
+		},
+	}
+}
+
+// isEncryptionEqual returns whether the account is encrypted with the customer-managed key of the
+// account options, or with the Microsoft-managed key if no customer-managed key is set.
+func isEncryptionEqual(account storage.Account, accountOptions *AccountOptions) bool {
+	var encryption *storage.Encryption
+	if account.AccountProperties != nil {
+		encryption = account.Encryption
+	}
+	if !isCustomerManagedKeyEnabled(accountOptions) {
+		return encryption == nil || !strings.EqualFold(string(encryption.KeySource), string(storage.KeySourceMicrosoftKeyvault))
+	}
+	if encryption == nil || encryption.KeyVaultProperties == nil ||
+		!strings.EqualFold(string(encryption.KeySource), string(storage.KeySourceMicrosoftKeyvault)) {
+		return false
+	}
+
+	properties := encryption.KeyVaultProperties
+	if !strings.EqualFold(strings.TrimSuffix(pointer.StringDeref(properties.KeyVaultURI, ""), "/"), strings.TrimSuffix(*accountOptions.KeyVaultURI, "/")) ||
+		!strings.EqualFold(pointer.StringDeref(properties.KeyName, ""), pointer.StringDeref(accountOptions.KeyName, "")) {
+		return false
+	}
+	// a versionless key is only equal to the automatically rotated key of the account, not to a
+	// pinned key version even if it's the latest one
+	if pointer.StringDeref(properties.KeyVersion, "") != pointer.StringDeref(accountOptions.KeyVersion, "") {
+		return false
+	}
+
+	var encryptionIdentity string
+	if encryption.EncryptionIdentity != nil {
+		encryptionIdentity = pointer.StringDeref(encryption.EncryptionIdentity.EncryptionUserAssignedIdentity, "")
+	}
+	return strings.EqualFold(encryptionIdentity, pointer.StringDeref(accountOptions.EncryptionUserAssignedIdentity, ""))
+}
+
+// RekeyStorageAccount updates the customer-managed key of the storage account created by the cloud
+// provider to the key of the account options, e.g. to another key or to a versionless key which is
+// rotated automatically. Only KeyName, KeyVersion, KeyVaultURI and EncryptionUserAssignedIdentity
+// of the account options are used, and the account is not updated if it already uses the key.
+func (az *Cloud) RekeyStorageAccount(ctx context.Context, subsID, resourceGroup, account string, accountOptions *AccountOptions) error {
+	if accountOptions == nil {
+		return fmt.Errorf("account options is nil")
+	}
+	if !isCustomerManagedKeyEnabled(accountOptions) {
+		return fmt.Errorf("KeyVaultURI is required to re-key storage account %s", account)
+	}
+	if err := validateEncryptionOptions(accountOptions); err != nil {
+		return err
+	}
+
+	// add lock to avoid concurrent update on the cache
+	az.lockMap.LockEntry(account)
+	defer az.lockMap.UnlockEntry(account)
+
+	result, rerr := az.getStorageAccountWithCache(ctx, subsID, resourceGroup, account)
+	if rerr != nil {
+		return fmt.Errorf("failed to get storage account %s: %w", account, rerr.Error())
+	}
+	if pointer.StringDeref(result.Tags[consts.CreatedByTag], "") != "azure" {
+		return fmt.Errorf("storage account %s is not created by the cloud provider, tag %s is not found", account, consts.CreatedByTag)
+	}
+	if isEncryptionEqual(result, accountOptions) {
+		klog.V(2).Infof("storage account(%s) is already encrypted with key(%s) in key vault(%s)", account, *accountOptions.KeyName, *accountOptions.KeyVaultURI)
+		return nil
+	}
+
+	encryption := getStorageAccountEncryption(accountOptions)
+	// infrastructure encryption can't be changed after the account is created
+	encryption.RequireInfrastructureEncryption = nil
+	updateParams := storage.AccountUpdateParameters{
+		Identity: getStorageAccountIdentity(accountOptions),
+		AccountPropertiesUpdateParameters: &storage.AccountPropertiesUpdateParameters{
+			Encryption: encryption,
+		},
+	}
+	_ = az.storageAccountCache.Delete(getStorageAccountCacheKey(subsID, resourceGroup, account)) // clean cache
+	klog.V(2).Infof("re-key storage account(%s) with key(%s) version(%s) in key vault(%s)", account, *accountOptions.KeyName, pointer.StringDeref(accountOptions.KeyVersion, ""), *accountOptions.KeyVaultURI)
+	if rerr := az.StorageAccountClient.Update(ctx, subsID, resourceGroup, account, updateParams); rerr != nil {
+		return fmt.Errorf("failed to re-key storage account %s: %w", account, rerr.Error())
+	}
+	return nil
+}
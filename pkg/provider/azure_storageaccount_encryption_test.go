@@ -0,0 +1,277 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/storageaccountclient/mockstorageaccountclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+	"sigs.k8s.io/cloud-provider-azure/pkg/retry"
+)
+
+const testEncryptionIdentity = "/subscriptions/subs/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/identity"
+
+// cmkEncryption returns the encryption of an account with the customer-managed key.
+func cmkEncryption(keyVaultURI, keyName, keyVersion, identity string) *storage.Encryption {
+	encryption := &storage.Encryption{
+		KeySource: storage.KeySourceMicrosoftKeyvault,
+		KeyVaultProperties: &storage.KeyVaultProperties{
+			KeyVaultURI: pointer.String(keyVaultURI),
+			KeyName:     pointer.String(keyName),
+			KeyVersion:  pointer.String(keyVersion),
+		},
+	}
+	if identity != "" {
+		encryption.EncryptionIdentity = &storage.EncryptionIdentity{EncryptionUserAssignedIdentity: pointer.String(identity)}
+	}
+	return encryption
+}
+
+func TestIsEncryptionEqual(t *testing.T) {
+	tests := []struct {
+		desc           string
+		encryption     *storage.Encryption
+		accountOptions *AccountOptions
+		expected       bool
+	}{
+		{
+			desc:           "account without encryption should match options without customer-managed key",
+			accountOptions: &AccountOptions{},
+			expected:       true,
+		},
+		{
+			desc:           "account with Microsoft-managed key should match options without customer-managed key",
+			encryption:     &storage.Encryption{KeySource: storage.KeySourceMicrosoftStorage},
+			accountOptions: &AccountOptions{},
+			expected:       true,
+		},
+		{
+			desc:           "account with customer-managed key should not match options without customer-managed key",
+			encryption:     cmkEncryption("https://vault.vault.azure.net/", "key", "", ""),
+			accountOptions: &AccountOptions{},
+			expected:       false,
+		},
+		{
+			desc:           "account without encryption should not match options with customer-managed key",
+			accountOptions: &AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net/"), KeyName: pointer.String("key")},
+			expected:       false,
+		},
+		{
+			desc:           "account with the same versionless key should match",
+			encryption:     cmkEncryption("https://VAULT.vault.azure.net/", "key", "", ""),
+			accountOptions: &AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net"), KeyName: pointer.String("key")},
+			expected:       true,
+		},
+		{
+			desc:           "account with another key should not match",
+			encryption:     cmkEncryption("https://vault.vault.azure.net/", "key", "", ""),
+			accountOptions: &AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net/"), KeyName: pointer.String("key2")},
+			expected:       false,
+		},
+		{
+			desc:           "account with a pinned key version should not match the versionless key",
+			encryption:     cmkEncryption("https://vault.vault.azure.net/", "key", "v1", ""),
+			accountOptions: &AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net/"), KeyName: pointer.String("key")},
+			expected:       false,
+		},
+		{
+			desc:           "account with the same key version should match",
+			encryption:     cmkEncryption("https://vault.vault.azure.net/", "key", "v1", ""),
+			accountOptions: &AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net/"), KeyName: pointer.String("key"), KeyVersion: pointer.String("v1")},
+			expected:       true,
+		},
+		{
+			desc:       "account with the same user-assigned identity should match",
+			encryption: cmkEncryption("https://vault.vault.azure.net/", "key", "", testEncryptionIdentity),
+			accountOptions: &AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net/"), KeyName: pointer.String("key"),
+				EncryptionUserAssignedIdentity: pointer.String(testEncryptionIdentity)},
+			expected: true,
+		},
+		{
+			desc:           "account with the system-assigned identity should not match the user-assigned identity",
+			encryption:     cmkEncryption("https://vault.vault.azure.net/", "key", "", ""),
+			accountOptions: &AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net/"), KeyName: pointer.String("key"), EncryptionUserAssignedIdentity: pointer.String(testEncryptionIdentity)},
+			expected:       false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			account := storage.Account{AccountProperties: &storage.AccountProperties{Encryption: test.encryption}}
+			assert.Equal(t, test.expected, isEncryptionEqual(account, test.accountOptions))
+		})
+	}
+}
+
+func TestGetStorageAccountEncryption(t *testing.T) {
+	accountOptions := &AccountOptions{
+		RequireInfrastructureEncryption: pointer.Bool(true),
+		KeyVaultURI:                     pointer.String("https://vault.vault.azure.net/"),
+		KeyName:                         pointer.String("key"),
+		EncryptionUserAssignedIdentity:  pointer.String(testEncryptionIdentity),
+	}
+	encryption := getStorageAccountEncryption(accountOptions)
+	assert.Equal(t, storage.KeySourceMicrosoftKeyvault, encryption.KeySource)
+	assert.Equal(t, pointer.Bool(true), encryption.RequireInfrastructureEncryption)
+	assert.Equal(t, pointer.String(""), encryption.KeyVaultProperties.KeyVersion)
+	assert.Equal(t, pointer.String(testEncryptionIdentity), encryption.EncryptionIdentity.EncryptionUserAssignedIdentity)
+	assert.Equal(t, &storage.Identity{
+		Type:                   storage.IdentityTypeUserAssigned,
+		UserAssignedIdentities: map[string]*storage.UserAssignedIdentity{testEncryptionIdentity: {}},
+	}, getStorageAccountIdentity(accountOptions))
+
+	assert.Nil(t, getStorageAccountEncryption(&AccountOptions{}))
+	assert.Nil(t, getStorageAccountIdentity(&AccountOptions{}))
+	assert.Equal(t, &storage.Identity{Type: storage.IdentityTypeSystemAssigned},
+		getStorageAccountIdentity(&AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net/"), KeyName: pointer.String("key")}))
+	assert.Equal(t, storage.KeySourceMicrosoftStorage, getStorageAccountEncryption(&AccountOptions{RequireInfrastructureEncryption: pointer.Bool(true)}).KeySource)
+}
+
+func TestValidateEncryptionOptions(t *testing.T) {
+	assert.NoError(t, validateEncryptionOptions(&AccountOptions{}))
+	assert.NoError(t, validateEncryptionOptions(&AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net/"), KeyName: pointer.String("key")}))
+	assert.EqualError(t, validateEncryptionOptions(&AccountOptions{KeyVaultURI: pointer.String("https://vault.vault.azure.net/")}),
+		"KeyName is required when KeyVaultURI is set")
+	assert.EqualError(t, validateEncryptionOptions(&AccountOptions{EncryptionUserAssignedIdentity: pointer.String(testEncryptionIdentity)}),
+		"KeyVaultURI is required when EncryptionUserAssignedIdentity is set")
+}
+
+func TestEnsureStorageAccountWithCustomerManagedKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	location := TestLocation
+	accounts := []storage.Account{
+		{
+			Name: pointer.String("pinned"), Sku: &storage.Sku{Name: storage.SkuNameStandardLRS}, Location: &location,
+			AccountProperties: &storage.AccountProperties{Encryption: cmkEncryption("https://vault.vault.azure.net/", "key", "v1", "")},
+		},
+		{
+			Name: pointer.String("versionless"), Sku: &storage.Sku{Name: storage.SkuNameStandardLRS}, Location: &location,
+			AccountProperties: &storage.AccountProperties{Encryption: cmkEncryption("https://vault.vault.azure.net/", "key", "", "")},
+		},
+	}
+	keys := storage.AccountListKeysResult{Keys: &[]storage.AccountKey{{Value: pointer.String("key")}}}
+
+	mockStorageAccountsClient := mockstorageaccountclient.NewMockInterface(ctrl)
+	cloud.StorageAccountClient = mockStorageAccountsClient
+	mockStorageAccountsClient.EXPECT().ListByResourceGroup(gomock.Any(), "subs", "rg").Return(accounts, nil).Times(2)
+	mockStorageAccountsClient.EXPECT().ListKeys(gomock.Any(), "subs", "rg", gomock.Any()).Return(keys, nil).AnyTimes()
+	mockStorageAccountsClient.EXPECT().Create(gomock.Any(), "subs", "rg", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _, _ string, parameters storage.AccountCreateParameters) *retry.Error {
+			assert.Equal(t, storage.IdentityTypeUserAssigned, parameters.Identity.Type)
+			assert.Equal(t, pointer.Bool(true), parameters.Encryption.RequireInfrastructureEncryption)
+			assert.Equal(t, storage.KeySourceMicrosoftKeyvault, parameters.Encryption.KeySource)
+			assert.Equal(t, pointer.String(testEncryptionIdentity), parameters.Encryption.EncryptionIdentity.EncryptionUserAssignedIdentity)
+			return nil
+		}).Times(1)
+
+	accountOptions := &AccountOptions{
+		SubscriptionID:         "subs",
+		ResourceGroup:          "rg",
+		Type:                   string(storage.SkuNameStandardLRS),
+		Location:               location,
+		EnableHTTPSTrafficOnly: true,
+		KeyVaultURI:            pointer.String("https://vault.vault.azure.net/"),
+		KeyName:                pointer.String("key"),
+	}
+	accountName, _, err := cloud.EnsureStorageAccount(context.TODO(), accountOptions, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "versionless", accountName)
+
+	accountOptions.RequireInfrastructureEncryption = pointer.Bool(true)
+	accountOptions.EncryptionUserAssignedIdentity = pointer.String(testEncryptionIdentity)
+	accountName, _, err = cloud.EnsureStorageAccount(context.TODO(), accountOptions, "")
+	assert.NoError(t, err)
+	assert.NotContains(t, []string{"pinned", "versionless"}, accountName)
+}
+
+func TestRekeyStorageAccount(t *testing.T) {
+	accountOptions := &AccountOptions{
+		KeyVaultURI:                    pointer.String("https://vault.vault.azure.net/"),
+		KeyName:                        pointer.String("key"),
+		EncryptionUserAssignedIdentity: pointer.String(testEncryptionIdentity),
+	}
+	createdByTags := map[string]*string{consts.CreatedByTag: pointer.String("azure")}
+
+	tests := []struct {
+		desc           string
+		account        storage.Account
+		accountOptions *AccountOptions
+		expectUpdate   bool
+		expectedErr    string
+	}{
+		{
+			desc:           "pinned key version should be re-keyed to the versionless key",
+			account:        storage.Account{Tags: createdByTags, AccountProperties: &storage.AccountProperties{Encryption: cmkEncryption("https://vault.vault.azure.net/", "key", "v1", "")}},
+			accountOptions: accountOptions,
+			expectUpdate:   true,
+		},
+		{
+			desc:           "account already using the key should not be updated",
+			account:        storage.Account{Tags: createdByTags, AccountProperties: &storage.AccountProperties{Encryption: cmkEncryption("https://vault.vault.azure.net/", "key", "", testEncryptionIdentity)}},
+			accountOptions: accountOptions,
+		},
+		{
+			desc:           "account not created by the cloud provider should not be re-keyed",
+			account:        storage.Account{AccountProperties: &storage.AccountProperties{}},
+			accountOptions: accountOptions,
+			expectedErr:    "storage account account is not created by the cloud provider, tag k8s-azure-created-by is not found",
+		},
+		{
+			desc:           "key vault URI is required",
+			accountOptions: &AccountOptions{KeyName: pointer.String("key")},
+			expectedErr:    "KeyVaultURI is required to re-key storage account account",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cloud := GetTestCloud(ctrl)
+
+			mockStorageAccountsClient := mockstorageaccountclient.NewMockInterface(ctrl)
+			cloud.StorageAccountClient = mockStorageAccountsClient
+			mockStorageAccountsClient.EXPECT().GetProperties(gomock.Any(), "subs", "rg", "account").Return(test.account, nil).MaxTimes(1)
+			if test.expectUpdate {
+				mockStorageAccountsClient.EXPECT().Update(gomock.Any(), "subs", "rg", "account", gomock.Any()).DoAndReturn(
+					func(_ context.Context, _, _, _ string, parameters storage.AccountUpdateParameters) *retry.Error {
+						assert.Equal(t, getStorageAccountIdentity(test.accountOptions), parameters.Identity)
+						assert.Nil(t, parameters.Encryption.RequireInfrastructureEncryption)
+						assert.Equal(t, pointer.String(""), parameters.Encryption.KeyVaultProperties.KeyVersion)
+						return nil
+					}).Times(1)
+			}
+
+			err := cloud.RekeyStorageAccount(context.TODO(), "subs", "rg", "account", test.accountOptions)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
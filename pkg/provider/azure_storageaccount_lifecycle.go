@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	blobLifecycleRuleType = "Lifecycle"
+	blobTypeBlockBlob     = "blockBlob"
+)
+
+// BlobLifecycleRule is a lifecycle management rule of the block blobs in the storage account. The
+// days are counted from the last modification of the blobs, and 0 means the action is not taken.
+type BlobLifecycleRule struct {
+	Name string
+	// PrefixMatch limits the rule to the blobs with the prefixes, which start with the container
+	// names, e.g. "container/path". The rule applies to all the blobs if it's empty.
+	PrefixMatch            []string
+	TierToCoolAfterDays    int32
+	TierToArchiveAfterDays int32
+	DeleteAfterDays        int32
+}
+
+// validateBlobLifecycleRules validates the blob lifecycle rules of the account options.
+func validateBlobLifecycleRules(rules []BlobLifecycleRule) error {
+	names := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return fmt.Errorf("name of the blob lifecycle rule is required")
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("blob lifecycle rule %s is duplicated", rule.Name)
+		}
+		names[rule.Name] = true
+		if rule.TierToCoolAfterDays < 0 || rule.TierToArchiveAfterDays < 0 || rule.DeleteAfterDays < 0 {
+			return fmt.Errorf("days of blob lifecycle rule %s can't be negative", rule.Name)
+		}
+		if rule.TierToCoolAfterDays == 0 && rule.TierToArchiveAfterDays == 0 && rule.DeleteAfterDays == 0 {
+			return fmt.Errorf("blob lifecycle rule %s has no action", rule.Name)
+		}
+	}
+	return nil
+}
+
+// daysAfterModification returns the condition of the action taken days after the last modification,
+// or nil if the action is not taken.
+func daysAfterModification(days int32) *storage.DateAfterModification {
+	if days == 0 {
+		return nil
+	}
+	return &storage.DateAfterModification{DaysAfterModificationGreaterThan: pointer.Float64(float64(days))}
+}
+
+// getBlobLifecyclePolicy returns the management policy of the blob lifecycle rules.
+func getBlobLifecyclePolicy(rules []BlobLifecycleRule) storage.ManagementPolicy {
+	policyRules := make([]storage.ManagementPolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		filters := &storage.ManagementPolicyFilter{BlobTypes: &[]string{blobTypeBlockBlob}}
+		if len(rule.PrefixMatch) > 0 {
+			prefixMatch := append([]string{}, rule.PrefixMatch...)
+			filters.PrefixMatch = &prefixMatch
+		}
+		policyRules = append(policyRules, storage.ManagementPolicyRule{
+			Name:    pointer.String(rule.Name),
+			Enabled: pointer.Bool(true),
+			Type:    pointer.String(blobLifecycleRuleType),
+			Definition: &storage.ManagementPolicyDefinition{
+				Filters: filters,
+				Actions: &storage.ManagementPolicyAction{
+					BaseBlob: &storage.ManagementPolicyBaseBlob{
+						TierToCool:    daysAfterModification(rule.TierToCoolAfterDays),
+						TierToArchive: daysAfterModification(rule.TierToArchiveAfterDays),
+						Delete:        daysAfterModification(rule.DeleteAfterDays),
+					},
+				},
+			},
+		})
+	}
+	return storage.ManagementPolicy{
+		ManagementPolicyProperties: &storage.ManagementPolicyProperties{
+			Policy: &storage.ManagementPolicySchema{Rules: &policyRules},
+		},
+	}
+}
+
+// getBlobLifecycleRule converts the management policy rule to the blob lifecycle rule, and returns
+// false if the rule has any setting the blob lifecycle rules don't have, e.g. snapshot actions.
+func getBlobLifecycleRule(policyRule storage.ManagementPolicyRule) (BlobLifecycleRule, bool) {
+	rule := BlobLifecycleRule{Name: pointer.StringDeref(policyRule.Name, "")}
+	definition := policyRule.Definition
+	if !pointer.BoolDeref(policyRule.Enabled, true) ||
+		pointer.StringDeref(policyRule.Type, blobLifecycleRuleType) != blobLifecycleRuleType ||
+		definition == nil || definition.Actions == nil || definition.Actions.BaseBlob == nil ||
+		definition.Actions.Snapshot != nil || definition.Actions.Version != nil {
+		return rule, false
+	}
+
+	if filters := definition.Filters; filters != nil {
+		if filters.BlobIndexMatch != nil && len(*filters.BlobIndexMatch) > 0 {
+			return rule, false
+		}
+		if filters.BlobTypes == nil || !reflect.DeepEqual(*filters.BlobTypes, []string{blobTypeBlockBlob}) {
+			return rule, false
+		}
+		if filters.PrefixMatch != nil && len(*filters.PrefixMatch) > 0 {
+			rule.PrefixMatch = append([]string{}, *filters.PrefixMatch...)
+			sort.Strings(rule.PrefixMatch)
+		}
+	}
+
+	days := func(condition *storage.DateAfterModification) (int32, bool) {
+		if condition == nil {
+			return 0, true
+		}
+		if condition.DaysAfterLastAccessTimeGreaterThan != nil || condition.DaysAfterLastTierChangeGreaterThan != nil ||
+			condition.DaysAfterCreationGreaterThan != nil || condition.DaysAfterModificationGreaterThan == nil {
+			return 0, false
+		}
+		return int32(*condition.DaysAfterModificationGreaterThan), true
+	}
+	baseBlob := definition.Actions.BaseBlob
+	var ok bool
+	if rule.TierToCoolAfterDays, ok = days(baseBlob.TierToCool); !ok {
+		return rule, false
+	}
+	if rule.TierToArchiveAfterDays, ok = days(baseBlob.TierToArchive); !ok {
+		return rule, false
+	}
+	if rule.DeleteAfterDays, ok = days(baseBlob.Delete); !ok {
+		return rule, false
+	}
+	return rule, !pointer.BoolDeref(baseBlob.EnableAutoTierToHotFromCool, false)
+}
+
+// isBlobLifecyclePolicyEqual returns whether the management policy has exactly the blob lifecycle rules.
+func isBlobLifecyclePolicyEqual(policy storage.ManagementPolicy, rules []BlobLifecycleRule) bool {
+	var policyRules []storage.ManagementPolicyRule
+	if policy.ManagementPolicyProperties != nil && policy.Policy != nil && policy.Policy.Rules != nil {
+		policyRules = *policy.Policy.Rules
+	}
+	if len(policyRules) != len(rules) {
+		return false
+	}
+
+	actual := make(map[string]BlobLifecycleRule, len(policyRules))
+	for _, policyRule := range policyRules {
+		rule, ok := getBlobLifecycleRule(policyRule)
+		if !ok {
+			return false
+		}
+		actual[rule.Name] = rule
+	}
+	for _, rule := range rules {
+		expected := rule
+		expected.PrefixMatch = nil
+		if len(rule.PrefixMatch) > 0 {
+			expected.PrefixMatch = append([]string{}, rule.PrefixMatch...)
+			sort.Strings(expected.PrefixMatch)
+		}
+		if !reflect.DeepEqual(actual[rule.Name], expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// getBlobLifecyclePolicyOfAccount returns the management policy of the storage account, which is
+// empty if the account has no management policy.
+func (az *Cloud) getBlobLifecyclePolicyOfAccount(ctx context.Context, subsID, resourceGroup, accountName string) (storage.ManagementPolicy, error) {
+	policy, err := az.BlobClient.GetManagementPolicy(ctx, subsID, resourceGroup, accountName)
+	if err != nil {
+		var detailedErr autorest.DetailedError
+		if errors, ok := err.(autorest.DetailedError); ok {
+			detailedErr = errors
+		}
+		if detailedErr.StatusCode == http.StatusNotFound {
+			return storage.ManagementPolicy{}, nil
+		}
+		return storage.ManagementPolicy{}, err
+	}
+	return policy, nil
+}
+
+// isBlobLifecycleRulesEqual returns whether the storage account has exactly the blob lifecycle rules of
+// the account options. Any account matches if the account options have no blob lifecycle rules, so that
+// the management policies are only read when the rules are requested. The errors other than not found
+// are returned instead of not matching, so that a throttled request doesn't create another account.
+func (az *Cloud) isBlobLifecycleRulesEqual(ctx context.Context, account storage.Account, accountOptions *AccountOptions) (bool, error) {
+	if len(accountOptions.BlobLifecycleRules) == 0 {
+		return true, nil
+	}
+
+	policy, err := az.getBlobLifecyclePolicyOfAccount(ctx, accountOptions.SubscriptionID, accountOptions.ResourceGroup, *account.Name)
+	if err != nil {
+		return false, fmt.Errorf("failed to get management policy of storage account %s: %w", *account.Name, err)
+	}
+	return isBlobLifecyclePolicyEqual(policy, accountOptions.BlobLifecycleRules), nil
+}
+
+// EnsureBlobLifecycleRules sets the blob lifecycle management policy of the storage account to the
+// rules, replacing the existing rules of the account. The policy is deleted if there are no rules.
+func (az *Cloud) EnsureBlobLifecycleRules(ctx context.Context, subsID, resourceGroup, accountName string, rules []BlobLifecycleRule) error {
+	if err := validateBlobLifecycleRules(rules); err != nil {
+		return err
+	}
+
+	policy, err := az.getBlobLifecyclePolicyOfAccount(ctx, subsID, resourceGroup, accountName)
+	if err != nil {
+		return fmt.Errorf("failed to get management policy of storage account %s: %w", accountName, err)
+	}
+	if isBlobLifecyclePolicyEqual(policy, rules) {
+		klog.V(4).Infof("blob lifecycle rules of storage account(%s) are up to date", accountName)
+		return nil
+	}
+
+	if len(rules) == 0 {
+		klog.V(2).Infof("delete blob lifecycle rules of storage account(%s)", accountName)
+		if err := az.BlobClient.DeleteManagementPolicy(ctx, subsID, resourceGroup, accountName); err != nil {
+			return fmt.Errorf("failed to delete management policy of storage account %s: %w", accountName, err)
+		}
+		return nil
+	}
+	klog.V(2).Infof("set blob lifecycle rules(%+v) of storage account(%s)", rules, accountName)
+	if _, err := az.BlobClient.CreateOrUpdateManagementPolicy(ctx, subsID, resourceGroup, accountName, getBlobLifecyclePolicy(rules)); err != nil {
+		return fmt.Errorf("failed to set management policy of storage account %s: %w", accountName, err)
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/blobclient/mockblobclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/storageaccountclient/mockstorageaccountclient"
+)
+
+func TestValidateBlobLifecycleRules(t *testing.T) {
+	tests := []struct {
+		desc        string
+		rules       []BlobLifecycleRule
+		expectedErr string
+	}{
+		{
+			desc: "no rules",
+		},
+		{
+			desc:  "valid rules",
+			rules: []BlobLifecycleRule{{Name: "cool", TierToCoolAfterDays: 30}, {Name: "delete", PrefixMatch: []string{"logs/"}, DeleteAfterDays: 90}},
+		},
+		{
+			desc:        "rule without name",
+			rules:       []BlobLifecycleRule{{DeleteAfterDays: 90}},
+			expectedErr: "name of the blob lifecycle rule is required",
+		},
+		{
+			desc:        "duplicated rules",
+			rules:       []BlobLifecycleRule{{Name: "rule", TierToCoolAfterDays: 30}, {Name: "rule", DeleteAfterDays: 90}},
+			expectedErr: "blob lifecycle rule rule is duplicated",
+		},
+		{
+			desc:        "negative days",
+			rules:       []BlobLifecycleRule{{Name: "rule", DeleteAfterDays: -1}},
+			expectedErr: "days of blob lifecycle rule rule can't be negative",
+		},
+		{
+			desc:        "rule without action",
+			rules:       []BlobLifecycleRule{{Name: "rule", PrefixMatch: []string{"logs/"}}},
+			expectedErr: "blob lifecycle rule rule has no action",
+		},
+	}
+
+	for _, test := range tests {
+		err := validateBlobLifecycleRules(test.rules)
+		if test.expectedErr == "" {
+			assert.NoError(t, err, test.desc)
+		} else {
+			assert.EqualError(t, err, test.expectedErr, test.desc)
+		}
+	}
+}
+
+func TestIsBlobLifecyclePolicyEqual(t *testing.T) {
+	rules := []BlobLifecycleRule{
+		{Name: "cool", TierToCoolAfterDays: 30, TierToArchiveAfterDays: 60},
+		{Name: "delete", PrefixMatch: []string{"logs/b", "logs/a"}, DeleteAfterDays: 90},
+	}
+	policy := getBlobLifecyclePolicy(rules)
+
+	disabled := getBlobLifecyclePolicy(rules)
+	(*disabled.Policy.Rules)[0].Enabled = pointer.Bool(false)
+	snapshotAction := getBlobLifecyclePolicy(rules)
+	(*snapshotAction.Policy.Rules)[1].Definition.Actions.Snapshot = &storage.ManagementPolicySnapShot{
+		Delete: &storage.DateAfterCreation{DaysAfterCreationGreaterThan: pointer.Float64(30)},
+	}
+	lastAccessTime := getBlobLifecyclePolicy(rules)
+	(*lastAccessTime.Policy.Rules)[0].Definition.Actions.BaseBlob.TierToCool = &storage.DateAfterModification{
+		DaysAfterLastAccessTimeGreaterThan: pointer.Float64(30),
+	}
+
+	tests := []struct {
+		desc     string
+		policy   storage.ManagementPolicy
+		rules    []BlobLifecycleRule
+		expected bool
+	}{
+		{
+			desc:     "empty policy without rules",
+			expected: true,
+		},
+		{
+			desc:   "empty policy with rules",
+			rules:  rules,
+			policy: storage.ManagementPolicy{},
+		},
+		{
+			desc:     "same rules",
+			policy:   policy,
+			rules:    rules,
+			expected: true,
+		},
+		{
+			desc:   "same rules in different order",
+			policy: policy,
+			rules: []BlobLifecycleRule{
+				{Name: "delete", PrefixMatch: []string{"logs/a", "logs/b"}, DeleteAfterDays: 90},
+				{Name: "cool", TierToCoolAfterDays: 30, TierToArchiveAfterDays: 60},
+			},
+			expected: true,
+		},
+		{
+			desc:   "different days",
+			policy: policy,
+			rules: []BlobLifecycleRule{
+				{Name: "cool", TierToCoolAfterDays: 30},
+				{Name: "delete", PrefixMatch: []string{"logs/a", "logs/b"}, DeleteAfterDays: 90},
+			},
+		},
+		{
+			desc:   "extra rule in the policy",
+			policy: policy,
+			rules:  rules[:1],
+		},
+		{
+			desc:   "disabled rule",
+			policy: disabled,
+			rules:  rules,
+		},
+		{
+			desc:   "snapshot action",
+			policy: snapshotAction,
+			rules:  rules,
+		},
+		{
+			desc:   "last access time condition",
+			policy: lastAccessTime,
+			rules:  rules,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, isBlobLifecyclePolicyEqual(test.policy, test.rules), test.desc)
+	}
+}
+
+func TestIsBlobLifecycleRulesEqual(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	rules := []BlobLifecycleRule{{Name: "delete", DeleteAfterDays: 90}}
+	account := storage.Account{Name: pointer.String("account")}
+	mockBlobClient := mockblobclient.NewMockInterface(ctrl)
+	cloud.BlobClient = mockBlobClient
+
+	// any account matches the account options without rules, without getting its management policy
+	equal, err := cloud.isBlobLifecycleRulesEqual(context.TODO(), account, &AccountOptions{SubscriptionID: "subs", ResourceGroup: "rg"})
+	assert.NoError(t, err)
+	assert.True(t, equal)
+
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(getBlobLifecyclePolicy(rules), nil).Times(1)
+	equal, err = cloud.isBlobLifecycleRulesEqual(context.TODO(), account, &AccountOptions{SubscriptionID: "subs", ResourceGroup: "rg", BlobLifecycleRules: rules})
+	assert.NoError(t, err)
+	assert.True(t, equal)
+
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(storage.ManagementPolicy{}, autorest.DetailedError{StatusCode: http.StatusNotFound}).Times(1)
+	equal, err = cloud.isBlobLifecycleRulesEqual(context.TODO(), account, &AccountOptions{SubscriptionID: "subs", ResourceGroup: "rg", BlobLifecycleRules: rules})
+	assert.NoError(t, err)
+	assert.False(t, equal)
+
+	// the errors other than not found are returned
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(storage.ManagementPolicy{}, errors.New("get error")).Times(1)
+	_, err = cloud.isBlobLifecycleRulesEqual(context.TODO(), account, &AccountOptions{SubscriptionID: "subs", ResourceGroup: "rg", BlobLifecycleRules: rules})
+	assert.EqualError(t, err, "failed to get management policy of storage account account: get error")
+}
+
+func TestGetStorageAccountsWithBlobLifecycleRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	rules := []BlobLifecycleRule{{Name: "delete", DeleteAfterDays: 90}}
+	accounts := []storage.Account{{
+		Name:     pointer.String("account"),
+		Location: pointer.String("eastus"),
+		Sku:      &storage.Sku{Name: storage.SkuNameStandardLRS},
+		AccountProperties: &storage.AccountProperties{
+			EnableHTTPSTrafficOnly: pointer.Bool(true),
+		},
+	}}
+	mockStorageAccountsClient := mockstorageaccountclient.NewMockInterface(ctrl)
+	mockStorageAccountsClient.EXPECT().ListByResourceGroup(gomock.Any(), "subs", "rg").Return(accounts, nil).Times(2)
+	cloud.StorageAccountClient = mockStorageAccountsClient
+	mockBlobClient := mockblobclient.NewMockInterface(ctrl)
+	cloud.BlobClient = mockBlobClient
+
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(getBlobLifecyclePolicy(rules), nil).Times(1)
+	result, err := cloud.getStorageAccounts(context.TODO(), &AccountOptions{SubscriptionID: "subs", ResourceGroup: "rg", EnableHTTPSTrafficOnly: true, BlobLifecycleRules: rules})
+	assert.NoError(t, err)
+	assert.Equal(t, []accountWithLocation{{Name: "account", StorageType: string(storage.SkuNameStandardLRS), Location: "eastus"}}, result)
+
+	// a throttled request fails the selection instead of skipping the account
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(storage.ManagementPolicy{}, autorest.DetailedError{StatusCode: http.StatusTooManyRequests}).Times(1)
+	_, err = cloud.getStorageAccounts(context.TODO(), &AccountOptions{SubscriptionID: "subs", ResourceGroup: "rg", EnableHTTPSTrafficOnly: true, BlobLifecycleRules: rules})
+	assert.Error(t, err)
+}
+
+func TestEnsureBlobLifecycleRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	rules := []BlobLifecycleRule{{Name: "cool", PrefixMatch: []string{"logs/"}, TierToCoolAfterDays: 30}}
+	mockBlobClient := mockblobclient.NewMockInterface(ctrl)
+	cloud.BlobClient = mockBlobClient
+
+	// the policy is set if the account has no policy
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(storage.ManagementPolicy{}, autorest.DetailedError{StatusCode: http.StatusNotFound}).Times(1)
+	mockBlobClient.EXPECT().CreateOrUpdateManagementPolicy(gomock.Any(), "subs", "rg", "account", getBlobLifecyclePolicy(rules)).Return(storage.ManagementPolicy{}, nil).Times(1)
+	assert.NoError(t, cloud.EnsureBlobLifecycleRules(context.TODO(), "subs", "rg", "account", rules))
+
+	// the policy is not updated if it's up to date
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(getBlobLifecyclePolicy(rules), nil).Times(1)
+	assert.NoError(t, cloud.EnsureBlobLifecycleRules(context.TODO(), "subs", "rg", "account", rules))
+
+	// the policy is deleted if there are no rules
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(getBlobLifecyclePolicy(rules), nil).Times(1)
+	mockBlobClient.EXPECT().DeleteManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(nil).Times(1)
+	assert.NoError(t, cloud.EnsureBlobLifecycleRules(context.TODO(), "subs", "rg", "account", nil))
+
+	// nothing is deleted if the account has no policy
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(storage.ManagementPolicy{}, autorest.DetailedError{StatusCode: http.StatusNotFound}).Times(1)
+	assert.NoError(t, cloud.EnsureBlobLifecycleRules(context.TODO(), "subs", "rg", "account", nil))
+
+	mockBlobClient.EXPECT().GetManagementPolicy(gomock.Any(), "subs", "rg", "account").Return(storage.ManagementPolicy{}, nil).Times(1)
+	mockBlobClient.EXPECT().CreateOrUpdateManagementPolicy(gomock.Any(), "subs", "rg", "account", gomock.Any()).Return(storage.ManagementPolicy{}, errors.New("set error")).Times(1)
+	assert.EqualError(t, cloud.EnsureBlobLifecycleRules(context.TODO(), "subs", "rg", "account", rules),
+		"failed to set management policy of storage account account: set error")
+
+	assert.EqualError(t, cloud.EnsureBlobLifecycleRules(context.TODO(), "subs", "rg", "account", []BlobLifecycleRule{{Name: "rule"}}),
+		"blob lifecycle rule rule has no action")
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2022-07-01/network"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
+)
+
+const privateDNSZoneResourceType = "privateDnsZones"
+
+// PrivateEndpointTarget is a virtual network resolving the private endpoints of the storage account.
+// The private endpoints are created in the subnet of the first target, so SubnetName must be empty
+// for the other targets. The virtual network and subnet of the cloud config are used if they're empty.
+type PrivateEndpointTarget struct {
+	VNetResourceGroup string
+	VNetName          string
+	SubnetName        string
+}
+
+// privateDNSZone is the private DNS zone resolving the private endpoints of a storage type.
+type privateDNSZone struct {
+	ID, ResourceGroup, Name string
+	// external zones are managed outside the cloud provider, including their virtual network links
+	external bool
+}
+
+// privateEndpointOptions are the private endpoints of a storage account, which are created for each
+// storage type in the first target subnet. A private DNS zone registers a single endpoint of the
+// account, so the other targets only link their virtual networks to the zones.
+type privateEndpointOptions struct {
+	targets      []PrivateEndpointTarget
+	storageTypes []StorageType
+	dnsZones     map[StorageType]privateDNSZone
+}
+
+// getPrivateEndpointOptions returns the private endpoints of the account options. The targets default
+// to the virtual network and subnet of the account options, and the storage types default to the
+// storage type of the account options. The private DNS zones not set in the account options are in
+// the resource group of the first target.
+func (az *Cloud) getPrivateEndpointOptions(accountOptions *AccountOptions, vnetResourceGroup, vnetName, subnetName string) (*privateEndpointOptions, error) {
+	options := &privateEndpointOptions{dnsZones: map[StorageType]privateDNSZone{}}
+
+	targets := accountOptions.PrivateEndpointTargets
+	if len(targets) == 0 {
+		targets = []PrivateEndpointTarget{{}}
+	}
+	for i, target := range targets {
+		if target.VNetResourceGroup == "" {
+			target.VNetResourceGroup = vnetResourceGroup
+		}
+		if target.VNetName == "" {
+			target.VNetName = vnetName
+		}
+		if i > 0 && target.SubnetName != "" {
+			return nil, fmt.Errorf("subnet(%s) of private endpoint target vnet(%s) is not used, only the subnet of the first target is used", target.SubnetName, target.VNetName)
+		}
+		if i == 0 && target.SubnetName == "" {
+			target.SubnetName = subnetName
+		}
+		for _, t := range options.targets {
+			if strings.EqualFold(t.VNetResourceGroup, target.VNetResourceGroup) && strings.EqualFold(t.VNetName, target.VNetName) {
+				return nil, fmt.Errorf("private endpoint target vnet(%s) in resourceGroup(%s) is duplicated", target.VNetName, target.VNetResourceGroup)
+			}
+		}
+		options.targets = append(options.targets, target)
+	}
+
+	storageTypes := accountOptions.PrivateEndpointStorageTypes
+	if len(storageTypes) == 0 {
+		storageTypes = []StorageType{accountOptions.StorageType}
+	}
+	for _, storageType := range storageTypes {
+		if storageType != StorageTypeFile && storageType != StorageTypeBlob {
+			return nil, fmt.Errorf("storage type %s of the private endpoints is not supported, supported types are %s and %s", storageType, StorageTypeFile, StorageTypeBlob)
+		}
+		if _, ok := options.dnsZones[storageType]; ok {
+			return nil, fmt.Errorf("storage type %s of the private endpoints is duplicated", storageType)
+		}
+		name := fmt.Sprintf(privateDNSZoneNameFmt, storageType, accountOptions.StorageEndpointSuffix)
+		zoneResourceGroup := options.targets[0].VNetResourceGroup
+		options.dnsZones[storageType] = privateDNSZone{
+			ID:            fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/privateDnsZones/%s", az.SubscriptionID, zoneResourceGroup, name),
+			ResourceGroup: zoneResourceGroup,
+			Name:          name,
+		}
+		options.storageTypes = append(options.storageTypes, storageType)
+	}
+
+	for _, zoneID := range accountOptions.PrivateDNSZoneIDs {
+		resource, err := azure.ParseResourceID(zoneID)
+		if err != nil || !strings.EqualFold(resource.ResourceType, privateDNSZoneResourceType) {
+			return nil, fmt.Errorf("invalid private DNS zone resource ID %s", zoneID)
+		}
+		var matched bool
+		for storageType, zone := range options.dnsZones {
+			if strings.EqualFold(resource.ResourceName, zone.Name) {
+				options.dnsZones[storageType] = privateDNSZone{ID: zoneID, ResourceGroup: resource.ResourceGroup, Name: zone.Name, external: true}
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("private DNS zone %s doesn't match the storage types(%v) of the private endpoints", zoneID, options.storageTypes)
+		}
+	}
+	return options, nil
+}
+
+// getVNetLinkName returns the name of the virtual network link of the target to the private DNS zones.
+// The first target keeps the name of the links created before multiple targets were supported, and
+// the names of the other targets include a hash of their resource group, so that the virtual networks
+// with the same name in different resource groups don't share a link.
+func getVNetLinkName(target PrivateEndpointTarget, first bool) string {
+	if first {
+		return target.VNetName + "-vnetlink"
+	}
+	return fmt.Sprintf("%s-%s-vnetlink", target.VNetName, MakeCRC32(strings.ToLower(target.VNetResourceGroup)))
+}
+
+// getStoragePrivateEndpointName returns the name of the private endpoint of the storage type.
+func getStoragePrivateEndpointName(accountName string, storageType StorageType) string {
+	privateEndpointName := accountName + "-pvtendpoint"
+	if storageType == StorageTypeBlob {
+		privateEndpointName = privateEndpointName + blobNameSuffix
+	}
+	return privateEndpointName
+}
+
+// isPrivateEndpointConnected returns whether the private endpoint is connected to the storage type of the account.
+func isPrivateEndpointConnected(privateEndpoint network.PrivateEndpoint, accountID string, storageType StorageType) bool {
+	if privateEndpoint.PrivateEndpointProperties == nil || privateEndpoint.PrivateLinkServiceConnections == nil {
+		return false
+	}
+	for _, connection := range *privateEndpoint.PrivateLinkServiceConnections {
+		properties := connection.PrivateLinkServiceConnectionProperties
+		if properties == nil || !strings.EqualFold(pointer.StringDeref(properties.PrivateLinkServiceID, ""), accountID) || properties.GroupIds == nil {
+			continue
+		}
+		for _, groupID := range *properties.GroupIds {
+			if strings.EqualFold(groupID, string(storageType)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPrivateDNSZoneGroupEqual returns whether the private DNS zone group registers the private endpoint in the zone.
+func isPrivateDNSZoneGroupEqual(privateDNSZoneGroup network.PrivateDNSZoneGroup, privateDNSZoneID string) bool {
+	if privateDNSZoneGroup.PrivateDNSZoneGroupPropertiesFormat == nil || privateDNSZoneGroup.PrivateDNSZoneConfigs == nil {
+		return false
+	}
+	for _, config := range *privateDNSZoneGroup.PrivateDNSZoneConfigs {
+		if config.PrivateDNSZonePropertiesFormat != nil && strings.EqualFold(pointer.StringDeref(config.PrivateDNSZoneID, ""), privateDNSZoneID) {
+			return true
+		}
+	}
+	return false
+}
+
+// ensurePrivateDNSZones creates the private DNS zones of the private endpoints and links them to the
+// virtual networks of the targets if they don't exist. The external zones are not changed.
+func (az *Cloud) ensurePrivateDNSZones(ctx context.Context, options *privateEndpointOptions) error {
+	clientFactory := az.NetworkClientFactory
+	if clientFactory == nil {
+		// multi-tenant support
+		clientFactory = az.ComputeClientFactory
+	}
+	for _, storageType := range options.storageTypes {
+		zone := options.dnsZones[storageType]
+		if zone.external {
+			klog.V(4).Infof("private DNS zone(%s) is managed externally, skip creating it and its virtual links", zone.ID)
+			continue
+		}
+		if _, err := clientFactory.GetPrivateZoneClient().Get(ctx, zone.ResourceGroup, zone.Name); err != nil {
+			if strings.Contains(err.Error(), consts.ResourceNotFoundMessageCode) {
+				// Create DNS zone first, this could make sure driver has write permission on vnetResourceGroup
+				if err := az.createPrivateDNSZone(ctx, zone.ResourceGroup, zone.Name); err != nil {
+					return fmt.Errorf("create private DNS zone(%s) in resourceGroup(%s): %w", zone.Name, zone.ResourceGroup, err)
+				}
+			} else {
+				return fmt.Errorf("get private dns zone %s returned with %v", zone.Name, err.Error())
+			}
+		}
+
+		for i, target := range options.targets {
+			// Create virtual link to the private DNS zone
+			vNetLinkName := getVNetLinkName(target, i == 0)
+			if _, err := clientFactory.GetVirtualNetworkLinkClient().Get(ctx, zone.ResourceGroup, zone.Name, vNetLinkName); err != nil {
+				if strings.Contains(err.Error(), consts.ResourceNotFoundMessageCode) {
+					if err := az.createVNetLink(ctx, vNetLinkName, target.VNetResourceGroup, target.VNetName, zone.ResourceGroup, zone.Name); err != nil {
+						return fmt.Errorf("create virtual link for vnet(%s) and DNS Zone(%s) in resourceGroup(%s): %w", target.VNetName, zone.Name, zone.ResourceGroup, err)
+					}
+				} else {
+					return fmt.Errorf("get virtual link for vnet(%s) and DNS Zone(%s) in resourceGroup(%s) returned with %w", target.VNetName, zone.Name, zone.ResourceGroup, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ensurePrivateEndpoints creates the private endpoints of the storage account in the first target and
+// registers them in the private DNS zones, skipping the endpoints and the DNS zone groups which already
+// exist. The other targets resolve the endpoints through the virtual network links of the zones.
+func (az *Cloud) ensurePrivateEndpoints(ctx context.Context, accountName, accountID, location string, options *privateEndpointOptions) error {
+	target := options.targets[0]
+	for _, storageType := range options.storageTypes {
+		privateEndpointName := getStoragePrivateEndpointName(accountName, storageType)
+		privateEndpoint, rerr := az.privateendpointclient.Get(ctx, target.VNetResourceGroup, privateEndpointName, "")
+		exists, rerr := checkResourceExistsFromError(rerr)
+		if rerr != nil {
+			return fmt.Errorf("get private endpoint(%s) in resourceGroup(%s): %w", privateEndpointName, target.VNetResourceGroup, rerr.Error())
+		}
+		if exists && isPrivateEndpointConnected(privateEndpoint, accountID, storageType) {
+			klog.V(4).Infof("private endpoint(%s) of storage account(%s) already exists", privateEndpointName, accountName)
+		} else if err := az.createPrivateEndpoint(ctx, accountName, &accountID, privateEndpointName, target.VNetResourceGroup, target.VNetName, target.SubnetName, location, storageType); err != nil {
+			return fmt.Errorf("create private endpoint for storage account(%s), resourceGroup(%s): %w", accountName, target.VNetResourceGroup, err)
+		}
+
+		dnsZoneGroupName := accountName + "-dnszonegroup"
+		if storageType == StorageTypeBlob {
+			dnsZoneGroupName = dnsZoneGroupName + blobNameSuffix
+		}
+		zone := options.dnsZones[storageType]
+		privateDNSZoneGroup, rerr := az.privatednszonegroupclient.Get(ctx, target.VNetResourceGroup, privateEndpointName, dnsZoneGroupName)
+		exists, rerr = checkResourceExistsFromError(rerr)
+		if rerr != nil {
+			return fmt.Errorf("get private DNS zone group(%s) of privateEndpoint(%s) in resourceGroup(%s): %w", dnsZoneGroupName, privateEndpointName, target.VNetResourceGroup, rerr.Error())
+		}
+		if exists && isPrivateDNSZoneGroupEqual(privateDNSZoneGroup, zone.ID) {
+			klog.V(4).Infof("private DNS zone group(%s) of private endpoint(%s) already exists", dnsZoneGroupName, privateEndpointName)
+			continue
+		}
+		if err := az.createPrivateDNSZoneGroup(ctx, dnsZoneGroupName, privateEndpointName, target.VNetResourceGroup, target.VNetName, zone.Name, zone.ID); err != nil {
+			return fmt.Errorf("create private DNS zone group - privateEndpoint(%s), vNetName(%s), resourceGroup(%s): %w", privateEndpointName, target.VNetName, target.VNetResourceGroup, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	privatedns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2022-07-01/network"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/mock_azclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/privatezoneclient/mock_privatezoneclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/virtualnetworklinkclient/mock_virtualnetworklinkclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/privatednszonegroupclient/mockprivatednszonegroupclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/privateendpointclient/mockprivateendpointclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/subnetclient/mocksubnetclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/retry"
+)
+
+const (
+	testFileZoneID     = "/subscriptions/subs/resourceGroups/vnetrg/providers/Microsoft.Network/privateDnsZones/privatelink.file.core.windows.net"
+	testBlobZoneID     = "/subscriptions/subs/resourceGroups/vnetrg/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net"
+	testExternalZoneID = "/subscriptions/hub/resourceGroups/dns/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net"
+)
+
+func TestGetPrivateEndpointOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+	cloud.SubscriptionID = "subs"
+
+	tests := []struct {
+		desc            string
+		accountOptions  *AccountOptions
+		expectedOptions *privateEndpointOptions
+		expectedErr     string
+	}{
+		{
+			desc:           "default target and storage type",
+			accountOptions: &AccountOptions{StorageType: StorageTypeFile, StorageEndpointSuffix: "core.windows.net"},
+			expectedOptions: &privateEndpointOptions{
+				targets:      []PrivateEndpointTarget{{VNetResourceGroup: "vnetrg", VNetName: "vnet", SubnetName: "subnet"}},
+				storageTypes: []StorageType{StorageTypeFile},
+				dnsZones: map[StorageType]privateDNSZone{
+					StorageTypeFile: {ID: testFileZoneID, ResourceGroup: "vnetrg", Name: "privatelink.file.core.windows.net"},
+				},
+			},
+		},
+		{
+			desc: "multiple targets and storage types with external zone",
+			accountOptions: &AccountOptions{
+				StorageType:                 StorageTypeFile,
+				StorageEndpointSuffix:       "core.windows.net",
+				PrivateEndpointTargets:      []PrivateEndpointTarget{{}, {VNetResourceGroup: "spokerg", VNetName: "spoke"}},
+				PrivateEndpointStorageTypes: []StorageType{StorageTypeFile, StorageTypeBlob},
+				PrivateDNSZoneIDs:           []string{testExternalZoneID},
+			},
+			expectedOptions: &privateEndpointOptions{
+				targets: []PrivateEndpointTarget{
+					{VNetResourceGroup: "vnetrg", VNetName: "vnet", SubnetName: "subnet"},
+					{VNetResourceGroup: "spokerg", VNetName: "spoke"},
+				},
+				storageTypes: []StorageType{StorageTypeFile, StorageTypeBlob},
+				dnsZones: map[StorageType]privateDNSZone{
+					StorageTypeFile: {ID: testFileZoneID, ResourceGroup: "vnetrg", Name: "privatelink.file.core.windows.net"},
+					StorageTypeBlob: {ID: testExternalZoneID, ResourceGroup: "dns", Name: "privatelink.blob.core.windows.net", external: true},
+				},
+			},
+		},
+		{
+			desc: "duplicated targets",
+			accountOptions: &AccountOptions{
+				StorageType:            StorageTypeFile,
+				PrivateEndpointTargets: []PrivateEndpointTarget{{}, {VNetResourceGroup: "VNETRG", VNetName: "vnet"}},
+			},
+			expectedErr: "private endpoint target vnet(vnet) in resourceGroup(VNETRG) is duplicated",
+		},
+		{
+			desc: "subnet of another target",
+			accountOptions: &AccountOptions{
+				StorageType:            StorageTypeFile,
+				PrivateEndpointTargets: []PrivateEndpointTarget{{}, {VNetResourceGroup: "spokerg", VNetName: "spoke", SubnetName: "subnet2"}},
+			},
+			expectedErr: "subnet(subnet2) of private endpoint target vnet(spoke) is not used, only the subnet of the first target is used",
+		},
+		{
+			desc:           "unsupported storage type",
+			accountOptions: &AccountOptions{PrivateEndpointStorageTypes: []StorageType{"queue"}},
+			expectedErr:    "storage type queue of the private endpoints is not supported, supported types are file and blob",
+		},
+		{
+			desc:           "duplicated storage types",
+			accountOptions: &AccountOptions{PrivateEndpointStorageTypes: []StorageType{StorageTypeBlob, StorageTypeBlob}},
+			expectedErr:    "storage type blob of the private endpoints is duplicated",
+		},
+		{
+			desc: "invalid zone ID",
+			accountOptions: &AccountOptions{
+				StorageType:       StorageTypeFile,
+				PrivateDNSZoneIDs: []string{"/subscriptions/hub/resourceGroups/dns/providers/Microsoft.Network/dnsZones/privatelink.file.core.windows.net"},
+			},
+			expectedErr: "invalid private DNS zone resource ID /subscriptions/hub/resourceGroups/dns/providers/Microsoft.Network/dnsZones/privatelink.file.core.windows.net",
+		},
+		{
+			desc: "zone of another storage type",
+			accountOptions: &AccountOptions{
+				StorageType:           StorageTypeFile,
+				StorageEndpointSuffix: "core.windows.net",
+				PrivateDNSZoneIDs:     []string{testExternalZoneID},
+			},
+			expectedErr: "private DNS zone " + testExternalZoneID + " doesn't match the storage types([file]) of the private endpoints",
+		},
+	}
+
+	for _, test := range tests {
+		options, err := cloud.getPrivateEndpointOptions(test.accountOptions, "vnetrg", "vnet", "subnet")
+		if test.expectedErr != "" {
+			assert.EqualError(t, err, test.expectedErr, test.desc)
+			continue
+		}
+		assert.NoError(t, err, test.desc)
+		assert.Equal(t, test.expectedOptions, options, test.desc)
+	}
+}
+
+func TestGetStoragePrivateEndpointName(t *testing.T) {
+	assert.Equal(t, "account-pvtendpoint", getStoragePrivateEndpointName("account", StorageTypeFile))
+	assert.Equal(t, "account-pvtendpoint-blob", getStoragePrivateEndpointName("account", StorageTypeBlob))
+}
+
+func TestGetVNetLinkName(t *testing.T) {
+	assert.Equal(t, "vnet-vnetlink", getVNetLinkName(PrivateEndpointTarget{VNetResourceGroup: "rg", VNetName: "vnet"}, true))
+	spokeLinkName := getVNetLinkName(PrivateEndpointTarget{VNetResourceGroup: "spokerg", VNetName: "vnet"}, false)
+	assert.Equal(t, "vnet-"+MakeCRC32("spokerg")+"-vnetlink", spokeLinkName)
+	assert.NotEqual(t, spokeLinkName, getVNetLinkName(PrivateEndpointTarget{VNetResourceGroup: "hubrg", VNetName: "vnet"}, false))
+	assert.Equal(t, spokeLinkName, getVNetLinkName(PrivateEndpointTarget{VNetResourceGroup: "SpokeRG", VNetName: "vnet"}, false))
+}
+
+func TestEnsurePrivateDNSZones(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	options := &privateEndpointOptions{
+		targets: []PrivateEndpointTarget{
+			{VNetResourceGroup: "vnetrg", VNetName: "vnet", SubnetName: "subnet"},
+			{VNetResourceGroup: "spokerg", VNetName: "spoke"},
+		},
+		storageTypes: []StorageType{StorageTypeFile, StorageTypeBlob},
+		dnsZones: map[StorageType]privateDNSZone{
+			StorageTypeFile: {ID: testFileZoneID, ResourceGroup: "vnetrg", Name: "privatelink.file.core.windows.net"},
+			StorageTypeBlob: {ID: testExternalZoneID, ResourceGroup: "dns", Name: "privatelink.blob.core.windows.net", external: true},
+		},
+	}
+
+	clientFactory := mock_azclient.NewMockClientFactory(ctrl)
+	mockPrivateDNSClient := mock_privatezoneclient.NewMockInterface(ctrl)
+	mockPrivateDNSClient.EXPECT().Get(gomock.Any(), "vnetrg", "privatelink.file.core.windows.net").Return(&privatedns.PrivateZone{}, nil).Times(1)
+	clientFactory.EXPECT().GetPrivateZoneClient().Return(mockPrivateDNSClient).AnyTimes()
+	mockVirtualNetworkLinksClient := mock_virtualnetworklinkclient.NewMockInterface(ctrl)
+	spokeLinkName := getVNetLinkName(options.targets[1], false)
+	mockVirtualNetworkLinksClient.EXPECT().Get(gomock.Any(), "vnetrg", "privatelink.file.core.windows.net", "vnet-vnetlink").Return(&privatedns.VirtualNetworkLink{}, nil).Times(1)
+	mockVirtualNetworkLinksClient.EXPECT().Get(gomock.Any(), "vnetrg", "privatelink.file.core.windows.net", spokeLinkName).Return(nil, errors.New("ResourceNotFound")).Times(1)
+	mockVirtualNetworkLinksClient.EXPECT().CreateOrUpdate(gomock.Any(), "vnetrg", "privatelink.file.core.windows.net", spokeLinkName, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _, _ string, link privatedns.VirtualNetworkLink) (*privatedns.VirtualNetworkLink, error) {
+			assert.Equal(t, "/subscriptions/subscription/resourceGroups/spokerg/providers/Microsoft.Network/virtualNetworks/spoke", *link.Properties.VirtualNetwork.ID)
+			return &link, nil
+		}).Times(1)
+	clientFactory.EXPECT().GetVirtualNetworkLinkClient().Return(mockVirtualNetworkLinksClient).AnyTimes()
+	cloud.NetworkClientFactory = clientFactory
+
+	assert.NoError(t, cloud.ensurePrivateDNSZones(context.TODO(), options))
+}
+
+func TestEnsurePrivateEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	accountID := "/subscriptions/subs/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/account"
+	options := &privateEndpointOptions{
+		targets: []PrivateEndpointTarget{
+			{VNetResourceGroup: "vnetrg", VNetName: "vnet", SubnetName: "subnet"},
+			{VNetResourceGroup: "spokerg", VNetName: "spoke"},
+		},
+		storageTypes: []StorageType{StorageTypeFile, StorageTypeBlob},
+		dnsZones: map[StorageType]privateDNSZone{
+			StorageTypeFile: {ID: testFileZoneID, ResourceGroup: "vnetrg", Name: "privatelink.file.core.windows.net"},
+			StorageTypeBlob: {ID: testExternalZoneID, ResourceGroup: "dns", Name: "privatelink.blob.core.windows.net", external: true},
+		},
+	}
+	connectedEndpoint := func(storageType StorageType) network.PrivateEndpoint {
+		return network.PrivateEndpoint{PrivateEndpointProperties: &network.PrivateEndpointProperties{
+			PrivateLinkServiceConnections: &[]network.PrivateLinkServiceConnection{{
+				PrivateLinkServiceConnectionProperties: &network.PrivateLinkServiceConnectionProperties{
+					PrivateLinkServiceID: pointer.String(accountID),
+					GroupIds:             &[]string{string(storageType)},
+				},
+			}},
+		}}
+	}
+	zoneGroup := func(zoneID string) network.PrivateDNSZoneGroup {
+		return network.PrivateDNSZoneGroup{PrivateDNSZoneGroupPropertiesFormat: &network.PrivateDNSZoneGroupPropertiesFormat{
+			PrivateDNSZoneConfigs: &[]network.PrivateDNSZoneConfig{{
+				PrivateDNSZonePropertiesFormat: &network.PrivateDNSZonePropertiesFormat{PrivateDNSZoneID: pointer.String(zoneID)},
+			}},
+		}}
+	}
+	notFound := &retry.Error{HTTPStatusCode: http.StatusNotFound}
+
+	// the file endpoint is up to date, and the blob endpoint is neither connected nor registered in the DNS zone
+	mockPrivateEndpointClient := mockprivateendpointclient.NewMockInterface(ctrl)
+	mockPrivateEndpointClient.EXPECT().Get(gomock.Any(), "vnetrg", "account-pvtendpoint", "").Return(connectedEndpoint(StorageTypeFile), nil).Times(1)
+	mockPrivateEndpointClient.EXPECT().Get(gomock.Any(), "vnetrg", "account-pvtendpoint-blob", "").Return(network.PrivateEndpoint{}, notFound).Times(1)
+	mockPrivateEndpointClient.EXPECT().CreateOrUpdate(gomock.Any(), "vnetrg", "account-pvtendpoint-blob", gomock.Any(), "", true).Return(nil).Times(1)
+	cloud.privateendpointclient = mockPrivateEndpointClient
+
+	mockSubnetsClient := mocksubnetclient.NewMockInterface(ctrl)
+	mockSubnetsClient.EXPECT().Get(gomock.Any(), "vnetrg", "vnet", "subnet", "").Return(network.Subnet{SubnetPropertiesFormat: &network.SubnetPropertiesFormat{}}, nil).Times(1)
+	mockSubnetsClient.EXPECT().CreateOrUpdate(gomock.Any(), "vnetrg", "vnet", "subnet", gomock.Any()).Return(nil).Times(1)
+	cloud.SubnetsClient = mockSubnetsClient
+
+	mockPrivateDNSZoneGroup := mockprivatednszonegroupclient.NewMockInterface(ctrl)
+	mockPrivateDNSZoneGroup.EXPECT().Get(gomock.Any(), "vnetrg", "account-pvtendpoint", "account-dnszonegroup").Return(zoneGroup(testFileZoneID), nil).Times(1)
+	mockPrivateDNSZoneGroup.EXPECT().Get(gomock.Any(), "vnetrg", "account-pvtendpoint-blob", "account-dnszonegroup-blob").Return(zoneGroup(testBlobZoneID), nil).Times(1)
+	mockPrivateDNSZoneGroup.EXPECT().CreateOrUpdate(gomock.Any(), "vnetrg", "account-pvtendpoint-blob", "account-dnszonegroup-blob", gomock.Any(), "", false).DoAndReturn(
+		func(_ context.Context, _, _, _ string, group network.PrivateDNSZoneGroup, _ string, _ bool) *retry.Error {
+			assert.True(t, isPrivateDNSZoneGroupEqual(group, testExternalZoneID))
+			return nil
+		}).Times(1)
+	cloud.privatednszonegroupclient = mockPrivateDNSZoneGroup
+
+	assert.NoError(t, cloud.ensurePrivateEndpoints(context.TODO(), "account", accountID, "location", options))
+
+	// errors other than not found are returned
+	mockPrivateEndpointClient.EXPECT().Get(gomock.Any(), "vnetrg", "account-pvtendpoint", "").Return(network.PrivateEndpoint{}, &retry.Error{HTTPStatusCode: http.StatusForbidden, RawError: errors.New("forbidden")}).Times(1)
+	assert.EqualError(t, cloud.ensurePrivateEndpoints(context.TODO(), "account", accountID, "location", options),
+		"get private endpoint(account-pvtendpoint) in resourceGroup(vnetrg): Retriable: false, RetryAfter: 0s, HTTPStatusCode: 403, RawError: forbidden")
+}
+
+func TestEnsurePrivateEndpointsWithSharedDNSZone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	accountID := "/subscriptions/subs/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/account"
+	options := &privateEndpointOptions{
+		targets: []PrivateEndpointTarget{
+			{VNetResourceGroup: "vnetrg", VNetName: "vnet", SubnetName: "subnet"},
+			{VNetResourceGroup: "spokerg", VNetName: "vnet"},
+		},
+		storageTypes: []StorageType{StorageTypeFile},
+		dnsZones: map[StorageType]privateDNSZone{
+			StorageTypeFile: {ID: testFileZoneID, ResourceGroup: "vnetrg", Name: "privatelink.file.core.windows.net"},
+		},
+	}
+	notFound := &retry.Error{HTTPStatusCode: http.StatusNotFound}
+
+	// the zone is linked to both virtual networks, whose names are the same in different resource groups
+	clientFactory := mock_azclient.NewMockClientFactory(ctrl)
+	mockPrivateDNSClient := mock_privatezoneclient.NewMockInterface(ctrl)
+	mockPrivateDNSClient.EXPECT().Get(gomock.Any(), "vnetrg", "privatelink.file.core.windows.net").Return(&privatedns.PrivateZone{}, nil).Times(1)
+	clientFactory.EXPECT().GetPrivateZoneClient().Return(mockPrivateDNSClient).AnyTimes()
+	mockVirtualNetworkLinksClient := mock_virtualnetworklinkclient.NewMockInterface(ctrl)
+	for i, target := range options.targets {
+		vNetLinkName := getVNetLinkName(target, i == 0)
+		vNetID := fmt.Sprintf("/subscriptions/subscription/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/vnet", target.VNetResourceGroup)
+		mockVirtualNetworkLinksClient.EXPECT().Get(gomock.Any(), "vnetrg", "privatelink.file.core.windows.net", vNetLinkName).Return(nil, errors.New("ResourceNotFound")).Times(1)
+		mockVirtualNetworkLinksClient.EXPECT().CreateOrUpdate(gomock.Any(), "vnetrg", "privatelink.file.core.windows.net", vNetLinkName, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _, _ string, link privatedns.VirtualNetworkLink) (*privatedns.VirtualNetworkLink, error) {
+				assert.Equal(t, vNetID, *link.Properties.VirtualNetwork.ID)
+				return &link, nil
+			}).Times(1)
+	}
+	clientFactory.EXPECT().GetVirtualNetworkLinkClient().Return(mockVirtualNetworkLinksClient).AnyTimes()
+	cloud.NetworkClientFactory = clientFactory
+
+	// a single endpoint in the first target is registered in the zone
+	mockPrivateEndpointClient := mockprivateendpointclient.NewMockInterface(ctrl)
+	mockPrivateEndpointClient.EXPECT().Get(gomock.Any(), "vnetrg", "account-pvtendpoint", "").Return(network.PrivateEndpoint{}, notFound).Times(1)
+	mockPrivateEndpointClient.EXPECT().CreateOrUpdate(gomock.Any(), "vnetrg", "account-pvtendpoint", gomock.Any(), "", true).Return(nil).Times(1)
+	cloud.privateendpointclient = mockPrivateEndpointClient
+
+	mockSubnetsClient := mocksubnetclient.NewMockInterface(ctrl)
+	mockSubnetsClient.EXPECT().Get(gomock.Any(), "vnetrg", "vnet", "subnet", "").Return(network.Subnet{SubnetPropertiesFormat: &network.SubnetPropertiesFormat{}}, nil).Times(1)
+	mockSubnetsClient.EXPECT().CreateOrUpdate(gomock.Any(), "vnetrg", "vnet", "subnet", gomock.Any()).Return(nil).Times(1)
+	cloud.SubnetsClient = mockSubnetsClient
+
+	mockPrivateDNSZoneGroup := mockprivatednszonegroupclient.NewMockInterface(ctrl)
+	mockPrivateDNSZoneGroup.EXPECT().Get(gomock.Any(), "vnetrg", "account-pvtendpoint", "account-dnszonegroup").Return(network.PrivateDNSZoneGroup{}, notFound).Times(1)
+	mockPrivateDNSZoneGroup.EXPECT().CreateOrUpdate(gomock.Any(), "vnetrg", "account-pvtendpoint", "account-dnszonegroup", gomock.Any(), "", false).Return(nil).Times(1)
+	cloud.privatednszonegroupclient = mockPrivateDNSZoneGroup
+
+	assert.NoError(t, cloud.ensurePrivateDNSZones(context.TODO(), options))
+	assert.NoError(t, cloud.ensurePrivateEndpoints(context.TODO(), "account", accountID, "location", options))
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	azcache "sigs.k8s.io/cloud-provider-azure/pkg/cache"
+)
+
+// AccountSelectionPolicy decides which of the matching storage accounts is picked when the account
+// name is not specified.
+type AccountSelectionPolicy string
+
+const (
+	// AccountSelectionPolicyDefault picks the first matching account, or a random one if
+	// PickRandomMatchingAccount is set.
+	AccountSelectionPolicyDefault AccountSelectionPolicy = ""
+	// AccountSelectionPolicyLeastShares picks the matching account with the least file shares.
+	AccountSelectionPolicyLeastShares AccountSelectionPolicy = "LeastShares"
+	// AccountSelectionPolicyLeastProvisionedCapacity picks the matching account with the least
+	// provisioned capacity, which is the sum of the quotas of its file shares.
+	AccountSelectionPolicyLeastProvisionedCapacity AccountSelectionPolicy = "LeastProvisionedCapacity"
+	// AccountSelectionPolicyRoundRobin picks the matching accounts in turn.
+	AccountSelectionPolicyRoundRobin AccountSelectionPolicy = "RoundRobin"
+
+	// fileShareUsageCacheTTL is how long the file share usage of a storage account is cached.
+	fileShareUsageCacheTTL = 5 * time.Minute
+)
+
+// fileShareUsage is the file share usage of a storage account.
+type fileShareUsage struct {
+	Shares         int
+	ProvisionedGiB int64
+}
+
+func (az *Cloud) newFileShareUsageCache() (azcache.Resource, error) {
+	getter := func(key string) (interface{}, error) {
+		parts := strings.Split(key, "/")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid file share usage cache key (%s)", key)
+		}
+		if az.FileClient == nil {
+			return nil, fmt.Errorf("FileClient is nil")
+		}
+
+		ctx, cancel := getContextWithCancel()
+		defer cancel()
+		shares, err := az.FileClient.WithSubscriptionID(parts[0]).ListFileShare(ctx, parts[1], parts[2], "", "")
+		if err != nil {
+			return nil, err
+		}
+		usage := &fileShareUsage{}
+		for _, share := range shares {
+			usage.Shares++
+			if share.FileShareProperties != nil {
+				usage.ProvisionedGiB += int64(pointer.Int32Deref(share.FileShareProperties.ShareQuota, 0))
+			}
+		}
+		return usage, nil
+	}
+	return azcache.NewTimedCache(fileShareUsageCacheTTL, getter, az.Config.DisableAPICallCache)
+}
+
+// getFileShareUsage returns the file share usage of the storage account from the cache.
+func (az *Cloud) getFileShareUsage(subsID, resourceGroup, account string) (*fileShareUsage, error) {
+	if az.fileShareUsageCache == nil {
+		return nil, fmt.Errorf("fileShareUsageCache is nil")
+	}
+	data, err := az.fileShareUsageCache.Get(getStorageAccountCacheKey(subsID, resourceGroup, account), azcache.CacheReadTypeDefault)
+	if err != nil {
+		return nil, err
+	}
+	usage, ok := data.(*fileShareUsage)
+	if !ok || usage == nil {
+		return nil, fmt.Errorf("failed to get the file share usage of storage account %s", account)
+	}
+	return usage, nil
+}
+
+// addFileShareUsage adds a created file share to the cached usage of the storage account, so that
+// the following selections before the cache expires don't pick the account as if it were unchanged.
+func (az *Cloud) addFileShareUsage(subsID, resourceGroup, account string, quotaGiB int) {
+	if az.fileShareUsageCache == nil || az.fileShareUsageCache.GetStore() == nil {
+		return
+	}
+	entry, exists, err := az.fileShareUsageCache.GetStore().GetByKey(getStorageAccountCacheKey(subsID, resourceGroup, account))
+	if err != nil || !exists {
+		return
+	}
+	cacheEntry := entry.(*azcache.AzureCacheEntry)
+	cacheEntry.Lock.Lock()
+	defer cacheEntry.Lock.Unlock()
+	if usage, ok := cacheEntry.Data.(*fileShareUsage); ok && usage != nil {
+		cacheEntry.Data = &fileShareUsage{
+			Shares:         usage.Shares + 1,
+			ProvisionedGiB: usage.ProvisionedGiB + int64(quotaGiB),
+		}
+	}
+}
+
+// removeFileShareUsage drops the cached usage of the storage account after a file share is deleted.
+func (az *Cloud) removeFileShareUsage(subsID, resourceGroup, account string) {
+	if az.fileShareUsageCache == nil {
+		return
+	}
+	_ = az.fileShareUsageCache.Delete(getStorageAccountCacheKey(subsID, resourceGroup, account))
+}
+
+// selectStorageAccount picks one of the matching accounts per the selection policy of the account
+// options. An empty name is returned if all the matching accounts reach MaxSharesPerAccount, so
+// that a new account is created.
+func (az *Cloud) selectStorageAccount(accountOptions *AccountOptions, subsID, resourceGroup string, accounts []accountWithLocation) (accountWithLocation, error) {
+	policy := accountOptions.AccountSelectionPolicy
+	switch policy {
+	case AccountSelectionPolicyDefault, AccountSelectionPolicyLeastShares, AccountSelectionPolicyLeastProvisionedCapacity, AccountSelectionPolicyRoundRobin:
+	default:
+		return accountWithLocation{}, fmt.Errorf("unsupported account selection policy %q", policy)
+	}
+	needUsage := accountOptions.MaxSharesPerAccount > 0 ||
+		policy == AccountSelectionPolicyLeastShares ||
+		policy == AccountSelectionPolicyLeastProvisionedCapacity
+
+	candidates := accounts
+	usages := map[string]*fileShareUsage{}
+	if needUsage {
+		candidates = make([]accountWithLocation, 0, len(accounts))
+		for _, account := range accounts {
+			usage, err := az.getFileShareUsage(subsID, resourceGroup, account.Name)
+			if err != nil {
+				return accountWithLocation{}, fmt.Errorf("failed to get the file share usage of storage account %s: %w", account.Name, err)
+			}
+			if accountOptions.MaxSharesPerAccount > 0 && usage.Shares >= accountOptions.MaxSharesPerAccount {
+				klog.V(4).Infof("skip storage account %s with %d file shares reaching the max shares per account %d", account.Name, usage.Shares, accountOptions.MaxSharesPerAccount)
+				continue
+			}
+			usages[account.Name] = usage
+			candidates = append(candidates, account)
+		}
+		if len(candidates) == 0 {
+			klog.V(2).Infof("all the %d matching accounts reach the max shares per account %d", len(accounts), accountOptions.MaxSharesPerAccount)
+			return accountWithLocation{}, nil
+		}
+	}
+
+	index := 0
+	switch policy {
+	case AccountSelectionPolicyLeastShares:
+		for i, account := range candidates {
+			if usages[account.Name].Shares < usages[candidates[index].Name].Shares {
+				index = i
+			}
+		}
+		klog.V(4).Infof("pick the matching account with the least file shares(%d), matching accounts: %s", usages[candidates[index].Name].Shares, candidates)
+	case AccountSelectionPolicyLeastProvisionedCapacity:
+		for i, account := range candidates {
+			if usages[account.Name].ProvisionedGiB < usages[candidates[index].Name].ProvisionedGiB {
+				index = i
+			}
+		}
+		klog.V(4).Infof("pick the matching account with the least provisioned capacity(%d GiB), matching accounts: %s", usages[candidates[index].Name].ProvisionedGiB, candidates)
+	case AccountSelectionPolicyRoundRobin:
+		// sort the accounts so that the turns don't depend on the order of the list result
+		candidates = append([]accountWithLocation{}, candidates...)
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+		index = int((az.accountRoundRobinIndex.Add(1) - 1) % uint64(len(candidates)))
+		klog.V(4).Infof("pick the matching account in turn, index: %d, matching accounts: %s", index, candidates)
+	case AccountSelectionPolicyDefault:
+		if accountOptions.PickRandomMatchingAccount {
+			// randomly pick one matching account
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(candidates))))
+			if err != nil || n == nil {
+				return accountWithLocation{}, err
+			}
+			index = int(n.Int64())
+			klog.V(4).Infof("randomly pick one matching account, index: %d, matching accounts: %s", index, candidates)
+		}
+	}
+	return candidates[index], nil
+}
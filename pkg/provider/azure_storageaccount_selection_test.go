@@ -0,0 +1,207 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/fileclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/fileclient/mockfileclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/storageaccountclient/mockstorageaccountclient"
+)
+
+// fileShares returns the file shares with the quotas.
+func fileShares(quotas ...int32) []storage.FileShareItem {
+	shares := make([]storage.FileShareItem, 0, len(quotas))
+	for _, quota := range quotas {
+		shares = append(shares, storage.FileShareItem{FileShareProperties: &storage.FileShareProperties{ShareQuota: pointer.Int32(quota)}})
+	}
+	return shares
+}
+
+func TestSelectStorageAccount(t *testing.T) {
+	accounts := []accountWithLocation{{Name: "account1"}, {Name: "account2"}, {Name: "account3"}}
+	shares := map[string][]storage.FileShareItem{
+		"account1": fileShares(100, 100, 100),
+		"account2": fileShares(1000),
+		"account3": fileShares(10, 10),
+	}
+
+	tests := []struct {
+		desc                string
+		policy              AccountSelectionPolicy
+		maxSharesPerAccount int
+		listErr             error
+		expectedAccounts    []string
+		expectedErr         string
+	}{
+		{
+			desc:             "default policy should pick the first account without listing the file shares",
+			expectedAccounts: []string{"account1", "account1"},
+		},
+		{
+			desc:             "least shares policy should pick the account with the least file shares",
+			policy:           AccountSelectionPolicyLeastShares,
+			expectedAccounts: []string{"account2", "account2", "account3"},
+		},
+		{
+			desc:             "least provisioned capacity policy should pick the account with the least share quotas",
+			policy:           AccountSelectionPolicyLeastProvisionedCapacity,
+			expectedAccounts: []string{"account3", "account3"},
+		},
+		{
+			desc:             "round-robin policy should pick the accounts in turn",
+			policy:           AccountSelectionPolicyRoundRobin,
+			expectedAccounts: []string{"account1", "account2", "account3", "account1"},
+		},
+		{
+			desc:                "accounts reaching the max shares per account should be skipped",
+			policy:              AccountSelectionPolicyRoundRobin,
+			maxSharesPerAccount: 3,
+			expectedAccounts:    []string{"account2", "account3", "account2"},
+		},
+		{
+			desc:                "no account should be picked if all the accounts reach the max shares per account",
+			maxSharesPerAccount: 1,
+			expectedAccounts:    []string{""},
+		},
+		{
+			desc:        "error should be returned if the file shares can't be listed",
+			policy:      AccountSelectionPolicyLeastShares,
+			listErr:     errors.New("list error"),
+			expectedErr: "failed to get the file share usage of storage account account1: list error",
+		},
+		{
+			desc:        "error should be returned for unsupported policy",
+			policy:      "MostShares",
+			expectedErr: `unsupported account selection policy "MostShares"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cloud := GetTestCloud(ctrl)
+
+			mockFileClient := mockfileclient.NewMockInterface(ctrl)
+			cloud.FileClient = mockFileClient
+			mockFileClient.EXPECT().WithSubscriptionID("subs").Return(mockFileClient).AnyTimes()
+			for _, account := range accounts {
+				// the usage is listed once and then cached
+				mockFileClient.EXPECT().ListFileShare(gomock.Any(), "rg", account.Name, "", "").Return(shares[account.Name], test.listErr).MaxTimes(1)
+			}
+
+			accountOptions := &AccountOptions{
+				AccountSelectionPolicy: test.policy,
+				MaxSharesPerAccount:    test.maxSharesPerAccount,
+			}
+			for _, expected := range test.expectedAccounts {
+				account, err := cloud.selectStorageAccount(accountOptions, "subs", "rg", accounts)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, account.Name)
+				if account.Name != "" {
+					cloud.addFileShareUsage("subs", "rg", account.Name, 1)
+				}
+			}
+			if test.expectedErr != "" {
+				_, err := cloud.selectStorageAccount(accountOptions, "subs", "rg", accounts)
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestFileShareUsageCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	mockFileClient := mockfileclient.NewMockInterface(ctrl)
+	cloud.FileClient = mockFileClient
+	mockFileClient.EXPECT().WithSubscriptionID("subs").Return(mockFileClient).AnyTimes()
+	mockFileClient.EXPECT().ListFileShare(gomock.Any(), "rg", "account", "", "").Return(fileShares(100, 200), nil).Times(2)
+	mockFileClient.EXPECT().DeleteFileShare(gomock.Any(), "rg", "account", "share", "").Return(nil).Times(1)
+
+	// the usage of the uncached account is not added
+	cloud.addFileShareUsage("subs", "rg", "account", 50)
+
+	usage, err := cloud.getFileShareUsage("subs", "rg", "account")
+	assert.NoError(t, err)
+	assert.Equal(t, &fileShareUsage{Shares: 2, ProvisionedGiB: 300}, usage)
+
+	cloud.addFileShareUsage("subs", "rg", "account", 50)
+	usage, err = cloud.getFileShareUsage("subs", "rg", "account")
+	assert.NoError(t, err)
+	assert.Equal(t, &fileShareUsage{Shares: 3, ProvisionedGiB: 350}, usage)
+
+	// the usage is listed again after a file share is deleted
+	assert.NoError(t, cloud.DeleteFileShare(context.TODO(), "subs", "rg", "account", "share"))
+	usage, err = cloud.getFileShareUsage("subs", "rg", "account")
+	assert.NoError(t, err)
+	assert.Equal(t, &fileShareUsage{Shares: 2, ProvisionedGiB: 300}, usage)
+}
+
+func TestCreateFileShareWithMaxSharesPerAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cloud := GetTestCloud(ctrl)
+
+	location := TestLocation
+	accounts := []storage.Account{
+		{Name: pointer.String("account1"), Sku: &storage.Sku{Name: storage.SkuNameStandardLRS}, Location: &location, AccountProperties: &storage.AccountProperties{}},
+	}
+	keys := storage.AccountListKeysResult{Keys: &[]storage.AccountKey{{Value: pointer.String("key")}}}
+
+	mockStorageAccountsClient := mockstorageaccountclient.NewMockInterface(ctrl)
+	cloud.StorageAccountClient = mockStorageAccountsClient
+	mockStorageAccountsClient.EXPECT().ListByResourceGroup(gomock.Any(), "subs", "rg").Return(accounts, nil).Times(2)
+	mockStorageAccountsClient.EXPECT().ListKeys(gomock.Any(), "subs", "rg", gomock.Any()).Return(keys, nil).AnyTimes()
+	// a new account is created once account1 reaches the max shares per account
+	mockStorageAccountsClient.EXPECT().Create(gomock.Any(), "subs", "rg", gomock.Not("account1"), gomock.Any()).Return(nil).Times(1)
+
+	mockFileClient := mockfileclient.NewMockInterface(ctrl)
+	cloud.FileClient = mockFileClient
+	mockFileClient.EXPECT().WithSubscriptionID("subs").Return(mockFileClient).AnyTimes()
+	mockFileClient.EXPECT().ListFileShare(gomock.Any(), "rg", "account1", "", "").Return(fileShares(100), nil).Times(1)
+	mockFileClient.EXPECT().CreateFileShare(gomock.Any(), "rg", gomock.Any(), gomock.Any(), "").Return(storage.FileShare{}, nil).Times(2)
+
+	newAccountOptions := func() *AccountOptions {
+		return &AccountOptions{
+			SubscriptionID:         "subs",
+			ResourceGroup:          "rg",
+			Type:                   string(storage.SkuNameStandardLRS),
+			Location:               location,
+			AccountSelectionPolicy: AccountSelectionPolicyLeastShares,
+			MaxSharesPerAccount:    2,
+		}
+	}
+	accountName, _, err := cloud.CreateFileShare(context.TODO(), newAccountOptions(), &fileclient.ShareOptions{Name: "share1", RequestGiB: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, "account1", accountName)
+
+	accountName, _, err = cloud.CreateFileShare(context.TODO(), newAccountOptions(), &fileclient.ShareOptions{Name: "share2", RequestGiB: 100})
+	assert.NoError(t, err)
+	assert.NotEqual(t, "account1", accountName)
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 	"testing"
@@ -527,9 +529,11 @@ func TestEnsureStorageAccount(t *testing.T) {
 			computeClientFactory.EXPECT().GetPrivateZoneClient().Return(mockPrivateDNSClient).AnyTimes()
 
 			mockPrivateDNSZoneGroup := mockprivatednszonegroupclient.NewMockInterface(ctrl)
+			mockPrivateDNSZoneGroup.EXPECT().Get(gomock.Any(), vnetResourceGroup, gomock.Any(), gomock.Any()).Return(network.PrivateDNSZoneGroup{}, &retry.Error{HTTPStatusCode: http.StatusNotFound}).Times(1)
 			mockPrivateDNSZoneGroup.EXPECT().CreateOrUpdate(gomock.Any(), vnetResourceGroup, gomock.Any(), gomock.Any(), gomock.Any(), "", false).Return(nil).Times(1)
 			cloud.privatednszonegroupclient = mockPrivateDNSZoneGroup
 			mockPrivateEndpointClient := mockprivateendpointclient.NewMockInterface(ctrl)
+			mockPrivateEndpointClient.EXPECT().Get(gomock.Any(), vnetResourceGroup, gomock.Any(), "").Return(network.PrivateEndpoint{}, &retry.Error{HTTPStatusCode: http.StatusNotFound}).Times(1)
 			mockPrivateEndpointClient.EXPECT().CreateOrUpdate(gomock.Any(), vnetResourceGroup, gomock.Any(), gomock.Any(), "", true).Return(nil).Times(1)
 			cloud.privateendpointclient = mockPrivateEndpointClient
 			mockVirtualNetworkLinksClient := mock_virtualnetworklinkclient.NewMockInterface(ctrl)
@@ -764,6 +768,48 @@ func TestRemoveStorageAccountTags(t *testing.T) {
 
 }
 
+func TestNewStorageAccountCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cloud := &Cloud{}
+	cloud.Config.CacheMaxStalenessInSeconds = 60
+	mockStorageAccountsClient := mockstorageaccountclient.NewMockInterface(ctrl)
+	cloud.StorageAccountClient = mockStorageAccountsClient
+	storageAccountCache, err := cloud.newStorageAccountCache()
+	assert.NoError(t, err)
+	cloud.storageAccountCache = storageAccountCache
+	assert.Equal(t, time.Minute, storageAccountCache.(*cache.TimedCache).MaxStaleness)
+
+	// the uncached storage account is got by the getter of the cache
+	original := storage.Account{Name: pointer.String("account")}
+	mockStorageAccountsClient.EXPECT().GetProperties(gomock.Any(), "subs", "rg", "account").Return(original, nil)
+	result, rerr := cloud.getStorageAccountWithCache(context.TODO(), "subs", "rg", "account")
+	assert.Nil(t, rerr)
+	assert.Equal(t, original, result)
+
+	// the expired storage account is returned and refreshed in the background
+	refreshed := storage.Account{Name: pointer.String("account"), Tags: map[string]*string{"key": pointer.String("value")}}
+	done := make(chan struct{})
+	mockStorageAccountsClient.EXPECT().GetProperties(gomock.Any(), "subs", "rg", "account").DoAndReturn(func(_ context.Context, _, _, _ string) (storage.Account, *retry.Error) {
+		defer close(done)
+		return refreshed, nil
+	})
+	entry, exists, err := storageAccountCache.GetStore().GetByKey(getStorageAccountCacheKey("subs", "rg", "account"))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	entry.(*cache.AzureCacheEntry).CreatedOn = time.Now().Add(-90 * time.Second)
+	result, rerr = cloud.getStorageAccountWithCache(context.TODO(), "subs", "rg", "account")
+	assert.Nil(t, rerr)
+	assert.Equal(t, original, result)
+
+	<-done
+	assert.Eventually(t, func() bool {
+		result, rerr = cloud.getStorageAccountWithCache(context.TODO(), "subs", "rg", "account")
+		return rerr == nil && len(result.Tags) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
 func TestIsPrivateEndpointAsExpected(t *testing.T) {
 	tests := []struct {
 		account        storage.Account
@@ -17,16 +17,15 @@ limitations under the License.
 package provider
 
 import (
+	"context"
+
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2022-07-01/network"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 )
 
 // CreateOrUpdateSubnet invokes az.SubnetClient.CreateOrUpdate with exponential backoff retry
-func (az *Cloud) CreateOrUpdateSubnet(service *v1.Service, subnet network.Subnet) error {
-	ctx, cancel := getContextWithCancel()
-	defer cancel()
-
+func (az *Cloud) CreateOrUpdateSubnet(ctx context.Context, service *v1.Service, subnet network.Subnet) error {
 	var rg string
 	if len(az.VnetResourceGroup) > 0 {
 		rg = az.VnetResourceGroup
@@ -45,7 +44,7 @@ func (az *Cloud) CreateOrUpdateSubnet(service *v1.Service, subnet network.Subnet
 	return nil
 }
 
-func (az *Cloud) getSubnet(virtualNetworkName string, subnetName string) (network.Subnet, bool, error) {
+func (az *Cloud) getSubnet(ctx context.Context, virtualNetworkName string, subnetName string) (network.Subnet, bool, error) {
 	var rg string
 	if len(az.VnetResourceGroup) > 0 {
 		rg = az.VnetResourceGroup
@@ -53,8 +52,6 @@ func (az *Cloud) getSubnet(virtualNetworkName string, subnetName string) (networ
 		rg = az.ResourceGroup
 	}
 
-	ctx, cancel := getContextWithCancel()
-	defer cancel()
 	subnet, err := az.SubnetsClient.Get(ctx, rg, virtualNetworkName, subnetName, "")
 	exists, rerr := checkResourceExistsFromError(err)
 	if rerr != nil {
@@ -121,7 +121,7 @@ func (ss *ScaleSet) newVMSSCache(ctx context.Context) (azcache.Resource, error)
 	if ss.Config.VmssCacheTTLInSeconds == 0 {
 		ss.Config.VmssCacheTTLInSeconds = consts.VMSSCacheTTLDefaultInSeconds
 	}
-	return azcache.NewTimedCache(time.Duration(ss.Config.VmssCacheTTLInSeconds)*time.Second, getter, ss.Config.DisableAPICallCache)
+	return azcache.NewTimedCache(time.Duration(ss.Config.VmssCacheTTLInSeconds)*time.Second, getter, ss.Config.DisableAPICallCache, ss.readMostlyCacheOptions()...)
 }
 
 func (ss *ScaleSet) getVMSSVMsFromCache(resourceGroup, vmssName string, crt azcache.AzureCacheReadType) (*sync.Map, error) {
@@ -144,25 +144,18 @@ func (ss *ScaleSet) getVMSSVMsFromCache(resourceGroup, vmssName string, crt azca
 func (ss *ScaleSet) newVMSSVirtualMachinesCache() (azcache.Resource, error) {
 	vmssVirtualMachinesCacheTTL := time.Duration(ss.Config.VmssVirtualMachinesCacheTTLInSeconds) * time.Second
 
-	getter := func(cacheKey string) (interface{}, error) {
+	// the getter is passed the cached VMs instead of reading the cache entry, which is not locked
+	// while the expired entry is refreshed in the background
+	getter := func(cacheKey string, previous interface{}) (interface{}, error) {
 		localCache := &sync.Map{} // [nodeName]*VMSSVirtualMachineEntry
 		oldCache := make(map[string]*VMSSVirtualMachineEntry)
 
-		if !ss.Cloud.Config.DisableAPICallCache {
-			entry, exists, err := ss.vmssVMCache.GetStore().GetByKey(cacheKey)
-			if err != nil {
-				return nil, err
-			}
-			if exists {
-				cached := entry.(*azcache.AzureCacheEntry).Data
-				if cached != nil {
-					virtualMachines := cached.(*sync.Map)
-					virtualMachines.Range(func(key, value interface{}) bool {
-						oldCache[key.(string)] = value.(*VMSSVirtualMachineEntry)
-						return true
-					})
-				}
-			}
+		if previous != nil {
+			virtualMachines := previous.(*sync.Map)
+			virtualMachines.Range(func(key, value interface{}) bool {
+				oldCache[key.(string)] = value.(*VMSSVirtualMachineEntry)
+				return true
+			})
 		}
 
 		result := strings.Split(cacheKey, "/")
@@ -173,7 +166,9 @@ func (ss *ScaleSet) newVMSSVirtualMachinesCache() (azcache.Resource, error) {
 
 		resourceGroupName, vmssName := result[0], result[1]
 
-		vms, err := ss.listScaleSetVMs(vmssName, resourceGroupName)
+		ctx, cancel := getContextWithCancel()
+		defer cancel()
+		vms, err := ss.listScaleSetVMs(ctx, vmssName, resourceGroupName)
 		if err != nil {
 			return nil, err
 		}
@@ -242,7 +237,7 @@ func (ss *ScaleSet) newVMSSVirtualMachinesCache() (azcache.Resource, error) {
 		return localCache, nil
 	}
 
-	return azcache.NewTimedCache(vmssVirtualMachinesCacheTTL, getter, ss.Cloud.Config.DisableAPICallCache)
+	return azcache.NewTimedCacheWithPrevious(vmssVirtualMachinesCacheTTL, getter, ss.Cloud.Config.DisableAPICallCache, ss.readMostlyCacheOptions()...)
 }
 
 // DeleteCacheForNode deletes Node from VMSS VM and VM caches.
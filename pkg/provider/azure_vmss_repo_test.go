@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -74,7 +75,7 @@ func TestCreateOrUpdateVMSS(t *testing.T) {
 		mockVMSSClient := az.VirtualMachineScaleSetsClient.(*mockvmssclient.MockInterface)
 		mockVMSSClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, testVMSSName).Return(test.vmss, test.clientErr)
 
-		err := az.CreateOrUpdateVMSS(az.ResourceGroup, testVMSSName, compute.VirtualMachineScaleSet{})
+		err := az.CreateOrUpdateVMSS(context.TODO(), az.ResourceGroup, testVMSSName, compute.VirtualMachineScaleSet{})
 		assert.Equal(t, test.expectedErr, err)
 	}
 }
@@ -170,7 +171,7 @@ func TestGetPrivateIPsForMachine(t *testing.T) {
 		mockInterfaceClient := az.InterfacesClient.(*mockinterfaceclient.MockInterface)
 		mockInterfaceClient.EXPECT().Get(gomock.Any(), az.ResourceGroup, "nic", gomock.Any()).Return(expectedInterface, nil).MaxTimes(1)
 
-		privateIPs, err := az.getPrivateIPsForMachine("vm")
+		privateIPs, err := az.getPrivateIPsForMachine(context.TODO(), "vm")
 		assert.Equal(t, test.expectedErr, err)
 		assert.Equal(t, test.expectedPrivateIPs, privateIPs)
 	}
@@ -245,7 +246,7 @@ func TestGetIPForMachineWithRetry(t *testing.T) {
 		mockPIPClient := az.PublicIPAddressesClient.(*mockpublicipclient.MockInterface)
 		mockPIPClient.EXPECT().List(gomock.Any(), az.ResourceGroup).Return([]network.PublicIPAddress{expectedPIP}, nil).MaxTimes(1)
 
-		privateIP, publicIP, err := az.GetIPForMachineWithRetry("vm")
+		privateIP, publicIP, err := az.GetIPForMachineWithRetry(context.TODO(), "vm")
 		assert.Equal(t, test.expectedErr, err)
 		assert.Equal(t, test.expectedPrivateIP, privateIP)
 		assert.Equal(t, test.expectedPublicIP, publicIP)
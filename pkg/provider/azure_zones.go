@@ -25,6 +25,7 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
@@ -39,6 +40,8 @@ var _ cloudprovider.Zones = (*Cloud)(nil)
 func (az *Cloud) refreshZones(ctx context.Context, refreshFunc func() error) {
 	klog.V(2).Info("refreshZones: refreshing zones every 30 minutes.")
 	err := wait.PollUntilContextCancel(ctx, consts.ZoneFetchingInterval, false, func(ctx context.Context) (bool, error) {
+		az.reloadLock.RLock()
+		defer az.reloadLock.RUnlock()
 		_ = refreshFunc()
 		return false, nil
 	})
@@ -227,3 +230,95 @@ func (az *Cloud) GetZoneByNodeName(_ context.Context, nodeName types.NodeName) (
 
 	return az.VMSet.GetZoneByNodeName(string(nodeName))
 }
+
+// NodePlacement describes where a node is placed in terms of Azure fault domains, update domains,
+// proximity placement groups and capacity reservation groups. Empty fields are unknown or unset.
+type NodePlacement struct {
+	FaultDomain              string
+	UpdateDomain             string
+	ProximityPlacementGroup  string
+	CapacityReservationGroup string
+}
+
+// Labels returns the node labels of the placement. Empty fields and values which are not
+// valid label values are skipped.
+func (p *NodePlacement) Labels() map[string]string {
+	labels := map[string]string{}
+	if p == nil {
+		return labels
+	}
+
+	for key, value := range map[string]string{
+		consts.LabelPlatformFaultDomain:      p.FaultDomain,
+		consts.LabelPlatformUpdateDomain:     p.UpdateDomain,
+		consts.LabelProximityPlacementGroup:  p.ProximityPlacementGroup,
+		consts.LabelCapacityReservationGroup: p.CapacityReservationGroup,
+	} {
+		if value == "" {
+			continue
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			klog.Warningf("NodePlacement.Labels: skipping label %s=%s: %s", key, value, strings.Join(errs, ", "))
+			continue
+		}
+		labels[key] = value
+	}
+	return labels
+}
+
+// GetNodePlacement returns the placement of the node. The fault domain and the update domain of
+// the current instance are read from IMDS when useInstanceMetadata is set. The proximity placement
+// group and the capacity reservation group are only available from ARM, so they are read from ARM
+// if credentials are provided, and left empty otherwise or if ARM fails.
+func (az *Cloud) GetNodePlacement(_ context.Context, nodeName types.NodeName) (*NodePlacement, error) {
+	// Returns nil for unmanaged nodes because azure cloud provider couldn't fetch information for them.
+	unmanaged, err := az.IsNodeUnmanaged(string(nodeName))
+	if err != nil {
+		return nil, err
+	}
+	if unmanaged {
+		klog.V(2).Infof("GetNodePlacement: omitting unmanaged node %q", nodeName)
+		return &NodePlacement{}, nil
+	}
+
+	if az.UseInstanceMetadata {
+		metadata, err := az.Metadata.GetMetadata(azcache.CacheReadTypeUnsafe)
+		if err != nil {
+			return nil, err
+		}
+
+		if metadata.Compute == nil {
+			_ = az.Metadata.imsCache.Delete(consts.MetadataCacheKey)
+			return nil, fmt.Errorf("failure of getting compute information from instance metadata")
+		}
+
+		isLocalInstance, err := az.isCurrentInstance(nodeName, metadata.Compute.Name)
+		if err != nil {
+			return nil, err
+		}
+		if isLocalInstance {
+			placement := &NodePlacement{
+				FaultDomain:  metadata.Compute.FaultDomain,
+				UpdateDomain: metadata.Compute.UpdateDomain,
+			}
+			// vmSet == nil indicates credentials are not provided.
+			if az.VMSet == nil {
+				return placement, nil
+			}
+			armPlacement, err := az.VMSet.GetPlacementByNodeName(string(nodeName))
+			if err != nil {
+				klog.Warningf("GetNodePlacement: failed to get the placement of node %q from ARM, only the fault domain and the update domain are returned: %v", nodeName, err)
+				return placement, nil
+			}
+			placement.ProximityPlacementGroup = armPlacement.ProximityPlacementGroup
+			placement.CapacityReservationGroup = armPlacement.CapacityReservationGroup
+			return placement, nil
+		}
+	}
+
+	if az.VMSet == nil {
+		// vmSet == nil indicates credentials are not provided.
+		return nil, fmt.Errorf("no credentials provided for Azure cloud provider")
+	}
+	return az.VMSet.GetPlacementByNodeName(string(nodeName))
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/vmclient/mockvmclient"
 	"sigs.k8s.io/cloud-provider-azure/pkg/azureclients/zoneclient/mockzoneclient"
+	"sigs.k8s.io/cloud-provider-azure/pkg/consts"
 	"sigs.k8s.io/cloud-provider-azure/pkg/retry"
 	utilsets "sigs.k8s.io/cloud-provider-azure/pkg/util/sets"
 )
@@ -336,3 +338,96 @@ func TestRefreshZones(t *testing.T) {
 	}()
 	az.refreshZones(ctx, az.syncRegionZonesMap)
 }
+
+func TestNodePlacementLabels(t *testing.T) {
+	placement := &NodePlacement{
+		FaultDomain:              "1",
+		UpdateDomain:             "",
+		ProximityPlacementGroup:  "ppg1",
+		CapacityReservationGroup: "invalid_label_value_" + strings.Repeat("a", 63),
+	}
+	assert.Equal(t, map[string]string{
+		consts.LabelPlatformFaultDomain:     "1",
+		consts.LabelProximityPlacementGroup: "ppg1",
+	}, placement.Labels())
+
+	var nilPlacement *NodePlacement
+	assert.Empty(t, nilPlacement.Labels())
+}
+
+func TestGetNodePlacement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cloud := GetTestCloud(ctrl)
+	mockVMSet := NewMockVMSet(ctrl)
+	mockVMSet.EXPECT().GetPlacementByNodeName("vm1").Return(&NodePlacement{FaultDomain: "2", ProximityPlacementGroup: "ppg1"}, nil)
+	cloud.VMSet = mockVMSet
+
+	placement, err := cloud.GetNodePlacement(context.TODO(), "vm1")
+	assert.NoError(t, err)
+	assert.Equal(t, &NodePlacement{FaultDomain: "2", ProximityPlacementGroup: "ppg1"}, placement)
+}
+
+func TestGetNodePlacementFromInstanceMetadata(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"compute":{"name":"vm1", "platformFaultDomain":"1", "platformUpdateDomain":"3"}}`)
+	}))
+	go func() {
+		_ = http.Serve(listener, mux)
+	}()
+	defer listener.Close()
+
+	testcases := []struct {
+		desc         string
+		armPlacement *NodePlacement
+		armErr       error
+		noVMSet      bool
+		expected     *NodePlacement
+	}{
+		{
+			desc:         "the proximity placement group and the capacity reservation group should be read from ARM",
+			armPlacement: &NodePlacement{FaultDomain: "2", UpdateDomain: "4", ProximityPlacementGroup: "ppg1", CapacityReservationGroup: "crg1"},
+			expected:     &NodePlacement{FaultDomain: "1", UpdateDomain: "3", ProximityPlacementGroup: "ppg1", CapacityReservationGroup: "crg1"},
+		},
+		{
+			desc:     "only the fault domain and the update domain should be returned if ARM fails",
+			armErr:   fmt.Errorf("ARM error"),
+			expected: &NodePlacement{FaultDomain: "1", UpdateDomain: "3"},
+		},
+		{
+			desc:     "only the fault domain and the update domain should be returned without credentials",
+			noVMSet:  true,
+			expected: &NodePlacement{FaultDomain: "1", UpdateDomain: "3"},
+		},
+	}
+	for _, test := range testcases {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cloud := GetTestCloud(ctrl)
+			cloud.UseInstanceMetadata = true
+			cloud.Metadata, err = NewInstanceMetadataService("http://" + listener.Addr().String() + "/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.noVMSet {
+				cloud.VMSet = nil
+			} else {
+				mockVMSet := NewMockVMSet(ctrl)
+				mockVMSet.EXPECT().GetPlacementByNodeName("vm1").Return(test.armPlacement, test.armErr)
+				cloud.VMSet = mockVMSet
+			}
+
+			placement, err := cloud.GetNodePlacement(context.TODO(), "vm1")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, placement)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
+)
+
+func init() {
+	// the clients of the azclient factories start a span per ARM request with the tracing provider
+	utils.TracingProvider = tracing.NewProvider(func(_, _ string) tracing.Tracer {
+		return tracing.NewTracer(newAzSDKSpan, &tracing.TracerOptions{SpanFromContext: azSDKSpanFromContext})
+	}, nil)
+}
+
+// newAzSDKSpan starts an OpenTelemetry span for the Azure SDK.
+func newAzSDKSpan(ctx context.Context, spanName string, options *tracing.SpanOptions) (context.Context, tracing.Span) {
+	var opts []trace.SpanStartOption
+	if options != nil {
+		opts = append(opts, trace.WithAttributes(toAttributes(options.Attributes)...), trace.WithSpanKind(toSpanKind(options.Kind)))
+	}
+	ctx, span := tracer().Start(ctx, spanName, opts...)
+	return ctx, wrapSpan(span)
+}
+
+func azSDKSpanFromContext(ctx context.Context) tracing.Span {
+	return wrapSpan(trace.SpanFromContext(ctx))
+}
+
+func wrapSpan(span trace.Span) tracing.Span {
+	return tracing.NewSpan(tracing.SpanImpl{
+		End: func() {
+			span.End()
+		},
+		SetAttributes: func(attributes ...tracing.Attribute) {
+			span.SetAttributes(toAttributes(attributes)...)
+		},
+		AddEvent: func(name string, attributes ...tracing.Attribute) {
+			span.AddEvent(name, trace.WithAttributes(toAttributes(attributes)...))
+		},
+		SetStatus: func(status tracing.SpanStatus, description string) {
+			switch status {
+			case tracing.SpanStatusOK:
+				span.SetStatus(codes.Ok, description)
+			case tracing.SpanStatusError:
+				span.SetStatus(codes.Error, description)
+			default:
+				span.SetStatus(codes.Unset, description)
+			}
+		},
+	})
+}
+
+func toAttributes(attributes []tracing.Attribute) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(attributes))
+	for _, entry := range attributes {
+		switch value := entry.Value.(type) {
+		case string:
+			result = append(result, attribute.String(entry.Key, value))
+		case int:
+			result = append(result, attribute.Int(entry.Key, value))
+		case int64:
+			result = append(result, attribute.Int64(entry.Key, value))
+		case bool:
+			result = append(result, attribute.Bool(entry.Key, value))
+		default:
+			result = append(result, attribute.String(entry.Key, fmt.Sprintf("%v", value)))
+		}
+	}
+	return result
+}
+
+func toSpanKind(kind tracing.SpanKind) trace.SpanKind {
+	switch kind {
+	case tracing.SpanKindServer:
+		return trace.SpanKindServer
+	case tracing.SpanKindClient:
+		return trace.SpanKindClient
+	case tracing.SpanKindProducer:
+		return trace.SpanKindProducer
+	case tracing.SpanKindConsumer:
+		return trace.SpanKindConsumer
+	default:
+		return trace.SpanKindInternal
+	}
+}
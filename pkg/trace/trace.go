@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package trace provides the OpenTelemetry spans of the cloud provider operations. The spans of the
+// Service, node and route operations are the parents of the spans of the ARM requests they send, so
+// that one trace shows all the ARM requests of one reconcile.
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "sigs.k8s.io/cloud-provider-azure"
+
+// The attribute keys of the spans.
+const (
+	// ServiceKey is the namespace/name of the Kubernetes Service.
+	ServiceKey = attribute.Key("k8s.service")
+	// NodeNameKey is the name of the Kubernetes node.
+	NodeNameKey = attribute.Key("k8s.node.name")
+	// ClusterNameKey is the name of the cluster.
+	ClusterNameKey = attribute.Key("k8s.cluster.name")
+	// LoadBalancerNameKey is the name of the Azure load balancer.
+	LoadBalancerNameKey = attribute.Key("azure.load_balancer.name")
+	// BackendPoolNameKey is the name of the backend pool of the Azure load balancer.
+	BackendPoolNameKey = attribute.Key("azure.backend_pool.name")
+	// VMSetNameKey is the name of the availability set or VMSS.
+	VMSetNameKey = attribute.Key("azure.vmset.name")
+	// RouteNameKey is the name of the route in the route table.
+	RouteNameKey = attribute.Key("azure.route.name")
+	// ResourceGroupKey is the resource group of the Azure resource.
+	ResourceGroupKey = attribute.Key("azure.resource_group")
+	// ResourceIDKey is the ID of the Azure resource of an ARM request.
+	ResourceIDKey = attribute.Key("azure.resource_id")
+)
+
+// tracer returns the tracer of the global tracer provider, which is a no-op until InitTracerProvider is called.
+func tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(instrumentationName)
+}
+
+// StartSpan starts a span as a child of the span of ctx, if any, and returns the context carrying the new span.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records the error of the operation in the span, if any, and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Link is a link from the span of a batch of operations to the span which requested one of them.
+type Link = trace.Link
+
+// LinkFromContext returns the link to the span of ctx. The batch updaters record it with the queued
+// operations, as the batches are processed after the requesting spans end.
+func LinkFromContext(ctx context.Context) Link {
+	return trace.LinkFromContext(ctx)
+}
+
+// StartBatchSpan starts the span of a batch of operations. The span is a child of the span requesting
+// the first operation, so that it is in the same trace, and is linked to the spans requesting the others.
+func StartBatchSpan(ctx context.Context, name string, links []Link, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	var parent trace.SpanContext
+	var validLinks []trace.Link
+	for _, link := range links {
+		switch {
+		case !link.SpanContext.IsValid():
+		case !parent.IsValid():
+			parent = link.SpanContext
+		case !link.SpanContext.Equal(parent):
+			validLinks = append(validLinks, link)
+		}
+	}
+	if parent.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, parent)
+	}
+	return tracer().Start(ctx, name, trace.WithLinks(validLinks...), trace.WithAttributes(attributes...))
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"sigs.k8s.io/cloud-provider-azure/pkg/azclient/utils"
+)
+
+// fakeExporter keeps the ended spans in memory.
+type fakeExporter struct {
+	lock  sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *fakeExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *fakeExporter) Shutdown(_ context.Context) error {
+	return nil
+}
+
+func (e *fakeExporter) span(t *testing.T, name string) sdktrace.ReadOnlySpan {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, span := range e.spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("span %q is not exported", name)
+	return nil
+}
+
+func setupExporter(t *testing.T) *fakeExporter {
+	exporter := &fakeExporter{}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter)))
+	original := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(original)
+		_ = tp.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func TestStartSpan(t *testing.T) {
+	exporter := setupExporter(t)
+
+	ctx, parent := StartSpan(context.Background(), "parent", ServiceKey.String("ns/svc"))
+	_, child := StartSpan(ctx, "child")
+	EndSpan(child, errors.New("failed"))
+	EndSpan(parent, nil)
+
+	parentSpan := exporter.span(t, "parent")
+	childSpan := exporter.span(t, "child")
+	assert.Equal(t, parentSpan.SpanContext().TraceID(), childSpan.SpanContext().TraceID())
+	assert.Equal(t, parentSpan.SpanContext().SpanID(), childSpan.Parent().SpanID())
+	assert.Contains(t, parentSpan.Attributes(), ServiceKey.String("ns/svc"))
+	assert.Equal(t, codes.Unset, parentSpan.Status().Code)
+	assert.Equal(t, codes.Error, childSpan.Status().Code)
+	assert.Equal(t, "failed", childSpan.Status().Description)
+	if assert.Len(t, childSpan.Events(), 1) {
+		assert.Equal(t, "exception", childSpan.Events()[0].Name)
+	}
+}
+
+func TestStartBatchSpan(t *testing.T) {
+	exporter := setupExporter(t)
+
+	ctx1, requester1 := StartSpan(context.Background(), "requester1")
+	ctx2, requester2 := StartSpan(context.Background(), "requester2")
+	links := []Link{
+		LinkFromContext(context.Background()),
+		LinkFromContext(ctx1),
+		LinkFromContext(ctx1),
+		LinkFromContext(ctx2),
+	}
+	EndSpan(requester1, nil)
+	EndSpan(requester2, nil)
+
+	_, batch := StartBatchSpan(context.Background(), "batch", links, attribute.Int("batch_size", len(links)))
+	EndSpan(batch, nil)
+
+	batchSpan := exporter.span(t, "batch")
+	assert.Equal(t, requester1.SpanContext().TraceID(), batchSpan.SpanContext().TraceID())
+	assert.Equal(t, requester1.SpanContext().SpanID(), batchSpan.Parent().SpanID())
+	if assert.Len(t, batchSpan.Links(), 1) {
+		assert.Equal(t, requester2.SpanContext(), batchSpan.Links()[0].SpanContext)
+	}
+	assert.Contains(t, batchSpan.Attributes(), attribute.Int("batch_size", len(links)))
+}
+
+func TestStartBatchSpanWithoutLinks(t *testing.T) {
+	exporter := setupExporter(t)
+
+	_, batch := StartBatchSpan(context.Background(), "batch", nil)
+	EndSpan(batch, nil)
+
+	batchSpan := exporter.span(t, "batch")
+	assert.False(t, batchSpan.Parent().IsValid())
+	assert.Empty(t, batchSpan.Links())
+}
+
+func TestAzSDKTracingProvider(t *testing.T) {
+	exporter := setupExporter(t)
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	tracer := utils.TracingProvider.NewTracer("test", "v1")
+	_, span := tracer.Start(ctx, "LoadBalancersClient.Get", &tracing.SpanOptions{
+		Kind:       tracing.SpanKindClient,
+		Attributes: []tracing.Attribute{{Key: "az.namespace", Value: "Microsoft.Network"}, {Key: "retry", Value: 2}},
+	})
+	span.SetStatus(tracing.SpanStatusError, "not found")
+	span.End()
+	EndSpan(parent, nil)
+
+	parentSpan := exporter.span(t, "parent")
+	sdkSpan := exporter.span(t, "LoadBalancersClient.Get")
+	assert.Equal(t, parentSpan.SpanContext().SpanID(), sdkSpan.Parent().SpanID())
+	assert.Contains(t, sdkSpan.Attributes(), attribute.String("az.namespace", "Microsoft.Network"))
+	assert.Contains(t, sdkSpan.Attributes(), attribute.Int("retry", 2))
+	assert.Equal(t, codes.Error, sdkSpan.Status().Code)
+	assert.Equal(t, "not found", sdkSpan.Status().Description)
+}
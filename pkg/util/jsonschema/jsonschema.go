@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonschema generates JSON Schemas from the JSON tags of Go structs, and validates
+// decoded JSON documents against them.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema generated from Go types.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+
+	// closed objects reject the properties that are not in Properties
+	closed bool
+}
+
+// MarshalJSON implements json.Marshaler, closed objects are marshaled with additionalProperties false.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type schema Schema
+	if !s.closed {
+		return json.Marshal((*schema)(s))
+	}
+	return json.Marshal(struct {
+		*schema
+		AdditionalProperties bool `json:"additionalProperties"`
+	}{schema: (*schema)(s)})
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Reflect generates the schema of the type of v from its JSON tags. The fields of embedded structs
+// without a JSON name are promoted like encoding/json does, the shallower fields win on conflicts.
+// Types decoding themselves with json.Unmarshaler accept any value.
+func Reflect(title string, v interface{}) *Schema {
+	s := reflectType(reflect.TypeOf(v))
+	s.Schema = draft
+	s.Title = title
+	return s
+}
+
+func reflectType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(unmarshalerType) || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: reflectType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: reflectType(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}, closed: true}
+		addFields(s, t)
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// addFields adds the direct fields of the struct first, then the promoted fields of its embedded structs.
+func addFields(s *Schema, t reflect.Type) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldType := f.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if f.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded = append(embedded, fieldType)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = reflectType(f.Type)
+	}
+	for _, e := range embedded {
+		promoted := &Schema{Properties: map[string]*Schema{}}
+		addFields(promoted, e)
+		for name, p := range promoted.Properties {
+			if _, ok := s.Properties[name]; !ok {
+				s.Properties[name] = p
+			}
+		}
+	}
+}
+
+// Validate validates the document decoded by encoding/json against the schema. The enum values
+// are matched case-insensitively. The unknown properties of closed objects are reported separately,
+// with the property of the schema they match case-insensitively if any.
+func (s *Schema) Validate(doc interface{}) (unknown field.ErrorList, errs field.ErrorList) {
+	s.validate(doc, nil, &unknown, &errs)
+	return unknown, errs
+}
+
+func (s *Schema) validate(value interface{}, path *field.Path, unknown, errs *field.ErrorList) {
+	if value == nil {
+		return
+	}
+	switch s.Type {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			*errs = append(*errs, field.TypeInvalid(path, value, "must be a string"))
+			return
+		}
+		if len(s.Enum) > 0 && !containsFold(s.Enum, str) {
+			*errs = append(*errs, field.NotSupported(path, str, s.Enum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			*errs = append(*errs, field.TypeInvalid(path, value, "must be a boolean"))
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			*errs = append(*errs, field.TypeInvalid(path, value, "must be an integer"))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			*errs = append(*errs, field.TypeInvalid(path, value, "must be a number"))
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			*errs = append(*errs, field.TypeInvalid(path, value, "must be an array"))
+			return
+		}
+		for i, item := range items {
+			s.Items.validate(item, path.Index(i), unknown, errs)
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, field.TypeInvalid(path, value, "must be an object"))
+			return
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if p, ok := s.Properties[name]; ok {
+				p.validate(object[name], child(path, name), unknown, errs)
+				continue
+			}
+			if s.AdditionalProperties != nil {
+				s.AdditionalProperties.validate(object[name], path.Key(name), unknown, errs)
+				continue
+			}
+			if !s.closed {
+				continue
+			}
+			detail := "unknown field"
+			if match := s.matchFold(name); match != "" {
+				detail = fmt.Sprintf("unknown field, did you mean %q?", match)
+				// encoding/json decodes the field case-insensitively
+				s.Properties[match].validate(object[name], child(path, name), unknown, errs)
+			}
+			*unknown = append(*unknown, field.Invalid(child(path, name), name, detail))
+		}
+	}
+}
+
+func (s *Schema) matchFold(name string) string {
+	for property := range s.Properties {
+		if strings.EqualFold(property, name) {
+			return property
+		}
+	}
+	return ""
+}
+
+func child(path *field.Path, name string) *field.Path {
+	if path == nil {
+		return field.NewPath(name)
+	}
+	return path.Child(name)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
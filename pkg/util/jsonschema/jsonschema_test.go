@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type embedded struct {
+	Shadowed string `json:"value"`
+	Promoted int    `json:"promoted,omitempty"`
+}
+
+type testConfig struct {
+	embedded
+	Value   string            `json:"value,omitempty"`
+	Ratio   float64           `json:"ratio,omitempty"`
+	Enabled *bool             `json:"enabled,omitempty"`
+	Inners  []inner           `json:"inners,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	Raw     json.RawMessage   `json:"raw,omitempty"`
+	Ignored string            `json:"-"`
+	hidden  string
+}
+
+func TestReflect(t *testing.T) {
+	schema := Reflect("test", testConfig{})
+	assert.Equal(t, "object", schema.Type)
+	assert.ElementsMatch(t, []string{"value", "promoted", "ratio", "enabled", "inners", "tags", "raw"}, keys(schema.Properties))
+	assert.Equal(t, "string", schema.Properties["value"].Type)
+	assert.Equal(t, "integer", schema.Properties["promoted"].Type)
+	assert.Equal(t, "number", schema.Properties["ratio"].Type)
+	assert.Equal(t, "boolean", schema.Properties["enabled"].Type)
+	assert.Equal(t, "string", schema.Properties["inners"].Items.Properties["name"].Type)
+	assert.Equal(t, "string", schema.Properties["tags"].AdditionalProperties.Type)
+	assert.Empty(t, schema.Properties["raw"].Type)
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var marshaled map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &marshaled))
+	assert.Equal(t, false, marshaled["additionalProperties"])
+	assert.Equal(t, "test", marshaled["title"])
+	assert.NotContains(t, marshaled["properties"].(map[string]interface{})["tags"], "properties")
+}
+
+func TestValidate(t *testing.T) {
+	schema := Reflect("test", testConfig{})
+	schema.Properties["value"].Enum = []string{"a", "b"}
+
+	for _, tc := range []struct {
+		desc            string
+		doc             string
+		expectedUnknown []string
+		expectedErrs    []string
+	}{
+		{
+			desc: "valid document",
+			doc:  `{"value": "A", "ratio": 1.5, "promoted": 1, "inners": [{"name": "x"}], "tags": {"k": "v"}, "raw": {"any": [1]}}`,
+		},
+		{
+			desc:            "unknown fields",
+			doc:             `{"unknown": 1, "inners": [{"nmae": "x"}], "Ratio": 1}`,
+			expectedUnknown: []string{`Ratio: Invalid value: "Ratio": unknown field, did you mean "ratio"?`, `inners[0].nmae: Invalid value: "nmae": unknown field`, `unknown: Invalid value: "unknown": unknown field`},
+		},
+		{
+			desc: "invalid values",
+			doc:  `{"value": "c", "promoted": 1.5, "enabled": "true", "tags": {"k": 1}}`,
+			expectedErrs: []string{
+				`enabled: Invalid value: "true": must be a boolean`,
+				`promoted: Invalid value: 1.5: must be an integer`,
+				`tags[k]: Invalid value: 1: must be a string`,
+				`value: Unsupported value: "c": supported values: "a", "b"`,
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var doc interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tc.doc), &doc))
+			unknown, errs := schema.Validate(doc)
+			assert.Equal(t, tc.expectedUnknown, errorStrings(unknown))
+			assert.Equal(t, tc.expectedErrs, errorStrings(errs))
+		})
+	}
+}
+
+func keys(m map[string]*Schema) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+func errorStrings(errs field.ErrorList) []string {
+	var result []string
+	for _, err := range errs {
+		result = append(result, err.Error())
+	}
+	return result
+}